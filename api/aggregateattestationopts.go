@@ -17,6 +17,8 @@ import "github.com/attestantio/go-eth2-client/spec/phase0"
 
 // AggregateAttestationOpts are the options for obtaining aggregate attestations.
 type AggregateAttestationOpts struct {
+	Common CommonOpts
+
 	// Slot is the slot for which the data is obtained.
 	Slot phase0.Slot
 	// AttestationDataRoot is the root for which the data is obtained.
@@ -17,6 +17,8 @@ import "github.com/attestantio/go-eth2-client/spec/phase0"
 
 // AttestationDataOpts are the options for obtaining attestation data.
 type AttestationDataOpts struct {
+	Common CommonOpts
+
 	// Slot is the slot for which the data is obtained.
 	Slot phase0.Slot
 	// CommitteeIndex is the committee index for which the data is obtained.
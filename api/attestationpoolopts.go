@@ -17,6 +17,8 @@ import "github.com/attestantio/go-eth2-client/spec/phase0"
 
 // AttestationPoolOpts are the options for obtaining the attestation pool.
 type AttestationPoolOpts struct {
+	Common CommonOpts
+
 	// Slot is the slot for which the data is obtained.
 	Slot phase0.Slot
 }
@@ -17,9 +17,11 @@ import "github.com/attestantio/go-eth2-client/spec/phase0"
 
 // BeaconCommitteesOpts are the options for obtaining proposer duties.
 type BeaconCommitteesOpts struct {
+	Common CommonOpts
+
 	// State is the state at which the data is obtained.
 	// It can be a slot number or state root, or one of the special values "genesis", "head", "justified" or "finalized".
-	State string
+	State StateID
 	// Epoch is the epoch for which the data is obtained.
 	// This is optional; if not supplied it will obtain the data at the epoch relating to the state.
 	Epoch *phase0.Epoch
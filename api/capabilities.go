@@ -0,0 +1,60 @@
+// Copyright © 2023 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import "errors"
+
+// ErrNotSupported is returned when a beacon node is known not to support an endpoint.
+var ErrNotSupported = errors.New("not supported by beacon node")
+
+// CapabilityProposalV3 is the capability to produce blocks with the v3 block production endpoint.
+const CapabilityProposalV3 = "ProposalV3"
+
+// Capabilities are the capabilities of a beacon node.
+type Capabilities struct {
+	// Client is the client software of the beacon node, for example "lighthouse" or "teku".
+	Client string
+	// DVTMiddleware is true if the beacon node is accessed through distributed validator middleware.
+	DVTMiddleware bool
+	// Endpoints are the capabilities of individual endpoints and features, keyed by provider
+	// function name (for example "BlindedProposal") or capability name (for example
+	// CapabilityProposalV3).
+	// Endpoints that are not present have not been probed.
+	Endpoints map[string]EndpointCapabilities
+}
+
+// EndpointCapabilities are the capabilities of an individual endpoint.
+type EndpointCapabilities struct {
+	// Supported is true if the endpoint is supported.
+	Supported bool
+	// SSZ is true if the endpoint has been seen to accept or return SSZ.
+	SSZ bool
+	// JSON is true if the endpoint has been seen to accept or return JSON.
+	JSON bool
+}
+
+// Supports returns true unless the endpoint is known to be unsupported.
+func (c *Capabilities) Supports(endpoint string) bool {
+	capabilities, exists := c.Endpoints[endpoint]
+
+	return !exists || capabilities.Supported
+}
+
+// SupportsSSZ returns true unless the endpoint is known not to support SSZ.
+// An endpoint is known not to support SSZ if it has only been seen to use JSON.
+func (c *Capabilities) SupportsSSZ(endpoint string) bool {
+	capabilities, exists := c.Endpoints[endpoint]
+
+	return !exists || capabilities.SSZ || !capabilities.JSON
+}
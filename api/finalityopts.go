@@ -15,7 +15,9 @@ package api
 
 // FinalityOpts are the options for obtaining finality checkpoints.
 type FinalityOpts struct {
+	Common CommonOpts
+
 	// State is the state at which the data is obtained.
 	// It can be a slot number or state root, or one of the special values "genesis", "head", "justified" or "finalized".
-	State string
+	State StateID
 }
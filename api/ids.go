@@ -0,0 +1,194 @@
+// Copyright © 2023 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+)
+
+// StateID is the identifier of a beacon state.
+// It can be a slot number or state root, or one of the special values "genesis", "head", "justified" or "finalized".
+type StateID string
+
+const (
+	// StateIDGenesis is the state at genesis.
+	StateIDGenesis StateID = "genesis"
+	// StateIDHead is the state at the head of the chain.
+	StateIDHead StateID = "head"
+	// StateIDJustified is the state at the latest justified checkpoint.
+	StateIDJustified StateID = "justified"
+	// StateIDFinalized is the state at the latest finalized checkpoint.
+	StateIDFinalized StateID = "finalized"
+)
+
+// StateIDFromSlot returns the state ID for the given slot.
+func StateIDFromSlot(slot phase0.Slot) StateID {
+	return StateID(fmt.Sprintf("%d", slot))
+}
+
+// StateIDFromRoot returns the state ID for the given state root.
+func StateIDFromRoot(root phase0.Root) StateID {
+	return StateID(fmt.Sprintf("%#x", root))
+}
+
+// StateIDFromEpochStart returns the state ID for the first slot of the given epoch.
+func StateIDFromEpochStart(epoch phase0.Epoch, slotsPerEpoch uint64) StateID {
+	return StateIDFromSlot(phase0.Slot(uint64(epoch) * slotsPerEpoch))
+}
+
+// String returns a string representation of the state ID.
+func (s StateID) String() string {
+	return string(s)
+}
+
+// IsNamed returns true if the state ID is one of the special values.
+func (s StateID) IsNamed() bool {
+	switch s {
+	case StateIDGenesis, StateIDHead, StateIDJustified, StateIDFinalized:
+		return true
+	default:
+		return false
+	}
+}
+
+// Slot returns the slot of the state ID, if it is a slot.
+func (s StateID) Slot() (phase0.Slot, bool) {
+	return slotFromID(string(s))
+}
+
+// Root returns the root of the state ID, if it is a root.
+func (s StateID) Root() (phase0.Root, bool) {
+	return rootFromID(string(s))
+}
+
+// Validate returns an error if the state ID is not valid.
+func (s StateID) Validate() error {
+	if s == "" {
+		return errors.New("no state specified")
+	}
+	if s.IsNamed() {
+		return nil
+	}
+
+	return validateID("state", string(s))
+}
+
+// BlockID is the identifier of a beacon block.
+// It can be a slot number or block root, or one of the special values "genesis", "head" or "finalized".
+type BlockID string
+
+const (
+	// BlockIDGenesis is the block at genesis.
+	BlockIDGenesis BlockID = "genesis"
+	// BlockIDHead is the block at the head of the chain.
+	BlockIDHead BlockID = "head"
+	// BlockIDFinalized is the block at the latest finalized checkpoint.
+	BlockIDFinalized BlockID = "finalized"
+)
+
+// BlockIDFromSlot returns the block ID for the given slot.
+func BlockIDFromSlot(slot phase0.Slot) BlockID {
+	return BlockID(fmt.Sprintf("%d", slot))
+}
+
+// BlockIDFromRoot returns the block ID for the given block root.
+func BlockIDFromRoot(root phase0.Root) BlockID {
+	return BlockID(fmt.Sprintf("%#x", root))
+}
+
+// BlockIDFromEpochStart returns the block ID for the first slot of the given epoch.
+func BlockIDFromEpochStart(epoch phase0.Epoch, slotsPerEpoch uint64) BlockID {
+	return BlockIDFromSlot(phase0.Slot(uint64(epoch) * slotsPerEpoch))
+}
+
+// String returns a string representation of the block ID.
+func (b BlockID) String() string {
+	return string(b)
+}
+
+// IsNamed returns true if the block ID is one of the special values.
+func (b BlockID) IsNamed() bool {
+	switch b {
+	case BlockIDGenesis, BlockIDHead, BlockIDFinalized:
+		return true
+	default:
+		return false
+	}
+}
+
+// Slot returns the slot of the block ID, if it is a slot.
+func (b BlockID) Slot() (phase0.Slot, bool) {
+	return slotFromID(string(b))
+}
+
+// Root returns the root of the block ID, if it is a root.
+func (b BlockID) Root() (phase0.Root, bool) {
+	return rootFromID(string(b))
+}
+
+// Validate returns an error if the block ID is not valid.
+func (b BlockID) Validate() error {
+	if b == "" {
+		return errors.New("no block specified")
+	}
+	if b.IsNamed() {
+		return nil
+	}
+
+	return validateID("block", string(b))
+}
+
+// slotFromID returns the slot of an ID, if it is a slot.
+func slotFromID(id string) (phase0.Slot, bool) {
+	slot, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+
+	return phase0.Slot(slot), true
+}
+
+// rootFromID returns the root of an ID, if it is a root.
+func rootFromID(id string) (phase0.Root, bool) {
+	if !strings.HasPrefix(id, "0x") {
+		return phase0.Root{}, false
+	}
+	data, err := hex.DecodeString(strings.TrimPrefix(id, "0x"))
+	if err != nil || len(data) != phase0.RootLength {
+		return phase0.Root{}, false
+	}
+
+	var root phase0.Root
+	copy(root[:], data)
+
+	return root, true
+}
+
+// validateID returns an error if the ID is neither a slot nor a root.
+func validateID(kind string, id string) error {
+	if _, isSlot := slotFromID(id); isSlot {
+		return nil
+	}
+	if _, isRoot := rootFromID(id); isRoot {
+		return nil
+	}
+
+	return fmt.Errorf("invalid %s ID %s", kind, id)
+}
@@ -0,0 +1,143 @@
+// Copyright © 2023 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api_test
+
+import (
+	"testing"
+
+	"github.com/attestantio/go-eth2-client/api"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStateID(t *testing.T) {
+	tests := []struct {
+		name  string
+		id    api.StateID
+		err   string
+		named bool
+		slot  *phase0.Slot
+		root  *phase0.Root
+	}{
+		{
+			name: "Empty",
+			id:   "",
+			err:  "no state specified",
+		},
+		{
+			name: "Invalid",
+			id:   "latest",
+			err:  "invalid state ID latest",
+		},
+		{
+			name: "RootShort",
+			id:   "0x0102",
+			err:  "invalid state ID 0x0102",
+		},
+		{
+			name:  "Head",
+			id:    api.StateIDHead,
+			named: true,
+		},
+		{
+			name:  "Justified",
+			id:    api.StateIDJustified,
+			named: true,
+		},
+		{
+			name: "Slot",
+			id:   api.StateIDFromSlot(12345),
+			slot: slotPtr(12345),
+		},
+		{
+			name: "EpochStart",
+			id:   api.StateIDFromEpochStart(10, 32),
+			slot: slotPtr(320),
+		},
+		{
+			name: "Root",
+			id:   api.StateIDFromRoot(phase0.Root{0x01, 0x02}),
+			root: &phase0.Root{0x01, 0x02},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.id.Validate()
+			if test.err != "" {
+				require.EqualError(t, err, test.err)
+
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.named, test.id.IsNamed())
+			slot, isSlot := test.id.Slot()
+			require.Equal(t, test.slot != nil, isSlot)
+			if test.slot != nil {
+				require.Equal(t, *test.slot, slot)
+			}
+			root, isRoot := test.id.Root()
+			require.Equal(t, test.root != nil, isRoot)
+			if test.root != nil {
+				require.Equal(t, *test.root, root)
+			}
+		})
+	}
+}
+
+func TestBlockID(t *testing.T) {
+	tests := []struct {
+		name string
+		id   api.BlockID
+		err  string
+	}{
+		{
+			name: "Empty",
+			id:   "",
+			err:  "no block specified",
+		},
+		{
+			name: "Justified",
+			id:   "justified",
+			err:  "invalid block ID justified",
+		},
+		{
+			name: "Head",
+			id:   api.BlockIDHead,
+		},
+		{
+			name: "Slot",
+			id:   api.BlockIDFromSlot(1),
+		},
+		{
+			name: "Root",
+			id:   api.BlockIDFromRoot(phase0.Root{}),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.id.Validate()
+			if test.err != "" {
+				require.EqualError(t, err, test.err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func slotPtr(slot phase0.Slot) *phase0.Slot {
+	return &slot
+}
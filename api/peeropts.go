@@ -15,6 +15,8 @@ package api
 
 // PeerOpts are the options for client side peer filtering.
 type PeerOpts struct {
+	Common CommonOpts
+
 	// State of the connection (disconnected, connecting, connected, disconnecting)
 	State []string
 	// Direction of the connection (inbound, outbound)
@@ -17,6 +17,8 @@ import "github.com/attestantio/go-eth2-client/spec/phase0"
 
 // ProposalOpts are the options for obtaining proposals.
 type ProposalOpts struct {
+	Common CommonOpts
+
 	// Slot is the slot for which the proposal should be fetched.
 	Slot phase0.Slot
 	// RandaoReveal is the RANDAO reveal for the proposal.
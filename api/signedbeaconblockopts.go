@@ -15,6 +15,9 @@ package api
 
 // SignedBeaconBlockOpts are the options for obtaining signed beacon blocks.
 type SignedBeaconBlockOpts struct {
+	Common CommonOpts
+
 	// Block is the ID of the block which the data is obtained.
-	Block string
+	// It can be a slot number or block root, or one of the special values "genesis", "head" or "finalized".
+	Block BlockID
 }
@@ -17,6 +17,8 @@ import "github.com/attestantio/go-eth2-client/spec/phase0"
 
 // SyncCommitteeContributionOpts are the options for obtaining sync committee contributions.
 type SyncCommitteeContributionOpts struct {
+	Common CommonOpts
+
 	// Slot is the slot for which the data is obtained.
 	Slot phase0.Slot
 	// SubcommitteeIndex is the index of the sync subcommittee for which the data is obtained.
@@ -17,9 +17,11 @@ import "github.com/attestantio/go-eth2-client/spec/phase0"
 
 // ValidatorBalancesOpts are the options for obtaining validator balances.
 type ValidatorBalancesOpts struct {
+	Common CommonOpts
+
 	// State is the state at which the data is obtained.
 	// It can be a slot number or state root, or one of the special values "genesis", "head", "justified" or "finalized".
-	State string
+	State StateID
 	// Indices is a list of validator indices to restrict the returned values.  If no indices are supplied then no filter will be applied.
 	Indices []phase0.ValidatorIndex
 }
@@ -0,0 +1,435 @@
+// Copyright © 2023 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	consensusclient "github.com/attestantio/go-eth2-client"
+	"github.com/attestantio/go-eth2-client/api"
+	apiv1 "github.com/attestantio/go-eth2-client/api/v1"
+	"github.com/attestantio/go-eth2-client/spec"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/pkg/errors"
+)
+
+// fetch returns the cached response for the given key if present, otherwise obtains
+// the response from the supplied function and caches it.
+func fetch[T any](s *Service,
+	call string,
+	key string,
+	fetcher func() (*api.Response[T], error),
+) (
+	*api.Response[T],
+	error,
+) {
+	key = fmt.Sprintf("%s:%s", call, key)
+
+	if value, exists := s.entries.get(key); exists {
+		monitorRequest(call, "hit")
+
+		return value.(*api.Response[T]), nil
+	}
+
+	if s.disk != nil {
+		response := &api.Response[T]{}
+		metadata, found, err := s.disk.read(key, &response.Data)
+		if err != nil {
+			s.log.Warn().Str("key", key).Err(err).Msg("Failed to read persisted value")
+		}
+		if found {
+			response.Metadata = metadata
+			monitorRequest(call, "hit")
+			s.entries.add(key, response)
+
+			return response, nil
+		}
+	}
+
+	monitorRequest(call, "miss")
+	response, err := fetcher()
+	if err != nil {
+		return nil, err
+	}
+	if response == nil {
+		return nil, nil
+	}
+
+	s.entries.add(key, response)
+	if s.disk != nil {
+		if err := s.disk.write(key, response.Data, response.Metadata); err != nil {
+			s.log.Warn().Str("key", key).Err(err).Msg("Failed to persist value")
+		}
+	}
+
+	return response, nil
+}
+
+// immutableID returns a canonical form of the given block or state ID if the data
+// to which it refers cannot change, or false if it can.
+func (s *Service) immutableID(ctx context.Context, id string) (string, bool) {
+	switch {
+	case id == "genesis":
+		return "0", true
+	case strings.HasPrefix(id, "0x") && len(id) == 66:
+		return strings.ToLower(id), true
+	}
+
+	slot, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		// A named ID such as "head", "justified" or "finalized", which can change.
+		return "", false
+	}
+
+	finalizedSlot, err := s.obtainFinalizedSlot(ctx)
+	if err != nil {
+		s.log.Debug().Err(err).Msg("Failed to obtain finalized slot")
+
+		return "", false
+	}
+	if phase0.Slot(slot) > finalizedSlot {
+		return "", false
+	}
+
+	return id, true
+}
+
+// immutableEpoch returns true if the given epoch is finalized.
+func (s *Service) immutableEpoch(ctx context.Context, epoch phase0.Epoch) bool {
+	finalizedSlot, err := s.obtainFinalizedSlot(ctx)
+	if err != nil {
+		s.log.Debug().Err(err).Msg("Failed to obtain finalized slot")
+
+		return false
+	}
+	slotsPerEpoch, err := s.slotsPerEpoch(ctx)
+	if err != nil {
+		return false
+	}
+
+	return uint64(epoch) <= uint64(finalizedSlot)/slotsPerEpoch
+}
+
+// obtainFinalizedSlot obtains the first slot of the latest finalized epoch.
+func (s *Service) obtainFinalizedSlot(ctx context.Context) (phase0.Slot, error) {
+	s.finalizedSlotMu.Lock()
+	defer s.finalizedSlotMu.Unlock()
+
+	if time.Since(s.finalizedSlotFetched) < finalityRefreshInterval {
+		return s.finalizedSlot, nil
+	}
+
+	provider, isProvider := s.next.(consensusclient.FinalityProvider)
+	if !isProvider {
+		return 0, errors.New("client does not provide finality")
+	}
+	response, err := provider.Finality(ctx, &api.FinalityOpts{State: "head"})
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to obtain finality")
+	}
+	slotsPerEpoch, err := s.slotsPerEpoch(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	s.finalizedSlot = phase0.Slot(uint64(response.Data.Finalized.Epoch) * slotsPerEpoch)
+	s.finalizedSlotFetched = time.Now()
+
+	return s.finalizedSlot, nil
+}
+
+// slotsPerEpoch obtains the number of slots per epoch from the client.
+func (s *Service) slotsPerEpoch(ctx context.Context) (uint64, error) {
+	provider, isProvider := s.next.(consensusclient.SlotsPerEpochProvider)
+	if !isProvider {
+		return 0, errors.New("client does not provide slots per epoch")
+	}
+	slotsPerEpoch, err := provider.SlotsPerEpoch(ctx)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to obtain slots per epoch")
+	}
+	if slotsPerEpoch == 0 {
+		return 0, errors.New("slots per epoch is 0")
+	}
+
+	return slotsPerEpoch, nil
+}
+
+// epochKey returns the key element for an optional epoch.
+func epochKey(epoch *phase0.Epoch) string {
+	if epoch == nil {
+		return "state"
+	}
+
+	return fmt.Sprintf("%d", *epoch)
+}
+
+// filterKey returns a short key element for filters on validator indices and public keys.
+func filterKey(indices []phase0.ValidatorIndex, pubKeys []phase0.BLSPubKey) string {
+	if len(indices) == 0 && len(pubKeys) == 0 {
+		return "all"
+	}
+
+	sortedIndices := make([]phase0.ValidatorIndex, len(indices))
+	copy(sortedIndices, indices)
+	sort.Slice(sortedIndices, func(i, j int) bool { return sortedIndices[i] < sortedIndices[j] })
+	sortedPubKeys := make([]string, len(pubKeys))
+	for i := range pubKeys {
+		sortedPubKeys[i] = fmt.Sprintf("%#x", pubKeys[i])
+	}
+	sort.Strings(sortedPubKeys)
+
+	hash := sha256.Sum256([]byte(fmt.Sprintf("%v:%v", sortedIndices, sortedPubKeys)))
+
+	return hex.EncodeToString(hash[:])
+}
+
+// SignedBeaconBlock fetches a signed beacon block given a block ID.
+func (s *Service) SignedBeaconBlock(ctx context.Context,
+	opts *api.SignedBeaconBlockOpts,
+) (
+	*api.Response[*spec.VersionedSignedBeaconBlock],
+	error,
+) {
+	next, isNext := s.next.(consensusclient.SignedBeaconBlockProvider)
+	if !isNext {
+		return nil, fmt.Errorf("%s@%s does not support this call", s.next.Name(), s.next.Address())
+	}
+	if opts == nil {
+		return next.SignedBeaconBlock(ctx, opts)
+	}
+
+	id, immutable := s.immutableID(ctx, opts.Block.String())
+	if !immutable {
+		monitorRequest("SignedBeaconBlock", "uncacheable")
+
+		return next.SignedBeaconBlock(ctx, opts)
+	}
+
+	return fetch(s, "SignedBeaconBlock", id, func() (*api.Response[*spec.VersionedSignedBeaconBlock], error) {
+		return next.SignedBeaconBlock(ctx, opts)
+	})
+}
+
+// BeaconBlockHeader provides the block header of a given block ID.
+func (s *Service) BeaconBlockHeader(ctx context.Context,
+	opts *api.BeaconBlockHeaderOpts,
+) (
+	*api.Response[*apiv1.BeaconBlockHeader],
+	error,
+) {
+	next, isNext := s.next.(consensusclient.BeaconBlockHeadersProvider)
+	if !isNext {
+		return nil, fmt.Errorf("%s@%s does not support this call", s.next.Name(), s.next.Address())
+	}
+	if opts == nil {
+		return next.BeaconBlockHeader(ctx, opts)
+	}
+
+	id, immutable := s.immutableID(ctx, opts.Block.String())
+	if !immutable {
+		monitorRequest("BeaconBlockHeader", "uncacheable")
+
+		return next.BeaconBlockHeader(ctx, opts)
+	}
+
+	return fetch(s, "BeaconBlockHeader", id, func() (*api.Response[*apiv1.BeaconBlockHeader], error) {
+		return next.BeaconBlockHeader(ctx, opts)
+	})
+}
+
+// BeaconBlockRoot fetches a block's root given a set of options.
+func (s *Service) BeaconBlockRoot(ctx context.Context,
+	opts *api.BeaconBlockRootOpts,
+) (
+	*api.Response[*phase0.Root],
+	error,
+) {
+	next, isNext := s.next.(consensusclient.BeaconBlockRootProvider)
+	if !isNext {
+		return nil, fmt.Errorf("%s@%s does not support this call", s.next.Name(), s.next.Address())
+	}
+	if opts == nil {
+		return next.BeaconBlockRoot(ctx, opts)
+	}
+
+	id, immutable := s.immutableID(ctx, opts.Block.String())
+	if !immutable {
+		monitorRequest("BeaconBlockRoot", "uncacheable")
+
+		return next.BeaconBlockRoot(ctx, opts)
+	}
+
+	return fetch(s, "BeaconBlockRoot", id, func() (*api.Response[*phase0.Root], error) {
+		return next.BeaconBlockRoot(ctx, opts)
+	})
+}
+
+// BeaconState fetches a beacon state.
+func (s *Service) BeaconState(ctx context.Context,
+	opts *api.BeaconStateOpts,
+) (
+	*api.Response[*spec.VersionedBeaconState],
+	error,
+) {
+	next, isNext := s.next.(consensusclient.BeaconStateProvider)
+	if !isNext {
+		return nil, fmt.Errorf("%s@%s does not support this call", s.next.Name(), s.next.Address())
+	}
+	if opts == nil {
+		return next.BeaconState(ctx, opts)
+	}
+
+	id, immutable := s.immutableID(ctx, opts.State.String())
+	if !immutable {
+		monitorRequest("BeaconState", "uncacheable")
+
+		return next.BeaconState(ctx, opts)
+	}
+
+	return fetch(s, "BeaconState", id, func() (*api.Response[*spec.VersionedBeaconState], error) {
+		return next.BeaconState(ctx, opts)
+	})
+}
+
+// BeaconCommittees fetches all beacon committees for the given options.
+func (s *Service) BeaconCommittees(ctx context.Context,
+	opts *api.BeaconCommitteesOpts,
+) (
+	*api.Response[[]*apiv1.BeaconCommittee],
+	error,
+) {
+	next, isNext := s.next.(consensusclient.BeaconCommitteesProvider)
+	if !isNext {
+		return nil, fmt.Errorf("%s@%s does not support this call", s.next.Name(), s.next.Address())
+	}
+	if opts == nil {
+		return next.BeaconCommittees(ctx, opts)
+	}
+
+	var key string
+	switch id, immutable := s.immutableID(ctx, opts.State.String()); {
+	case opts.Epoch != nil && s.immutableEpoch(ctx, *opts.Epoch):
+		// Committees for a finalized epoch are the same regardless of the state used to obtain them.
+		key = fmt.Sprintf("epoch:%d", *opts.Epoch)
+	case immutable:
+		key = fmt.Sprintf("state:%s:%s", id, epochKey(opts.Epoch))
+	default:
+		monitorRequest("BeaconCommittees", "uncacheable")
+
+		return next.BeaconCommittees(ctx, opts)
+	}
+
+	return fetch(s, "BeaconCommittees", key, func() (*api.Response[[]*apiv1.BeaconCommittee], error) {
+		return next.BeaconCommittees(ctx, opts)
+	})
+}
+
+// SyncCommittee fetches the sync committee for the given state.
+func (s *Service) SyncCommittee(ctx context.Context,
+	opts *api.SyncCommitteeOpts,
+) (
+	*api.Response[*apiv1.SyncCommittee],
+	error,
+) {
+	next, isNext := s.next.(consensusclient.SyncCommitteesProvider)
+	if !isNext {
+		return nil, fmt.Errorf("%s@%s does not support this call", s.next.Name(), s.next.Address())
+	}
+	if opts == nil {
+		return next.SyncCommittee(ctx, opts)
+	}
+
+	var key string
+	switch id, immutable := s.immutableID(ctx, opts.State.String()); {
+	case opts.Epoch != nil && s.immutableEpoch(ctx, *opts.Epoch):
+		// The sync committee for a finalized epoch is the same regardless of the state used to obtain it.
+		key = fmt.Sprintf("epoch:%d", *opts.Epoch)
+	case immutable:
+		key = fmt.Sprintf("state:%s:%s", id, epochKey(opts.Epoch))
+	default:
+		monitorRequest("SyncCommittee", "uncacheable")
+
+		return next.SyncCommittee(ctx, opts)
+	}
+
+	return fetch(s, "SyncCommittee", key, func() (*api.Response[*apiv1.SyncCommittee], error) {
+		return next.SyncCommittee(ctx, opts)
+	})
+}
+
+// Validators provides the validators, with their balance and status, for the given options.
+func (s *Service) Validators(ctx context.Context,
+	opts *api.ValidatorsOpts,
+) (
+	*api.Response[map[phase0.ValidatorIndex]*apiv1.Validator],
+	error,
+) {
+	next, isNext := s.next.(consensusclient.ValidatorsProvider)
+	if !isNext {
+		return nil, fmt.Errorf("%s@%s does not support this call", s.next.Name(), s.next.Address())
+	}
+	if opts == nil {
+		return next.Validators(ctx, opts)
+	}
+
+	id, immutable := s.immutableID(ctx, opts.State.String())
+	if !immutable {
+		monitorRequest("Validators", "uncacheable")
+
+		return next.Validators(ctx, opts)
+	}
+
+	key := fmt.Sprintf("%s:%s", id, filterKey(opts.Indices, opts.PubKeys))
+
+	return fetch(s, "Validators", key, func() (*api.Response[map[phase0.ValidatorIndex]*apiv1.Validator], error) {
+		return next.Validators(ctx, opts)
+	})
+}
+
+// ValidatorBalances provides the validator balances for the given options.
+func (s *Service) ValidatorBalances(ctx context.Context,
+	opts *api.ValidatorBalancesOpts,
+) (
+	*api.Response[map[phase0.ValidatorIndex]phase0.Gwei],
+	error,
+) {
+	next, isNext := s.next.(consensusclient.ValidatorBalancesProvider)
+	if !isNext {
+		return nil, fmt.Errorf("%s@%s does not support this call", s.next.Name(), s.next.Address())
+	}
+	if opts == nil {
+		return next.ValidatorBalances(ctx, opts)
+	}
+
+	id, immutable := s.immutableID(ctx, opts.State.String())
+	if !immutable {
+		monitorRequest("ValidatorBalances", "uncacheable")
+
+		return next.ValidatorBalances(ctx, opts)
+	}
+
+	key := fmt.Sprintf("%s:%s", id, filterKey(opts.Indices, nil))
+
+	return fetch(s, "ValidatorBalances", key, func() (*api.Response[map[phase0.ValidatorIndex]phase0.Gwei], error) {
+		return next.ValidatorBalances(ctx, opts)
+	})
+}
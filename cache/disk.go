@@ -0,0 +1,284 @@
+// Copyright © 2023 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/pkg/errors"
+)
+
+// diskStore persists cached results as JSON files in a directory.
+// The total size of the files is bounded; when it is exceeded the least recently used
+// files are removed.
+type diskStore struct {
+	dir     string
+	maxSize int64
+
+	mu    sync.Mutex
+	size  int64
+	files map[string]*diskFile
+}
+
+// diskFile is a file in the store.
+type diskFile struct {
+	size int64
+	used time.Time
+}
+
+// diskRecord is the persisted form of a result.
+type diskRecord struct {
+	Data     json.RawMessage               `json:"data"`
+	Metadata map[string]*diskMetadataValue `json:"metadata,omitempty"`
+}
+
+// diskMetadataValue is a persisted metadata value, along with its type so that it can
+// be restored as the type it was stored as.
+type diskMetadataValue struct {
+	Type  string          `json:"type"`
+	Value json.RawMessage `json:"value"`
+}
+
+// newDiskStore creates a store in the given directory, creating it if required.
+func newDiskStore(dir string, maxSize int64) (*diskStore, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, errors.Wrap(err, "failed to create persistence directory")
+	}
+
+	d := &diskStore{
+		dir:     dir,
+		maxSize: maxSize,
+		files:   make(map[string]*diskFile),
+	}
+
+	// Pick up files from previous runs, using their modification time as their last use.
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read persistence directory")
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		d.files[filepath.Join(dir, entry.Name())] = &diskFile{
+			size: info.Size(),
+			used: info.ModTime(),
+		}
+		d.size += info.Size()
+	}
+	d.mu.Lock()
+	d.prune()
+	d.mu.Unlock()
+
+	return d, nil
+}
+
+// path returns the path of the file for the given key.
+func (d *diskStore) path(key string) string {
+	hash := sha256.Sum256([]byte(key))
+
+	return filepath.Join(d.dir, hex.EncodeToString(hash[:])+".json")
+}
+
+// read reads the data for the given key, returning its metadata and false if it is not present.
+func (d *diskStore) read(key string, data any) (map[string]any, bool, error) {
+	path := d.path(key)
+	content, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, false, nil
+		}
+
+		return nil, false, errors.Wrap(err, "failed to read cached value")
+	}
+	record := &diskRecord{}
+	if err := json.Unmarshal(content, record); err != nil {
+		return nil, false, errors.Wrap(err, "failed to parse cached value")
+	}
+	if err := json.Unmarshal(record.Data, data); err != nil {
+		return nil, false, errors.Wrap(err, "failed to parse cached data")
+	}
+	metadata, err := decodeMetadata(record.Metadata)
+	if err != nil {
+		return nil, false, err
+	}
+
+	d.mu.Lock()
+	if file, exists := d.files[path]; exists {
+		file.used = time.Now()
+	}
+	d.mu.Unlock()
+	// Record the use on disk as well, so that it survives restarts.
+	now := time.Now()
+	_ = os.Chtimes(path, now, now)
+
+	return metadata, true, nil
+}
+
+// write writes the data and metadata for the given key.
+func (d *diskStore) write(key string, data any, metadata map[string]any) error {
+	encodedData, err := json.Marshal(data)
+	if err != nil {
+		return errors.Wrap(err, "failed to encode data")
+	}
+	encodedMetadata, err := encodeMetadata(metadata)
+	if err != nil {
+		return err
+	}
+	content, err := json.Marshal(&diskRecord{
+		Data:     encodedData,
+		Metadata: encodedMetadata,
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to encode value")
+	}
+	if int64(len(content)) > d.maxSize {
+		// Too large to persist.
+		return nil
+	}
+
+	// Write to a temporary file and rename, so that readers never see partial data.
+	tmp, err := os.CreateTemp(d.dir, "tmp-*")
+	if err != nil {
+		return errors.Wrap(err, "failed to create temporary file")
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(content); err != nil {
+		tmp.Close()
+
+		return errors.Wrap(err, "failed to write temporary file")
+	}
+	if err := tmp.Close(); err != nil {
+		return errors.Wrap(err, "failed to close temporary file")
+	}
+	path := d.path(key)
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return errors.Wrap(err, "failed to rename temporary file")
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if file, exists := d.files[path]; exists {
+		d.size -= file.size
+	}
+	d.files[path] = &diskFile{
+		size: int64(len(content)),
+		used: time.Now(),
+	}
+	d.size += int64(len(content))
+	d.prune()
+
+	return nil
+}
+
+// prune removes the least recently used files until the store is within its maximum size.
+// It must be called with the lock held.
+func (d *diskStore) prune() {
+	if d.size <= d.maxSize {
+		return
+	}
+
+	paths := make([]string, 0, len(d.files))
+	for path := range d.files {
+		paths = append(paths, path)
+	}
+	sort.Slice(paths, func(i, j int) bool {
+		return d.files[paths[i]].used.Before(d.files[paths[j]].used)
+	})
+	for _, path := range paths {
+		if d.size <= d.maxSize {
+			break
+		}
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			continue
+		}
+		d.size -= d.files[path].size
+		delete(d.files, path)
+	}
+}
+
+// encodeMetadata encodes metadata values along with their types.
+// Values of types that are not known are stored as generic JSON, and are restored as such.
+func encodeMetadata(metadata map[string]any) (map[string]*diskMetadataValue, error) {
+	if len(metadata) == 0 {
+		return nil, nil
+	}
+
+	res := make(map[string]*diskMetadataValue, len(metadata))
+	for k, v := range metadata {
+		valueType := "json"
+		switch v.(type) {
+		case phase0.Root:
+			valueType = "root"
+		case phase0.Slot:
+			valueType = "slot"
+		case phase0.Epoch:
+			valueType = "epoch"
+		}
+		value, err := json.Marshal(v)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to encode metadata %s", k)
+		}
+		res[k] = &diskMetadataValue{
+			Type:  valueType,
+			Value: value,
+		}
+	}
+
+	return res, nil
+}
+
+// decodeMetadata decodes metadata values to the types with which they were stored.
+func decodeMetadata(metadata map[string]*diskMetadataValue) (map[string]any, error) {
+	res := make(map[string]any, len(metadata))
+	for k, v := range metadata {
+		var err error
+		switch v.Type {
+		case "root":
+			var value phase0.Root
+			err = json.Unmarshal(v.Value, &value)
+			res[k] = value
+		case "slot":
+			var value phase0.Slot
+			err = json.Unmarshal(v.Value, &value)
+			res[k] = value
+		case "epoch":
+			var value phase0.Epoch
+			err = json.Unmarshal(v.Value, &value)
+			res[k] = value
+		default:
+			var value any
+			err = json.Unmarshal(v.Value, &value)
+			res[k] = value
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to decode metadata %s", k)
+		}
+	}
+
+	return res, nil
+}
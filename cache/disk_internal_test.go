@@ -0,0 +1,51 @@
+// Copyright © 2023 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cache
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiskStorePrune(t *testing.T) {
+	dir := t.TempDir()
+
+	// Each value is 16 bytes on disk, so this holds two values but not three.
+	d, err := newDiskStore(dir, 40)
+	require.NoError(t, err)
+
+	var data string
+	require.NoError(t, d.write("a", "value", nil))
+	require.NoError(t, d.write("b", "value", nil))
+	_, found, err := d.read("a", &data)
+	require.NoError(t, err)
+	require.True(t, found)
+	require.NoError(t, d.write("c", "value", nil))
+
+	// b was the least recently used so should have been removed.
+	_, found, err = d.read("b", &data)
+	require.NoError(t, err)
+	require.False(t, found)
+	for _, key := range []string{"a", "c"} {
+		_, found, err = d.read(key, &data)
+		require.NoError(t, err)
+		require.True(t, found)
+	}
+
+	// A new store should pick up the existing files.
+	d, err = newDiskStore(dir, 40)
+	require.NoError(t, err)
+	require.Len(t, d.files, 2)
+}
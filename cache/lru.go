@@ -0,0 +1,92 @@
+// Copyright © 2023 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cache
+
+import (
+	"container/list"
+	"sync"
+)
+
+// lru is a size-bounded least recently used cache.
+// It is bounded both by the number of entries and by their total estimated size.
+type lru struct {
+	mu         sync.Mutex
+	maxEntries int
+	maxSize    int
+	size       int
+	order      *list.List
+	items      map[string]*list.Element
+}
+
+// lruEntry is an entry in the cache.
+type lruEntry struct {
+	key   string
+	value any
+	size  int
+}
+
+// newLRU creates a new cache holding at most the given number of entries and estimated bytes.
+func newLRU(maxEntries int, maxSize int) *lru {
+	return &lru{
+		maxEntries: maxEntries,
+		maxSize:    maxSize,
+		order:      list.New(),
+		items:      make(map[string]*list.Element),
+	}
+}
+
+// get returns the value for the given key, if present.
+func (l *lru) get(key string) (any, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	element, exists := l.items[key]
+	if !exists {
+		return nil, false
+	}
+	l.order.MoveToFront(element)
+
+	return element.Value.(*lruEntry).value, true
+}
+
+// add adds the value for the given key, evicting the least recently used entries if required.
+// Values larger than the maximum size are not held.
+func (l *lru) add(key string, value any) {
+	size := estimateSize(value)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if element, exists := l.items[key]; exists {
+		l.remove(element)
+	}
+	if size > l.maxSize {
+		return
+	}
+
+	l.items[key] = l.order.PushFront(&lruEntry{key: key, value: value, size: size})
+	l.size += size
+	for l.order.Len() > l.maxEntries || l.size > l.maxSize {
+		l.remove(l.order.Back())
+	}
+}
+
+// remove removes an element from the cache.
+// It must be called with the lock held.
+func (l *lru) remove(element *list.Element) {
+	entry := element.Value.(*lruEntry)
+	l.order.Remove(element)
+	delete(l.items, entry.key)
+	l.size -= entry.size
+}
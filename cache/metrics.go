@@ -0,0 +1,62 @@
+// Copyright © 2023 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cache
+
+import (
+	"context"
+
+	"github.com/attestantio/go-eth2-client/metrics"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var requestsMetric *prometheus.CounterVec
+
+func registerMetrics(ctx context.Context, monitor metrics.Service) error {
+	if requestsMetric != nil {
+		// Already registered.
+		return nil
+	}
+	if monitor == nil {
+		// No monitor.
+		return nil
+	}
+	if monitor.Presenter() == "prometheus" {
+		return registerPrometheusMetrics(ctx)
+	}
+
+	return nil
+}
+
+func registerPrometheusMetrics(_ context.Context) error {
+	requestsMetric = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "consensusclient",
+		Subsystem: "cache",
+		Name:      "requests_total",
+		Help:      "Number of requests to the cache",
+	}, []string{"call", "result"})
+	if err := prometheus.Register(requestsMetric); err != nil {
+		return errors.Wrap(err, "failed to register requests_total")
+	}
+
+	return nil
+}
+
+// monitorRequest records the result of a request to the cache.
+// Result is one of "hit", "miss" or "uncacheable".
+func monitorRequest(call string, result string) {
+	if requestsMetric != nil {
+		requestsMetric.WithLabelValues(call, result).Inc()
+	}
+}
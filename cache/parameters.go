@@ -0,0 +1,126 @@
+// Copyright © 2023 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cache
+
+import (
+	consensusclient "github.com/attestantio/go-eth2-client"
+	"github.com/attestantio/go-eth2-client/metrics"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+)
+
+type parameters struct {
+	logLevel         zerolog.Level
+	monitor          metrics.Service
+	client           consensusclient.Service
+	maxEntries       int
+	maxSize          int
+	persistenceDir   string
+	maxPersistedSize int64
+}
+
+// Parameter is the interface for service parameters.
+type Parameter interface {
+	apply(p *parameters)
+}
+
+type parameterFunc func(*parameters)
+
+func (f parameterFunc) apply(p *parameters) {
+	f(p)
+}
+
+// WithLogLevel sets the log level for the module.
+func WithLogLevel(logLevel zerolog.Level) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.logLevel = logLevel
+	})
+}
+
+// WithMonitor sets the monitor for the module.
+func WithMonitor(monitor metrics.Service) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.monitor = monitor
+	})
+}
+
+// WithClient sets the client whose results are cached.
+func WithClient(client consensusclient.Service) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.client = client
+	})
+}
+
+// WithMaxEntries sets the maximum number of results held in memory.
+// When this is exceeded the least recently used result is evicted.
+func WithMaxEntries(maxEntries int) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.maxEntries = maxEntries
+	})
+}
+
+// WithMaxSize sets the maximum estimated size in bytes of results held in memory.
+// When this is exceeded the least recently used results are evicted.  Results larger
+// than this, such as beacon states on a large chain, are not held in memory.
+func WithMaxSize(maxSize int) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.maxSize = maxSize
+	})
+}
+
+// WithPersistenceDir sets a directory in which results are persisted, allowing them
+// to survive eviction from memory and restarts.
+func WithPersistenceDir(dir string) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.persistenceDir = dir
+	})
+}
+
+// WithMaxPersistedSize sets the maximum size in bytes of results persisted to disk.
+// When this is exceeded the least recently used results are removed.
+func WithMaxPersistedSize(maxPersistedSize int64) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.maxPersistedSize = maxPersistedSize
+	})
+}
+
+// parseAndCheckParameters parses and checks parameters to ensure that mandatory parameters are present and correct.
+func parseAndCheckParameters(params ...Parameter) (*parameters, error) {
+	parameters := parameters{
+		logLevel:         zerolog.GlobalLevel(),
+		maxEntries:       512,
+		maxSize:          256 * 1024 * 1024,
+		maxPersistedSize: 4 * 1024 * 1024 * 1024,
+	}
+	for _, p := range params {
+		if params != nil {
+			p.apply(&parameters)
+		}
+	}
+
+	if parameters.client == nil {
+		return nil, errors.New("no client specified")
+	}
+	if parameters.maxEntries < 1 {
+		return nil, errors.New("maximum entries must be at least 1")
+	}
+	if parameters.maxSize < 1 {
+		return nil, errors.New("maximum size must be at least 1")
+	}
+	if parameters.maxPersistedSize < 1 {
+		return nil, errors.New("maximum persisted size must be at least 1")
+	}
+
+	return &parameters, nil
+}
@@ -0,0 +1,594 @@
+// Copyright © 2023 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cache
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	consensusclient "github.com/attestantio/go-eth2-client"
+	"github.com/attestantio/go-eth2-client/api"
+	apiv1 "github.com/attestantio/go-eth2-client/api/v1"
+	"github.com/attestantio/go-eth2-client/spec"
+	"github.com/attestantio/go-eth2-client/spec/altair"
+	"github.com/attestantio/go-eth2-client/spec/capella"
+	"github.com/attestantio/go-eth2-client/spec/deneb"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+)
+
+// The calls in this file return data that can change, or that is already cached
+// by the underlying service, so are passed straight through.
+
+// EpochFromStateID converts a state ID to its epoch.
+func (s *Service) EpochFromStateID(ctx context.Context, stateID string) (phase0.Epoch, error) {
+	next, isNext := s.next.(consensusclient.EpochFromStateIDProvider)
+	if !isNext {
+		return 0, fmt.Errorf("%s@%s does not support this call", s.next.Name(), s.next.Address())
+	}
+
+	return next.EpochFromStateID(ctx, stateID)
+}
+
+// SlotFromStateID converts a state ID to its slot.
+func (s *Service) SlotFromStateID(ctx context.Context, stateID string) (phase0.Slot, error) {
+	next, isNext := s.next.(consensusclient.SlotFromStateIDProvider)
+	if !isNext {
+		return 0, fmt.Errorf("%s@%s does not support this call", s.next.Name(), s.next.Address())
+	}
+
+	return next.SlotFromStateID(ctx, stateID)
+}
+
+// NodeVersion returns a free-text string with the node version.
+func (s *Service) NodeVersion(ctx context.Context) (*api.Response[string], error) {
+	next, isNext := s.next.(consensusclient.NodeVersionProvider)
+	if !isNext {
+		return nil, fmt.Errorf("%s@%s does not support this call", s.next.Name(), s.next.Address())
+	}
+
+	return next.NodeVersion(ctx)
+}
+
+// SlotDuration provides the duration of a slot of the chain.
+func (s *Service) SlotDuration(ctx context.Context) (time.Duration, error) {
+	next, isNext := s.next.(consensusclient.SlotDurationProvider)
+	if !isNext {
+		return 0, fmt.Errorf("%s@%s does not support this call", s.next.Name(), s.next.Address())
+	}
+
+	return next.SlotDuration(ctx)
+}
+
+// SlotsPerEpoch provides the slots per epoch of the chain.
+func (s *Service) SlotsPerEpoch(ctx context.Context) (uint64, error) {
+	next, isNext := s.next.(consensusclient.SlotsPerEpochProvider)
+	if !isNext {
+		return 0, fmt.Errorf("%s@%s does not support this call", s.next.Name(), s.next.Address())
+	}
+
+	return next.SlotsPerEpoch(ctx)
+}
+
+// FarFutureEpoch provides the far future epoch of the chain.
+func (s *Service) FarFutureEpoch(ctx context.Context) (phase0.Epoch, error) {
+	next, isNext := s.next.(consensusclient.FarFutureEpochProvider)
+	if !isNext {
+		return 0, fmt.Errorf("%s@%s does not support this call", s.next.Name(), s.next.Address())
+	}
+
+	return next.FarFutureEpoch(ctx)
+}
+
+// GenesisValidatorsRoot provides the genesis validators root of the chain.
+func (s *Service) GenesisValidatorsRoot(ctx context.Context) ([]byte, error) {
+	next, isNext := s.next.(consensusclient.GenesisValidatorsRootProvider)
+	if !isNext {
+		return nil, fmt.Errorf("%s@%s does not support this call", s.next.Name(), s.next.Address())
+	}
+
+	return next.GenesisValidatorsRoot(ctx)
+}
+
+// TargetAggregatorsPerCommittee provides the target number of aggregators for each attestation committee.
+func (s *Service) TargetAggregatorsPerCommittee(ctx context.Context) (uint64, error) {
+	next, isNext := s.next.(consensusclient.TargetAggregatorsPerCommitteeProvider)
+	if !isNext {
+		return 0, fmt.Errorf("%s@%s does not support this call", s.next.Name(), s.next.Address())
+	}
+
+	return next.TargetAggregatorsPerCommittee(ctx)
+}
+
+// DepositContract provides details of the execution deposit contract for the chain.
+func (s *Service) DepositContract(ctx context.Context) (*api.Response[*apiv1.DepositContract], error) {
+	next, isNext := s.next.(consensusclient.DepositContractProvider)
+	if !isNext {
+		return nil, fmt.Errorf("%s@%s does not support this call", s.next.Name(), s.next.Address())
+	}
+
+	return next.DepositContract(ctx)
+}
+
+// WriteSignedBeaconBlockSSZ writes the SSZ-encoded signed beacon block given a block ID to the supplied writer.
+// The block is not decoded, so this is suitable for persisting blocks without holding them in memory.
+func (s *Service) WriteSignedBeaconBlockSSZ(ctx context.Context, opts *api.SignedBeaconBlockOpts, w io.Writer) (*api.Response[*api.SSZWriteResult], error) {
+	next, isNext := s.next.(consensusclient.SignedBeaconBlockSSZWriter)
+	if !isNext {
+		return nil, fmt.Errorf("%s@%s does not support this call", s.next.Name(), s.next.Address())
+	}
+
+	return next.WriteSignedBeaconBlockSSZ(ctx, opts, w)
+}
+
+// BlobSidecars fetches the blobs given a block ID.
+func (s *Service) BlobSidecars(ctx context.Context, opts *api.BlobSidecarsOpts) (*api.Response[[]*deneb.BlobSidecar], error) {
+	next, isNext := s.next.(consensusclient.BlobSidecarsProvider)
+	if !isNext {
+		return nil, fmt.Errorf("%s@%s does not support this call", s.next.Name(), s.next.Address())
+	}
+
+	return next.BlobSidecars(ctx, opts)
+}
+
+// AggregateAttestation fetches the aggregate attestation for the given options.
+func (s *Service) AggregateAttestation(ctx context.Context, opts *api.AggregateAttestationOpts) (*api.Response[*phase0.Attestation], error) {
+	next, isNext := s.next.(consensusclient.AggregateAttestationProvider)
+	if !isNext {
+		return nil, fmt.Errorf("%s@%s does not support this call", s.next.Name(), s.next.Address())
+	}
+
+	return next.AggregateAttestation(ctx, opts)
+}
+
+// SubmitAggregateAttestations submits aggregate attestations.
+func (s *Service) SubmitAggregateAttestations(ctx context.Context, aggregateAndProofs []*phase0.SignedAggregateAndProof) error {
+	next, isNext := s.next.(consensusclient.AggregateAttestationsSubmitter)
+	if !isNext {
+		return fmt.Errorf("%s@%s does not support this call", s.next.Name(), s.next.Address())
+	}
+
+	return next.SubmitAggregateAttestations(ctx, aggregateAndProofs)
+}
+
+// AttestationData fetches the attestation data for the given options.
+func (s *Service) AttestationData(ctx context.Context, opts *api.AttestationDataOpts) (*api.Response[*phase0.AttestationData], error) {
+	next, isNext := s.next.(consensusclient.AttestationDataProvider)
+	if !isNext {
+		return nil, fmt.Errorf("%s@%s does not support this call", s.next.Name(), s.next.Address())
+	}
+
+	return next.AttestationData(ctx, opts)
+}
+
+// AttestationPool fetches the attestation pool for the given options.
+func (s *Service) AttestationPool(ctx context.Context, opts *api.AttestationPoolOpts) (*api.Response[[]*phase0.Attestation], error) {
+	next, isNext := s.next.(consensusclient.AttestationPoolProvider)
+	if !isNext {
+		return nil, fmt.Errorf("%s@%s does not support this call", s.next.Name(), s.next.Address())
+	}
+
+	return next.AttestationPool(ctx, opts)
+}
+
+// SubmitAttestations submits attestations.
+func (s *Service) SubmitAttestations(ctx context.Context, attestations []*phase0.Attestation) error {
+	next, isNext := s.next.(consensusclient.AttestationsSubmitter)
+	if !isNext {
+		return fmt.Errorf("%s@%s does not support this call", s.next.Name(), s.next.Address())
+	}
+
+	return next.SubmitAttestations(ctx, attestations)
+}
+
+// SubmitAttesterSlashing submits an attester slashing
+func (s *Service) SubmitAttesterSlashing(ctx context.Context, slashing *phase0.AttesterSlashing) error {
+	next, isNext := s.next.(consensusclient.AttesterSlashingSubmitter)
+	if !isNext {
+		return fmt.Errorf("%s@%s does not support this call", s.next.Name(), s.next.Address())
+	}
+
+	return next.SubmitAttesterSlashing(ctx, slashing)
+}
+
+// AttesterDuties obtains attester duties.
+func (s *Service) AttesterDuties(ctx context.Context, opts *api.AttesterDutiesOpts) (*api.Response[[]*apiv1.AttesterDuty], error) {
+	next, isNext := s.next.(consensusclient.AttesterDutiesProvider)
+	if !isNext {
+		return nil, fmt.Errorf("%s@%s does not support this call", s.next.Name(), s.next.Address())
+	}
+
+	return next.AttesterDuties(ctx, opts)
+}
+
+// SyncCommitteeDuties obtains sync committee duties.
+// If validatorIndicess is nil it will return all duties for the given epoch.
+func (s *Service) SyncCommitteeDuties(ctx context.Context, opts *api.SyncCommitteeDutiesOpts) (*api.Response[[]*apiv1.SyncCommitteeDuty], error) {
+	next, isNext := s.next.(consensusclient.SyncCommitteeDutiesProvider)
+	if !isNext {
+		return nil, fmt.Errorf("%s@%s does not support this call", s.next.Name(), s.next.Address())
+	}
+
+	return next.SyncCommitteeDuties(ctx, opts)
+}
+
+// SubmitSyncCommitteeMessages submits sync committee messages.
+func (s *Service) SubmitSyncCommitteeMessages(ctx context.Context, messages []*altair.SyncCommitteeMessage) error {
+	next, isNext := s.next.(consensusclient.SyncCommitteeMessagesSubmitter)
+	if !isNext {
+		return fmt.Errorf("%s@%s does not support this call", s.next.Name(), s.next.Address())
+	}
+
+	return next.SubmitSyncCommitteeMessages(ctx, messages)
+}
+
+// SubmitSyncCommitteeSubscriptions subscribes to sync committees.
+func (s *Service) SubmitSyncCommitteeSubscriptions(ctx context.Context, subscriptions []*apiv1.SyncCommitteeSubscription) error {
+	next, isNext := s.next.(consensusclient.SyncCommitteeSubscriptionsSubmitter)
+	if !isNext {
+		return fmt.Errorf("%s@%s does not support this call", s.next.Name(), s.next.Address())
+	}
+
+	return next.SubmitSyncCommitteeSubscriptions(ctx, subscriptions)
+}
+
+// SyncCommitteeContribution provides a sync committee contribution.
+func (s *Service) SyncCommitteeContribution(ctx context.Context, opts *api.SyncCommitteeContributionOpts) (*api.Response[*altair.SyncCommitteeContribution], error) {
+	next, isNext := s.next.(consensusclient.SyncCommitteeContributionProvider)
+	if !isNext {
+		return nil, fmt.Errorf("%s@%s does not support this call", s.next.Name(), s.next.Address())
+	}
+
+	return next.SyncCommitteeContribution(ctx, opts)
+}
+
+// SubmitSyncCommitteeContributions submits sync committee contributions.
+func (s *Service) SubmitSyncCommitteeContributions(ctx context.Context, contributionAndProofs []*altair.SignedContributionAndProof) error {
+	next, isNext := s.next.(consensusclient.SyncCommitteeContributionsSubmitter)
+	if !isNext {
+		return fmt.Errorf("%s@%s does not support this call", s.next.Name(), s.next.Address())
+	}
+
+	return next.SubmitSyncCommitteeContributions(ctx, contributionAndProofs)
+}
+
+// SubmitBLSToExecutionChanges submits BLS to execution address change operations.
+func (s *Service) SubmitBLSToExecutionChanges(ctx context.Context, blsToExecutionChanges []*capella.SignedBLSToExecutionChange) error {
+	next, isNext := s.next.(consensusclient.BLSToExecutionChangesSubmitter)
+	if !isNext {
+		return fmt.Errorf("%s@%s does not support this call", s.next.Name(), s.next.Address())
+	}
+
+	return next.SubmitBLSToExecutionChanges(ctx, blsToExecutionChanges)
+}
+
+// Proposal fetches a proposal for signing.
+func (s *Service) Proposal(ctx context.Context, opts *api.ProposalOpts) (*api.Response[*api.VersionedProposal], error) {
+	next, isNext := s.next.(consensusclient.ProposalProvider)
+	if !isNext {
+		return nil, fmt.Errorf("%s@%s does not support this call", s.next.Name(), s.next.Address())
+	}
+
+	return next.Proposal(ctx, opts)
+}
+
+// SubmitProposalSlashing submits a proposal slashing.
+func (s *Service) SubmitProposalSlashing(ctx context.Context, slashing *phase0.ProposerSlashing) error {
+	next, isNext := s.next.(consensusclient.ProposalSlashingSubmitter)
+	if !isNext {
+		return fmt.Errorf("%s@%s does not support this call", s.next.Name(), s.next.Address())
+	}
+
+	return next.SubmitProposalSlashing(ctx, slashing)
+}
+
+// SubmitBeaconBlock submits a beacon block.
+//
+// Deprecated: this will not work from the deneb hard-fork onwards.  Use ProposalSubmitter.SubmitProposal() instead.
+func (s *Service) SubmitBeaconBlock(ctx context.Context, block *spec.VersionedSignedBeaconBlock) error {
+	next, isNext := s.next.(consensusclient.BeaconBlockSubmitter)
+	if !isNext {
+		return fmt.Errorf("%s@%s does not support this call", s.next.Name(), s.next.Address())
+	}
+
+	return next.SubmitBeaconBlock(ctx, block)
+}
+
+// SubmitProposal submits a proposal.
+func (s *Service) SubmitProposal(ctx context.Context, block *api.VersionedSignedProposal) error {
+	next, isNext := s.next.(consensusclient.ProposalSubmitter)
+	if !isNext {
+		return fmt.Errorf("%s@%s does not support this call", s.next.Name(), s.next.Address())
+	}
+
+	return next.SubmitProposal(ctx, block)
+}
+
+// SubmitBeaconCommitteeSubscriptions subscribes to beacon committees.
+func (s *Service) SubmitBeaconCommitteeSubscriptions(ctx context.Context, subscriptions []*apiv1.BeaconCommitteeSubscription) error {
+	next, isNext := s.next.(consensusclient.BeaconCommitteeSubscriptionsSubmitter)
+	if !isNext {
+		return fmt.Errorf("%s@%s does not support this call", s.next.Name(), s.next.Address())
+	}
+
+	return next.SubmitBeaconCommitteeSubscriptions(ctx, subscriptions)
+}
+
+// WriteBeaconStateSSZ writes the SSZ-encoded beacon state given a state ID to the supplied writer.
+// The state is not decoded, so this is suitable for persisting states without holding them in memory.
+func (s *Service) WriteBeaconStateSSZ(ctx context.Context, opts *api.BeaconStateOpts, w io.Writer) (*api.Response[*api.SSZWriteResult], error) {
+	next, isNext := s.next.(consensusclient.BeaconStateSSZWriter)
+	if !isNext {
+		return nil, fmt.Errorf("%s@%s does not support this call", s.next.Name(), s.next.Address())
+	}
+
+	return next.WriteBeaconStateSSZ(ctx, opts, w)
+}
+
+// BeaconStateRandao fetches a beacon state RANDAO given a state ID.
+func (s *Service) BeaconStateRandao(ctx context.Context, opts *api.BeaconStateRandaoOpts) (*api.Response[*phase0.Root], error) {
+	next, isNext := s.next.(consensusclient.BeaconStateRandaoProvider)
+	if !isNext {
+		return nil, fmt.Errorf("%s@%s does not support this call", s.next.Name(), s.next.Address())
+	}
+
+	return next.BeaconStateRandao(ctx, opts)
+}
+
+// BeaconStateRoot fetches a beacon state root given a state ID.
+func (s *Service) BeaconStateRoot(ctx context.Context, opts *api.BeaconStateRootOpts) (*api.Response[*phase0.Root], error) {
+	next, isNext := s.next.(consensusclient.BeaconStateRootProvider)
+	if !isNext {
+		return nil, fmt.Errorf("%s@%s does not support this call", s.next.Name(), s.next.Address())
+	}
+
+	return next.BeaconStateRoot(ctx, opts)
+}
+
+// BlindedProposal fetches a blinded proposed beacon block for signing.
+func (s *Service) BlindedProposal(ctx context.Context, opts *api.BlindedProposalOpts) (*api.Response[*api.VersionedBlindedProposal], error) {
+	next, isNext := s.next.(consensusclient.BlindedProposalProvider)
+	if !isNext {
+		return nil, fmt.Errorf("%s@%s does not support this call", s.next.Name(), s.next.Address())
+	}
+
+	return next.BlindedProposal(ctx, opts)
+}
+
+// SubmitBlindedBeaconBlock submits a beacon block.
+//
+// Deprecated: this will not work from the deneb hard-fork onwards.  Use BlindedProposalSubmitter.SubmitBlindedProposal() instead.
+func (s *Service) SubmitBlindedBeaconBlock(ctx context.Context, block *api.VersionedSignedBlindedBeaconBlock) error {
+	next, isNext := s.next.(consensusclient.BlindedBeaconBlockSubmitter)
+	if !isNext {
+		return fmt.Errorf("%s@%s does not support this call", s.next.Name(), s.next.Address())
+	}
+
+	return next.SubmitBlindedBeaconBlock(ctx, block)
+}
+
+// SubmitBlindedProposal submits a beacon block.
+func (s *Service) SubmitBlindedProposal(ctx context.Context, block *api.VersionedSignedBlindedProposal) error {
+	next, isNext := s.next.(consensusclient.BlindedProposalSubmitter)
+	if !isNext {
+		return fmt.Errorf("%s@%s does not support this call", s.next.Name(), s.next.Address())
+	}
+
+	return next.SubmitBlindedProposal(ctx, block)
+}
+
+// SubmitValidatorRegistrations submits a validator registration.
+func (s *Service) SubmitValidatorRegistrations(ctx context.Context, registrations []*api.VersionedSignedValidatorRegistration) error {
+	next, isNext := s.next.(consensusclient.ValidatorRegistrationsSubmitter)
+	if !isNext {
+		return fmt.Errorf("%s@%s does not support this call", s.next.Name(), s.next.Address())
+	}
+
+	return next.SubmitValidatorRegistrations(ctx, registrations)
+}
+
+// Events feeds requested events with the given topics to the supplied handler.
+func (s *Service) Events(ctx context.Context, topics []string, handler consensusclient.EventHandlerFunc) error {
+	next, isNext := s.next.(consensusclient.EventsProvider)
+	if !isNext {
+		return fmt.Errorf("%s@%s does not support this call", s.next.Name(), s.next.Address())
+	}
+
+	return next.Events(ctx, topics, handler)
+}
+
+// Finality provides the finality given a state ID.
+func (s *Service) Finality(ctx context.Context, opts *api.FinalityOpts) (*api.Response[*apiv1.Finality], error) {
+	next, isNext := s.next.(consensusclient.FinalityProvider)
+	if !isNext {
+		return nil, fmt.Errorf("%s@%s does not support this call", s.next.Name(), s.next.Address())
+	}
+
+	return next.Finality(ctx, opts)
+}
+
+// Fork fetches all current fork choice context.
+func (s *Service) ForkChoice(ctx context.Context) (*api.Response[*apiv1.ForkChoice], error) {
+	next, isNext := s.next.(consensusclient.ForkChoiceProvider)
+	if !isNext {
+		return nil, fmt.Errorf("%s@%s does not support this call", s.next.Name(), s.next.Address())
+	}
+
+	return next.ForkChoice(ctx)
+}
+
+// Fork fetches fork information for the given state.
+func (s *Service) Fork(ctx context.Context, opts *api.ForkOpts) (*api.Response[*phase0.Fork], error) {
+	next, isNext := s.next.(consensusclient.ForkProvider)
+	if !isNext {
+		return nil, fmt.Errorf("%s@%s does not support this call", s.next.Name(), s.next.Address())
+	}
+
+	return next.Fork(ctx, opts)
+}
+
+// ForkSchedule provides details of past and future changes in the chain's fork version.
+func (s *Service) ForkSchedule(ctx context.Context) (*api.Response[[]*phase0.Fork], error) {
+	next, isNext := s.next.(consensusclient.ForkScheduleProvider)
+	if !isNext {
+		return nil, fmt.Errorf("%s@%s does not support this call", s.next.Name(), s.next.Address())
+	}
+
+	return next.ForkSchedule(ctx)
+}
+
+// Genesis fetches genesis information for the chain.
+func (s *Service) Genesis(ctx context.Context) (*api.Response[*apiv1.Genesis], error) {
+	next, isNext := s.next.(consensusclient.GenesisProvider)
+	if !isNext {
+		return nil, fmt.Errorf("%s@%s does not support this call", s.next.Name(), s.next.Address())
+	}
+
+	return next.Genesis(ctx)
+}
+
+// NodeSyncing provides the state of the node's synchronization with the chain.
+func (s *Service) NodeSyncing(ctx context.Context) (*api.Response[*apiv1.SyncState], error) {
+	next, isNext := s.next.(consensusclient.NodeSyncingProvider)
+	if !isNext {
+		return nil, fmt.Errorf("%s@%s does not support this call", s.next.Name(), s.next.Address())
+	}
+
+	return next.NodeSyncing(ctx)
+}
+
+// NodePeers provides the peers of the node.
+func (s *Service) NodePeers(ctx context.Context, opts *api.PeerOpts) (*api.Response[[]*apiv1.Peer], error) {
+	next, isNext := s.next.(consensusclient.NodePeersProvider)
+	if !isNext {
+		return nil, fmt.Errorf("%s@%s does not support this call", s.next.Name(), s.next.Address())
+	}
+
+	return next.NodePeers(ctx, opts)
+}
+
+// SubmitProposalPreparations provides the beacon node with information required if a proposal for the given validators
+// shows up in the next epoch.
+func (s *Service) SubmitProposalPreparations(ctx context.Context, preparations []*apiv1.ProposalPreparation) error {
+	next, isNext := s.next.(consensusclient.ProposalPreparationsSubmitter)
+	if !isNext {
+		return fmt.Errorf("%s@%s does not support this call", s.next.Name(), s.next.Address())
+	}
+
+	return next.SubmitProposalPreparations(ctx, preparations)
+}
+
+// ProposerDuties obtains proposer duties for the given options.
+func (s *Service) ProposerDuties(ctx context.Context, opts *api.ProposerDutiesOpts) (*api.Response[[]*apiv1.ProposerDuty], error) {
+	next, isNext := s.next.(consensusclient.ProposerDutiesProvider)
+	if !isNext {
+		return nil, fmt.Errorf("%s@%s does not support this call", s.next.Name(), s.next.Address())
+	}
+
+	return next.ProposerDuties(ctx, opts)
+}
+
+// Spec provides the spec information of the chain.
+func (s *Service) Spec(ctx context.Context) (*api.Response[map[string]any], error) {
+	next, isNext := s.next.(consensusclient.SpecProvider)
+	if !isNext {
+		return nil, fmt.Errorf("%s@%s does not support this call", s.next.Name(), s.next.Address())
+	}
+
+	return next.Spec(ctx)
+}
+
+// SyncState provides the state of the node's synchronization with the chain.
+func (s *Service) SyncState(ctx context.Context) (*apiv1.SyncState, error) {
+	next, isNext := s.next.(consensusclient.SyncStateProvider)
+	if !isNext {
+		return nil, fmt.Errorf("%s@%s does not support this call", s.next.Name(), s.next.Address())
+	}
+
+	return next.SyncState(ctx)
+}
+
+// SubmitVoluntaryExit submits a voluntary exit.
+func (s *Service) SubmitVoluntaryExit(ctx context.Context, voluntaryExit *phase0.SignedVoluntaryExit) error {
+	next, isNext := s.next.(consensusclient.VoluntaryExitSubmitter)
+	if !isNext {
+		return fmt.Errorf("%s@%s does not support this call", s.next.Name(), s.next.Address())
+	}
+
+	return next.SubmitVoluntaryExit(ctx, voluntaryExit)
+}
+
+// VoluntaryExitPool fetches the voluntary exit pool.
+func (s *Service) VoluntaryExitPool(ctx context.Context) ([]*phase0.SignedVoluntaryExit, error) {
+	next, isNext := s.next.(consensusclient.VoluntaryExitPoolProvider)
+	if !isNext {
+		return nil, fmt.Errorf("%s@%s does not support this call", s.next.Name(), s.next.Address())
+	}
+
+	return next.VoluntaryExitPool(ctx)
+}
+
+// Domain provides a domain for a given domain type at a given epoch.
+func (s *Service) Domain(ctx context.Context, domainType phase0.DomainType, epoch phase0.Epoch) (phase0.Domain, error) {
+	next, isNext := s.next.(consensusclient.DomainProvider)
+	if !isNext {
+		return phase0.Domain{}, fmt.Errorf("%s@%s does not support this call", s.next.Name(), s.next.Address())
+	}
+
+	return next.Domain(ctx, domainType, epoch)
+}
+
+// GenesisDomain returns the domain for the given domain type at genesis.
+// N.B. this is not always the same as the domain at epoch 0.  It is possible
+// for a chain's fork schedule to have multiple forks at genesis.  In this situation,
+// GenesisDomain() will return the first, and Domain() will return the last.
+func (s *Service) GenesisDomain(ctx context.Context, domainType phase0.DomainType) (phase0.Domain, error) {
+	next, isNext := s.next.(consensusclient.DomainProvider)
+	if !isNext {
+		return phase0.Domain{}, fmt.Errorf("%s@%s does not support this call", s.next.Name(), s.next.Address())
+	}
+
+	return next.GenesisDomain(ctx, domainType)
+}
+
+// GenesisTime provides the genesis time of the chain.
+func (s *Service) GenesisTime(ctx context.Context) (time.Time, error) {
+	next, isNext := s.next.(consensusclient.GenesisTimeProvider)
+	if !isNext {
+		return time.Time{}, fmt.Errorf("%s@%s does not support this call", s.next.Name(), s.next.Address())
+	}
+
+	return next.GenesisTime(ctx)
+}
+
+// Capabilities provides the capabilities of the node.
+func (s *Service) Capabilities(ctx context.Context) (*api.Response[*api.Capabilities], error) {
+	next, isNext := s.next.(consensusclient.CapabilitiesProvider)
+	if !isNext {
+		return nil, fmt.Errorf("%s@%s does not support this call", s.next.Name(), s.next.Address())
+	}
+
+	return next.Capabilities(ctx)
+}
+
+// NodeClient provides the client for the node.
+func (s *Service) NodeClient(ctx context.Context) (*api.Response[string], error) {
+	next, isNext := s.next.(consensusclient.NodeClientProvider)
+	if !isNext {
+		return nil, fmt.Errorf("%s@%s does not support this call", s.next.Name(), s.next.Address())
+	}
+
+	return next.NodeClient(ctx)
+}
@@ -0,0 +1,89 @@
+// Copyright © 2023 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cache
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	consensusclient "github.com/attestantio/go-eth2-client"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+	zerologger "github.com/rs/zerolog/log"
+)
+
+// finalityRefreshInterval is the time after which the finalized slot is refetched.
+// Finality only moves forward, so a stale value is safe but results in fewer cache hits.
+const finalityRefreshInterval = time.Minute
+
+// Service is an Ethereum 2 client that caches results that cannot change.
+type Service struct {
+	log     zerolog.Logger
+	next    consensusclient.Service
+	entries *lru
+	disk    *diskStore
+
+	finalizedSlot        phase0.Slot
+	finalizedSlotFetched time.Time
+	finalizedSlotMu      sync.Mutex
+}
+
+// New creates a new Ethereum 2 client that caches results from the supplied client.
+// Only results that cannot change are cached: blocks, headers, states, committees and validator
+// information that are referenced by root or by a finalized slot.  Lookups using "head",
+// "justified" or "finalized" are always passed to the client.
+// Cached results are shared between callers, so must not be modified.
+func New(ctx context.Context, params ...Parameter) (consensusclient.Service, error) {
+	parameters, err := parseAndCheckParameters(params...)
+	if err != nil {
+		return nil, errors.Wrap(err, "problem with parameters")
+	}
+
+	// Set logging.
+	log := zerologger.With().Str("service", "client").Str("impl", "cache").Logger()
+	if parameters.logLevel != log.GetLevel() {
+		log = log.Level(parameters.logLevel)
+	}
+
+	if err := registerMetrics(ctx, parameters.monitor); err != nil {
+		return nil, errors.Wrap(err, "failed to register metrics")
+	}
+
+	s := &Service{
+		log:     log,
+		next:    parameters.client,
+		entries: newLRU(parameters.maxEntries, parameters.maxSize),
+	}
+	if parameters.persistenceDir != "" {
+		s.disk, err = newDiskStore(parameters.persistenceDir, parameters.maxPersistedSize)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return s, nil
+}
+
+// Name returns the name of the client implementation.
+func (s *Service) Name() string {
+	return fmt.Sprintf("cache(%s)", s.next.Name())
+}
+
+// Address returns the address of the client.
+func (s *Service) Address() string {
+	return s.next.Address()
+}
@@ -0,0 +1,317 @@
+// Copyright © 2023 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cache_test
+
+import (
+	"context"
+	"os"
+	"sync/atomic"
+	"testing"
+
+	consensusclient "github.com/attestantio/go-eth2-client"
+	"github.com/attestantio/go-eth2-client/api"
+	apiv1 "github.com/attestantio/go-eth2-client/api/v1"
+	"github.com/attestantio/go-eth2-client/cache"
+	"github.com/attestantio/go-eth2-client/mock"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+// counting is a client that counts the number of block header requests it receives.
+type counting struct {
+	*mock.Service
+	calls atomic.Int64
+}
+
+func (c *counting) BeaconBlockHeader(_ context.Context,
+	opts *api.BeaconBlockHeaderOpts,
+) (
+	*api.Response[*apiv1.BeaconBlockHeader],
+	error,
+) {
+	c.calls.Add(1)
+
+	return &api.Response[*apiv1.BeaconBlockHeader]{
+		Data: &apiv1.BeaconBlockHeader{
+			Root:      phase0.Root{0x01},
+			Canonical: true,
+			Header: &phase0.SignedBeaconBlockHeader{
+				Message: &phase0.BeaconBlockHeader{
+					Slot:          100,
+					ProposerIndex: 1,
+				},
+			},
+		},
+		Metadata: map[string]any{
+			"block":          opts.Block,
+			"dependent_root": phase0.Root{0x02},
+		},
+	}, nil
+}
+
+func TestService(t *testing.T) {
+	ctx := context.Background()
+
+	client, err := mock.New(ctx)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		params []cache.Parameter
+		err    string
+	}{
+		{
+			name: "ClientMissing",
+			params: []cache.Parameter{
+				cache.WithLogLevel(zerolog.Disabled),
+			},
+			err: "problem with parameters: no client specified",
+		},
+		{
+			name: "MaxEntriesZero",
+			params: []cache.Parameter{
+				cache.WithLogLevel(zerolog.Disabled),
+				cache.WithClient(client),
+				cache.WithMaxEntries(0),
+			},
+			err: "problem with parameters: maximum entries must be at least 1",
+		},
+		{
+			name: "MaxSizeZero",
+			params: []cache.Parameter{
+				cache.WithLogLevel(zerolog.Disabled),
+				cache.WithClient(client),
+				cache.WithMaxSize(0),
+			},
+			err: "problem with parameters: maximum size must be at least 1",
+		},
+		{
+			name: "MaxPersistedSizeZero",
+			params: []cache.Parameter{
+				cache.WithLogLevel(zerolog.Disabled),
+				cache.WithClient(client),
+				cache.WithMaxPersistedSize(0),
+			},
+			err: "problem with parameters: maximum persisted size must be at least 1",
+		},
+		{
+			name: "Good",
+			params: []cache.Parameter{
+				cache.WithLogLevel(zerolog.Disabled),
+				cache.WithClient(client),
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := cache.New(ctx, test.params...)
+			if test.err != "" {
+				require.EqualError(t, err, test.err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestCaching(t *testing.T) {
+	ctx := context.Background()
+
+	mockClient, err := mock.New(ctx)
+	require.NoError(t, err)
+	next := &counting{Service: mockClient}
+
+	s, err := cache.New(ctx,
+		cache.WithLogLevel(zerolog.Disabled),
+		cache.WithClient(next),
+	)
+	require.NoError(t, err)
+	provider := s.(consensusclient.BeaconBlockHeadersProvider)
+
+	tests := []struct {
+		name  string
+		block api.BlockID
+		calls int64
+	}{
+		{
+			name:  "Head",
+			block: "head",
+			calls: 1,
+		},
+		{
+			name:  "HeadAgain",
+			block: "head",
+			calls: 2,
+		},
+		{
+			name:  "Finalized",
+			block: "finalized",
+			calls: 3,
+		},
+		{
+			name:  "FinalizedSlot",
+			block: "100",
+			calls: 4,
+		},
+		{
+			name:  "FinalizedSlotAgain",
+			block: "100",
+			calls: 4,
+		},
+		{
+			name:  "UnfinalizedSlot",
+			block: "1000",
+			calls: 5,
+		},
+		{
+			name:  "UnfinalizedSlotAgain",
+			block: "1000",
+			calls: 6,
+		},
+		{
+			name:  "Root",
+			block: "0x0100000000000000000000000000000000000000000000000000000000000000",
+			calls: 7,
+		},
+		{
+			name:  "RootAgain",
+			block: "0x0100000000000000000000000000000000000000000000000000000000000000",
+			calls: 7,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			response, err := provider.BeaconBlockHeader(ctx, &api.BeaconBlockHeaderOpts{Block: test.block})
+			require.NoError(t, err)
+			require.NotNil(t, response.Data)
+			require.Equal(t, test.calls, next.calls.Load())
+		})
+	}
+}
+
+func TestEviction(t *testing.T) {
+	ctx := context.Background()
+
+	mockClient, err := mock.New(ctx)
+	require.NoError(t, err)
+	next := &counting{Service: mockClient}
+
+	s, err := cache.New(ctx,
+		cache.WithLogLevel(zerolog.Disabled),
+		cache.WithClient(next),
+		cache.WithMaxEntries(2),
+	)
+	require.NoError(t, err)
+	provider := s.(consensusclient.BeaconBlockHeadersProvider)
+
+	for _, block := range []api.BlockID{"1", "2", "1", "3", "1", "2"} {
+		_, err := provider.BeaconBlockHeader(ctx, &api.BeaconBlockHeaderOpts{Block: block})
+		require.NoError(t, err)
+	}
+	// 1, 2 and 3 are fetched initially; 2 is then evicted by 3 so must be refetched.
+	require.Equal(t, int64(4), next.calls.Load())
+}
+
+func TestMaxSize(t *testing.T) {
+	ctx := context.Background()
+
+	mockClient, err := mock.New(ctx)
+	require.NoError(t, err)
+	next := &counting{Service: mockClient}
+
+	// Too small to hold any result, so every request goes to the client.
+	s, err := cache.New(ctx,
+		cache.WithLogLevel(zerolog.Disabled),
+		cache.WithClient(next),
+		cache.WithMaxSize(16),
+	)
+	require.NoError(t, err)
+	provider := s.(consensusclient.BeaconBlockHeadersProvider)
+
+	for i := 0; i < 3; i++ {
+		_, err := provider.BeaconBlockHeader(ctx, &api.BeaconBlockHeaderOpts{Block: "100"})
+		require.NoError(t, err)
+	}
+	require.Equal(t, int64(3), next.calls.Load())
+}
+
+func TestPersistence(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	mockClient, err := mock.New(ctx)
+	require.NoError(t, err)
+	next := &counting{Service: mockClient}
+
+	s1, err := cache.New(ctx,
+		cache.WithLogLevel(zerolog.Disabled),
+		cache.WithClient(next),
+		cache.WithPersistenceDir(dir),
+	)
+	require.NoError(t, err)
+	response1, err := s1.(consensusclient.BeaconBlockHeadersProvider).BeaconBlockHeader(ctx, &api.BeaconBlockHeaderOpts{Block: "100"})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), next.calls.Load())
+
+	// A new cache using the same directory should not need to call the client.
+	s2, err := cache.New(ctx,
+		cache.WithLogLevel(zerolog.Disabled),
+		cache.WithClient(next),
+		cache.WithPersistenceDir(dir),
+	)
+	require.NoError(t, err)
+	response2, err := s2.(consensusclient.BeaconBlockHeadersProvider).BeaconBlockHeader(ctx, &api.BeaconBlockHeaderOpts{Block: "100"})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), next.calls.Load())
+	require.Equal(t, response1.Data, response2.Data)
+	require.Equal(t, phase0.Root{0x02}, response2.Metadata["dependent_root"])
+}
+
+func TestMaxPersistedSize(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	mockClient, err := mock.New(ctx)
+	require.NoError(t, err)
+	next := &counting{Service: mockClient}
+
+	// Too small to persist any result, so a new cache must go to the client.
+	s1, err := cache.New(ctx,
+		cache.WithLogLevel(zerolog.Disabled),
+		cache.WithClient(next),
+		cache.WithPersistenceDir(dir),
+		cache.WithMaxPersistedSize(16),
+	)
+	require.NoError(t, err)
+	_, err = s1.(consensusclient.BeaconBlockHeadersProvider).BeaconBlockHeader(ctx, &api.BeaconBlockHeaderOpts{Block: "100"})
+	require.NoError(t, err)
+
+	s2, err := cache.New(ctx,
+		cache.WithLogLevel(zerolog.Disabled),
+		cache.WithClient(next),
+		cache.WithPersistenceDir(dir),
+		cache.WithMaxPersistedSize(16),
+	)
+	require.NoError(t, err)
+	_, err = s2.(consensusclient.BeaconBlockHeadersProvider).BeaconBlockHeader(ctx, &api.BeaconBlockHeaderOpts{Block: "100"})
+	require.NoError(t, err)
+	require.Equal(t, int64(2), next.calls.Load())
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Empty(t, entries)
+}
@@ -0,0 +1,106 @@
+// Copyright © 2023 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cache
+
+import (
+	"reflect"
+)
+
+// sszSizer is implemented by types that know their SSZ-encoded size.
+type sszSizer interface {
+	SizeSSZ() int
+}
+
+// estimateSize estimates the memory used by a value, in bytes.
+// Values that know their SSZ size use it, as it is cheap to calculate and close
+// to their size in memory; other values are walked.
+func estimateSize(value any) int {
+	return estimateValueSize(reflect.ValueOf(value))
+}
+
+func estimateValueSize(v reflect.Value) int {
+	if !v.IsValid() {
+		return 0
+	}
+	if v.CanInterface() {
+		if sizer, isSizer := v.Interface().(sszSizer); isSizer && !(v.Kind() == reflect.Pointer && v.IsNil()) {
+			return sizer.SizeSSZ()
+		}
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return int(v.Type().Size())
+		}
+
+		return int(v.Type().Size()) + estimateValueSize(v.Elem())
+	case reflect.Struct:
+		size := 0
+		for i := 0; i < v.NumField(); i++ {
+			size += estimateValueSize(v.Field(i))
+		}
+
+		return size
+	case reflect.Slice, reflect.Array:
+		elemType := v.Type().Elem()
+		size := int(v.Type().Size())
+		if v.Kind() == reflect.Array {
+			size = 0
+		}
+		if isFlat(elemType) {
+			return size + v.Len()*int(elemType.Size())
+		}
+		for i := 0; i < v.Len(); i++ {
+			size += estimateValueSize(v.Index(i))
+		}
+
+		return size
+	case reflect.Map:
+		size := int(v.Type().Size())
+		iter := v.MapRange()
+		for iter.Next() {
+			size += estimateValueSize(iter.Key()) + estimateValueSize(iter.Value())
+		}
+
+		return size
+	case reflect.String:
+		return int(v.Type().Size()) + v.Len()
+	default:
+		return int(v.Type().Size())
+	}
+}
+
+// isFlat returns true if values of the type hold no references, so their size is fixed.
+func isFlat(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128:
+		return true
+	case reflect.Array:
+		return isFlat(t.Elem())
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			if !isFlat(t.Field(i).Type) {
+				return false
+			}
+		}
+
+		return true
+	default:
+		return false
+	}
+}
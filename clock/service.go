@@ -0,0 +1,273 @@
+// Copyright © 2023 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clock
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	consensusclient "github.com/attestantio/go-eth2-client"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/pkg/errors"
+)
+
+// defaultEpochsPerSyncCommitteePeriod is used if the client does not provide the value in its spec.
+const defaultEpochsPerSyncCommitteePeriod = 256
+
+// Service is a clock for the chain, providing slots, epochs and sync committee
+// periods for the current time along with notifications at their boundaries.
+type Service struct {
+	source                       Source
+	genesisTime                  time.Time
+	slotDuration                 time.Duration
+	slotsPerEpoch                uint64
+	epochsPerSyncCommitteePeriod uint64
+	forkSchedule                 []*phase0.Fork
+}
+
+// New creates a new chain clock.
+func New(ctx context.Context, params ...Parameter) (*Service, error) {
+	parameters, err := parseAndCheckParameters(params...)
+	if err != nil {
+		return nil, errors.Wrap(err, "problem with parameters")
+	}
+
+	genesisTimeProvider, isProvider := parameters.client.(consensusclient.GenesisTimeProvider)
+	if !isProvider {
+		return nil, errors.New("client does not provide genesis time")
+	}
+	genesisTime, err := genesisTimeProvider.GenesisTime(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to obtain genesis time")
+	}
+
+	slotDurationProvider, isProvider := parameters.client.(consensusclient.SlotDurationProvider)
+	if !isProvider {
+		return nil, errors.New("client does not provide slot duration")
+	}
+	slotDuration, err := slotDurationProvider.SlotDuration(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to obtain slot duration")
+	}
+	if slotDuration <= 0 {
+		return nil, errors.New("slot duration must be positive")
+	}
+
+	slotsPerEpochProvider, isProvider := parameters.client.(consensusclient.SlotsPerEpochProvider)
+	if !isProvider {
+		return nil, errors.New("client does not provide slots per epoch")
+	}
+	slotsPerEpoch, err := slotsPerEpochProvider.SlotsPerEpoch(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to obtain slots per epoch")
+	}
+	if slotsPerEpoch == 0 {
+		return nil, errors.New("slots per epoch must be positive")
+	}
+
+	epochsPerSyncCommitteePeriod, err := obtainEpochsPerSyncCommitteePeriod(ctx, parameters.client)
+	if err != nil {
+		return nil, err
+	}
+
+	forkSchedule, err := obtainForkSchedule(ctx, parameters.client)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Service{
+		source:                       parameters.source,
+		genesisTime:                  genesisTime,
+		slotDuration:                 slotDuration,
+		slotsPerEpoch:                slotsPerEpoch,
+		epochsPerSyncCommitteePeriod: epochsPerSyncCommitteePeriod,
+		forkSchedule:                 forkSchedule,
+	}, nil
+}
+
+// obtainEpochsPerSyncCommitteePeriod obtains the sync committee period length from the spec,
+// falling back to the mainnet value if it is not available.
+func obtainEpochsPerSyncCommitteePeriod(ctx context.Context, client consensusclient.Service) (uint64, error) {
+	specProvider, isProvider := client.(consensusclient.SpecProvider)
+	if !isProvider {
+		return defaultEpochsPerSyncCommitteePeriod, nil
+	}
+	specResponse, err := specProvider.Spec(ctx)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to obtain spec")
+	}
+	tmp, exists := specResponse.Data["EPOCHS_PER_SYNC_COMMITTEE_PERIOD"]
+	if !exists {
+		return defaultEpochsPerSyncCommitteePeriod, nil
+	}
+	epochsPerSyncCommitteePeriod, isUint := tmp.(uint64)
+	if !isUint {
+		return 0, errors.New("EPOCHS_PER_SYNC_COMMITTEE_PERIOD of unexpected type")
+	}
+	if epochsPerSyncCommitteePeriod == 0 {
+		return 0, errors.New("EPOCHS_PER_SYNC_COMMITTEE_PERIOD must be positive")
+	}
+
+	return epochsPerSyncCommitteePeriod, nil
+}
+
+// obtainForkSchedule obtains the fork schedule, ordered by epoch.
+func obtainForkSchedule(ctx context.Context, client consensusclient.Service) ([]*phase0.Fork, error) {
+	forkScheduleProvider, isProvider := client.(consensusclient.ForkScheduleProvider)
+	if !isProvider {
+		return nil, nil
+	}
+	forkScheduleResponse, err := forkScheduleProvider.ForkSchedule(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to obtain fork schedule")
+	}
+
+	forkSchedule := make([]*phase0.Fork, len(forkScheduleResponse.Data))
+	copy(forkSchedule, forkScheduleResponse.Data)
+	sort.SliceStable(forkSchedule, func(i, j int) bool {
+		return forkSchedule[i].Epoch < forkSchedule[j].Epoch
+	})
+
+	return forkSchedule, nil
+}
+
+// GenesisTime provides the genesis time of the chain.
+func (s *Service) GenesisTime() time.Time {
+	return s.genesisTime
+}
+
+// SlotDuration provides the duration of a slot of the chain.
+func (s *Service) SlotDuration() time.Duration {
+	return s.slotDuration
+}
+
+// SlotsPerEpoch provides the number of slots in an epoch of the chain.
+func (s *Service) SlotsPerEpoch() uint64 {
+	return s.slotsPerEpoch
+}
+
+// EpochsPerSyncCommitteePeriod provides the number of epochs in a sync committee period of the chain.
+func (s *Service) EpochsPerSyncCommitteePeriod() uint64 {
+	return s.epochsPerSyncCommitteePeriod
+}
+
+// Now provides the current time according to the clock's source.
+func (s *Service) Now() time.Time {
+	return s.source.Now()
+}
+
+// SlotFraction provides the given fraction of a slot's duration, for example
+// SlotFraction(1, 3) for the point at which attestations are due.
+func (s *Service) SlotFraction(numerator uint64, denominator uint64) time.Duration {
+	if denominator == 0 {
+		return 0
+	}
+
+	return time.Duration(uint64(s.slotDuration) * numerator / denominator)
+}
+
+// CurrentSlot provides the current slot; this is 0 prior to genesis.
+func (s *Service) CurrentSlot() phase0.Slot {
+	return s.SlotAt(s.source.Now())
+}
+
+// CurrentEpoch provides the current epoch; this is 0 prior to genesis.
+func (s *Service) CurrentEpoch() phase0.Epoch {
+	return s.SlotToEpoch(s.CurrentSlot())
+}
+
+// CurrentSyncCommitteePeriod provides the current sync committee period; this is 0 prior to genesis.
+func (s *Service) CurrentSyncCommitteePeriod() uint64 {
+	return s.EpochToSyncCommitteePeriod(s.CurrentEpoch())
+}
+
+// SlotAt provides the slot at the given time; this is 0 prior to genesis.
+func (s *Service) SlotAt(t time.Time) phase0.Slot {
+	if t.Before(s.genesisTime) {
+		return 0
+	}
+
+	return phase0.Slot(t.Sub(s.genesisTime) / s.slotDuration)
+}
+
+// SlotToEpoch provides the epoch of the given slot.
+func (s *Service) SlotToEpoch(slot phase0.Slot) phase0.Epoch {
+	return phase0.Epoch(uint64(slot) / s.slotsPerEpoch)
+}
+
+// EpochToSyncCommitteePeriod provides the sync committee period of the given epoch.
+func (s *Service) EpochToSyncCommitteePeriod(epoch phase0.Epoch) uint64 {
+	return uint64(epoch) / s.epochsPerSyncCommitteePeriod
+}
+
+// FirstSlotOfEpoch provides the first slot of the given epoch.
+func (s *Service) FirstSlotOfEpoch(epoch phase0.Epoch) phase0.Slot {
+	return phase0.Slot(uint64(epoch) * s.slotsPerEpoch)
+}
+
+// FirstEpochOfSyncCommitteePeriod provides the first epoch of the given sync committee period.
+func (s *Service) FirstEpochOfSyncCommitteePeriod(period uint64) phase0.Epoch {
+	return phase0.Epoch(period * s.epochsPerSyncCommitteePeriod)
+}
+
+// SlotStart provides the time at which the given slot starts.
+func (s *Service) SlotStart(slot phase0.Slot) time.Time {
+	return s.genesisTime.Add(time.Duration(slot) * s.slotDuration)
+}
+
+// EpochStart provides the time at which the given epoch starts.
+func (s *Service) EpochStart(epoch phase0.Epoch) time.Time {
+	return s.SlotStart(s.FirstSlotOfEpoch(epoch))
+}
+
+// SyncCommitteePeriodStart provides the time at which the given sync committee period starts.
+func (s *Service) SyncCommitteePeriodStart(period uint64) time.Time {
+	return s.EpochStart(s.FirstEpochOfSyncCommitteePeriod(period))
+}
+
+// ForkAt provides the fork that is active at the given epoch.
+func (s *Service) ForkAt(epoch phase0.Epoch) (*phase0.Fork, error) {
+	var fork *phase0.Fork
+	for _, scheduled := range s.forkSchedule {
+		if scheduled.Epoch > epoch {
+			break
+		}
+		fork = scheduled
+	}
+	if fork == nil {
+		return nil, errors.New("no fork scheduled for epoch")
+	}
+
+	return fork, nil
+}
+
+// ForkEpoch provides the epoch at which the fork with the given version activates.
+func (s *Service) ForkEpoch(version phase0.Version) (phase0.Epoch, error) {
+	for _, scheduled := range s.forkSchedule {
+		if scheduled.CurrentVersion == version {
+			return scheduled.Epoch, nil
+		}
+	}
+
+	return 0, errors.New("fork version not in schedule")
+}
+
+// ForkSchedule provides the fork schedule of the chain, ordered by epoch.
+func (s *Service) ForkSchedule() []*phase0.Fork {
+	forkSchedule := make([]*phase0.Fork, len(s.forkSchedule))
+	copy(forkSchedule, s.forkSchedule)
+
+	return forkSchedule
+}
@@ -0,0 +1,240 @@
+// Copyright © 2023 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clock_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/attestantio/go-eth2-client/clock"
+	"github.com/attestantio/go-eth2-client/mock"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/stretchr/testify/require"
+)
+
+var genesis = time.Unix(1606824023, 0)
+
+func newClock(ctx context.Context, t *testing.T, now time.Time) (*clock.Service, *clock.Fake, *mock.Service) {
+	t.Helper()
+
+	fake := clock.NewFake(now)
+	// The mock outlives the test's context, as it only holds static data.
+	client, err := mock.New(context.Background(),
+		mock.WithGenesisTime(genesis),
+		mock.WithClockSource(fake),
+	)
+	require.NoError(t, err)
+
+	c, err := clock.New(ctx,
+		clock.WithClient(client),
+		clock.WithSource(fake),
+	)
+	require.NoError(t, err)
+
+	return c, fake, client
+}
+
+func TestService(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name   string
+		params []clock.Parameter
+		err    string
+	}{
+		{
+			name: "ClientMissing",
+			err:  "problem with parameters: no client specified",
+		},
+		{
+			name: "SourceNil",
+			params: []clock.Parameter{
+				clock.WithClient(&mock.Service{}),
+				clock.WithSource(nil),
+			},
+			err: "problem with parameters: no source specified",
+		},
+		{
+			name: "Good",
+			params: []clock.Parameter{
+				clock.WithClient(&mock.Service{}),
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := clock.New(ctx, test.params...)
+			if test.err != "" {
+				require.EqualError(t, err, test.err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestCurrent(t *testing.T) {
+	ctx := context.Background()
+
+	c, fake, _ := newClock(ctx, t, genesis.Add(-time.Minute))
+	require.Equal(t, genesis, c.GenesisTime())
+	require.Equal(t, 12*time.Second, c.SlotDuration())
+	require.Equal(t, uint64(32), c.SlotsPerEpoch())
+	require.Equal(t, uint64(256), c.EpochsPerSyncCommitteePeriod())
+
+	// Prior to genesis.
+	require.Equal(t, phase0.Slot(0), c.CurrentSlot())
+	require.Equal(t, phase0.Epoch(0), c.CurrentEpoch())
+
+	fake.Set(genesis.Add(100*12*time.Second + 5*time.Second))
+	require.Equal(t, phase0.Slot(100), c.CurrentSlot())
+	require.Equal(t, phase0.Epoch(3), c.CurrentEpoch())
+	require.Equal(t, uint64(0), c.CurrentSyncCommitteePeriod())
+
+	fake.Set(c.SyncCommitteePeriodStart(2))
+	require.Equal(t, phase0.Slot(2*256*32), c.CurrentSlot())
+	require.Equal(t, phase0.Epoch(512), c.CurrentEpoch())
+	require.Equal(t, uint64(2), c.CurrentSyncCommitteePeriod())
+}
+
+func TestStarts(t *testing.T) {
+	ctx := context.Background()
+
+	c, _, _ := newClock(ctx, t, genesis)
+	require.Equal(t, genesis, c.SlotStart(0))
+	require.Equal(t, genesis.Add(10*12*time.Second), c.SlotStart(10))
+	require.Equal(t, genesis.Add(64*12*time.Second), c.EpochStart(2))
+	require.Equal(t, phase0.Slot(64), c.FirstSlotOfEpoch(2))
+	require.Equal(t, phase0.Epoch(256), c.FirstEpochOfSyncCommitteePeriod(1))
+	require.Equal(t, 4*time.Second, c.SlotFraction(1, 3))
+	require.Equal(t, 8*time.Second, c.SlotFraction(2, 3))
+	require.Equal(t, time.Duration(0), c.SlotFraction(1, 0))
+}
+
+func TestForks(t *testing.T) {
+	ctx := context.Background()
+
+	c, _, _ := newClock(ctx, t, genesis)
+	require.Len(t, c.ForkSchedule(), 2)
+
+	fork, err := c.ForkAt(0)
+	require.NoError(t, err)
+	require.Equal(t, phase0.Version{0x01, 0x02, 0x03, 0x04}, fork.CurrentVersion)
+
+	fork, err = c.ForkAt(1023)
+	require.NoError(t, err)
+	require.Equal(t, phase0.Version{0x01, 0x02, 0x03, 0x04}, fork.CurrentVersion)
+
+	fork, err = c.ForkAt(1024)
+	require.NoError(t, err)
+	require.Equal(t, phase0.Version{0x11, 0x12, 0x13, 0x14}, fork.CurrentVersion)
+
+	epoch, err := c.ForkEpoch(phase0.Version{0x11, 0x12, 0x13, 0x14})
+	require.NoError(t, err)
+	require.Equal(t, phase0.Epoch(1024), epoch)
+
+	_, err = c.ForkEpoch(phase0.Version{0xff, 0xff, 0xff, 0xff})
+	require.EqualError(t, err, "fork version not in schedule")
+}
+
+func TestSlots(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	c, fake, _ := newClock(ctx, t, genesis.Add(5*12*time.Second+5*time.Second))
+	slots := c.Slots(ctx, c.SlotFraction(1, 3))
+
+	// Nothing until 1/3 of the way through the next slot.
+	fake.Advance(10 * time.Second)
+	requireNothing(t, slots)
+	fake.Advance(2 * time.Second)
+	require.Equal(t, phase0.Slot(6), receive(t, slots))
+
+	// Missed slots are skipped.
+	fake.Advance(3 * 12 * time.Second)
+	require.Equal(t, phase0.Slot(9), receive(t, slots))
+	requireNothing(t, slots)
+
+	cancel()
+	_, open := <-slots
+	require.False(t, open)
+}
+
+func TestEpochs(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	c, fake, _ := newClock(ctx, t, genesis)
+	epochs := make(chan phase0.Epoch, 4)
+	c.OnEpoch(ctx, 0, func(_ context.Context, epoch phase0.Epoch) {
+		epochs <- epoch
+	})
+
+	fake.Set(c.EpochStart(1).Add(-time.Second))
+	requireNothing(t, epochs)
+	fake.Set(c.EpochStart(1))
+	require.Equal(t, phase0.Epoch(1), receive(t, epochs))
+}
+
+func TestSyncCommitteePeriods(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	c, fake, _ := newClock(ctx, t, genesis)
+	periods := c.SyncCommitteePeriods(ctx, -c.SlotDuration())
+
+	fake.Set(c.SyncCommitteePeriodStart(1).Add(-2 * c.SlotDuration()))
+	requireNothing(t, periods)
+	fake.Set(c.SyncCommitteePeriodStart(1).Add(-c.SlotDuration()))
+	require.Equal(t, uint64(1), receive(t, periods))
+}
+
+func TestMockDriven(t *testing.T) {
+	ctx := context.Background()
+
+	c, fake, client := newClock(ctx, t, genesis)
+	fake.Advance(7 * c.SlotDuration())
+
+	response, err := client.NodeSyncing(ctx)
+	require.NoError(t, err)
+	require.Equal(t, c.CurrentSlot(), response.Data.HeadSlot)
+	require.Equal(t, phase0.Slot(7), response.Data.HeadSlot)
+}
+
+func receive[T any](t *testing.T, ch <-chan T) T {
+	t.Helper()
+
+	select {
+	case value := <-ch:
+		return value
+	case <-time.After(time.Second):
+		require.FailNow(t, "timed out waiting for tick")
+	}
+
+	var value T
+
+	return value
+}
+
+func requireNothing[T any](t *testing.T, ch <-chan T) {
+	t.Helper()
+
+	select {
+	case value := <-ch:
+		require.FailNow(t, "unexpected tick", "received %v", value)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
@@ -0,0 +1,123 @@
+// Copyright © 2023 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// Source is a source of the current time.
+type Source interface {
+	// Now returns the current time.
+	Now() time.Time
+	// After returns a channel that receives the current time once the duration has elapsed.
+	After(d time.Duration) <-chan time.Time
+}
+
+type systemSource struct{}
+
+// SystemSource returns a source that uses the system clock.
+func SystemSource() Source {
+	return systemSource{}
+}
+
+// Now returns the current time.
+func (systemSource) Now() time.Time {
+	return time.Now()
+}
+
+// After returns a channel that receives the current time once the duration has elapsed.
+func (systemSource) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+// Fake is a source of time that only moves when told to.
+type Fake struct {
+	mu      sync.Mutex
+	now     time.Time
+	waiters []*fakeWaiter
+}
+
+type fakeWaiter struct {
+	deadline time.Time
+	ch       chan time.Time
+}
+
+// NewFake creates a fake source starting at the given time.
+func NewFake(now time.Time) *Fake {
+	return &Fake{
+		now: now,
+	}
+}
+
+// Now returns the current time.
+func (f *Fake) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.now
+}
+
+// After returns a channel that receives the current time once the fake
+// has been advanced by at least the duration.
+func (f *Fake) After(d time.Duration) <-chan time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	ch := make(chan time.Time, 1)
+	if d <= 0 {
+		ch <- f.now
+
+		return ch
+	}
+	f.waiters = append(f.waiters, &fakeWaiter{
+		deadline: f.now.Add(d),
+		ch:       ch,
+	})
+
+	return ch
+}
+
+// Advance moves the fake forward by the given duration, firing any waiters that are due.
+func (f *Fake) Advance(d time.Duration) {
+	f.mu.Lock()
+	f.set(f.now.Add(d))
+	f.mu.Unlock()
+}
+
+// Set moves the fake to the given time, firing any waiters that are due.
+// Attempts to move the fake backwards are ignored.
+func (f *Fake) Set(now time.Time) {
+	f.mu.Lock()
+	if now.After(f.now) {
+		f.set(now)
+	}
+	f.mu.Unlock()
+}
+
+// set sets the time and fires due waiters; it must be called with the lock held.
+func (f *Fake) set(now time.Time) {
+	f.now = now
+	waiters := f.waiters[:0]
+	for _, waiter := range f.waiters {
+		if waiter.deadline.After(now) {
+			waiters = append(waiters, waiter)
+
+			continue
+		}
+		waiter.ch <- now
+	}
+	f.waiters = waiters
+}
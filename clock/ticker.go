@@ -0,0 +1,112 @@
+// Copyright © 2023 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clock
+
+import (
+	"context"
+	"time"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+)
+
+// Slots provides a channel that receives each slot as it starts, delayed by the
+// given offset; for example an offset of SlotFraction(1, 3) notifies when
+// attestations for the slot are due.  Slots that are missed because the receiver
+// is slow are skipped.  The channel is closed when the context is done.
+func (s *Service) Slots(ctx context.Context, offset time.Duration) <-chan phase0.Slot {
+	return ticks[phase0.Slot](ctx, s, offset, 1)
+}
+
+// Epochs provides a channel that receives each epoch as it starts, delayed by the
+// given offset.  Epochs that are missed because the receiver is slow are skipped.
+// The channel is closed when the context is done.
+func (s *Service) Epochs(ctx context.Context, offset time.Duration) <-chan phase0.Epoch {
+	return ticks[phase0.Epoch](ctx, s, offset, s.slotsPerEpoch)
+}
+
+// SyncCommitteePeriods provides a channel that receives each sync committee period
+// as it starts, delayed by the given offset.  Periods that are missed because the
+// receiver is slow are skipped.  The channel is closed when the context is done.
+func (s *Service) SyncCommitteePeriods(ctx context.Context, offset time.Duration) <-chan uint64 {
+	return ticks[uint64](ctx, s, offset, s.slotsPerEpoch*s.epochsPerSyncCommitteePeriod)
+}
+
+// OnSlot calls the handler for each slot as it starts, delayed by the given offset,
+// until the context is done.
+func (s *Service) OnSlot(ctx context.Context, offset time.Duration, handler func(context.Context, phase0.Slot)) {
+	go handle(ctx, s.Slots(ctx, offset), handler)
+}
+
+// OnEpoch calls the handler for each epoch as it starts, delayed by the given offset,
+// until the context is done.
+func (s *Service) OnEpoch(ctx context.Context, offset time.Duration, handler func(context.Context, phase0.Epoch)) {
+	go handle(ctx, s.Epochs(ctx, offset), handler)
+}
+
+// OnSyncCommitteePeriod calls the handler for each sync committee period as it starts,
+// delayed by the given offset, until the context is done.
+func (s *Service) OnSyncCommitteePeriod(ctx context.Context, offset time.Duration, handler func(context.Context, uint64)) {
+	go handle(ctx, s.SyncCommitteePeriods(ctx, offset), handler)
+}
+
+func handle[T any](ctx context.Context, ch <-chan T, handler func(context.Context, T)) {
+	for value := range ch {
+		handler(ctx, value)
+	}
+}
+
+// ticks sends the index of each boundary, which occur every interval slots, once
+// the offset past the boundary has been reached.
+func ticks[T ~uint64](ctx context.Context, s *Service, offset time.Duration, interval uint64) <-chan T {
+	ch := make(chan T, 1)
+	period := time.Duration(interval) * s.slotDuration
+	first := s.genesisTime.Add(offset)
+
+	// The first tick is calculated up front so that time moving on before the
+	// goroutine starts does not cause it to be missed.
+	var next uint64
+	if now := s.source.Now(); !now.Before(first) {
+		next = uint64(now.Sub(first)/period) + 1
+	}
+
+	go func() {
+		defer close(ch)
+		for {
+			now := s.source.Now()
+			if wait := first.Add(time.Duration(next) * period).Sub(now); wait > 0 {
+				select {
+				case <-ctx.Done():
+					return
+				case <-s.source.After(wait):
+				}
+
+				continue
+			}
+
+			// Skip any ticks that have passed in their entirety.
+			if latest := uint64(now.Sub(first) / period); latest > next {
+				next = latest
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case ch <- T(next):
+			}
+			next++
+		}
+	}()
+
+	return ch
+}
@@ -34,7 +34,7 @@ func (s *Service) AttestationData(ctx context.Context,
 		return nil, errors.New("no options specified")
 	}
 
-	httpResponse, err := s.get2(ctx, fmt.Sprintf("/eth/v1/validator/attestation_data?slot=%d&committee_index=%d", opts.Slot, opts.CommitteeIndex))
+	httpResponse, err := s.get2(ctx, "AttestationData", fmt.Sprintf("/eth/v1/validator/attestation_data?slot=%d&committee_index=%d", opts.Slot, opts.CommitteeIndex), &opts.Common)
 	if err != nil {
 		return nil, err
 	}
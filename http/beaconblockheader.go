@@ -33,8 +33,11 @@ func (s *Service) BeaconBlockHeader(ctx context.Context,
 	if opts == nil {
 		return nil, errors.New("no options specified")
 	}
+	if err := opts.Block.Validate(); err != nil {
+		return nil, err
+	}
 
-	httpResponse, err := s.get2(ctx, fmt.Sprintf("/eth/v1/beacon/headers/%s", opts.Block))
+	httpResponse, err := s.get2(ctx, "BeaconBlockHeader", fmt.Sprintf("/eth/v1/beacon/headers/%s", opts.Block), &opts.Common)
 	if err != nil {
 		return nil, err
 	}
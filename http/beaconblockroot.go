@@ -37,11 +37,11 @@ func (s *Service) BeaconBlockRoot(ctx context.Context,
 	if opts == nil {
 		return nil, errors.New("no options specified")
 	}
-	if opts.Block == "" {
-		return nil, errors.New("no block specified")
+	if err := opts.Block.Validate(); err != nil {
+		return nil, err
 	}
 
-	httpResponse, err := s.get2(ctx, fmt.Sprintf("/eth/v1/beacon/blocks/%s/root", opts.Block))
+	httpResponse, err := s.get2(ctx, "BeaconBlockRoot", fmt.Sprintf("/eth/v1/beacon/blocks/%s/root", opts.Block), &opts.Common)
 	if err != nil {
 		return nil, err
 	}
@@ -14,9 +14,9 @@
 package http
 
 import (
-	"bytes"
 	"context"
 	"fmt"
+	"io"
 
 	"github.com/attestantio/go-eth2-client/api"
 	"github.com/attestantio/go-eth2-client/spec"
@@ -38,87 +38,132 @@ func (s *Service) BeaconState(ctx context.Context,
 	if opts == nil {
 		return nil, errors.New("no options specified")
 	}
-	if opts.State == "" {
-		return nil, errors.New("no state specified")
+	if err := opts.State.Validate(); err != nil {
+		return nil, err
 	}
 
-	httpResponse, err := s.get2(ctx, fmt.Sprintf("/eth/v2/debug/beacon/states/%s", opts.State))
+	// Beacon states can be very large, so stream the response to avoid holding
+	// multiple copies of it in memory.
+	res, err := s.getStream(ctx, "BeaconState", fmt.Sprintf("/eth/v2/debug/beacon/states/%s", opts.State), &opts.Common, s.acceptHeader("BeaconState"))
 	if err != nil {
 		return nil, err
 	}
+	defer res.body.Close()
 
-	switch httpResponse.contentType {
+	switch res.contentType {
 	case ContentTypeSSZ:
-		return s.beaconStateFromSSZ(httpResponse)
+		data, err := readStreamBody(res)
+		if err != nil {
+			return nil, err
+		}
+
+		return s.beaconStateFromSSZ(res.consensusVersion, res.headers, data)
 	case ContentTypeJSON:
-		return s.beaconStateFromJSON(httpResponse)
+		body, err := jsonStreamBody(res)
+		if err != nil {
+			return nil, err
+		}
+
+		return s.beaconStateFromJSON(res.consensusVersion, body)
 	default:
-		return nil, fmt.Errorf("unhandled content type %v", httpResponse.contentType)
+		return nil, fmt.Errorf("unhandled content type %v", res.contentType)
+	}
+}
+
+// WriteBeaconStateSSZ writes the SSZ-encoded beacon state to the supplied writer.
+func (s *Service) WriteBeaconStateSSZ(ctx context.Context,
+	opts *api.BeaconStateOpts,
+	w io.Writer,
+) (
+	*api.Response[*api.SSZWriteResult],
+	error,
+) {
+	if opts == nil {
+		return nil, errors.New("no options specified")
+	}
+	if err := opts.State.Validate(); err != nil {
+		return nil, err
+	}
+	if w == nil {
+		return nil, errors.New("no writer specified")
 	}
+
+	return s.writeSSZ(ctx, "BeaconState", fmt.Sprintf("/eth/v2/debug/beacon/states/%s", opts.State), &opts.Common, w)
 }
 
-func (s *Service) beaconStateFromSSZ(res *httpResponse) (*api.Response[*spec.VersionedBeaconState], error) {
+func (s *Service) beaconStateFromSSZ(version spec.DataVersion,
+	headers map[string]string,
+	data []byte,
+) (
+	*api.Response[*spec.VersionedBeaconState],
+	error,
+) {
 	response := &api.Response[*spec.VersionedBeaconState]{
 		Data: &spec.VersionedBeaconState{
-			Version: res.consensusVersion,
+			Version: version,
 		},
-		Metadata: metadataFromHeaders(res.headers),
+		Metadata: metadataFromHeaders(headers),
 	}
 
-	switch res.consensusVersion {
+	switch version {
 	case spec.DataVersionPhase0:
 		response.Data.Phase0 = &phase0.BeaconState{}
-		if err := response.Data.Phase0.UnmarshalSSZ(res.body); err != nil {
+		if err := response.Data.Phase0.UnmarshalSSZ(data); err != nil {
 			return nil, errors.Wrap(err, "failed to decode phase0 beacon state")
 		}
 	case spec.DataVersionAltair:
 		response.Data.Altair = &altair.BeaconState{}
-		if err := response.Data.Altair.UnmarshalSSZ(res.body); err != nil {
+		if err := response.Data.Altair.UnmarshalSSZ(data); err != nil {
 			return nil, errors.Wrap(err, "failed to decode altair beacon state")
 		}
 	case spec.DataVersionBellatrix:
 		response.Data.Bellatrix = &bellatrix.BeaconState{}
-		if err := response.Data.Bellatrix.UnmarshalSSZ(res.body); err != nil {
+		if err := response.Data.Bellatrix.UnmarshalSSZ(data); err != nil {
 			return nil, errors.Wrap(err, "failed to decode bellatrix beacon state")
 		}
 	case spec.DataVersionCapella:
 		response.Data.Capella = &capella.BeaconState{}
-		if err := response.Data.Capella.UnmarshalSSZ(res.body); err != nil {
+		if err := response.Data.Capella.UnmarshalSSZ(data); err != nil {
 			return nil, errors.Wrap(err, "failed to decode capella beacon state")
 		}
 	case spec.DataVersionDeneb:
 		response.Data.Deneb = &deneb.BeaconState{}
-		if err := response.Data.Deneb.UnmarshalSSZ(res.body); err != nil {
+		if err := response.Data.Deneb.UnmarshalSSZ(data); err != nil {
 			return nil, errors.Wrap(err, "failed to decode deneb beacon state")
 		}
 	default:
-		return nil, fmt.Errorf("unhandled state version %s", res.consensusVersion)
+		return nil, fmt.Errorf("unhandled state version %s", version)
 	}
 
 	return response, nil
 }
 
-func (s *Service) beaconStateFromJSON(res *httpResponse) (*api.Response[*spec.VersionedBeaconState], error) {
+func (s *Service) beaconStateFromJSON(version spec.DataVersion,
+	body io.Reader,
+) (
+	*api.Response[*spec.VersionedBeaconState],
+	error,
+) {
 	response := &api.Response[*spec.VersionedBeaconState]{
 		Data: &spec.VersionedBeaconState{
-			Version: res.consensusVersion,
+			Version: version,
 		},
 	}
 
 	var err error
-	switch res.consensusVersion {
+	switch version {
 	case spec.DataVersionPhase0:
-		response.Data.Phase0, response.Metadata, err = decodeJSONResponse(bytes.NewReader(res.body), &phase0.BeaconState{})
+		response.Data.Phase0, response.Metadata, err = decodeJSONResponse(body, &phase0.BeaconState{})
 	case spec.DataVersionAltair:
-		response.Data.Altair, response.Metadata, err = decodeJSONResponse(bytes.NewReader(res.body), &altair.BeaconState{})
+		response.Data.Altair, response.Metadata, err = decodeJSONResponse(body, &altair.BeaconState{})
 	case spec.DataVersionBellatrix:
-		response.Data.Bellatrix, response.Metadata, err = decodeJSONResponse(bytes.NewReader(res.body), &bellatrix.BeaconState{})
+		response.Data.Bellatrix, response.Metadata, err = decodeJSONResponse(body, &bellatrix.BeaconState{})
 	case spec.DataVersionCapella:
-		response.Data.Capella, response.Metadata, err = decodeJSONResponse(bytes.NewReader(res.body), &capella.BeaconState{})
+		response.Data.Capella, response.Metadata, err = decodeJSONResponse(body, &capella.BeaconState{})
 	case spec.DataVersionDeneb:
-		response.Data.Deneb, response.Metadata, err = decodeJSONResponse(bytes.NewReader(res.body), &deneb.BeaconState{})
+		response.Data.Deneb, response.Metadata, err = decodeJSONResponse(body, &deneb.BeaconState{})
 	default:
-		err = fmt.Errorf("unsupported version %s", res.consensusVersion)
+		err = fmt.Errorf("unsupported version %s", version)
 	}
 	if err != nil {
 		return nil, err
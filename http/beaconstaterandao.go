@@ -32,11 +32,11 @@ func (s *Service) BeaconStateRandao(ctx context.Context, opts *api.BeaconStateRa
 	if opts == nil {
 		return nil, errors.New("no options specified")
 	}
-	if opts.State == "" {
-		return nil, errors.New("no state specified")
+	if err := opts.State.Validate(); err != nil {
+		return nil, err
 	}
 
-	httpResponse, err := s.get2(ctx, fmt.Sprintf("/eth/v1/beacon/states/%s/randao", opts.State))
+	httpResponse, err := s.get2(ctx, "BeaconStateRandao", fmt.Sprintf("/eth/v1/beacon/states/%s/randao", opts.State), &opts.Common)
 	if err != nil {
 		return nil, err
 	}
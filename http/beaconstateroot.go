@@ -32,11 +32,11 @@ func (s *Service) BeaconStateRoot(ctx context.Context, opts *api.BeaconStateRoot
 	if opts == nil {
 		return nil, errors.New("no options specified")
 	}
-	if opts.State == "" {
-		return nil, errors.New("no state specified")
+	if err := opts.State.Validate(); err != nil {
+		return nil, err
 	}
 
-	httpResponse, err := s.get2(ctx, fmt.Sprintf("/eth/v1/beacon/states/%s/root", opts.State))
+	httpResponse, err := s.get2(ctx, "BeaconStateRoot", fmt.Sprintf("/eth/v1/beacon/states/%s/root", opts.State), &opts.Common)
 	if err != nil {
 		return nil, err
 	}
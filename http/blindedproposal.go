@@ -53,7 +53,7 @@ func (s *Service) BlindedProposal(ctx context.Context,
 		url = fmt.Sprintf("%s&skip_randao_verification", url)
 	}
 
-	res, err := s.get2(ctx, url)
+	res, err := s.get2(ctx, "BlindedProposal", url, &opts.Common)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to request blinded beacon block proposal")
 	}
@@ -82,7 +82,7 @@ func (s *Service) BlindedProposal(ctx context.Context,
 
 	// Only check the RANDAO reveal and graffiti if we are not connected to DVT middleware,
 	// as the returned values will be decided by the middleware.
-	if !s.connectedToDVTMiddleware {
+	if !s.connectedToDVTMiddleware(ctx) {
 		blockRandaoReveal, err := response.Data.RandaoReveal()
 		if err != nil {
 			return nil, err
@@ -155,6 +155,8 @@ func (s *Service) blindedProposalFromJSON(res *httpResponse) (*api.Response[*api
 	if err != nil {
 		return nil, err
 	}
+	// Headers carry additional information, such as the value of the proposal.
+	addHeadersToMetadata(response.Metadata, res.headers)
 
 	return response, nil
 }
@@ -0,0 +1,230 @@
+// Copyright © 2023 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package http
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/attestantio/go-eth2-client/api"
+	"github.com/pkg/errors"
+)
+
+// capabilityProbe is a request used to find out if a beacon node supports an endpoint.
+type capabilityProbe struct {
+	name     string
+	endpoint string
+}
+
+// capabilityProbes are requests that are invalid but harmless.  A beacon node that supports
+// the endpoint will reject the request with a client error, whereas one that does not support
+// the endpoint will return not found or similar.
+var capabilityProbes = []capabilityProbe{
+	{name: "BlindedProposal", endpoint: "/eth/v1/validator/blinded_blocks/0"},
+	{name: "BlobSidecars", endpoint: "/eth/v1/beacon/blob_sidecars/invalid"},
+	{name: api.CapabilityProposalV3, endpoint: "/eth/v3/validator/blocks/0"},
+}
+
+// Capabilities provides the capabilities of the node.
+func (s *Service) Capabilities(ctx context.Context) (*api.Response[*api.Capabilities], error) {
+	capabilities, err := s.nodeCapabilities(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &api.Response[*api.Capabilities]{
+		Data:     capabilities,
+		Metadata: make(map[string]any),
+	}, nil
+}
+
+// nodeCapabilities returns the capabilities of the node, probing it if required.
+func (s *Service) nodeCapabilities(ctx context.Context) (*api.Capabilities, error) {
+	if capabilities := s.cachedCapabilities(); capabilities != nil {
+		return capabilities, nil
+	}
+
+	// Probing makes requests of its own, so use a separate lock to ensure that only
+	// one probe runs at a time without blocking access to the cached value.
+	s.capabilitiesProbeMutex.Lock()
+	defer s.capabilitiesProbeMutex.Unlock()
+	if capabilities := s.cachedCapabilities(); capabilities != nil {
+		// Someone else probed the node whilst we were waiting for the lock.
+		return capabilities, nil
+	}
+
+	capabilities, err := s.probeCapabilities(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s.capabilitiesMutex.Lock()
+	s.capabilities = capabilities
+	s.capabilitiesMutex.Unlock()
+
+	return capabilities, nil
+}
+
+// cachedCapabilities returns the capabilities of the node if known, without probing it.
+func (s *Service) cachedCapabilities() *api.Capabilities {
+	s.capabilitiesMutex.RLock()
+	defer s.capabilitiesMutex.RUnlock()
+
+	return s.capabilities
+}
+
+// probeCapabilities probes the node for its capabilities.
+func (s *Service) probeCapabilities(ctx context.Context) (*api.Capabilities, error) {
+	response, err := s.NodeVersion(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to obtain node version")
+	}
+	version := strings.ToLower(response.Data)
+
+	capabilities := &api.Capabilities{
+		Client:        clientFromNodeVersion(version),
+		DVTMiddleware: strings.Contains(version, "charon"),
+		Endpoints:     make(map[string]api.EndpointCapabilities),
+	}
+
+	for _, probe := range capabilityProbes {
+		statusCode, _, err := s.probe(ctx, probe.endpoint, ContentTypeJSON.MediaType())
+		if err != nil {
+			s.log.Debug().Str("endpoint", probe.endpoint).Err(err).Msg("Failed to probe endpoint")
+
+			continue
+		}
+		capabilities.Endpoints[probe.name] = api.EndpointCapabilities{
+			Supported: statusCode != http.StatusNotFound &&
+				statusCode != http.StatusMethodNotAllowed &&
+				statusCode != http.StatusNotImplemented,
+		}
+	}
+
+	// Check SSZ support with the genesis block, which is small.
+	statusCode, contentType, err := s.probe(ctx, "/eth/v2/beacon/blocks/genesis", s.acceptHeader(""))
+	switch {
+	case err != nil:
+		s.log.Debug().Err(err).Msg("Failed to probe SSZ support")
+	case statusCode == http.StatusOK:
+		capabilities.Endpoints["SignedBeaconBlock"] = api.EndpointCapabilities{
+			Supported: true,
+			SSZ:       contentType == ContentTypeSSZ,
+			JSON:      contentType == ContentTypeJSON,
+		}
+	}
+
+	return capabilities, nil
+}
+
+// probe sends a GET request to the node, returning the status code and content type of the response.
+func (s *Service) probe(ctx context.Context, endpoint string, accept string) (int, ContentType, error) {
+	opCtx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(opCtx,
+		http.MethodGet,
+		fmt.Sprintf("%s%s", strings.TrimSuffix(s.base.String(), "/"), endpoint),
+		nil,
+	)
+	if err != nil {
+		return 0, ContentTypeUnknown, errors.Wrap(err, "failed to create GET request")
+	}
+	s.addExtraHeaders(req)
+	req.Header.Set("Accept", accept)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, ContentTypeUnknown, errors.Wrap(err, "failed to call GET endpoint")
+	}
+	defer resp.Body.Close()
+	// Drain the body to allow the connection to be reused.
+	if _, err := io.Copy(io.Discard, resp.Body); err != nil {
+		return 0, ContentTypeUnknown, errors.Wrap(err, "failed to read body")
+	}
+
+	contentType, err := contentTypeFromResponse(resp)
+	if err != nil {
+		contentType = ContentTypeUnknown
+	}
+
+	return resp.StatusCode, contentType, nil
+}
+
+// checkSupported returns an error if the node is known not to support the call.
+func (s *Service) checkSupported(call string) error {
+	capabilities := s.cachedCapabilities()
+	if capabilities != nil && !capabilities.Supports(call) {
+		return errors.Wrap(api.ErrNotSupported, call)
+	}
+
+	return nil
+}
+
+// hasCapability returns true if the node is known to have the capability.
+func (s *Service) hasCapability(capability string) bool {
+	capabilities := s.cachedCapabilities()
+	if capabilities == nil {
+		return false
+	}
+
+	return capabilities.Endpoints[capability].Supported
+}
+
+// recordResponseFormat records the format of a successful response to a call that
+// could have returned either SSZ or JSON.
+func (s *Service) recordResponseFormat(call string, contentType ContentType) {
+	s.capabilitiesMutex.Lock()
+	defer s.capabilitiesMutex.Unlock()
+
+	if s.capabilities == nil {
+		// Not yet probed.
+		return
+	}
+
+	current := s.capabilities.Endpoints[call]
+	updated := api.EndpointCapabilities{
+		Supported: true,
+		SSZ:       current.SSZ || contentType == ContentTypeSSZ,
+		JSON:      current.JSON || contentType == ContentTypeJSON,
+	}
+	if current == updated {
+		return
+	}
+
+	// Capabilities are handed out to callers, so copy rather than update in place.
+	capabilities := &api.Capabilities{
+		Client:        s.capabilities.Client,
+		DVTMiddleware: s.capabilities.DVTMiddleware,
+		Endpoints:     make(map[string]api.EndpointCapabilities, len(s.capabilities.Endpoints)+1),
+	}
+	for k, v := range s.capabilities.Endpoints {
+		capabilities.Endpoints[k] = v
+	}
+	capabilities.Endpoints[call] = updated
+	s.capabilities = capabilities
+}
+
+// connectedToDVTMiddleware returns true if the node is accessed through DVT middleware.
+func (s *Service) connectedToDVTMiddleware(ctx context.Context) bool {
+	capabilities, err := s.nodeCapabilities(ctx)
+	if err != nil {
+		return false
+	}
+
+	return capabilities.DVTMiddleware
+}
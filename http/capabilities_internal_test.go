@@ -0,0 +1,163 @@
+// Copyright © 2023 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/attestantio/go-eth2-client/api"
+	"github.com/attestantio/go-eth2-client/spec/altair"
+	"github.com/attestantio/go-eth2-client/spec/bellatrix"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/prysmaticlabs/go-bitfield"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCapabilities(t *testing.T) {
+	ctx := context.Background()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/eth/v1/node/version":
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"data":{"version":"Lighthouse/v4.5.0/charon"}}`))
+		case "/eth/v1/validator/blinded_blocks/0", "/eth/v1/beacon/blob_sidecars/invalid":
+			w.WriteHeader(http.StatusBadRequest)
+		case "/eth/v2/beacon/blocks/genesis":
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	base, err := url.Parse(srv.URL)
+	require.NoError(t, err)
+	s := &Service{
+		log:     zerolog.Nop(),
+		base:    base,
+		address: srv.URL,
+		client:  srv.Client(),
+		timeout: time.Second,
+	}
+
+	response, err := s.Capabilities(ctx)
+	require.NoError(t, err)
+	capabilities := response.Data
+	require.Equal(t, "lighthouse", capabilities.Client)
+	require.True(t, capabilities.DVTMiddleware)
+	require.True(t, capabilities.Supports("BlindedProposal"))
+	require.True(t, capabilities.Supports("BlobSidecars"))
+	require.False(t, capabilities.Supports(api.CapabilityProposalV3))
+	require.False(t, s.hasCapability(api.CapabilityProposalV3))
+	// Endpoints that have not been probed are assumed to be supported.
+	require.True(t, capabilities.Supports("AttestationData"))
+	require.True(t, capabilities.SupportsSSZ("AttestationData"))
+	// Block returned JSON when SSZ was preferred.
+	require.False(t, capabilities.SupportsSSZ("SignedBeaconBlock"))
+	require.Equal(t, ContentTypeJSON.MediaType(), s.acceptHeader("SignedBeaconBlock"))
+	require.NotEqual(t, ContentTypeJSON.MediaType(), s.acceptHeader("BeaconState"))
+
+	require.True(t, s.connectedToDVTMiddleware(ctx))
+
+	// Calls that are known to be unsupported fail without contacting the node.
+	_, _, err = s.requestContext(ctx, api.CapabilityProposalV3, nil)
+	require.ErrorIs(t, err, api.ErrNotSupported)
+
+	// Responses update the capabilities without changing those already handed out.
+	s.recordResponseFormat("BeaconState", ContentTypeJSON)
+	require.False(t, s.cachedCapabilities().SupportsSSZ("BeaconState"))
+	require.True(t, capabilities.SupportsSSZ("BeaconState"))
+}
+
+func TestProposalV3(t *testing.T) {
+	ctx := context.Background()
+
+	block := &bellatrix.BeaconBlock{
+		Slot: 1,
+		Body: &bellatrix.BeaconBlockBody{
+			ETH1Data: &phase0.ETH1Data{
+				BlockHash: make([]byte, 32),
+			},
+			ProposerSlashings: []*phase0.ProposerSlashing{},
+			AttesterSlashings: []*phase0.AttesterSlashing{},
+			Attestations:      []*phase0.Attestation{},
+			Deposits:          []*phase0.Deposit{},
+			VoluntaryExits:    []*phase0.SignedVoluntaryExit{},
+			SyncAggregate: &altair.SyncAggregate{
+				SyncCommitteeBits: bitfield.NewBitvector512(),
+			},
+			ExecutionPayload: &bellatrix.ExecutionPayload{
+				ExtraData:    []byte{},
+				Transactions: []bellatrix.Transaction{},
+			},
+		},
+	}
+	data, err := json.Marshal(block)
+	require.NoError(t, err)
+
+	var paths []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/eth/v1/node/version":
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"data":{"version":"Teku/v23.10.0"}}`))
+		case "/eth/v3/validator/blocks/0":
+			w.WriteHeader(http.StatusBadRequest)
+		case "/eth/v2/validator/blocks/1", "/eth/v3/validator/blocks/1":
+			paths = append(paths, r.URL.Path)
+			if r.URL.Path == "/eth/v3/validator/blocks/1" {
+				require.Equal(t, "0", r.URL.Query().Get("builder_boost_factor"))
+				w.Header().Set("Eth-Execution-Payload-Blinded", "false")
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set("Eth-Consensus-Version", "bellatrix")
+			_, _ = w.Write([]byte(fmt.Sprintf(`{"version":"bellatrix","data":%s}`, string(data))))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	base, err := url.Parse(srv.URL)
+	require.NoError(t, err)
+	s := &Service{
+		log:     zerolog.Nop(),
+		base:    base,
+		address: srv.URL,
+		client:  srv.Client(),
+		timeout: time.Second,
+	}
+
+	// Before the node has been probed the v2 endpoint is used.
+	_, err = s.Proposal(ctx, &api.ProposalOpts{Slot: 1})
+	require.NoError(t, err)
+
+	_, err = s.Capabilities(ctx)
+	require.NoError(t, err)
+	require.True(t, s.hasCapability(api.CapabilityProposalV3))
+	_, err = s.Proposal(ctx, &api.ProposalOpts{Slot: 1})
+	require.NoError(t, err)
+
+	require.Equal(t, []string{"/eth/v2/validator/blocks/1", "/eth/v3/validator/blocks/1"}, paths)
+}
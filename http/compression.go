@@ -0,0 +1,214 @@
+// Copyright © 2023 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package http
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/pkg/errors"
+)
+
+// acceptEncodings are the encodings that we accept for compressed responses, in order of preference.
+const acceptEncodings = "zstd, gzip;q=0.9, deflate;q=0.8"
+
+// compressionTransport is an HTTP transport that negotiates compressed responses
+// and optionally compresses large request bodies.
+type compressionTransport struct {
+	next http.RoundTripper
+	// negotiate is true if compressed responses should be requested.
+	negotiate bool
+	// requestThreshold is the size above which request bodies are compressed.
+	// A value of 0 disables compression of request bodies.
+	requestThreshold int64
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *compressionTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.negotiate && req.Header.Get("Accept-Encoding") == "" {
+		req = req.Clone(req.Context())
+		req.Header.Set("Accept-Encoding", acceptEncodings)
+	}
+
+	if t.requestThreshold > 0 && req.Body != nil && req.ContentLength > t.requestThreshold {
+		var err error
+		req, err = compressRequest(req)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if t.negotiate {
+		if err := decompressResponse(resp); err != nil {
+			resp.Body.Close()
+
+			return nil, err
+		}
+	}
+
+	return resp, nil
+}
+
+// compressRequest returns a copy of the request with a gzip-compressed body.
+func compressRequest(req *http.Request) (*http.Request, error) {
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read request body")
+	}
+	req.Body.Close()
+
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	if _, err := writer.Write(body); err != nil {
+		return nil, errors.Wrap(err, "failed to compress request body")
+	}
+	if err := writer.Close(); err != nil {
+		return nil, errors.Wrap(err, "failed to compress request body")
+	}
+	compressed := buf.Bytes()
+	monitorCompression("request", "gzip", len(compressed), len(body))
+
+	req = req.Clone(req.Context())
+	req.Header.Set("Content-Encoding", "gzip")
+	req.ContentLength = int64(len(compressed))
+	req.Body = io.NopCloser(bytes.NewReader(compressed))
+	req.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(compressed)), nil
+	}
+
+	return req, nil
+}
+
+// decompressResponse replaces the body of a compressed response with a decompressing reader.
+func decompressResponse(resp *http.Response) error {
+	encoding := strings.ToLower(strings.TrimSpace(resp.Header.Get("Content-Encoding")))
+	if encoding == "" || encoding == "identity" {
+		return nil
+	}
+	if !hasBody(resp) {
+		// Nothing to decompress, and decoders fail on an empty stream.
+		return nil
+	}
+
+	wire := &countingReader{r: resp.Body}
+	var decoder io.ReadCloser
+	switch encoding {
+	case "gzip":
+		reader, err := gzip.NewReader(wire)
+		if err != nil {
+			return errors.Wrap(err, "failed to create gzip reader")
+		}
+		decoder = reader
+	case "deflate":
+		reader, err := zlib.NewReader(wire)
+		if err != nil {
+			return errors.Wrap(err, "failed to create deflate reader")
+		}
+		decoder = reader
+	case "zstd":
+		reader, err := zstd.NewReader(wire, zstd.WithDecoderConcurrency(1))
+		if err != nil {
+			return errors.Wrap(err, "failed to create zstd reader")
+		}
+		decoder = reader.IOReadCloser()
+	default:
+		return fmt.Errorf("unsupported content encoding %s", encoding)
+	}
+
+	resp.Body = &decompressingReader{
+		decoder:  decoder,
+		body:     resp.Body,
+		wire:     wire,
+		encoding: encoding,
+	}
+	// The content length refers to the compressed body, so is no longer valid.
+	resp.Header.Del("Content-Encoding")
+	resp.Header.Del("Content-Length")
+	resp.ContentLength = -1
+	resp.Uncompressed = true
+
+	return nil
+}
+
+// hasBody returns true if the response can carry a body.
+func hasBody(resp *http.Response) bool {
+	if resp.ContentLength == 0 || resp.Body == nil || resp.Body == http.NoBody {
+		return false
+	}
+	if resp.StatusCode == http.StatusNoContent || resp.StatusCode == http.StatusNotModified {
+		return false
+	}
+	if resp.Request != nil && resp.Request.Method == http.MethodHead {
+		return false
+	}
+
+	return true
+}
+
+// countingReader counts the number of bytes read through it.
+type countingReader struct {
+	r    io.Reader
+	read int64
+}
+
+// Read implements io.Reader.
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.read += int64(n)
+
+	return n, err
+}
+
+// decompressingReader decompresses a response body, recording the
+// compression achieved when it is closed.
+type decompressingReader struct {
+	decoder  io.ReadCloser
+	body     io.ReadCloser
+	wire     *countingReader
+	encoding string
+	read     int64
+	closed   bool
+}
+
+// Read implements io.Reader.
+func (d *decompressingReader) Read(p []byte) (int, error) {
+	n, err := d.decoder.Read(p)
+	d.read += int64(n)
+
+	return n, err
+}
+
+// Close implements io.Closer.
+func (d *decompressingReader) Close() error {
+	if d.closed {
+		return nil
+	}
+	d.closed = true
+
+	monitorCompression("response", d.encoding, int(d.wire.read), int(d.read))
+	d.decoder.Close()
+
+	return d.body.Close()
+}
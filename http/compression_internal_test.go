@@ -0,0 +1,150 @@
+// Copyright © 2023 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package http
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompressionTransport(t *testing.T) {
+	body := bytes.Repeat([]byte(`{"index":"1","balance":"32000000000"},`), 1000)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			require.Equal(t, "gzip", r.Header.Get("Content-Encoding"))
+			reader, err := gzip.NewReader(r.Body)
+			require.NoError(t, err)
+			data, err := io.ReadAll(reader)
+			require.NoError(t, err)
+			require.Equal(t, body, data)
+			w.WriteHeader(http.StatusOK)
+
+			return
+		}
+
+		encoding := r.URL.Query().Get("encoding")
+		require.Contains(t, r.Header.Get("Accept-Encoding"), encoding)
+		var buf bytes.Buffer
+		var writer io.WriteCloser
+		switch encoding {
+		case "gzip":
+			writer = gzip.NewWriter(&buf)
+		case "deflate":
+			writer = zlib.NewWriter(&buf)
+		case "zstd":
+			var err error
+			writer, err = zstd.NewWriter(&buf)
+			require.NoError(t, err)
+		}
+		_, err := writer.Write(body)
+		require.NoError(t, err)
+		require.NoError(t, writer.Close())
+		w.Header().Set("Content-Encoding", encoding)
+		_, _ = w.Write(buf.Bytes())
+	}))
+	defer srv.Close()
+
+	client := &http.Client{
+		Transport: &compressionTransport{
+			next:             http.DefaultTransport,
+			negotiate:        true,
+			requestThreshold: 1024,
+		},
+	}
+
+	for _, encoding := range []string{"gzip", "deflate", "zstd"} {
+		t.Run(encoding, func(t *testing.T) {
+			resp, err := client.Get(srv.URL + "?encoding=" + encoding)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+			require.Equal(t, int64(-1), resp.ContentLength)
+			require.Empty(t, resp.Header.Get("Content-Encoding"))
+			data, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+			require.Equal(t, body, data)
+		})
+	}
+
+	t.Run("Post", func(t *testing.T) {
+		resp, err := client.Post(srv.URL, "application/json", bytes.NewReader(body))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+}
+
+func TestCompressionEmptyBody(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Encoding", "gzip")
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	client := &http.Client{
+		Transport: &compressionTransport{
+			next:      http.DefaultTransport,
+			negotiate: true,
+		},
+	}
+
+	for _, method := range []string{http.MethodGet, http.MethodHead} {
+		t.Run(method, func(t *testing.T) {
+			req, err := http.NewRequest(method, srv.URL, nil)
+			require.NoError(t, err)
+			resp, err := client.Do(req)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+			require.Equal(t, http.StatusNoContent, resp.StatusCode)
+		})
+	}
+}
+
+func TestDecompressingReaderDoubleClose(t *testing.T) {
+	metric := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "test_compressed_bytes"}, []string{"direction", "encoding"})
+	previous := compressedBytesMetric
+	compressedBytesMetric = metric
+	defer func() { compressedBytesMetric = previous }()
+
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	_, err := writer.Write([]byte("data"))
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+	compressed := buf.Len()
+
+	resp := &http.Response{
+		StatusCode:    http.StatusOK,
+		Header:        http.Header{"Content-Encoding": []string{"gzip"}},
+		Body:          io.NopCloser(&buf),
+		ContentLength: int64(compressed),
+	}
+	require.NoError(t, decompressResponse(resp))
+	_, err = io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	require.NoError(t, resp.Body.Close())
+
+	require.Equal(t, float64(compressed), testutil.ToFloat64(metric.WithLabelValues("response", "gzip")))
+}
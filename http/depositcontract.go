@@ -45,7 +45,7 @@ func (s *Service) DepositContract(ctx context.Context) (*api.Response[*apiv1.Dep
 	}
 
 	// Up to us to fetch the information.
-	httpResponse, err := s.get2(ctx, "/eth/v1/config/deposit_contract")
+	httpResponse, err := s.get2(ctx, "DepositContract", "/eth/v1/config/deposit_contract", nil)
 	if err != nil {
 		return nil, err
 	}
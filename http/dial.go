@@ -0,0 +1,65 @@
+// Copyright © 2023 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package http
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// DialContextFunc is a function that creates network connections.
+type DialContextFunc func(ctx context.Context, network string, address string) (net.Conn, error)
+
+// unixScheme is the prefix for addresses that refer to a Unix domain socket.
+const unixScheme = "unix://"
+
+// parseAddress parses the address of the beacon node, returning the base URL for requests
+// and, if the address refers to a Unix domain socket, the path to the socket.
+func parseAddress(address string) (*url.URL, string, error) {
+	socket := ""
+	if strings.HasPrefix(address, unixScheme) {
+		socket = strings.TrimPrefix(address, unixScheme)
+		if socket == "" {
+			return nil, "", errors.New("no socket path specified")
+		}
+		// Requests are sent over the socket, so the host is only used for the Host header.
+		address = "http://localhost"
+	}
+
+	if !strings.HasPrefix(address, "http") {
+		address = fmt.Sprintf("http://%s", address)
+	}
+	if !strings.HasSuffix(address, "/") {
+		address = fmt.Sprintf("%s/", address)
+	}
+	base, err := url.Parse(address)
+	if err != nil {
+		return nil, "", errors.Wrap(err, "invalid URL")
+	}
+
+	return base, socket, nil
+}
+
+// unixDialContext returns a function that connects to the given Unix domain socket using
+// the supplied dial function, regardless of the network and address requested.
+func unixDialContext(dial DialContextFunc, socket string) DialContextFunc {
+	return func(ctx context.Context, _ string, _ string) (net.Conn, error) {
+		return dial(ctx, "unix", socket)
+	}
+}
@@ -0,0 +1,115 @@
+// Copyright © 2023 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package http
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAddress(t *testing.T) {
+	tests := []struct {
+		name    string
+		address string
+		base    string
+		socket  string
+		err     string
+	}{
+		{
+			name:    "HostPort",
+			address: "localhost:5052",
+			base:    "http://localhost:5052/",
+		},
+		{
+			name:    "HTTPS",
+			address: "https://node.example.com/path",
+			base:    "https://node.example.com/path/",
+		},
+		{
+			name:    "Unix",
+			address: "unix:///var/run/beacon.sock",
+			base:    "http://localhost/",
+			socket:  "/var/run/beacon.sock",
+		},
+		{
+			name:    "UnixNoPath",
+			address: "unix://",
+			err:     "no socket path specified",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			base, socket, err := parseAddress(test.address)
+			if test.err != "" {
+				require.EqualError(t, err, test.err)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, test.base, base.String())
+				require.Equal(t, test.socket, socket)
+			}
+		})
+	}
+}
+
+func TestUnixSocket(t *testing.T) {
+	ctx := context.Background()
+
+	socket := filepath.Join(t.TempDir(), "beacon.sock")
+	listener, err := net.Listen("unix", socket)
+	require.NoError(t, err)
+	srv := &http.Server{
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"data":{"version":"test/v1.0.0"}}`))
+		}),
+	}
+	go func() {
+		_ = srv.Serve(listener)
+	}()
+	defer srv.Close()
+
+	base, path, err := parseAddress("unix://" + socket)
+	require.NoError(t, err)
+
+	dialed := ""
+	dialContext := unixDialContext(func(ctx context.Context, network string, address string) (net.Conn, error) {
+		dialed = address
+
+		return (&net.Dialer{}).DialContext(ctx, network, address)
+	}, path)
+	s := &Service{
+		log:     zerolog.Nop(),
+		base:    base,
+		address: "unix://" + socket,
+		client: &http.Client{
+			Transport: &http.Transport{
+				DialContext: dialContext,
+			},
+		},
+		timeout: time.Second,
+	}
+
+	response, err := s.NodeVersion(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "test/v1.0.0", response.Data)
+	require.Equal(t, socket, dialed)
+}
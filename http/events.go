@@ -34,6 +34,8 @@ import (
 )
 
 // Events feeds requested events with the given topics to the supplied handler.
+// Events are fed until the context is done, or the context with which the service was
+// created is done.
 func (s *Service) Events(ctx context.Context, topics []string, handler client.EventHandlerFunc) error {
 	// #nosec G404
 	log := s.log.With().Str("id", fmt.Sprintf("%02x", rand.Int31())).Str("address", s.address).Logger()
@@ -58,14 +60,29 @@ func (s *Service) Events(ctx context.Context, topics []string, handler client.Ev
 	log.Trace().Str("url", url).Msg("GET request to events stream")
 
 	client := sse.NewClient(url)
-	client.Connection.Transport = &http.Transport{
-		Dial: (&net.Dialer{
+	dialContext := s.dialContext
+	if dialContext == nil {
+		dialContext = (&net.Dialer{
 			Timeout:   2 * time.Second,
 			KeepAlive: 2 * time.Second,
-		}).Dial,
+		}).DialContext
+	}
+	client.Connection.Transport = &http.Transport{
+		DialContext: dialContext,
 	}
 
+	// Stop the stream when the service is stopped, as well as when the caller is done.
+	ctx, cancel := context.WithCancel(ctx)
+	go func() {
+		select {
+		case <-s.done:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
 	go func() {
+		defer cancel()
 		for {
 			select {
 			case <-time.After(time.Second):
@@ -16,6 +16,9 @@ package http
 import (
 	"bytes"
 	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"os"
 	"testing"
 	"time"
@@ -155,3 +158,41 @@ func TestEventHandler(t *testing.T) {
 		})
 	}
 }
+
+func TestEventsServiceDone(t *testing.T) {
+	connected := make(chan struct{}, 1)
+	disconnected := make(chan struct{}, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.WriteHeader(http.StatusOK)
+		w.(http.Flusher).Flush()
+		connected <- struct{}{}
+		<-r.Context().Done()
+		disconnected <- struct{}{}
+	}))
+	defer srv.Close()
+
+	base, err := url.Parse(srv.URL)
+	require.NoError(t, err)
+	done := make(chan struct{})
+	s := &Service{
+		log:     zerolog.Nop(),
+		base:    base,
+		address: srv.URL,
+		done:    done,
+	}
+
+	// The stream is stopped when the service is, even though the caller's context is not done.
+	require.NoError(t, s.Events(context.Background(), []string{"head"}, func(*api.Event) {}))
+	select {
+	case <-connected:
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "events stream not connected")
+	}
+	close(done)
+	select {
+	case <-disconnected:
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "events stream not stopped")
+	}
+}
@@ -33,8 +33,11 @@ func (s *Service) Finality(ctx context.Context,
 	if opts == nil {
 		return nil, errors.New("no options specified")
 	}
+	if err := opts.State.Validate(); err != nil {
+		return nil, err
+	}
 
-	httpResponse, err := s.get2(ctx, fmt.Sprintf("/eth/v1/beacon/states/%s/finality_checkpoints", opts.State))
+	httpResponse, err := s.get2(ctx, "Finality", fmt.Sprintf("/eth/v1/beacon/states/%s/finality_checkpoints", opts.State), &opts.Common)
 	if err != nil {
 		return nil, err
 	}
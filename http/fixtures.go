@@ -0,0 +1,299 @@
+// Copyright © 2023 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+)
+
+// fixtureRequestHeaders are the request headers that are recorded in fixtures and
+// used to match requests on replay.  Other headers, such as those supplied with
+// WithExtraHeaders, are not recorded as they may contain credentials.
+var fixtureRequestHeaders = []string{"Accept", "Content-Type", "Eth-Consensus-Version"}
+
+// Fixture is a recorded request to a beacon node and its response.
+type Fixture struct {
+	Method string `json:"method"`
+	// URL is the path and query of the request, without the scheme and host.
+	URL            string            `json:"url"`
+	RequestHeaders map[string]string `json:"request_headers,omitempty"`
+	RequestBody    *FixtureBody      `json:"request_body,omitempty"`
+	StatusCode     int               `json:"status_code"`
+	Headers        http.Header       `json:"headers,omitempty"`
+	Body           *FixtureBody      `json:"body,omitempty"`
+}
+
+// FixtureBody is the body of a recorded request or response.
+// Text bodies are held as text, to keep fixtures readable; binary bodies such
+// as SSZ are held as base64.
+type FixtureBody struct {
+	Text   string `json:"text,omitempty"`
+	Binary []byte `json:"binary,omitempty"`
+}
+
+// newFixtureBody creates a fixture body from data, returning nil if there is no data.
+func newFixtureBody(data []byte) *FixtureBody {
+	switch {
+	case len(data) == 0:
+		return nil
+	case utf8.Valid(data):
+		return &FixtureBody{Text: string(data)}
+	default:
+		return &FixtureBody{Binary: data}
+	}
+}
+
+// Bytes returns the data of the body.
+func (b *FixtureBody) Bytes() []byte {
+	switch {
+	case b == nil:
+		return nil
+	case b.Binary != nil:
+		return b.Binary
+	default:
+		return []byte(b.Text)
+	}
+}
+
+// key returns the key used to match a request to a fixture.
+func (f *Fixture) key() string {
+	return fixtureKey(f.Method, f.URL, f.RequestHeaders, f.RequestBody.Bytes())
+}
+
+func fixtureKey(method string, url string, headers map[string]string, body []byte) string {
+	key := fmt.Sprintf("%s %s", method, url)
+	for _, header := range fixtureRequestHeaders {
+		key = fmt.Sprintf("%s\n%s: %s", key, header, headers[header])
+	}
+
+	return fmt.Sprintf("%s\n\n%s", key, body)
+}
+
+// LoadFixtures loads fixtures from a file written by SaveFixtures.
+func LoadFixtures(path string) ([]*Fixture, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read fixtures")
+	}
+
+	var fixtures []*Fixture
+	if err := json.Unmarshal(data, &fixtures); err != nil {
+		return nil, errors.Wrap(err, "failed to parse fixtures")
+	}
+
+	return fixtures, nil
+}
+
+// SaveFixtures saves fixtures to a file.
+func SaveFixtures(path string, fixtures []*Fixture) error {
+	data, err := json.MarshalIndent(fixtures, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal fixtures")
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return errors.Wrap(err, "failed to write fixtures")
+	}
+
+	return nil
+}
+
+// RecordingTransport is an HTTP transport that records each request and its
+// response as a fixture, for later replay with ReplayTransport.
+type RecordingTransport struct {
+	next     http.RoundTripper
+	mutex    sync.Mutex
+	fixtures []*Fixture
+}
+
+// NewRecordingTransport creates a transport that sends requests to the next
+// transport, recording them as it does so.  If next is nil then
+// http.DefaultTransport is used.
+func NewRecordingTransport(next http.RoundTripper) *RecordingTransport {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	return &RecordingTransport{
+		next: next,
+	}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *RecordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	fixture := &Fixture{
+		Method:         req.Method,
+		URL:            req.URL.RequestURI(),
+		RequestHeaders: requestFixtureHeaders(req),
+	}
+
+	if req.Body != nil {
+		body, err := io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read request body")
+		}
+		fixture.RequestBody = newFixtureBody(body)
+		req = req.Clone(req.Context())
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read response body")
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	fixture.StatusCode = resp.StatusCode
+	fixture.Headers = resp.Header.Clone()
+	fixture.Body = newFixtureBody(body)
+
+	t.mutex.Lock()
+	t.fixtures = append(t.fixtures, fixture)
+	t.mutex.Unlock()
+
+	return resp, nil
+}
+
+// Fixtures returns the fixtures recorded so far, in the order that their requests were made.
+func (t *RecordingTransport) Fixtures() []*Fixture {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	fixtures := make([]*Fixture, len(t.fixtures))
+	copy(fixtures, t.fixtures)
+
+	return fixtures
+}
+
+// Save saves the fixtures recorded so far to a file.
+func (t *RecordingTransport) Save(path string) error {
+	return SaveFixtures(path, t.Fixtures())
+}
+
+// ReplayTransport is an HTTP transport that serves responses from recorded fixtures
+// rather than contacting a beacon node.
+// Requests are matched to fixtures by method, path, query, the headers that affect
+// the response and body.  Repeated matching requests are served the matching fixtures
+// in the order they were recorded, with the last being repeated once all have been served.
+// Requests that do not match any fixture receive a 404 response.
+type ReplayTransport struct {
+	mutex     sync.Mutex
+	fixtures  map[string][]*Fixture
+	served    map[string]int
+	unmatched []string
+}
+
+// NewReplayTransport creates a transport that replays the supplied fixtures.
+func NewReplayTransport(fixtures []*Fixture) *ReplayTransport {
+	t := &ReplayTransport{
+		fixtures: make(map[string][]*Fixture),
+		served:   make(map[string]int),
+	}
+	for _, fixture := range fixtures {
+		key := fixture.key()
+		t.fixtures[key] = append(t.fixtures[key], fixture)
+	}
+
+	return t
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *ReplayTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read request body")
+		}
+	}
+	key := fixtureKey(req.Method, req.URL.RequestURI(), requestFixtureHeaders(req), body)
+
+	t.mutex.Lock()
+	fixtures, exists := t.fixtures[key]
+	if !exists {
+		t.unmatched = append(t.unmatched, fmt.Sprintf("%s %s", req.Method, req.URL.RequestURI()))
+		t.mutex.Unlock()
+
+		return replayResponse(req, http.StatusNotFound, http.Header{"Content-Type": []string{"application/json"}},
+			[]byte(`{"code":404,"message":"no fixture for request"}`)), nil
+	}
+	fixture := fixtures[t.served[key]]
+	if t.served[key] < len(fixtures)-1 {
+		t.served[key]++
+	}
+	t.mutex.Unlock()
+
+	return replayResponse(req, fixture.StatusCode, fixture.Headers.Clone(), fixture.Body.Bytes()), nil
+}
+
+// Unmatched returns the requests that did not match any fixture.
+func (t *ReplayTransport) Unmatched() []string {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	unmatched := make([]string, len(t.unmatched))
+	copy(unmatched, t.unmatched)
+
+	return unmatched
+}
+
+// replayResponse creates a response for a replayed request.
+func replayResponse(req *http.Request, statusCode int, headers http.Header, body []byte) *http.Response {
+	if headers == nil {
+		headers = make(http.Header)
+	}
+	headers.Set("Content-Length", strconv.Itoa(len(body)))
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", statusCode, http.StatusText(statusCode)),
+		StatusCode:    statusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        headers,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
+
+// requestFixtureHeaders returns the headers of a request that are recorded in fixtures.
+func requestFixtureHeaders(req *http.Request) map[string]string {
+	headers := make(map[string]string)
+	for _, header := range fixtureRequestHeaders {
+		if value := req.Header.Get(header); value != "" {
+			headers[header] = value
+		}
+	}
+
+	return headers
+}
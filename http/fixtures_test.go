@@ -0,0 +1,94 @@
+// Copyright © 2023 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package http_test
+
+import (
+	"context"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	client "github.com/attestantio/go-eth2-client"
+	"github.com/attestantio/go-eth2-client/api"
+	"github.com/attestantio/go-eth2-client/http"
+	"github.com/attestantio/go-eth2-client/mock"
+	"github.com/attestantio/go-eth2-client/server"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecordReplay(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Record against a server backed by the mock.
+	mockClient, err := mock.New(context.Background())
+	require.NoError(t, err)
+	s, err := server.New(ctx,
+		server.WithLogLevel(zerolog.Disabled),
+		server.WithService(mockClient),
+	)
+	require.NoError(t, err)
+	srv := httptest.NewServer(s)
+
+	recorder := http.NewRecordingTransport(nil)
+	recording, err := http.New(ctx,
+		http.WithLogLevel(zerolog.Disabled),
+		http.WithAddress(srv.URL),
+		http.WithTransport(recorder),
+	)
+	require.NoError(t, err)
+	recordedBlock, err := recording.(client.SignedBeaconBlockProvider).SignedBeaconBlock(ctx, &api.SignedBeaconBlockOpts{Block: "head"})
+	require.NoError(t, err)
+	recordedDuties, err := recording.(client.AttesterDutiesProvider).AttesterDuties(ctx, &api.AttesterDutiesOpts{
+		Epoch:   2,
+		Indices: []phase0.ValidatorIndex{1, 2, 3},
+	})
+	require.NoError(t, err)
+	srv.Close()
+
+	path := filepath.Join(t.TempDir(), "fixtures.json")
+	require.NoError(t, recorder.Save(path))
+	fixtures, err := http.LoadFixtures(path)
+	require.NoError(t, err)
+	require.Equal(t, recorder.Fixtures(), fixtures)
+
+	// Replay without the server.
+	replayer := http.NewReplayTransport(fixtures)
+	replaying, err := http.New(ctx,
+		http.WithLogLevel(zerolog.Disabled),
+		http.WithAddress("http://replay.invalid"),
+		http.WithTransport(replayer),
+	)
+	require.NoError(t, err)
+	replayedBlock, err := replaying.(client.SignedBeaconBlockProvider).SignedBeaconBlock(ctx, &api.SignedBeaconBlockOpts{Block: "head"})
+	require.NoError(t, err)
+	require.Equal(t, recordedBlock.Data, replayedBlock.Data)
+	replayedDuties, err := replaying.(client.AttesterDutiesProvider).AttesterDuties(ctx, &api.AttesterDutiesOpts{
+		Epoch:   2,
+		Indices: []phase0.ValidatorIndex{1, 2, 3},
+	})
+	require.NoError(t, err)
+	require.Equal(t, recordedDuties.Data, replayedDuties.Data)
+	require.Empty(t, replayer.Unmatched())
+
+	// A request that was not recorded is not found.
+	_, err = replaying.(client.AttesterDutiesProvider).AttesterDuties(ctx, &api.AttesterDutiesOpts{
+		Epoch:   3,
+		Indices: []phase0.ValidatorIndex{1},
+	})
+	require.ErrorContains(t, err, "404")
+	require.Equal(t, []string{"POST /eth/v1/validator/duties/attester/3"}, replayer.Unmatched())
+}
@@ -33,11 +33,11 @@ func (s *Service) Fork(ctx context.Context,
 	if opts == nil {
 		return nil, errors.New("no options specified")
 	}
-	if opts.State == "" {
-		return nil, errors.New("no state specified")
+	if err := opts.State.Validate(); err != nil {
+		return nil, err
 	}
 
-	res, err := s.get2(ctx, fmt.Sprintf("/eth/v1/beacon/states/%s/fork", opts.State))
+	res, err := s.get2(ctx, "Fork", fmt.Sprintf("/eth/v1/beacon/states/%s/fork", opts.State), &opts.Common)
 	if err != nil {
 		return nil, err
 	}
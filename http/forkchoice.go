@@ -25,7 +25,7 @@ import (
 
 // ForkChoice fetches all current fork choice context.
 func (s *Service) ForkChoice(ctx context.Context) (*api.Response[*apiv1.ForkChoice], error) {
-	httpResponse, err := s.get2(ctx, "/eth/v1/debug/fork_choice")
+	httpResponse, err := s.get2(ctx, "ForkChoice", "/eth/v1/debug/fork_choice", nil)
 	if err != nil {
 		return nil, err
 	}
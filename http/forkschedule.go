@@ -45,7 +45,7 @@ func (s *Service) ForkSchedule(ctx context.Context) (*api.Response[[]*phase0.For
 	}
 
 	// Up to us to fetch the information.
-	httpResponse, err := s.get2(ctx, "/eth/v1/config/fork_schedule")
+	httpResponse, err := s.get2(ctx, "ForkSchedule", "/eth/v1/config/fork_schedule", nil)
 	if err != nil {
 		return nil, err
 	}
@@ -50,7 +50,7 @@ func (s *Service) Genesis(ctx context.Context) (*api.Response[*apiv1.Genesis], e
 	}
 
 	// Up to us to fetch the information.
-	respBodyReader, err := s.get(ctx, "/eth/v1/beacon/genesis")
+	respBodyReader, err := s.get(ctx, "Genesis", "/eth/v1/beacon/genesis", nil)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to request genesis")
 	}
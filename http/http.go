@@ -23,6 +23,7 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
+	"time"
 
 	"github.com/attestantio/go-eth2-client/api"
 	"github.com/attestantio/go-eth2-client/spec"
@@ -34,7 +35,7 @@ import (
 
 // get sends an HTTP get request and returns the body.
 // If the response from the server is a 404 this will return nil for both the reader and the error.
-func (s *Service) get(ctx context.Context, endpoint string) (io.Reader, error) {
+func (s *Service) get(ctx context.Context, call string, endpoint string, opts *api.CommonOpts) (io.Reader, error) {
 	// #nosec G404
 	log := s.log.With().Str("id", fmt.Sprintf("%02x", rand.Int31())).Str("address", s.address).Str("endpoint", endpoint).Logger()
 	log.Trace().Msg("GET request")
@@ -44,7 +45,10 @@ func (s *Service) get(ctx context.Context, endpoint string) (io.Reader, error) {
 		return nil, errors.Wrap(err, "invalid endpoint")
 	}
 
-	opCtx, cancel := context.WithTimeout(ctx, s.timeout)
+	opCtx, cancel, err := s.requestContext(ctx, call, opts)
+	if err != nil {
+		return nil, err
+	}
 	req, err := http.NewRequestWithContext(opCtx, http.MethodGet, url.String(), nil)
 	if err != nil {
 		cancel()
@@ -96,7 +100,7 @@ func (s *Service) get(ctx context.Context, endpoint string) (io.Reader, error) {
 }
 
 // post sends an HTTP post request and returns the body.
-func (s *Service) post(ctx context.Context, endpoint string, body io.Reader) (io.Reader, error) {
+func (s *Service) post(ctx context.Context, call string, endpoint string, opts *api.CommonOpts, body io.Reader) (io.Reader, error) {
 	// #nosec G404
 	log := s.log.With().Str("id", fmt.Sprintf("%02x", rand.Int31())).Str("address", s.address).Str("endpoint", endpoint).Logger()
 	if e := log.Trace(); e.Enabled() {
@@ -114,7 +118,10 @@ func (s *Service) post(ctx context.Context, endpoint string, body io.Reader) (io
 		return nil, errors.Wrap(err, "invalid endpoint")
 	}
 
-	opCtx, cancel := context.WithTimeout(ctx, s.timeout)
+	opCtx, cancel, err := s.requestContext(ctx, call, opts)
+	if err != nil {
+		return nil, err
+	}
 	req, err := http.NewRequestWithContext(opCtx, http.MethodPost, url.String(), body)
 	if err != nil {
 		cancel()
@@ -166,7 +173,9 @@ func (s *Service) post(ctx context.Context, endpoint string, body io.Reader) (io
 //
 //nolint:unparam
 func (s *Service) post2(ctx context.Context,
+	call string,
 	endpoint string,
+	opts *api.CommonOpts,
 	body io.Reader,
 	contentType ContentType,
 	headers map[string]string,
@@ -191,7 +200,10 @@ func (s *Service) post2(ctx context.Context,
 		return nil, errors.Wrap(err, "invalid endpoint")
 	}
 
-	opCtx, cancel := context.WithTimeout(ctx, s.timeout)
+	opCtx, cancel, err := s.requestContext(ctx, call, opts)
+	if err != nil {
+		return nil, err
+	}
 	req, err := http.NewRequestWithContext(opCtx, http.MethodPost, url.String(), body)
 	if err != nil {
 		cancel()
@@ -243,6 +255,53 @@ func (s *Service) post2(ctx context.Context,
 	return bytes.NewReader(data), nil
 }
 
+// requestContext returns the context for a request, applying the call's timeout and
+// waiting for any rate or concurrency limits to allow the request to proceed.
+// The returned cancel function must be called once the request has completed.
+func (s *Service) requestContext(ctx context.Context,
+	call string,
+	opts *api.CommonOpts,
+) (
+	context.Context,
+	context.CancelFunc,
+	error,
+) {
+	if err := s.checkSupported(call); err != nil {
+		return nil, nil, err
+	}
+
+	opCtx, cancel := context.WithTimeout(ctx, s.callTimeout(call, opts))
+	if s.limiter == nil {
+		return opCtx, cancel, nil
+	}
+
+	release, err := s.limiter.acquire(opCtx, call)
+	if err != nil {
+		cancel()
+
+		return nil, nil, errors.Wrap(err, "request not allowed by limits")
+	}
+
+	return opCtx, func() {
+		release()
+		cancel()
+	}, nil
+}
+
+// callTimeout returns the timeout for a call.
+// A timeout supplied in the options for the call takes precedence, followed by a
+// timeout configured for the call's endpoint, followed by the service timeout.
+func (s *Service) callTimeout(call string, opts *api.CommonOpts) time.Duration {
+	if opts != nil && opts.Timeout > 0 {
+		return opts.Timeout
+	}
+	if timeout, exists := s.endpointTimeouts[call]; exists {
+		return timeout
+	}
+
+	return s.timeout
+}
+
 func (s *Service) addExtraHeaders(req *http.Request) {
 	for k, v := range s.extraHeaders {
 		req.Header.Add(k, v)
@@ -262,12 +321,111 @@ type httpResponse struct {
 	body             []byte
 }
 
+// httpStreamResponse is an HTTP response for which the body has not been read.
+// The caller is responsible for closing the body once it has finished with it.
+type httpStreamResponse struct {
+	statusCode       int
+	contentType      ContentType
+	contentLength    int64
+	headers          map[string]string
+	consensusVersion spec.DataVersion
+	body             io.ReadCloser
+}
+
+// cancelOnCloseReader cancels the request context when the body is closed.
+type cancelOnCloseReader struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+// Close closes the underlying reader and cancels the request context.
+func (r *cancelOnCloseReader) Close() error {
+	defer r.cancel()
+
+	return r.ReadCloser.Close()
+}
+
 // get2 sends an HTTP get request and returns the body.
 // If the response from the server is a 404 this will return nil for both the reader and the error.
-func (s *Service) get2(ctx context.Context, endpoint string) (*httpResponse, error) {
+func (s *Service) get2(ctx context.Context, call string, endpoint string, opts *api.CommonOpts) (*httpResponse, error) {
 	ctx, span := otel.Tracer("attestantio.go-eth2-client.http").Start(ctx, "get2")
 	defer span.End()
 
+	stream, err := s.getStream(ctx, call, endpoint, opts, s.acceptHeader(call))
+	if err != nil {
+		span.RecordError(err)
+
+		return nil, err
+	}
+	defer stream.body.Close()
+
+	res := &httpResponse{
+		statusCode:       stream.statusCode,
+		contentType:      stream.contentType,
+		headers:          stream.headers,
+		consensusVersion: stream.consensusVersion,
+	}
+
+	if stream.statusCode == http.StatusNoContent {
+		// Nothing returned.  This is not considered an error.
+		span.AddEvent("Received empty response")
+
+		return res, nil
+	}
+
+	// Although it would be more efficient to keep the body as a Reader, that would
+	// require the calling function to be aware that it needs to clode the body
+	// once it is done with it.  To avoid that complexity, we read here and store the
+	// body as a byte array.  Callers that handle large responses should use
+	// getStream() instead.
+	res.body, err = readStreamBody(stream)
+	if err != nil {
+		span.RecordError(err)
+
+		return nil, err
+	}
+	span.SetAttributes(attribute.String("content-type", res.contentType.String()))
+
+	if res.consensusVersion == spec.DataVersionUnknown {
+		if err := populateConsensusVersionFromBody(res); err != nil {
+			return nil, errors.Wrap(err, "failed to parse consensus version")
+		}
+	}
+
+	return res, nil
+}
+
+// acceptHeader returns the value of the Accept header for requests that can
+// return either SSZ or JSON.
+func (s *Service) acceptHeader(call string) string {
+	if s.enforceJSON {
+		// JSON only.
+		return ContentTypeJSON.MediaType()
+	}
+	if capabilities := s.cachedCapabilities(); capabilities != nil && !capabilities.SupportsSSZ(call) {
+		// Node is known not to return SSZ for this call.
+		return ContentTypeJSON.MediaType()
+	}
+
+	// Prefer SSZ, JSON if not.
+	return "application/octet-stream;q=1,application/json;q=0.9"
+}
+
+// getStream sends an HTTP get request and returns the response without reading
+// the body, allowing large responses to be decoded or written out as they arrive.
+// The caller must close the body of the returned response.
+func (s *Service) getStream(ctx context.Context,
+	call string,
+	endpoint string,
+	opts *api.CommonOpts,
+	accept string,
+) (
+	*httpStreamResponse,
+	error,
+) {
+	ctx, span := otel.Tracer("attestantio.go-eth2-client.http").Start(ctx, "getStream")
+	defer span.End()
+
 	// #nosec G404
 	log := s.log.With().Str("id", fmt.Sprintf("%02x", rand.Int31())).Str("address", s.address).Str("endpoint", endpoint).Logger()
 	log.Trace().Msg("GET request")
@@ -277,8 +435,10 @@ func (s *Service) get2(ctx context.Context, endpoint string) (*httpResponse, err
 		return nil, errors.Wrap(err, "invalid endpoint")
 	}
 
-	opCtx, cancel := context.WithTimeout(ctx, s.timeout)
-	defer cancel()
+	opCtx, cancel, err := s.requestContext(ctx, call, opts)
+	if err != nil {
+		return nil, err
+	}
 	req, err := http.NewRequestWithContext(opCtx, http.MethodGet, url.String(), nil)
 	if err != nil {
 		cancel()
@@ -286,28 +446,27 @@ func (s *Service) get2(ctx context.Context, endpoint string) (*httpResponse, err
 		return nil, errors.Wrap(err, "failed to create GET request")
 	}
 	s.addExtraHeaders(req)
-	if s.enforceJSON {
-		// JSON only.
-		req.Header.Set("Accept", "application/json")
-	} else {
-		// Prefer SSZ, JSON if not.
-		req.Header.Set("Accept", "application/octet-stream;q=1,application/json;q=0.9")
-	}
+	req.Header.Set("Accept", accept)
 	span.AddEvent("Sending request")
 
 	resp, err := s.client.Do(req)
 	if err != nil {
+		cancel()
 		span.RecordError(errors.New("Request failed"))
 
 		return nil, errors.Wrap(err, "failed to call GET endpoint")
 	}
-	defer resp.Body.Close()
 	log = log.With().Int("status_code", resp.StatusCode).Logger()
 
-	res := &httpResponse{
-		statusCode: resp.StatusCode,
+	res := &httpStreamResponse{
+		statusCode:    resp.StatusCode,
+		contentLength: resp.ContentLength,
+		headers:       headersFromResponse(resp),
+		body: &cancelOnCloseReader{
+			ReadCloser: resp.Body,
+			cancel:     cancel,
+		},
 	}
-	populateHeaders(res, resp)
 
 	if resp.StatusCode == http.StatusNoContent {
 		// Nothing returned.  This is not considered an error.
@@ -317,97 +476,214 @@ func (s *Service) get2(ctx context.Context, endpoint string) (*httpResponse, err
 		return res, nil
 	}
 
-	// Although it would be more efficient to keep the body as a Reader, that would
-	// require the calling function to be aware that it needs to clode the body
-	// once it is done with it.  To avoid that complexity, we read here and store the
-	// body as a byte array.
-	res.body, err = io.ReadAll(resp.Body)
-	if err != nil {
-		span.RecordError(err)
-		log.Warn().Err(err).Msg("Failed to read body")
-
-		return nil, errors.Wrap(err, "failed to read body")
-	}
-
 	statusFamily := resp.StatusCode / 100
 	if statusFamily != 2 {
+		defer res.body.Close()
+		data, err := io.ReadAll(res.body)
+		if err != nil {
+			span.RecordError(err)
+			log.Warn().Err(err).Msg("Failed to read body")
+
+			return nil, errors.Wrap(err, "failed to read body")
+		}
 		span.SetStatus(codes.Error, fmt.Sprintf("Status code %d", resp.StatusCode))
-		trimmedResponse := bytes.ReplaceAll(bytes.ReplaceAll(res.body, []byte{0x0a}, []byte{}), []byte{0x0d}, []byte{})
+		trimmedResponse := bytes.ReplaceAll(bytes.ReplaceAll(data, []byte{0x0a}, []byte{}), []byte{0x0d}, []byte{})
 		log.Debug().Int("status_code", resp.StatusCode).RawJSON("response", trimmedResponse).Msg("GET failed")
 
 		return nil, &api.Error{
 			Method:     http.MethodGet,
 			StatusCode: resp.StatusCode,
 			Endpoint:   endpoint,
-			Data:       res.body,
+			Data:       data,
 		}
 	}
 
-	if err := populateContentType(res, resp); err != nil {
+	res.contentType, err = contentTypeFromResponse(resp)
+	if err != nil {
 		// For now, assume that unknown type is JSON.
 		log.Debug().Err(err).Msg("Failed to obtain content type; assuming JSON")
 		res.contentType = ContentTypeJSON
 	}
 	span.SetAttributes(attribute.String("content-type", res.contentType.String()))
+	if strings.Contains(accept, ContentTypeSSZ.MediaType()) {
+		s.recordResponseFormat(call, res.contentType)
+	}
+
+	res.consensusVersion, err = consensusVersionFromResponse(resp)
+	if err != nil {
+		res.body.Close()
 
-	if err := populateConsensusVersion(res, resp); err != nil {
 		return nil, errors.Wrap(err, "failed to parse consensus version")
 	}
 
 	return res, nil
 }
 
-func populateConsensusVersion(res *httpResponse, resp *http.Response) error {
-	res.consensusVersion = spec.DataVersionUnknown
-	respConsensusVersions, exists := resp.Header["Eth-Consensus-Version"]
-	if !exists {
-		// No consensus version supplied in response; obtain it from the body if possible.
-		if res.contentType != ContentTypeJSON {
-			// Not present here either.  Many responses do not provide this information, so assume
-			// this is one of them.
-			return nil
-		}
-		var metadata responseMetadata
-		if err := json.Unmarshal(res.body, &metadata); err != nil {
-			return errors.Wrap(err, "no consensus version header and failed to parse response")
+// writeSSZ fetches the SSZ-encoded response from the given endpoint and writes it
+// to the supplied writer without decoding it.
+func (s *Service) writeSSZ(ctx context.Context,
+	call string,
+	endpoint string,
+	opts *api.CommonOpts,
+	w io.Writer,
+) (
+	*api.Response[*api.SSZWriteResult],
+	error,
+) {
+	if s.enforceJSON {
+		return nil, errors.New("cannot write SSZ when JSON is enforced")
+	}
+
+	res, err := s.getStream(ctx, call, endpoint, opts, ContentTypeSSZ.MediaType())
+	if err != nil {
+		return nil, err
+	}
+	defer res.body.Close()
+
+	if res.statusCode == http.StatusNoContent {
+		return nil, errors.New("no data returned")
+	}
+	if res.contentType != ContentTypeSSZ {
+		return nil, fmt.Errorf("beacon node returned %v rather than SSZ", res.contentType)
+	}
+
+	length, err := io.Copy(w, res.body)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to write SSZ data")
+	}
+	if res.contentLength > 0 && length != res.contentLength {
+		return nil, fmt.Errorf("wrote %d bytes but expected %d", length, res.contentLength)
+	}
+
+	return &api.Response[*api.SSZWriteResult]{
+		Data: &api.SSZWriteResult{
+			Version: res.consensusVersion,
+			Length:  length,
+		},
+		Metadata: metadataFromHeaders(res.headers),
+	}, nil
+}
+
+// maxStreamBodyPreallocation is the maximum size of the buffer allocated up front for a
+// streamed response body, so that a bad Content-Length cannot force a large allocation.
+const maxStreamBodyPreallocation = 64 * 1024 * 1024
+
+// readStreamBody reads the full body of a streamed response.
+// If the content length is known then the buffer is allocated up front, up to a
+// limit, avoiding the repeated reallocation and copying that would otherwise occur
+// for large bodies.
+func readStreamBody(res *httpStreamResponse) ([]byte, error) {
+	if res.contentLength <= 0 {
+		data, err := io.ReadAll(res.body)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read body")
 		}
-		res.consensusVersion = metadata.Version
 
-		return nil
+		return data, nil
+	}
+
+	size := res.contentLength
+	if size > maxStreamBodyPreallocation {
+		size = maxStreamBodyPreallocation
+	}
+	buf := bytes.NewBuffer(make([]byte, 0, size))
+	if _, err := buf.ReadFrom(io.LimitReader(res.body, res.contentLength)); err != nil {
+		return nil, errors.Wrap(err, "failed to read body")
+	}
+	if int64(buf.Len()) < res.contentLength {
+		return nil, errors.Wrap(io.ErrUnexpectedEOF, "failed to read body")
+	}
+
+	return buf.Bytes(), nil
+}
+
+// jsonStreamBody returns a reader for the JSON body of a streamed response.
+// If the consensus version was not supplied in the response headers then the
+// body is read in full to obtain it, and the returned reader is for the
+// in-memory copy.
+func jsonStreamBody(res *httpStreamResponse) (io.Reader, error) {
+	if res.consensusVersion != spec.DataVersionUnknown {
+		return res.body, nil
+	}
+
+	data, err := readStreamBody(res)
+	if err != nil {
+		return nil, err
+	}
+	var metadata responseMetadata
+	if err := json.Unmarshal(data, &metadata); err != nil {
+		return nil, errors.Wrap(err, "no consensus version header and failed to parse response")
+	}
+	res.consensusVersion = metadata.Version
+
+	return bytes.NewReader(data), nil
+}
+
+// consensusVersionFromResponse obtains the consensus version from the response headers.
+// If the header is not present this returns spec.DataVersionUnknown.
+func consensusVersionFromResponse(resp *http.Response) (spec.DataVersion, error) {
+	respConsensusVersions, exists := resp.Header["Eth-Consensus-Version"]
+	if !exists {
+		return spec.DataVersionUnknown, nil
 	}
 	if len(respConsensusVersions) != 1 {
-		return fmt.Errorf("malformed consensus version (%d entries)", len(respConsensusVersions))
+		return spec.DataVersionUnknown, fmt.Errorf("malformed consensus version (%d entries)", len(respConsensusVersions))
+	}
+	var consensusVersion spec.DataVersion
+	if err := consensusVersion.UnmarshalJSON([]byte(fmt.Sprintf("%q", respConsensusVersions[0]))); err != nil {
+		return spec.DataVersionUnknown, errors.Wrap(err, "failed to parse consensus version")
+	}
+
+	return consensusVersion, nil
+}
+
+// populateConsensusVersionFromBody obtains the consensus version from the body of the response,
+// for when it was not supplied in the response headers.
+func populateConsensusVersionFromBody(res *httpResponse) error {
+	res.consensusVersion = spec.DataVersionUnknown
+	if res.contentType != ContentTypeJSON {
+		// Not present here either.  Many responses do not provide this information, so assume
+		// this is one of them.
+		return nil
 	}
-	if err := res.consensusVersion.UnmarshalJSON([]byte(fmt.Sprintf("%q", respConsensusVersions[0]))); err != nil {
-		return errors.Wrap(err, "failed to parse consensus version")
+	var metadata responseMetadata
+	if err := json.Unmarshal(res.body, &metadata); err != nil {
+		return errors.Wrap(err, "no consensus version header and failed to parse response")
 	}
+	res.consensusVersion = metadata.Version
 
 	return nil
 }
 
-func populateHeaders(res *httpResponse, resp *http.Response) {
-	res.headers = make(map[string]string, len(resp.Header))
+func headersFromResponse(resp *http.Response) map[string]string {
+	headers := make(map[string]string, len(resp.Header))
 	for k, v := range resp.Header {
-		res.headers[k] = strings.Join(v, ";")
+		headers[k] = strings.Join(v, ";")
 	}
+
+	return headers
 }
 
-func populateContentType(res *httpResponse, resp *http.Response) error {
+func contentTypeFromResponse(resp *http.Response) (ContentType, error) {
 	respContentTypes, exists := resp.Header["Content-Type"]
 	if !exists {
-		return errors.New("no content type supplied in response")
+		return ContentTypeUnknown, errors.New("no content type supplied in response")
 	}
 	if len(respContentTypes) != 1 {
-		return fmt.Errorf("malformed content type (%d entries)", len(respContentTypes))
+		return ContentTypeUnknown, fmt.Errorf("malformed content type (%d entries)", len(respContentTypes))
 	}
 
-	var err error
-	res.contentType, err = ParseFromMediaType(respContentTypes[0])
-	if err != nil {
-		return err
-	}
+	return ParseFromMediaType(respContentTypes[0])
+}
 
-	return nil
+// addHeadersToMetadata adds headers to existing metadata, without overwriting values
+// that are already present.
+func addHeadersToMetadata(metadata map[string]any, headers map[string]string) {
+	for k, v := range headers {
+		if _, exists := metadata[k]; !exists {
+			metadata[k] = v
+		}
+	}
 }
 
 func metadataFromHeaders(headers map[string]string) map[string]any {
@@ -0,0 +1,165 @@
+// Copyright © 2023 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package http
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/attestantio/go-eth2-client/api"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCallTimeout(t *testing.T) {
+	s := &Service{
+		timeout: 2 * time.Second,
+		endpointTimeouts: map[string]time.Duration{
+			"BeaconState": 5 * time.Minute,
+		},
+	}
+
+	tests := []struct {
+		name     string
+		call     string
+		opts     *api.CommonOpts
+		expected time.Duration
+	}{
+		{
+			name:     "Default",
+			call:     "AttestationData",
+			expected: 2 * time.Second,
+		},
+		{
+			name:     "Endpoint",
+			call:     "BeaconState",
+			expected: 5 * time.Minute,
+		},
+		{
+			name:     "CallOverridesEndpoint",
+			call:     "BeaconState",
+			opts:     &api.CommonOpts{Timeout: 10 * time.Minute},
+			expected: 10 * time.Minute,
+		},
+		{
+			name:     "CallOverridesDefault",
+			call:     "AttestationData",
+			opts:     &api.CommonOpts{Timeout: time.Second},
+			expected: time.Second,
+		},
+		{
+			name:     "CallZero",
+			call:     "BeaconState",
+			opts:     &api.CommonOpts{},
+			expected: 5 * time.Minute,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.Equal(t, test.expected, s.callTimeout(test.call, test.opts))
+		})
+	}
+}
+
+func TestEndpointTimeouts(t *testing.T) {
+	ctx := context.Background()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"data":{"slot":"1","index":"0","beacon_block_root":"0x0000000000000000000000000000000000000000000000000000000000000000","source":{"epoch":"0","root":"0x0000000000000000000000000000000000000000000000000000000000000000"},"target":{"epoch":"0","root":"0x0000000000000000000000000000000000000000000000000000000000000000"}}}`))
+	}))
+	defer srv.Close()
+
+	base, err := url.Parse(srv.URL)
+	require.NoError(t, err)
+	s := &Service{
+		log:     zerolog.Nop(),
+		base:    base,
+		address: srv.URL,
+		client:  srv.Client(),
+		timeout: 50 * time.Millisecond,
+		endpointTimeouts: map[string]time.Duration{
+			"AttestationData": 10 * time.Second,
+		},
+	}
+
+	// Endpoint timeout is long enough.
+	_, err = s.AttestationData(ctx, &api.AttestationDataOpts{Slot: 1})
+	require.NoError(t, err)
+
+	// Per-call timeout is too short.
+	_, err = s.AttestationData(ctx, &api.AttestationDataOpts{
+		Common: api.CommonOpts{Timeout: 50 * time.Millisecond},
+		Slot:   1,
+	})
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestReadStreamBody(t *testing.T) {
+	tests := []struct {
+		name          string
+		body          []byte
+		contentLength int64
+		expected      []byte
+		err           string
+	}{
+		{
+			name:          "UnknownLength",
+			body:          []byte("body"),
+			contentLength: -1,
+			expected:      []byte("body"),
+		},
+		{
+			name:          "KnownLength",
+			body:          []byte("body"),
+			contentLength: 4,
+			expected:      []byte("body"),
+		},
+		{
+			name:          "Short",
+			body:          []byte("bo"),
+			contentLength: 4,
+			err:           "failed to read body: unexpected EOF",
+		},
+		{
+			name:          "HugeLength",
+			body:          []byte("body"),
+			contentLength: 1 << 40,
+			err:           "failed to read body: unexpected EOF",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			data, err := readStreamBody(&httpStreamResponse{
+				body:          io.NopCloser(bytes.NewReader(test.body)),
+				contentLength: test.contentLength,
+			})
+			if test.err != "" {
+				require.EqualError(t, err, test.err)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, test.expected, data)
+			}
+		})
+	}
+}
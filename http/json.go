@@ -9,42 +9,60 @@ import (
 	"github.com/pkg/errors"
 )
 
+// decodeJSONResponse decodes a standard beacon API JSON response, returning
+// the data and any metadata.
+// The body is decoded as a stream, so that the data is unmarshalled directly
+// from the reader rather than requiring an intermediate copy of the response.
 func decodeJSONResponse[T any](body io.Reader, res T) (T, map[string]any, error) {
 	if body == nil {
 		return res, nil, errors.New("no body to read")
 	}
 
-	decoded := make(map[string]json.RawMessage)
-
-	if err := json.NewDecoder(body).Decode(&decoded); err != nil {
+	decoder := json.NewDecoder(body)
+	token, err := decoder.Token()
+	if err != nil {
 		return res, nil, errors.Wrap(err, "failed to parse JSON")
 	}
+	if delim, isDelim := token.(json.Delim); !isDelim || delim != '{' {
+		return res, nil, errors.New("failed to parse JSON: response is not an object")
+	}
 
 	data := clone.Clone(res).(T)
 	metadata := make(map[string]any)
-	for k, v := range decoded {
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return res, nil, errors.Wrap(err, "failed to parse JSON")
+		}
+		k, isString := token.(string)
+		if !isString {
+			return res, nil, errors.New("failed to parse JSON: invalid key")
+		}
 		switch k {
 		case "data":
-			err := json.Unmarshal(v, &data)
+			err := decoder.Decode(&data)
 			if err != nil {
 				return res, nil, errors.Wrap(err, "failed to unmarshal data")
 			}
 		case "dependent_root":
 			var val phase0.Root
-			err := json.Unmarshal(v, &val)
+			err := decoder.Decode(&val)
 			if err != nil {
 				return res, nil, errors.Wrap(err, "failed to unmarshal dependent root")
 			}
 			metadata[k] = val
 		default:
 			var val any
-			err := json.Unmarshal(v, &val)
+			err := decoder.Decode(&val)
 			if err != nil {
 				return res, nil, errors.Wrapf(err, "failed to unmarshal metadata %s", k)
 			}
 			metadata[k] = val
 		}
 	}
+	if _, err := decoder.Token(); err != nil {
+		return res, nil, errors.Wrap(err, "failed to parse JSON")
+	}
 
 	return data, metadata, nil
 }
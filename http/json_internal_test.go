@@ -52,3 +52,17 @@ func TestDecodeJSONArray(t *testing.T) {
 	require.Equal(t, expectedData, data)
 	require.Equal(t, expectedMetadata, metadata)
 }
+
+func TestDecodeJSONNotObject(t *testing.T) {
+	input := []byte(`[{"previous_version":"0x00000001","current_version":"0x00000002","epoch":"3"}]`)
+
+	_, _, err := decodeJSONResponse(bytes.NewReader(input), &phase0.Fork{})
+	require.EqualError(t, err, "failed to parse JSON: response is not an object")
+}
+
+func TestDecodeJSONTruncated(t *testing.T) {
+	input := []byte(`{"execution_optimistic":false,"data":{"previous_version":"0x00000001","current_version":"0x00000002","epoch":"3"}`)
+
+	_, _, err := decodeJSONResponse(bytes.NewReader(input), &phase0.Fork{})
+	require.Error(t, err)
+}
@@ -0,0 +1,323 @@
+// Copyright © 2023 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package http
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// EndpointClass is a class of endpoint, used to apply rate and concurrency limits.
+type EndpointClass string
+
+const (
+	// EndpointClassDuty is the class of validator-critical endpoints, such as fetching
+	// attestation data or duties and submitting signed data.
+	// Calls in this class bypass service-wide rate limits and take priority for
+	// service-wide concurrency.
+	EndpointClassDuty EndpointClass = "duty"
+	// EndpointClassBulk is the class of endpoints that return large amounts of data,
+	// such as beacon states and validators.
+	EndpointClassBulk EndpointClass = "bulk"
+	// EndpointClassDefault is the class of all other endpoints.
+	EndpointClassDefault EndpointClass = "default"
+)
+
+// dutyCalls are the calls that are validator-critical.
+var dutyCalls = map[string]bool{
+	"AggregateAttestation":      true,
+	"AttestationData":           true,
+	"AttesterDuties":            true,
+	"BlindedProposal":           true,
+	"Proposal":                  true,
+	"ProposerDuties":            true,
+	"SyncCommitteeContribution": true,
+	"SyncCommitteeDuties":       true,
+}
+
+// bulkCalls are the calls that return large amounts of data.
+var bulkCalls = map[string]bool{
+	"AttestationPool":   true,
+	"BeaconCommittees":  true,
+	"BeaconState":       true,
+	"BlobSidecars":      true,
+	"ForkChoice":        true,
+	"NodePeers":         true,
+	"SignedBeaconBlock": true,
+	"SyncCommittee":     true,
+	"ValidatorBalances": true,
+	"Validators":        true,
+	"VoluntaryExitPool": true,
+}
+
+// endpointClass returns the class of the given call.
+func endpointClass(call string) EndpointClass {
+	switch {
+	case dutyCalls[call], strings.HasPrefix(call, "Submit"):
+		return EndpointClassDuty
+	case bulkCalls[call]:
+		return EndpointClassBulk
+	default:
+		return EndpointClassDefault
+	}
+}
+
+// Limits are the rate and concurrency limits for requests to a beacon node.
+type Limits struct {
+	// RequestsPerSecond is the sustained rate at which requests can be made.
+	// A value of 0 means that the rate is not limited.
+	RequestsPerSecond float64
+	// Burst is the number of requests that can be made at once before the rate limit applies.
+	// If not set this defaults to 1.
+	Burst int
+	// MaxInFlight is the maximum number of requests that can be outstanding at any time.
+	// A value of 0 means that concurrency is not limited.
+	MaxInFlight int
+}
+
+// limiter applies rate and concurrency limits to requests.
+type limiter struct {
+	serviceBucket   *tokenBucket
+	serviceInFlight *prioritySemaphore
+	classBuckets    map[EndpointClass]*tokenBucket
+	classInFlight   map[EndpointClass]*prioritySemaphore
+}
+
+// newLimiter creates a new limiter, returning nil if no limits are configured.
+func newLimiter(serviceLimits *Limits, classLimits map[EndpointClass]Limits) *limiter {
+	l := &limiter{
+		classBuckets:  make(map[EndpointClass]*tokenBucket),
+		classInFlight: make(map[EndpointClass]*prioritySemaphore),
+	}
+	limited := false
+	if serviceLimits != nil {
+		l.serviceBucket = newTokenBucket(serviceLimits.RequestsPerSecond, serviceLimits.Burst)
+		l.serviceInFlight = newPrioritySemaphore(serviceLimits.MaxInFlight)
+		limited = l.serviceBucket != nil || l.serviceInFlight != nil
+	}
+	for class, limits := range classLimits {
+		if bucket := newTokenBucket(limits.RequestsPerSecond, limits.Burst); bucket != nil {
+			l.classBuckets[class] = bucket
+			limited = true
+		}
+		if semaphore := newPrioritySemaphore(limits.MaxInFlight); semaphore != nil {
+			l.classInFlight[class] = semaphore
+			limited = true
+		}
+	}
+	if !limited {
+		return nil
+	}
+
+	return l
+}
+
+// acquire waits until a request for the given call is allowed to proceed.
+// It returns a function that must be called once the request has completed.
+func (l *limiter) acquire(ctx context.Context, call string) (func(), error) {
+	class := endpointClass(call)
+	priority := class == EndpointClassDuty
+	started := time.Now()
+	defer func() {
+		monitorLimiterWait(class, time.Since(started))
+	}()
+
+	// Duty calls are not subject to the service-wide rate limit.
+	if l.serviceBucket != nil && !priority {
+		if err := l.serviceBucket.wait(ctx); err != nil {
+			return nil, err
+		}
+	}
+	if bucket, exists := l.classBuckets[class]; exists {
+		if err := bucket.wait(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	releases := make([]func(), 0, 2)
+	release := func() {
+		for _, release := range releases {
+			release()
+		}
+	}
+	if semaphore, exists := l.classInFlight[class]; exists {
+		if err := semaphore.acquire(ctx, priority); err != nil {
+			return nil, err
+		}
+		releases = append(releases, semaphore.release)
+	}
+	if l.serviceInFlight != nil {
+		if err := l.serviceInFlight.acquire(ctx, priority); err != nil {
+			release()
+
+			return nil, err
+		}
+		releases = append(releases, l.serviceInFlight.release)
+	}
+
+	var once sync.Once
+
+	return func() { once.Do(release) }, nil
+}
+
+// tokenBucket is a simple token bucket rate limiter.
+type tokenBucket struct {
+	mu       sync.Mutex
+	rate     float64
+	capacity float64
+	tokens   float64
+	last     time.Time
+}
+
+// newTokenBucket creates a token bucket, returning nil if the rate is not limited.
+func newTokenBucket(rate float64, burst int) *tokenBucket {
+	if rate <= 0 {
+		return nil
+	}
+	if burst < 1 {
+		burst = 1
+	}
+
+	return &tokenBucket{
+		rate:     rate,
+		capacity: float64(burst),
+		tokens:   float64(burst),
+		last:     time.Now(),
+	}
+}
+
+// wait waits until a token is available, and takes it.
+func (b *tokenBucket) wait(ctx context.Context) error {
+	b.mu.Lock()
+	now := time.Now()
+	b.tokens += now.Sub(b.last).Seconds() * b.rate
+	if b.tokens > b.capacity {
+		b.tokens = b.capacity
+	}
+	b.last = now
+	// Take the token now, even if it means going in to debt, so that waiters are served in order.
+	b.tokens--
+	if b.tokens >= 0 {
+		b.mu.Unlock()
+
+		return nil
+	}
+	delay := time.Duration(-b.tokens / b.rate * float64(time.Second))
+	b.mu.Unlock()
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		// Return the token we were unable to use.
+		b.mu.Lock()
+		b.tokens++
+		b.mu.Unlock()
+
+		return errors.Wrap(ctx.Err(), "rate limit wait")
+	}
+}
+
+// prioritySemaphore is a counting semaphore in which high-priority waiters are
+// granted before low-priority waiters.
+type prioritySemaphore struct {
+	mu       sync.Mutex
+	max      int
+	inFlight int
+	high     []chan struct{}
+	low      []chan struct{}
+}
+
+// newPrioritySemaphore creates a semaphore, returning nil if concurrency is not limited.
+func newPrioritySemaphore(max int) *prioritySemaphore {
+	if max <= 0 {
+		return nil
+	}
+
+	return &prioritySemaphore{
+		max: max,
+	}
+}
+
+// acquire acquires the semaphore.
+func (p *prioritySemaphore) acquire(ctx context.Context, high bool) error {
+	p.mu.Lock()
+	if p.inFlight < p.max && len(p.high) == 0 && (high || len(p.low) == 0) {
+		p.inFlight++
+		p.mu.Unlock()
+
+		return nil
+	}
+	ch := make(chan struct{})
+	if high {
+		p.high = append(p.high, ch)
+	} else {
+		p.low = append(p.low, ch)
+	}
+	p.mu.Unlock()
+
+	select {
+	case <-ch:
+		return nil
+	case <-ctx.Done():
+		p.mu.Lock()
+		removed := false
+		if high {
+			p.high, removed = removeWaiter(p.high, ch)
+		} else {
+			p.low, removed = removeWaiter(p.low, ch)
+		}
+		p.mu.Unlock()
+		if !removed {
+			// We were granted the semaphore as the context finished; hand it back.
+			p.release()
+		}
+
+		return errors.Wrap(ctx.Err(), "concurrency limit wait")
+	}
+}
+
+// release releases the semaphore, handing it to the next waiter if present.
+func (p *prioritySemaphore) release() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	switch {
+	case len(p.high) > 0:
+		close(p.high[0])
+		p.high = p.high[1:]
+	case len(p.low) > 0:
+		close(p.low[0])
+		p.low = p.low[1:]
+	default:
+		p.inFlight--
+	}
+}
+
+// removeWaiter removes a waiter from a queue, returning true if it was present.
+func removeWaiter(queue []chan struct{}, ch chan struct{}) ([]chan struct{}, bool) {
+	for i := range queue {
+		if queue[i] == ch {
+			return append(queue[:i], queue[i+1:]...), true
+		}
+	}
+
+	return queue, false
+}
@@ -0,0 +1,126 @@
+// Copyright © 2023 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package http
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEndpointClass(t *testing.T) {
+	require.Equal(t, EndpointClassDuty, endpointClass("AttestationData"))
+	require.Equal(t, EndpointClassDuty, endpointClass("SubmitAttestations"))
+	require.Equal(t, EndpointClassBulk, endpointClass("BeaconState"))
+	require.Equal(t, EndpointClassDefault, endpointClass("Genesis"))
+}
+
+func TestNewLimiterUnlimited(t *testing.T) {
+	require.Nil(t, newLimiter(nil, nil))
+	require.Nil(t, newLimiter(&Limits{}, map[EndpointClass]Limits{EndpointClassBulk: {}}))
+}
+
+func TestLimiterRate(t *testing.T) {
+	ctx := context.Background()
+	l := newLimiter(&Limits{RequestsPerSecond: 10, Burst: 1}, nil)
+	require.NotNil(t, l)
+
+	// First request uses the burst.
+	release, err := l.acquire(ctx, "Genesis")
+	require.NoError(t, err)
+	release()
+
+	// Second request has to wait for a token.
+	started := time.Now()
+	release, err = l.acquire(ctx, "Genesis")
+	require.NoError(t, err)
+	release()
+	require.GreaterOrEqual(t, time.Since(started), 50*time.Millisecond)
+
+	// Duty requests bypass the service-wide rate limit.
+	started = time.Now()
+	release, err = l.acquire(ctx, "AttestationData")
+	require.NoError(t, err)
+	release()
+	require.Less(t, time.Since(started), 50*time.Millisecond)
+
+	// Waiting is bounded by the context.
+	_, err = l.acquire(ctx, "Genesis")
+	require.NoError(t, err)
+	timeoutCtx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	_, err = l.acquire(timeoutCtx, "Genesis")
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestLimiterInFlightPriority(t *testing.T) {
+	ctx := context.Background()
+	l := newLimiter(&Limits{MaxInFlight: 1}, nil)
+	require.NotNil(t, l)
+
+	release, err := l.acquire(ctx, "BeaconState")
+	require.NoError(t, err)
+
+	order := make(chan string, 2)
+	go func() {
+		r, err := l.acquire(ctx, "Validators")
+		if err == nil {
+			order <- "bulk"
+			r()
+		}
+	}()
+	// Ensure the bulk request is queued first.
+	time.Sleep(20 * time.Millisecond)
+	go func() {
+		r, err := l.acquire(ctx, "AttestationData")
+		if err == nil {
+			order <- "duty"
+			r()
+		}
+	}()
+	time.Sleep(20 * time.Millisecond)
+
+	release()
+	// Releasing more than once has no effect.
+	release()
+
+	require.Equal(t, "duty", <-order)
+	require.Equal(t, "bulk", <-order)
+}
+
+func TestLimiterInFlightCancel(t *testing.T) {
+	ctx := context.Background()
+	l := newLimiter(nil, map[EndpointClass]Limits{EndpointClassBulk: {MaxInFlight: 1}})
+	require.NotNil(t, l)
+
+	release, err := l.acquire(ctx, "BeaconState")
+	require.NoError(t, err)
+
+	// Other classes are not limited.
+	otherRelease, err := l.acquire(ctx, "Genesis")
+	require.NoError(t, err)
+	otherRelease()
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	_, err = l.acquire(timeoutCtx, "Validators")
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	release()
+	release, err = l.acquire(ctx, "Validators")
+	require.NoError(t, err)
+	release()
+}
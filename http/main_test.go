@@ -15,12 +15,21 @@ package http_test
 
 import (
 	"encoding/hex"
+	"flag"
+	"fmt"
+	"io"
+	nethttp "net/http"
+	"net/http/httptest"
+	"net/url"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/attestantio/go-eth2-client/http"
 	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/pkg/errors"
 	"github.com/rs/zerolog"
 )
 
@@ -28,11 +37,183 @@ import (
 // var timeout = 60 * time.Second
 var timeout = 10 * time.Minute
 
+// defaultFixtures is the directory of fixtures replayed if neither HTTP_ADDRESS nor
+// HTTP_FIXTURES is set.  The fixtures in it were recorded from the server package
+// backed by the mock client, rather than a live beacon node, so cover only those
+// tests whose checks hold against mock data.
+var defaultFixtures = filepath.Join("testdata", "fixtures")
+
+// offlineTests are the tests that do not contact a beacon node, and so are always run.
+var offlineTests = []string{
+	"TestCallTimeout",
+	"TestCapabilities",
+	"TestClientShouldSendExtraHeadersWhenProvided",
+	"TestCompressionEmptyBody",
+	"TestCompressionTransport",
+	"TestDecodeJSON.*",
+	"TestDecompressingReaderDoubleClose",
+	"TestEndpointClass",
+	"TestEndpointTimeouts",
+	"TestError",
+	"TestEventsServiceDone",
+	"TestLimiter.*",
+	"TestNewLimiterUnlimited",
+	"TestParseAddress",
+	"TestProposalV3",
+	"TestReadStreamBody",
+	"TestRecordReplay",
+	"TestUnixSocket",
+	"TestWriteSSZ",
+}
+
+// TestMain runs the tests in one of three modes:
+//   - if HTTP_ADDRESS is set, tests run against the beacon node at that address;
+//   - if HTTP_ADDRESS and HTTP_FIXTURES are both set, tests run against the beacon node
+//     and its responses are recorded to the HTTP_FIXTURES directory;
+//   - otherwise, tests run against a server that replays the fixtures in the HTTP_FIXTURES
+//     directory, or testdata/fixtures if it is not set.  Unless -run is supplied, only the
+//     offline tests and those that the fixtures were recorded for are run.
 func TestMain(m *testing.M) {
 	zerolog.SetGlobalLevel(zerolog.Disabled)
-	if os.Getenv("HTTP_ADDRESS") != "" {
+	flag.Parse()
+
+	address := os.Getenv("HTTP_ADDRESS")
+	fixtures := os.Getenv("HTTP_FIXTURES")
+	switch {
+	case address != "" && fixtures != "":
+		os.Exit(record(m, address, fixtures))
+	case address != "":
 		os.Exit(m.Run())
+	default:
+		if fixtures == "" {
+			fixtures = defaultFixtures
+		}
+		os.Exit(replay(m, fixtures))
+	}
+}
+
+// record runs the tests through a proxy to the beacon node, saving the responses
+// as fixtures in the given directory.
+// Streaming endpoints, such as events, cannot be recorded so -run should be used to
+// select the tests to record.
+func record(m *testing.M, address string, dir string) int {
+	base, err := url.Parse(address)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "invalid HTTP_ADDRESS: %v\n", err)
+
+		return 1
+	}
+	recorder := http.NewRecordingTransport(nil)
+	srv := httptest.NewServer(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		req := r.Clone(r.Context())
+		req.RequestURI = ""
+		req.Host = ""
+		req.URL.Scheme = base.Scheme
+		req.URL.Host = base.Host
+		req.URL.User = base.User
+		// Leave compression to the transport, so that recorded bodies are readable.
+		req.Header.Del("Accept-Encoding")
+		serveFixtureResponse(w, recorder, req)
+	}))
+	if err := os.Setenv("HTTP_ADDRESS", srv.URL); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to set HTTP_ADDRESS: %v\n", err)
+
+		return 1
+	}
+
+	res := m.Run()
+	srv.Close()
+
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to create fixtures directory: %v\n", err)
+
+		return 1
+	}
+	if err := recorder.Save(filepath.Join(dir, "fixtures.json")); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to save fixtures: %v\n", err)
+
+		return 1
+	}
+	tests := flag.Lookup("test.run").Value.String()
+	if tests == "" {
+		tests = ".*"
+	}
+	if err := os.WriteFile(filepath.Join(dir, "tests"), []byte(tests+"\n"), 0o600); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to save tests: %v\n", err)
+
+		return 1
+	}
+
+	return res
+}
+
+// replay runs the tests against a server that replays the fixtures in the given directory.
+// If the directory does not contain fixtures only the offline tests are run.
+func replay(m *testing.M, dir string) int {
+	tests := fmt.Sprintf("^(%s)$", strings.Join(offlineTests, "|"))
+
+	fixtures, err := http.LoadFixtures(filepath.Join(dir, "fixtures.json"))
+	switch {
+	case err == nil:
+		recorded, err := os.ReadFile(filepath.Join(dir, "tests"))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to read fixture tests: %v\n", err)
+
+			return 1
+		}
+		tests = fmt.Sprintf("%s|%s", tests, strings.TrimSpace(string(recorded)))
+	case os.IsNotExist(errors.Cause(err)):
+		fixtures = nil
+	default:
+		fmt.Fprintf(os.Stderr, "failed to load fixtures: %v\n", err)
+
+		return 1
+	}
+
+	if fixtures != nil {
+		replayer := http.NewReplayTransport(fixtures)
+		srv := httptest.NewServer(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+			serveFixtureResponse(w, replayer, r)
+		}))
+		defer srv.Close()
+		if err := os.Setenv("HTTP_ADDRESS", srv.URL); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to set HTTP_ADDRESS: %v\n", err)
+
+			return 1
+		}
+		defer func() {
+			for _, request := range replayer.Unmatched() {
+				fmt.Fprintf(os.Stderr, "no fixture for %s\n", request)
+			}
+		}()
+	}
+
+	if flag.Lookup("test.run").Value.String() == "" {
+		if err := flag.Set("test.run", tests); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to select tests: %v\n", err)
+
+			return 1
+		}
+	}
+
+	return m.Run()
+}
+
+// serveFixtureResponse serves the response to a request obtained from a fixture transport.
+func serveFixtureResponse(w nethttp.ResponseWriter, transport nethttp.RoundTripper, r *nethttp.Request) {
+	resp, err := transport.RoundTrip(r)
+	if err != nil {
+		nethttp.Error(w, err.Error(), nethttp.StatusBadGateway)
+
+		return
+	}
+	defer resp.Body.Close()
+
+	for k, v := range resp.Header {
+		w.Header()[k] = v
 	}
+	w.WriteHeader(resp.StatusCode)
+	_, _ = io.Copy(w, resp.Body)
 }
 
 // mustParseRoot is used for testing.
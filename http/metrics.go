@@ -0,0 +1,99 @@
+// Copyright © 2023 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package http
+
+import (
+	"context"
+	"time"
+
+	"github.com/attestantio/go-eth2-client/metrics"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	compressedBytesMetric *prometheus.CounterVec
+	savedBytesMetric      *prometheus.CounterVec
+	limiterWaitMetric     *prometheus.HistogramVec
+)
+
+func registerMetrics(ctx context.Context, monitor metrics.Service) error {
+	if compressedBytesMetric != nil {
+		// Already registered.
+		return nil
+	}
+	if monitor == nil {
+		// No monitor.
+		return nil
+	}
+	if monitor.Presenter() == "prometheus" {
+		return registerPrometheusMetrics(ctx)
+	}
+
+	return nil
+}
+
+func registerPrometheusMetrics(_ context.Context) error {
+	compressedBytesMetric = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "consensusclient",
+		Subsystem: "http",
+		Name:      "compressed_bytes_total",
+		Help:      "Number of compressed bytes transferred",
+	}, []string{"direction", "encoding"})
+	if err := prometheus.Register(compressedBytesMetric); err != nil {
+		return errors.Wrap(err, "failed to register compressed_bytes_total")
+	}
+	savedBytesMetric = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "consensusclient",
+		Subsystem: "http",
+		Name:      "compression_saved_bytes_total",
+		Help:      "Number of bytes saved by compression",
+	}, []string{"direction", "encoding"})
+	if err := prometheus.Register(savedBytesMetric); err != nil {
+		return errors.Wrap(err, "failed to register compression_saved_bytes_total")
+	}
+	limiterWaitMetric = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "consensusclient",
+		Subsystem: "http",
+		Name:      "limiter_wait_seconds",
+		Help:      "Time spent waiting for rate and concurrency limits before sending a request",
+		Buckets: []float64{
+			0.001, 0.005, 0.01, 0.05,
+			0.1, 0.25, 0.5, 1.0,
+			2.0, 5.0, 10.0,
+		},
+	}, []string{"class"})
+	if err := prometheus.Register(limiterWaitMetric); err != nil {
+		return errors.Wrap(err, "failed to register limiter_wait_seconds")
+	}
+
+	return nil
+}
+
+// monitorCompression records the compressed and uncompressed sizes of a transfer.
+func monitorCompression(direction string, encoding string, compressed int, uncompressed int) {
+	if compressedBytesMetric != nil {
+		compressedBytesMetric.WithLabelValues(direction, encoding).Add(float64(compressed))
+	}
+	if savedBytesMetric != nil && uncompressed > compressed {
+		savedBytesMetric.WithLabelValues(direction, encoding).Add(float64(uncompressed - compressed))
+	}
+}
+
+// monitorLimiterWait records the time a request waited for rate and concurrency limits.
+func monitorLimiterWait(class EndpointClass, duration time.Duration) {
+	if limiterWaitMetric != nil {
+		limiterWaitMetric.WithLabelValues(string(class)).Observe(duration.Seconds())
+	}
+}
@@ -27,24 +27,28 @@ func (s *Service) NodeClient(ctx context.Context) (*api.Response[string], error)
 		return nil, err
 	}
 
-	nodeVersion := strings.ToLower(response.Data)
+	client := clientFromNodeVersion(strings.ToLower(response.Data))
 
-	var client string
+	return &api.Response[string]{
+		Data:     client,
+		Metadata: make(map[string]any),
+	}, nil
+}
+
+// clientFromNodeVersion returns the name of the client given its lower-case node version.
+func clientFromNodeVersion(nodeVersion string) string {
 	switch {
 	case strings.HasPrefix(nodeVersion, "lighthouse"):
-		client = "lighthouse"
+		return "lighthouse"
+	case strings.HasPrefix(nodeVersion, "lodestar"):
+		return "lodestar"
 	case strings.HasPrefix(nodeVersion, "nimbus"):
-		client = "nimbus"
+		return "nimbus"
 	case strings.HasPrefix(nodeVersion, "prysm"):
-		client = "prysm"
+		return "prysm"
 	case strings.HasPrefix(nodeVersion, "teku"):
-		client = "teku"
+		return "teku"
 	default:
-		client = nodeVersion
+		return nodeVersion
 	}
-
-	return &api.Response[string]{
-		Data:     client,
-		Metadata: make(map[string]any),
-	}, nil
 }
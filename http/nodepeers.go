@@ -27,7 +27,7 @@ func (s *Service) NodePeers(ctx context.Context, opts *api.PeerOpts) (*api.Respo
 		request = fmt.Sprintf("%s?%s", request, strings.Join(additionalFields, "&"))
 	}
 
-	httpResponse, err := s.get2(ctx, request)
+	httpResponse, err := s.get2(ctx, "NodePeers", request, &opts.Common)
 	if err != nil {
 		return nil, err
 	}
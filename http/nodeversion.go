@@ -48,7 +48,7 @@ func (s *Service) NodeVersion(ctx context.Context) (*api.Response[string], error
 	}
 
 	// Up to us to fetch the information.
-	httpResponse, err := s.get2(ctx, "/eth/v1/node/version")
+	httpResponse, err := s.get2(ctx, "NodeVersion", "/eth/v1/node/version", nil)
 	if err != nil {
 		return nil, err
 	}
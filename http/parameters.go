@@ -14,20 +14,31 @@
 package http
 
 import (
+	"fmt"
+	"net/http"
 	"time"
 
+	"github.com/attestantio/go-eth2-client/metrics"
 	"github.com/pkg/errors"
 	"github.com/rs/zerolog"
 )
 
 type parameters struct {
-	logLevel        zerolog.Level
-	address         string
-	timeout         time.Duration
-	indexChunkSize  int
-	pubKeyChunkSize int
-	extraHeaders    map[string]string
-	enforceJSON     bool
+	logLevel                 zerolog.Level
+	monitor                  metrics.Service
+	address                  string
+	timeout                  time.Duration
+	endpointTimeouts         map[string]time.Duration
+	indexChunkSize           int
+	pubKeyChunkSize          int
+	extraHeaders             map[string]string
+	enforceJSON              bool
+	compression              bool
+	postCompressionThreshold int
+	limits                   *Limits
+	dialContext              DialContextFunc
+	transport                http.RoundTripper
+	endpointClassLimits      map[EndpointClass]Limits
 }
 
 // Parameter is the interface for service parameters.
@@ -48,7 +59,15 @@ func WithLogLevel(logLevel zerolog.Level) Parameter {
 	})
 }
 
+// WithMonitor sets the monitor for the service.
+func WithMonitor(monitor metrics.Service) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.monitor = monitor
+	})
+}
+
 // WithAddress provides the address for the endpoint.
+// Addresses of the form unix:///path/to/socket connect to the beacon node over a Unix domain socket.
 func WithAddress(address string) Parameter {
 	return parameterFunc(func(p *parameters) {
 		p.address = address
@@ -62,6 +81,15 @@ func WithTimeout(timeout time.Duration) Parameter {
 	})
 }
 
+// WithEndpointTimeouts sets the maximum duration for requests to specific endpoints, overriding the timeout
+// set by WithTimeout.  Endpoints are referenced by the name of their provider function, for example
+// "AttestationData" or "BeaconState".
+func WithEndpointTimeouts(timeouts map[string]time.Duration) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.endpointTimeouts = timeouts
+	})
+}
+
 // WithIndexChunkSize sets the maximum number of indices to send for individual validator requests.
 func WithIndexChunkSize(indexChunkSize int) Parameter {
 	return parameterFunc(func(p *parameters) {
@@ -90,14 +118,65 @@ func WithEnforceJSON(enforceJSON bool) Parameter {
 	})
 }
 
+// WithCompression requests compressed responses from the beacon node, using zstd, gzip or deflate.
+func WithCompression(compression bool) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.compression = compression
+	})
+}
+
+// WithPostCompressionThreshold sets the size in bytes above which POST request bodies are gzip-compressed.
+// Not all beacon nodes accept compressed requests, so this is disabled by default; a value of 0 disables it.
+func WithPostCompressionThreshold(threshold int) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.postCompressionThreshold = threshold
+	})
+}
+
+// WithLimits sets service-wide rate and concurrency limits for requests to the beacon node.
+// Duty calls are not subject to the service-wide rate limit, and are given priority when
+// waiting for the service-wide concurrency limit.
+func WithLimits(limits Limits) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.limits = &limits
+	})
+}
+
+// WithEndpointClassLimits sets rate and concurrency limits for requests to specific classes of endpoint.
+// These apply in addition to any service-wide limits set by WithLimits.
+func WithEndpointClassLimits(limits map[EndpointClass]Limits) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.endpointClassLimits = limits
+	})
+}
+
+// WithDialContext sets a custom function to create connections to the beacon node, for both
+// requests and the events stream.  If the address is a Unix domain socket then the function
+// is called with the network "unix" and the path of the socket.
+func WithDialContext(dialContext DialContextFunc) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.dialContext = dialContext
+	})
+}
+
+// WithTransport sets the transport used for requests to the beacon node, in place of
+// the default HTTP transport.  This allows requests to be recorded or replayed with
+// RecordingTransport and ReplayTransport.  The transport is not used for the events stream.
+func WithTransport(transport http.RoundTripper) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.transport = transport
+	})
+}
+
 // parseAndCheckParameters parses and checks parameters to ensure that mandatory parameters are present and correct.
 func parseAndCheckParameters(params ...Parameter) (*parameters, error) {
 	parameters := parameters{
-		logLevel:        zerolog.GlobalLevel(),
-		timeout:         2 * time.Second,
-		indexChunkSize:  -1,
-		pubKeyChunkSize: -1,
-		extraHeaders:    make(map[string]string),
+		logLevel:         zerolog.GlobalLevel(),
+		timeout:          2 * time.Second,
+		indexChunkSize:   -1,
+		pubKeyChunkSize:  -1,
+		extraHeaders:     make(map[string]string),
+		endpointTimeouts: make(map[string]time.Duration),
 	}
 	for _, p := range params {
 		if params != nil {
@@ -111,12 +190,51 @@ func parseAndCheckParameters(params ...Parameter) (*parameters, error) {
 	if parameters.timeout == 0 {
 		return nil, errors.New("no timeout specified")
 	}
+	for endpoint, timeout := range parameters.endpointTimeouts {
+		if timeout <= 0 {
+			return nil, fmt.Errorf("invalid timeout for endpoint %s", endpoint)
+		}
+	}
 	if parameters.indexChunkSize == 0 {
 		return nil, errors.New("no index chunk size specified")
 	}
 	if parameters.pubKeyChunkSize == 0 {
 		return nil, errors.New("no public key chunk size specified")
 	}
+	if parameters.limits != nil {
+		if err := checkLimits(parameters.limits); err != nil {
+			return nil, errors.Wrap(err, "invalid limits")
+		}
+	}
+	for class, limits := range parameters.endpointClassLimits {
+		switch class {
+		case EndpointClassDuty, EndpointClassBulk, EndpointClassDefault:
+		default:
+			return nil, fmt.Errorf("unknown endpoint class %s", class)
+		}
+		limits := limits
+		if err := checkLimits(&limits); err != nil {
+			return nil, errors.Wrap(err, fmt.Sprintf("invalid limits for endpoint class %s", class))
+		}
+	}
+	if parameters.postCompressionThreshold < 0 {
+		return nil, errors.New("POST compression threshold cannot be negative")
+	}
 
 	return &parameters, nil
 }
+
+// checkLimits checks that limits are valid.
+func checkLimits(limits *Limits) error {
+	if limits.RequestsPerSecond < 0 {
+		return errors.New("requests per second cannot be negative")
+	}
+	if limits.Burst < 0 {
+		return errors.New("burst cannot be negative")
+	}
+	if limits.MaxInFlight < 0 {
+		return errors.New("maximum in-flight requests cannot be negative")
+	}
+
+	return nil
+}
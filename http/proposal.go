@@ -17,6 +17,7 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/attestantio/go-eth2-client/api"
 	apiv1deneb "github.com/attestantio/go-eth2-client/api/v1/deneb"
@@ -46,7 +47,15 @@ func (s *Service) Proposal(ctx context.Context,
 		return nil, errors.New("no slot specified")
 	}
 
-	url := fmt.Sprintf("/eth/v2/validator/blocks/%d?randao_reveal=%#x&graffiti=%#x", opts.Slot, opts.RandaoReveal, opts.Graffiti)
+	// Prefer the v3 endpoint if the node supports it.  The v3 endpoint can return a blinded
+	// block, which a VersionedProposal cannot hold, so ask for a local payload.
+	v3 := s.hasCapability(api.CapabilityProposalV3)
+	var url string
+	if v3 {
+		url = fmt.Sprintf("/eth/v3/validator/blocks/%d?randao_reveal=%#x&graffiti=%#x&builder_boost_factor=0", opts.Slot, opts.RandaoReveal, opts.Graffiti)
+	} else {
+		url = fmt.Sprintf("/eth/v2/validator/blocks/%d?randao_reveal=%#x&graffiti=%#x", opts.Slot, opts.RandaoReveal, opts.Graffiti)
+	}
 
 	if opts.SkipRandaoVerification {
 		if !opts.RandaoReveal.IsInfinity() {
@@ -55,10 +64,13 @@ func (s *Service) Proposal(ctx context.Context,
 		url = fmt.Sprintf("%s&skip_randao_verification", url)
 	}
 
-	res, err := s.get2(ctx, url)
+	res, err := s.get2(ctx, "Proposal", url, &opts.Common)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to request beacon block proposal")
 	}
+	if v3 && strings.EqualFold(res.headers["Eth-Execution-Payload-Blinded"], "true") {
+		return nil, errors.New("beacon block proposal is blinded")
+	}
 
 	var response *api.Response[*api.VersionedProposal]
 	switch res.contentType {
@@ -84,7 +96,7 @@ func (s *Service) Proposal(ctx context.Context,
 
 	// Only check the RANDAO reveal and graffiti if we are not connected to DVT middleware,
 	// as the returned values will be decided by the middleware.
-	if !s.connectedToDVTMiddleware {
+	if !s.connectedToDVTMiddleware(ctx) {
 		blockRandaoReveal, err := response.Data.RandaoReveal()
 		if err != nil {
 			return nil, err
@@ -171,6 +183,8 @@ func (s *Service) beaconBlockProposalFromJSON(res *httpResponse) (*api.Response[
 	if err != nil {
 		return nil, err
 	}
+	// Headers carry additional information, such as the value of the proposal.
+	addHeadersToMetadata(response.Metadata, res.headers)
 
 	return response, nil
 }
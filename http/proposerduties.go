@@ -35,7 +35,7 @@ func (s *Service) ProposerDuties(ctx context.Context,
 		return nil, errors.New("no options specified")
 	}
 
-	httpResponse, err := s.get2(ctx, fmt.Sprintf("/eth/v1/validator/duties/proposer/%d", opts.Epoch))
+	httpResponse, err := s.get2(ctx, "ProposerDuties", fmt.Sprintf("/eth/v1/validator/duties/proposer/%d", opts.Epoch), &opts.Common)
 	if err != nil {
 		return nil, err
 	}
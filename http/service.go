@@ -15,16 +15,15 @@ package http
 
 import (
 	"context"
-	"fmt"
 	"net"
 	"net/http"
 	"net/url"
-	"strings"
 	"sync"
 	"time"
 
 	eth2client "github.com/attestantio/go-eth2-client"
-	api "github.com/attestantio/go-eth2-client/api/v1"
+	"github.com/attestantio/go-eth2-client/api"
+	apiv1 "github.com/attestantio/go-eth2-client/api/v1"
 	"github.com/attestantio/go-eth2-client/spec/phase0"
 	"github.com/pkg/errors"
 	"github.com/rs/zerolog"
@@ -40,28 +39,40 @@ type Service struct {
 	address string
 	client  *http.Client
 	timeout time.Duration
+	// dialContext is the custom dialer for connections, if any.
+	dialContext DialContextFunc
+	// endpointTimeouts are timeouts for specific endpoints, keyed by provider function name.
+	endpointTimeouts map[string]time.Duration
+	// limiter applies rate and concurrency limits to requests; nil if there are no limits.
+	limiter *limiter
+	// done is closed when the context with which the service was created is done.
+	done <-chan struct{}
 
 	// Various information from the node that does not change during the
 	// lifetime of a beacon node.
-	genesis              *api.Genesis
+	genesis              *apiv1.Genesis
 	genesisMutex         sync.RWMutex
 	spec                 map[string]interface{}
 	specMutex            sync.RWMutex
-	depositContract      *api.DepositContract
+	depositContract      *apiv1.DepositContract
 	depositContractMutex sync.RWMutex
 	forkSchedule         []*phase0.Fork
 	forkScheduleMutex    sync.RWMutex
 	nodeVersion          string
 	nodeVersionMutex     sync.RWMutex
 
+	// Capabilities of the node, probed at startup and on refresh.
+	capabilities           *api.Capabilities
+	capabilitiesMutex      sync.RWMutex
+	capabilitiesProbeMutex sync.Mutex
+
 	// User-specified chunk sizes.
 	userIndexChunkSize  int
 	userPubKeyChunkSize int
 	extraHeaders        map[string]string
 
 	// Endpoint support.
-	enforceJSON              bool
-	connectedToDVTMiddleware bool
+	enforceJSON bool
 }
 
 // New creates a new Ethereum 2 client service, connecting with a standard HTTP.
@@ -77,31 +88,57 @@ func New(ctx context.Context, params ...Parameter) (eth2client.Service, error) {
 		log = log.Level(parameters.logLevel)
 	}
 
-	client := &http.Client{
-		Timeout: parameters.timeout,
-		Transport: &http.Transport{
-			DialContext: (&net.Dialer{
-				Timeout:   parameters.timeout,
-				KeepAlive: 30 * time.Second,
-				DualStack: true,
-			}).DialContext,
+	if parameters.monitor != nil {
+		if err := registerMetrics(ctx, parameters.monitor); err != nil {
+			return nil, errors.Wrap(err, "failed to register metrics")
+		}
+	}
+
+	base, socket, err := parseAddress(parameters.address)
+	if err != nil {
+		return nil, err
+	}
+
+	// dialContext is only set if the user has requested something other than
+	// the default TCP dialer, and is also used for the events stream.
+	dialContext := parameters.dialContext
+	if socket != "" {
+		if dialContext == nil {
+			dialContext = (&net.Dialer{
+				Timeout: parameters.timeout,
+			}).DialContext
+		}
+		dialContext = unixDialContext(dialContext, socket)
+	}
+	restDialContext := dialContext
+	if restDialContext == nil {
+		restDialContext = (&net.Dialer{
+			Timeout:   parameters.timeout,
+			KeepAlive: 30 * time.Second,
+			DualStack: true,
+		}).DialContext
+	}
+
+	transport := parameters.transport
+	if transport == nil {
+		transport = &http.Transport{
+			DialContext:         restDialContext,
 			MaxIdleConns:        64,
 			MaxConnsPerHost:     64,
 			MaxIdleConnsPerHost: 64,
 			IdleConnTimeout:     600 * time.Second,
-		},
-	}
-
-	address := parameters.address
-	if !strings.HasPrefix(address, "http") {
-		address = fmt.Sprintf("http://%s", address)
+		}
 	}
-	if !strings.HasSuffix(address, "/") {
-		address = fmt.Sprintf("%s/", address)
+	if parameters.compression || parameters.postCompressionThreshold > 0 {
+		transport = &compressionTransport{
+			next:             transport,
+			negotiate:        parameters.compression,
+			requestThreshold: int64(parameters.postCompressionThreshold),
+		}
 	}
-	base, err := url.Parse(address)
-	if err != nil {
-		return nil, errors.Wrap(err, "invalid URL")
+	// No overall timeout is set on the client, as timeouts are set per call.
+	client := &http.Client{
+		Transport: transport,
 	}
 
 	s := &Service{
@@ -109,11 +146,15 @@ func New(ctx context.Context, params ...Parameter) (eth2client.Service, error) {
 		base:                base,
 		address:             parameters.address,
 		client:              client,
+		dialContext:         dialContext,
 		timeout:             parameters.timeout,
+		endpointTimeouts:    parameters.endpointTimeouts,
+		limiter:             newLimiter(parameters.limits, parameters.endpointClassLimits),
 		userIndexChunkSize:  parameters.indexChunkSize,
 		userPubKeyChunkSize: parameters.pubKeyChunkSize,
 		extraHeaders:        parameters.extraHeaders,
 		enforceJSON:         parameters.enforceJSON,
+		done:                ctx.Done(),
 	}
 
 	// Fetch static values to confirm the connection is good.
@@ -124,11 +165,6 @@ func New(ctx context.Context, params ...Parameter) (eth2client.Service, error) {
 	// Periodially refetch static values in case of client update.
 	s.periodicClearStaticValues(ctx)
 
-	// Handle connection to DVT middleware.
-	if err := s.checkDVT(ctx); err != nil {
-		return nil, errors.Wrap(err, "failed to check DVT connection")
-	}
-
 	// Close the service on context done.
 	go func(s *Service) {
 		<-ctx.Done()
@@ -157,6 +193,9 @@ func (s *Service) fetchStaticValues(ctx context.Context) error {
 	if _, err := s.NodeVersion(ctx); err != nil {
 		return errors.Wrap(err, "failed to fetch node version")
 	}
+	if _, err := s.Capabilities(ctx); err != nil {
+		return errors.Wrap(err, "failed to fetch capabilities")
+	}
 
 	return nil
 }
@@ -186,6 +225,13 @@ func (s *Service) periodicClearStaticValues(ctx context.Context) {
 				s.nodeVersionMutex.Lock()
 				s.nodeVersion = ""
 				s.nodeVersionMutex.Unlock()
+				s.capabilitiesMutex.Lock()
+				s.capabilities = nil
+				s.capabilitiesMutex.Unlock()
+				// Reprobe capabilities immediately, as they are used to route requests.
+				if _, err := s.Capabilities(ctx); err != nil {
+					s.log.Debug().Err(err).Msg("Failed to refresh capabilities")
+				}
 			case <-ctx.Done():
 				return
 			}
@@ -193,23 +239,6 @@ func (s *Service) periodicClearStaticValues(ctx context.Context) {
 	}(s, ctx)
 }
 
-// checkDVT checks if connected to DVT middleware and sets
-// internal flags appropriately.
-func (s *Service) checkDVT(ctx context.Context) error {
-	response, err := s.NodeVersion(ctx)
-	if err != nil {
-		return errors.Wrap(err, "failed to obtain node version for DVT check")
-	}
-
-	version := strings.ToLower(response.Data)
-
-	if strings.Contains(version, "charon") {
-		s.connectedToDVTMiddleware = true
-	}
-
-	return nil
-}
-
 // Name provides the name of the service.
 func (s *Service) Name() string {
 	return "Standard (HTTP)"
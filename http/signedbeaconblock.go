@@ -14,9 +14,9 @@
 package http
 
 import (
-	"bytes"
 	"context"
 	"fmt"
+	"io"
 
 	"github.com/attestantio/go-eth2-client/api"
 	"github.com/attestantio/go-eth2-client/spec"
@@ -38,20 +38,34 @@ func (s *Service) SignedBeaconBlock(ctx context.Context,
 	if opts == nil {
 		return nil, errors.New("no options specified")
 	}
+	if err := opts.Block.Validate(); err != nil {
+		return nil, err
+	}
 
-	httpResponse, err := s.get2(ctx, fmt.Sprintf("/eth/v2/beacon/blocks/%s", opts.Block))
+	res, err := s.getStream(ctx, "SignedBeaconBlock", fmt.Sprintf("/eth/v2/beacon/blocks/%s", opts.Block), &opts.Common, s.acceptHeader("SignedBeaconBlock"))
 	if err != nil {
 		return nil, err
 	}
+	defer res.body.Close()
 
 	var response *api.Response[*spec.VersionedSignedBeaconBlock]
-	switch httpResponse.contentType {
+	switch res.contentType {
 	case ContentTypeSSZ:
-		response, err = s.signedBeaconBlockFromSSZ(httpResponse)
+		var data []byte
+		data, err = readStreamBody(res)
+		if err != nil {
+			return nil, err
+		}
+		response, err = s.signedBeaconBlockFromSSZ(res.consensusVersion, res.headers, data)
 	case ContentTypeJSON:
-		response, err = s.signedBeaconBlockFromJSON(httpResponse)
+		var body io.Reader
+		body, err = jsonStreamBody(res)
+		if err != nil {
+			return nil, err
+		}
+		response, err = s.signedBeaconBlockFromJSON(res.consensusVersion, body)
 	default:
-		return nil, fmt.Errorf("unhandled content type %v", httpResponse.contentType)
+		return nil, fmt.Errorf("unhandled content type %v", res.contentType)
 	}
 	if err != nil {
 		return nil, err
@@ -60,68 +74,100 @@ func (s *Service) SignedBeaconBlock(ctx context.Context,
 	return response, nil
 }
 
-func (s *Service) signedBeaconBlockFromSSZ(res *httpResponse) (*api.Response[*spec.VersionedSignedBeaconBlock], error) {
+// WriteSignedBeaconBlockSSZ writes the SSZ-encoded signed beacon block to the supplied writer.
+func (s *Service) WriteSignedBeaconBlockSSZ(ctx context.Context,
+	opts *api.SignedBeaconBlockOpts,
+	w io.Writer,
+) (
+	*api.Response[*api.SSZWriteResult],
+	error,
+) {
+	if opts == nil {
+		return nil, errors.New("no options specified")
+	}
+	if err := opts.Block.Validate(); err != nil {
+		return nil, err
+	}
+	if w == nil {
+		return nil, errors.New("no writer specified")
+	}
+
+	return s.writeSSZ(ctx, "SignedBeaconBlock", fmt.Sprintf("/eth/v2/beacon/blocks/%s", opts.Block), &opts.Common, w)
+}
+
+func (s *Service) signedBeaconBlockFromSSZ(version spec.DataVersion,
+	headers map[string]string,
+	data []byte,
+) (
+	*api.Response[*spec.VersionedSignedBeaconBlock],
+	error,
+) {
 	response := &api.Response[*spec.VersionedSignedBeaconBlock]{
 		Data: &spec.VersionedSignedBeaconBlock{
-			Version: res.consensusVersion,
+			Version: version,
 		},
-		Metadata: metadataFromHeaders(res.headers),
+		Metadata: metadataFromHeaders(headers),
 	}
 
-	switch res.consensusVersion {
+	switch version {
 	case spec.DataVersionPhase0:
 		response.Data.Phase0 = &phase0.SignedBeaconBlock{}
-		if err := response.Data.Phase0.UnmarshalSSZ(res.body); err != nil {
+		if err := response.Data.Phase0.UnmarshalSSZ(data); err != nil {
 			return nil, errors.Wrap(err, "failed to decode phase0 signed beacon block")
 		}
 	case spec.DataVersionAltair:
 		response.Data.Altair = &altair.SignedBeaconBlock{}
-		if err := response.Data.Altair.UnmarshalSSZ(res.body); err != nil {
+		if err := response.Data.Altair.UnmarshalSSZ(data); err != nil {
 			return nil, errors.Wrap(err, "failed to decode altair signed beacon block")
 		}
 	case spec.DataVersionBellatrix:
 		response.Data.Bellatrix = &bellatrix.SignedBeaconBlock{}
-		if err := response.Data.Bellatrix.UnmarshalSSZ(res.body); err != nil {
+		if err := response.Data.Bellatrix.UnmarshalSSZ(data); err != nil {
 			return nil, errors.Wrap(err, "failed to decode bellatrix signed beacon block")
 		}
 	case spec.DataVersionCapella:
 		response.Data.Capella = &capella.SignedBeaconBlock{}
-		if err := response.Data.Capella.UnmarshalSSZ(res.body); err != nil {
+		if err := response.Data.Capella.UnmarshalSSZ(data); err != nil {
 			return nil, errors.Wrap(err, "failed to decode capella signed beacon block")
 		}
 	case spec.DataVersionDeneb:
 		response.Data.Deneb = &deneb.SignedBeaconBlock{}
-		if err := response.Data.Deneb.UnmarshalSSZ(res.body); err != nil {
+		if err := response.Data.Deneb.UnmarshalSSZ(data); err != nil {
 			return nil, errors.Wrap(err, "failed to decode deneb signed block contents")
 		}
 	default:
-		return nil, fmt.Errorf("unhandled block version %s", res.consensusVersion)
+		return nil, fmt.Errorf("unhandled block version %s", version)
 	}
 
 	return response, nil
 }
 
-func (s *Service) signedBeaconBlockFromJSON(res *httpResponse) (*api.Response[*spec.VersionedSignedBeaconBlock], error) {
+func (s *Service) signedBeaconBlockFromJSON(version spec.DataVersion,
+	body io.Reader,
+) (
+	*api.Response[*spec.VersionedSignedBeaconBlock],
+	error,
+) {
 	response := &api.Response[*spec.VersionedSignedBeaconBlock]{
 		Data: &spec.VersionedSignedBeaconBlock{
-			Version: res.consensusVersion,
+			Version: version,
 		},
 	}
 
 	var err error
-	switch res.consensusVersion {
+	switch version {
 	case spec.DataVersionPhase0:
-		response.Data.Phase0, response.Metadata, err = decodeJSONResponse(bytes.NewReader(res.body), &phase0.SignedBeaconBlock{})
+		response.Data.Phase0, response.Metadata, err = decodeJSONResponse(body, &phase0.SignedBeaconBlock{})
 	case spec.DataVersionAltair:
-		response.Data.Altair, response.Metadata, err = decodeJSONResponse(bytes.NewReader(res.body), &altair.SignedBeaconBlock{})
+		response.Data.Altair, response.Metadata, err = decodeJSONResponse(body, &altair.SignedBeaconBlock{})
 	case spec.DataVersionBellatrix:
-		response.Data.Bellatrix, response.Metadata, err = decodeJSONResponse(bytes.NewReader(res.body), &bellatrix.SignedBeaconBlock{})
+		response.Data.Bellatrix, response.Metadata, err = decodeJSONResponse(body, &bellatrix.SignedBeaconBlock{})
 	case spec.DataVersionCapella:
-		response.Data.Capella, response.Metadata, err = decodeJSONResponse(bytes.NewReader(res.body), &capella.SignedBeaconBlock{})
+		response.Data.Capella, response.Metadata, err = decodeJSONResponse(body, &capella.SignedBeaconBlock{})
 	case spec.DataVersionDeneb:
-		response.Data.Deneb, response.Metadata, err = decodeJSONResponse(bytes.NewReader(res.body), &deneb.SignedBeaconBlock{})
+		response.Data.Deneb, response.Metadata, err = decodeJSONResponse(body, &deneb.SignedBeaconBlock{})
 	default:
-		return nil, fmt.Errorf("unhandled version %s", res.consensusVersion)
+		return nil, fmt.Errorf("unhandled version %s", version)
 	}
 	if err != nil {
 		return nil, err
@@ -49,7 +49,7 @@ func (s *Service) Spec(ctx context.Context) (*api.Response[map[string]any], erro
 	}
 
 	// Up to us to fetch the information.
-	httpResponse, err := s.get2(ctx, "/eth/v1/config/spec")
+	httpResponse, err := s.get2(ctx, "Spec", "/eth/v1/config/spec", nil)
 	if err != nil {
 		return nil, err
 	}
@@ -29,7 +29,7 @@ func (s *Service) SubmitAggregateAttestations(ctx context.Context, aggregateAndP
 		return errors.Wrap(err, "failed to marshal JSON")
 	}
 
-	_, err = s.post(ctx, "/eth/v1/validator/aggregate_and_proofs", bytes.NewBuffer(specJSON))
+	_, err = s.post(ctx, "SubmitAggregateAttestations", "/eth/v1/validator/aggregate_and_proofs", nil, bytes.NewBuffer(specJSON))
 	if err != nil {
 		return errors.Wrap(err, "failed to submit aggregate and proofs")
 	}
@@ -29,7 +29,7 @@ func (s *Service) SubmitAttestations(ctx context.Context, attestations []*phase0
 		return errors.Wrap(err, "failed to marshal JSON")
 	}
 
-	_, err = s.post(ctx, "/eth/v1/beacon/pool/attestations", bytes.NewBuffer(specJSON))
+	_, err = s.post(ctx, "SubmitAttestations", "/eth/v1/beacon/pool/attestations", nil, bytes.NewBuffer(specJSON))
 	if err != nil {
 		return errors.Wrap(err, "failed to submit beacon attestations")
 	}
@@ -28,7 +28,7 @@ func (s *Service) SubmitAttesterSlashing(ctx context.Context, slashing *phase0.A
 		return errors.Wrap(err, "failed to marshal JSON")
 	}
 
-	_, err = s.post(ctx, "/eth/v1/beacon/pool/attester_slashings", bytes.NewBuffer(specJSON))
+	_, err = s.post(ctx, "SubmitAttesterSlashing", "/eth/v1/beacon/pool/attester_slashings", nil, bytes.NewBuffer(specJSON))
 	if err != nil {
 		return errors.Wrap(err, "failed to submit proposal slashing")
 	}
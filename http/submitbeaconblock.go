@@ -51,7 +51,7 @@ func (s *Service) SubmitBeaconBlock(ctx context.Context, block *spec.VersionedSi
 		return errors.Wrap(err, "failed to marshal JSON")
 	}
 
-	_, err = s.post(ctx, "/eth/v1/beacon/blocks", bytes.NewBuffer(specJSON))
+	_, err = s.post(ctx, "SubmitBeaconBlock", "/eth/v1/beacon/blocks", nil, bytes.NewBuffer(specJSON))
 	if err != nil {
 		return errors.Wrap(err, "failed to submit beacon block")
 	}
@@ -29,7 +29,7 @@ func (s *Service) SubmitBeaconCommitteeSubscriptions(ctx context.Context, subscr
 		return errors.Wrap(err, "failed to encode beacon committee subscriptions")
 	}
 
-	_, err := s.post(ctx, "/eth/v1/validator/beacon_committee_subscriptions", &reqBodyReader)
+	_, err := s.post(ctx, "SubmitBeaconCommitteeSubscriptions", "/eth/v1/validator/beacon_committee_subscriptions", nil, &reqBodyReader)
 	if err != nil {
 		return errors.Wrap(err, "failed to request beacon committee subscriptions")
 	}
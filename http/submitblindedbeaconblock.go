@@ -52,7 +52,7 @@ func (s *Service) SubmitBlindedBeaconBlock(ctx context.Context, block *api.Versi
 		return errors.Wrap(err, "failed to marshal JSON")
 	}
 
-	_, err = s.post(ctx, "/eth/v1/beacon/blinded_blocks", bytes.NewBuffer(specJSON))
+	_, err = s.post(ctx, "SubmitBlindedBeaconBlock", "/eth/v1/beacon/blinded_blocks", nil, bytes.NewBuffer(specJSON))
 	if err != nil {
 		return errors.Wrap(err, "failed to submit blinded beacon block")
 	}
@@ -53,7 +53,7 @@ func (s *Service) SubmitBlindedProposal(ctx context.Context, proposal *api.Versi
 
 	headers := make(map[string]string)
 	headers["Eth-Consensus-Version"] = strings.ToLower(proposal.Version.String())
-	_, err = s.post2(ctx, "/eth/v2/beacon/blinded_blocks", bytes.NewBuffer(specJSON), ContentTypeJSON, headers)
+	_, err = s.post2(ctx, "SubmitBlindedProposal", "/eth/v2/beacon/blinded_blocks", nil, bytes.NewBuffer(specJSON), ContentTypeJSON, headers)
 	if err != nil {
 		return errors.Wrap(err, "failed to submit blinded proposal")
 	}
@@ -29,7 +29,7 @@ func (s *Service) SubmitBLSToExecutionChanges(ctx context.Context, blsToExecutio
 		return errors.Wrap(err, "failed to marshal JSON")
 	}
 
-	_, err = s.post(ctx, "/eth/v1/beacon/pool/bls_to_execution_changes", bytes.NewBuffer(specJSON))
+	_, err = s.post(ctx, "SubmitBLSToExecutionChanges", "/eth/v1/beacon/pool/bls_to_execution_changes", nil, bytes.NewBuffer(specJSON))
 	if err != nil {
 		return errors.Wrap(err, "failed to submit BLS to execution change")
 	}
@@ -30,7 +30,7 @@ func (s *Service) SubmitProposalPreparations(ctx context.Context, preparations [
 		return errors.Wrap(err, "failed to encode proposal preparations")
 	}
 
-	_, err := s.post(ctx, "/eth/v1/validator/prepare_beacon_proposer", &reqBodyReader)
+	_, err := s.post(ctx, "SubmitProposalPreparations", "/eth/v1/validator/prepare_beacon_proposer", nil, &reqBodyReader)
 	if err != nil {
 		return errors.Wrap(err, "failed to send proposal preparations")
 	}
@@ -28,7 +28,7 @@ func (s *Service) SubmitProposalSlashing(ctx context.Context, slashing phase0.Pr
 		return errors.Wrap(err, "failed to marshal JSON")
 	}
 
-	_, err = s.post(ctx, "/eth/v1/beacon/pool/proposer_slashings", bytes.NewBuffer(specJSON))
+	_, err = s.post(ctx, "SubmitProposalSlashing", "/eth/v1/beacon/pool/proposer_slashings", nil, bytes.NewBuffer(specJSON))
 	if err != nil {
 		return errors.Wrap(err, "failed to submit proposal slashing")
 	}
@@ -29,7 +29,7 @@ func (s *Service) SubmitSyncCommitteeContributions(ctx context.Context, contribu
 		return errors.Wrap(err, "failed to marshal JSON")
 	}
 
-	_, err = s.post(ctx, "/eth/v1/validator/contribution_and_proofs", bytes.NewBuffer(specJSON))
+	_, err = s.post(ctx, "SubmitSyncCommitteeContributions", "/eth/v1/validator/contribution_and_proofs", nil, bytes.NewBuffer(specJSON))
 	if err != nil {
 		return errors.Wrap(err, "failed to submit contribution and proofs")
 	}
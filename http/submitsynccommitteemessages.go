@@ -29,7 +29,7 @@ func (s *Service) SubmitSyncCommitteeMessages(ctx context.Context, messages []*a
 		return errors.Wrap(err, "failed to marshal JSON")
 	}
 
-	_, err = s.post(ctx, "/eth/v1/beacon/pool/sync_committees", bytes.NewBuffer(specJSON))
+	_, err = s.post(ctx, "SubmitSyncCommitteeMessages", "/eth/v1/beacon/pool/sync_committees", nil, bytes.NewBuffer(specJSON))
 	if err != nil {
 		return errors.Wrap(err, "failed to submit sync committee messages")
 	}
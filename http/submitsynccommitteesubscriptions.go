@@ -29,7 +29,7 @@ func (s *Service) SubmitSyncCommitteeSubscriptions(ctx context.Context, subscrip
 		return errors.Wrap(err, "failed to encode sync committee subscriptions")
 	}
 
-	_, err := s.post(ctx, "/eth/v1/validator/sync_committee_subscriptions", &reqBodyReader)
+	_, err := s.post(ctx, "SubmitSyncCommitteeSubscriptions", "/eth/v1/validator/sync_committee_subscriptions", nil, &reqBodyReader)
 	if err != nil {
 		return errors.Wrap(err, "failed to request sync committee subscriptions")
 	}
@@ -58,7 +58,7 @@ func (s *Service) SubmitValidatorRegistrations(ctx context.Context, registration
 	if err != nil {
 		return errors.Wrap(err, "failed to marshal JSON")
 	}
-	_, err = s.post(ctx, "/eth/v1/validator/register_validator", bytes.NewBuffer(specJSON))
+	_, err = s.post(ctx, "SubmitValidatorRegistrations", "/eth/v1/validator/register_validator", nil, bytes.NewBuffer(specJSON))
 	if err != nil {
 		return errors.Wrap(err, "failed to submit validator registration")
 	}
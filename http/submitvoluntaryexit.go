@@ -29,7 +29,7 @@ func (s *Service) SubmitVoluntaryExit(ctx context.Context, voluntaryExit *phase0
 		return errors.Wrap(err, "failed to marshal JSON")
 	}
 
-	_, err = s.post(ctx, "/eth/v1/beacon/pool/voluntary_exits", bytes.NewBuffer(specJSON))
+	_, err = s.post(ctx, "SubmitVoluntaryExit", "/eth/v1/beacon/pool/voluntary_exits", nil, bytes.NewBuffer(specJSON))
 	if err != nil {
 		return errors.Wrap(err, "failed to submit voluntary exit")
 	}
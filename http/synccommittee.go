@@ -33,8 +33,8 @@ func (s *Service) SyncCommittee(ctx context.Context,
 	if opts == nil {
 		return nil, errors.New("no options specified")
 	}
-	if opts.State == "" {
-		return nil, errors.New("no state specified")
+	if err := opts.State.Validate(); err != nil {
+		return nil, err
 	}
 
 	url := fmt.Sprintf("/eth/v1/beacon/states/%s/sync_committees", opts.State)
@@ -42,7 +42,7 @@ func (s *Service) SyncCommittee(ctx context.Context,
 		url = fmt.Sprintf("%s?epoch=%d", url, *opts.Epoch)
 	}
 
-	httpResponse, err := s.get2(ctx, url)
+	httpResponse, err := s.get2(ctx, "SyncCommittee", url, &opts.Common)
 	if err != nil {
 		return nil, err
 	}
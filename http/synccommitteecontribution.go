@@ -37,7 +37,7 @@ func (s *Service) SyncCommitteeContribution(ctx context.Context,
 		return nil, errors.New("no beacon block root specified")
 	}
 
-	httpResponse, err := s.get2(ctx, fmt.Sprintf("/eth/v1/validator/sync_committee_contribution?slot=%d&subcommittee_index=%d&beacon_block_root=%#x", opts.Slot, opts.SubcommitteeIndex, opts.BeaconBlockRoot))
+	httpResponse, err := s.get2(ctx, "SyncCommitteeContribution", fmt.Sprintf("/eth/v1/validator/sync_committee_contribution?slot=%d&subcommittee_index=%d&beacon_block_root=%#x", opts.Slot, opts.SubcommitteeIndex, opts.BeaconBlockRoot), &opts.Common)
 	if err != nil {
 		return nil, err
 	}
@@ -35,8 +35,8 @@ func (s *Service) ValidatorBalances(ctx context.Context,
 	if opts == nil {
 		return nil, errors.New("no options specified")
 	}
-	if opts.State == "" {
-		return nil, errors.New("no state specified")
+	if err := opts.State.Validate(); err != nil {
+		return nil, err
 	}
 
 	if len(opts.Indices) > s.indexChunkSize(ctx) {
@@ -52,7 +52,7 @@ func (s *Service) ValidatorBalances(ctx context.Context,
 		url = fmt.Sprintf("%s?id=%s", url, strings.Join(ids, ","))
 	}
 
-	response, err := s.get2(ctx, url)
+	response, err := s.get2(ctx, "ValidatorBalances", url, &opts.Common)
 	if err != nil {
 		return nil, err
 	}
@@ -82,6 +82,7 @@ func (s *Service) chunkedValidatorBalances(ctx context.Context,
 			chunkEnd = len(opts.Indices)
 		}
 		chunkOpts := &api.ValidatorBalancesOpts{
+			Common:  opts.Common,
 			State:   opts.State,
 			Indices: opts.Indices[chunkStart:chunkEnd],
 		}
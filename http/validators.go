@@ -33,6 +33,7 @@ import (
 var indexChunkSizes = map[string]int{
 	"default":    1000,
 	"lighthouse": 1000,
+	"lodestar":   1000,
 	"nimbus":     1000,
 	"prysm":      1000,
 	"teku":       1000,
@@ -47,6 +48,7 @@ var indexChunkSizes = map[string]int{
 var pubKeyChunkSizes = map[string]int{
 	"default":    75,
 	"lighthouse": 75,
+	"lodestar":   75,
 	"nimbus":     75,
 	"prysm":      75,
 	"teku":       75,
@@ -58,27 +60,15 @@ func (s *Service) indexChunkSize(ctx context.Context) int {
 		return s.userIndexChunkSize
 	}
 
-	var nodeClient string
-	response, err := s.NodeClient(ctx)
+	nodeClient := "default"
+	capabilities, err := s.nodeCapabilities(ctx)
 	if err == nil {
-		nodeClient = response.Data
-	} else {
-		// Use default.
-		nodeClient = "default"
+		if _, exists := indexChunkSizes[capabilities.Client]; exists {
+			nodeClient = capabilities.Client
+		}
 	}
 
-	switch {
-	case strings.Contains(nodeClient, "lighthouse"):
-		return indexChunkSizes["lighthouse"]
-	case strings.Contains(nodeClient, "nimbus"):
-		return indexChunkSizes["nimbus"]
-	case strings.Contains(nodeClient, "prysm"):
-		return indexChunkSizes["prysm"]
-	case strings.Contains(nodeClient, "teku"):
-		return indexChunkSizes["teku"]
-	default:
-		return indexChunkSizes["default"]
-	}
+	return indexChunkSizes[nodeClient]
 }
 
 // pubKeyChunkSize is the maximum number of validator public keys to send in each request.
@@ -87,27 +77,15 @@ func (s *Service) pubKeyChunkSize(ctx context.Context) int {
 		return s.userPubKeyChunkSize
 	}
 
-	var nodeClient string
-	response, err := s.NodeClient(ctx)
+	nodeClient := "default"
+	capabilities, err := s.nodeCapabilities(ctx)
 	if err == nil {
-		nodeClient = response.Data
-	} else {
-		// Use default.
-		nodeClient = "default"
+		if _, exists := pubKeyChunkSizes[capabilities.Client]; exists {
+			nodeClient = capabilities.Client
+		}
 	}
 
-	switch {
-	case strings.Contains(nodeClient, "lighthouse"):
-		return pubKeyChunkSizes["lighthouse"]
-	case strings.Contains(nodeClient, "nimbus"):
-		return pubKeyChunkSizes["nimbus"]
-	case strings.Contains(nodeClient, "prysm"):
-		return pubKeyChunkSizes["prysm"]
-	case strings.Contains(nodeClient, "teku"):
-		return pubKeyChunkSizes["teku"]
-	default:
-		return pubKeyChunkSizes["default"]
-	}
+	return pubKeyChunkSizes[nodeClient]
 }
 
 // Validators provides the validators, with their balance and status, for the given options.
@@ -120,8 +98,8 @@ func (s *Service) Validators(ctx context.Context,
 	if opts == nil {
 		return nil, errors.New("no options specified")
 	}
-	if opts.State == "" {
-		return nil, errors.New("no state specified")
+	if err := opts.State.Validate(); err != nil {
+		return nil, err
 	}
 	if len(opts.Indices) > 0 && len(opts.PubKeys) > 0 {
 		return nil, errors.New("cannot specify both indices and public keys")
@@ -152,12 +130,15 @@ func (s *Service) Validators(ctx context.Context,
 		url = fmt.Sprintf("%s?id=%s", url, strings.Join(ids, ","))
 	}
 
-	respBodyReader, err := s.get(ctx, url)
+	// The validators response can be large, so decode it as it arrives rather than
+	// reading it in to memory first.
+	res, err := s.getStream(ctx, "Validators", url, &opts.Common, ContentTypeJSON.MediaType())
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to request validators")
 	}
+	defer res.body.Close()
 
-	data, metadata, err := decodeJSONResponse(respBodyReader, []*apiv1.Validator{})
+	data, metadata, err := decodeJSONResponse(res.body, []*apiv1.Validator{})
 	if err != nil {
 		return nil, err
 	}
@@ -183,7 +164,7 @@ func (s *Service) validatorsFromState(ctx context.Context,
 	*api.Response[map[phase0.ValidatorIndex]*apiv1.Validator],
 	error,
 ) {
-	stateResponse, err := s.BeaconState(ctx, &api.BeaconStateOpts{State: opts.State})
+	stateResponse, err := s.BeaconState(ctx, &api.BeaconStateOpts{Common: opts.Common, State: opts.State})
 	if err != nil {
 		return nil, err
 	}
@@ -265,7 +246,7 @@ func (s *Service) chunkedValidatorsByIndex(ctx context.Context,
 			chunkEnd = len(opts.Indices)
 		}
 		chunk := opts.Indices[chunkStart:chunkEnd]
-		chunkRes, err := s.Validators(ctx, &api.ValidatorsOpts{State: opts.State, Indices: chunk})
+		chunkRes, err := s.Validators(ctx, &api.ValidatorsOpts{Common: opts.Common, State: opts.State, Indices: chunk})
 		if err != nil {
 			return nil, errors.Wrap(err, "failed to obtain chunk")
 		}
@@ -300,7 +281,7 @@ func (s *Service) chunkedValidatorsByPubkey(ctx context.Context,
 			chunkEnd = len(opts.PubKeys)
 		}
 		chunk := opts.PubKeys[chunkStart:chunkEnd]
-		chunkRes, err := s.Validators(ctx, &api.ValidatorsOpts{State: opts.State, PubKeys: chunk})
+		chunkRes, err := s.Validators(ctx, &api.ValidatorsOpts{Common: opts.Common, State: opts.State, PubKeys: chunk})
 		if err != nil {
 			return nil, errors.Wrap(err, "failed to obtain chunk")
 		}
@@ -27,7 +27,7 @@ type voluntaryExitPoolJSON struct {
 
 // VoluntaryExitPool obtains the voluntary exit pool.
 func (s *Service) VoluntaryExitPool(ctx context.Context) ([]*phase0.SignedVoluntaryExit, error) {
-	respBodyReader, err := s.get(ctx, "/eth/v1/beacon/pool/voluntary_exits")
+	respBodyReader, err := s.get(ctx, "VoluntaryExitPool", "/eth/v1/beacon/pool/voluntary_exits", nil)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to request voluntary exit pool")
 	}
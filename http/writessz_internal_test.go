@@ -0,0 +1,89 @@
+// Copyright © 2023 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package http
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/attestantio/go-eth2-client/api"
+	"github.com/attestantio/go-eth2-client/spec"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteSSZ(t *testing.T) {
+	ctx := context.Background()
+
+	body := bytes.Repeat([]byte{0x01, 0x02, 0x03, 0x04}, 1024)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/eth/v2/debug/beacon/states/head":
+			w.Header().Set("Content-Type", "application/octet-stream")
+			w.Header().Set("Eth-Consensus-Version", "capella")
+			_, _ = w.Write(body)
+		case "/eth/v2/beacon/blocks/head":
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"version":"capella","data":{}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"code":404,"message":"not found"}`))
+		}
+	}))
+	defer srv.Close()
+
+	base, err := url.Parse(srv.URL)
+	require.NoError(t, err)
+	s := &Service{
+		log:     zerolog.Nop(),
+		base:    base,
+		address: srv.URL,
+		client:  srv.Client(),
+		timeout: timeout,
+	}
+
+	t.Run("StateGood", func(t *testing.T) {
+		var buf bytes.Buffer
+		res, err := s.WriteBeaconStateSSZ(ctx, &api.BeaconStateOpts{State: "head"}, &buf)
+		require.NoError(t, err)
+		require.Equal(t, spec.DataVersionCapella, res.Data.Version)
+		require.Equal(t, int64(len(body)), res.Data.Length)
+		require.Equal(t, body, buf.Bytes())
+	})
+
+	t.Run("StateMissing", func(t *testing.T) {
+		var buf bytes.Buffer
+		_, err := s.WriteBeaconStateSSZ(ctx, &api.BeaconStateOpts{State: "1"}, &buf)
+		var apiErr *api.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode)
+		require.Zero(t, buf.Len())
+	})
+
+	t.Run("BlockJSON", func(t *testing.T) {
+		var buf bytes.Buffer
+		_, err := s.WriteSignedBeaconBlockSSZ(ctx, &api.SignedBeaconBlockOpts{Block: "head"}, &buf)
+		require.EqualError(t, err, "beacon node returned JSON rather than SSZ")
+		require.Zero(t, buf.Len())
+	})
+
+	t.Run("WriterMissing", func(t *testing.T) {
+		_, err := s.WriteBeaconStateSSZ(ctx, &api.BeaconStateOpts{State: "head"}, nil)
+		require.EqualError(t, err, "no writer specified")
+	})
+}
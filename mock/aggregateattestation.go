@@ -22,11 +22,15 @@ import (
 
 // AggregateAttestation fetches the aggregate attestation given an attestation.
 func (s *Service) AggregateAttestation(_ context.Context,
-	_ *api.AggregateAttestationOpts,
+	opts *api.AggregateAttestationOpts,
 ) (
 	*api.Response[*phase0.Attestation],
 	error,
 ) {
+	if s.chain != nil {
+		return s.chain.aggregateAttestation(opts)
+	}
+
 	return &api.Response[*phase0.Attestation]{
 		Data: &phase0.Attestation{
 			Data: &phase0.AttestationData{
@@ -22,11 +22,15 @@ import (
 
 // AttestationData fetches the attestation data for the given slot and committee index.
 func (s *Service) AttestationData(_ context.Context,
-	_ *api.AttestationDataOpts,
+	opts *api.AttestationDataOpts,
 ) (
 	*api.Response[*phase0.AttestationData],
 	error,
 ) {
+	if s.chain != nil {
+		return s.chain.attestationData(opts)
+	}
+
 	return &api.Response[*phase0.AttestationData]{
 		Data: &phase0.AttestationData{
 			Source: &phase0.Checkpoint{},
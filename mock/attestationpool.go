@@ -22,11 +22,15 @@ import (
 
 // AttestationPool fetches the attestation pool for the given slot.
 func (s *Service) AttestationPool(_ context.Context,
-	_ *api.AttestationPoolOpts,
+	opts *api.AttestationPoolOpts,
 ) (
 	*api.Response[[]*phase0.Attestation],
 	error,
 ) {
+	if s.chain != nil {
+		return s.chain.attestationPool(opts)
+	}
+
 	data := make([]*phase0.Attestation, 5)
 	for i := 0; i < 5; i++ {
 		data[i] = &phase0.Attestation{
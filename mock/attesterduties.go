@@ -18,15 +18,23 @@ import (
 
 	"github.com/attestantio/go-eth2-client/api"
 	apiv1 "github.com/attestantio/go-eth2-client/api/v1"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
 )
 
 // AttesterDuties obtains attester duties.
 // If validatorIndicess is nil it will return all duties for the given epoch.
 func (s *Service) AttesterDuties(_ context.Context, opts *api.AttesterDutiesOpts) (*api.Response[[]*apiv1.AttesterDuty], error) {
+	if s.chain != nil {
+		return s.chain.attesterDuties(opts)
+	}
+
 	data := make([]*apiv1.AttesterDuty, len(opts.Indices))
 	for i := range opts.Indices {
 		data[i] = &apiv1.AttesterDuty{
-			ValidatorIndex: opts.Indices[i],
+			Slot:             phase0.Slot(uint64(opts.Epoch)*32 + uint64(i)%32),
+			ValidatorIndex:   opts.Indices[i],
+			CommitteeLength:  1,
+			CommitteesAtSlot: 1,
 		}
 	}
 
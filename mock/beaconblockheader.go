@@ -23,11 +23,15 @@ import (
 
 // BeaconBlockHeader provides the block header of a given block ID.
 func (s *Service) BeaconBlockHeader(_ context.Context,
-	_ *api.BeaconBlockHeaderOpts,
+	opts *api.BeaconBlockHeaderOpts,
 ) (
 	*api.Response[*apiv1.BeaconBlockHeader],
 	error,
 ) {
+	if s.chain != nil {
+		return s.chain.beaconBlockHeader(opts)
+	}
+
 	return &api.Response[*apiv1.BeaconBlockHeader]{
 		Data: &apiv1.BeaconBlockHeader{
 			Header: &phase0.SignedBeaconBlockHeader{
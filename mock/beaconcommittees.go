@@ -22,11 +22,15 @@ import (
 
 // BeaconCommittees fetches all beacon committees for the epoch at the given state.
 func (s *Service) BeaconCommittees(_ context.Context,
-	_ *api.BeaconCommitteesOpts,
+	opts *api.BeaconCommitteesOpts,
 ) (
 	*api.Response[[]*apiv1.BeaconCommittee],
 	error,
 ) {
+	if s.chain != nil {
+		return s.chain.beaconCommittees(opts)
+	}
+
 	data := make([]*apiv1.BeaconCommittee, 5)
 	for i := 0; i < 5; i++ {
 		data[i] = &apiv1.BeaconCommittee{}
@@ -21,7 +21,11 @@ import (
 )
 
 // BeaconStateRoot fetches a beacon state's root given a state ID.
-func (s *Service) BeaconStateRoot(_ context.Context, _ *api.BeaconStateRootOpts) (*api.Response[*phase0.Root], error) {
+func (s *Service) BeaconStateRoot(_ context.Context, opts *api.BeaconStateRootOpts) (*api.Response[*phase0.Root], error) {
+	if s.chain != nil {
+		return s.chain.beaconStateRoot(opts)
+	}
+
 	data := phase0.Root{}
 
 	return &api.Response[*phase0.Root]{
@@ -0,0 +1,32 @@
+// Copyright © 2023 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mock
+
+import (
+	"context"
+
+	"github.com/attestantio/go-eth2-client/api"
+)
+
+// Capabilities provides the capabilities of the node.
+// The mock supports all endpoints, so no endpoint capabilities are returned.
+func (s *Service) Capabilities(_ context.Context) (*api.Response[*api.Capabilities], error) {
+	return &api.Response[*api.Capabilities]{
+		Data: &api.Capabilities{
+			Client:    s.nodeVersion,
+			Endpoints: make(map[string]api.EndpointCapabilities),
+		},
+		Metadata: make(map[string]any),
+	}, nil
+}
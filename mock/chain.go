@@ -0,0 +1,423 @@
+// Copyright © 2023 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mock
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	client "github.com/attestantio/go-eth2-client"
+	"github.com/attestantio/go-eth2-client/api"
+	apiv1 "github.com/attestantio/go-eth2-client/api/v1"
+	"github.com/attestantio/go-eth2-client/clock"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+)
+
+const (
+	chainSlotDuration                 = 12 * time.Second
+	chainSlotsPerEpoch                = uint64(32)
+	chainFarFutureEpoch               = phase0.Epoch(0xffffffffffffffff)
+	chainMaxEffectiveBalance          = phase0.Gwei(32000000000)
+	chainTargetCommitteeSize          = uint64(128)
+	chainMaxCommitteesPerSlot         = uint64(64)
+	chainMaxAttestations              = 128
+	chainMaxVoluntaryExits            = 16
+	chainMaxSeedLookahead             = phase0.Epoch(4)
+	chainMinWithdrawabilityDelay      = phase0.Epoch(256)
+	chainEpochsPerSyncCommitteePeriod = uint64(256)
+	chainSyncCommitteeSize            = uint64(512)
+	chainSyncCommitteeSubnetCount     = uint64(4)
+	// chainMaxGenesisAge is the maximum number of slots that the genesis of a simulated chain can be
+	// in the past, as the chain builds a block for every slot up to the current one.
+	chainMaxGenesisAge = uint64(8192)
+	// chainAttestationReward is the amount by which a validator's balance changes at the end of an
+	// epoch, up if the validator's attestation for the epoch was included in a block and down if not.
+	chainAttestationReward = phase0.Gwei(10000)
+	// chainEventBuffer is the number of events buffered for each subscriber.
+	chainEventBuffer = 1024
+)
+
+// chain is a deterministic in-memory beacon chain, used by the mock when simulation is enabled.
+//
+// The chain has a block in every slot.  A block for the current slot can be submitted until the
+// slot ends, after which the chain builds the block itself.  Attestations and voluntary exits
+// submitted to the chain are held in pools until they are included in a block.  Rewards and
+// penalties for attestations are applied at epoch transitions, and justification and finalization
+// always trail the current epoch by one and two epochs respectively.
+type chain struct {
+	source      clock.Source
+	genesisTime time.Time
+
+	mutex      sync.Mutex
+	validators []*phase0.Validator
+	// balances holds the validator balances at the start of each epoch.
+	balances     [][]phase0.Gwei
+	blocks       []*phase0.SignedBeaconBlock
+	blockRoots   []phase0.Root
+	stateRoots   []phase0.Root
+	blockSlots   map[phase0.Root]phase0.Slot
+	stateSlots   map[phase0.Root]phase0.Slot
+	attestations []*phase0.Attestation
+	exits        []*phase0.SignedVoluntaryExit
+	// participation holds the validators with included attestations for each epoch.
+	participation map[phase0.Epoch]map[phase0.ValidatorIndex]bool
+	actives       map[phase0.Epoch][]phase0.ValidatorIndex
+	shuffles      map[phase0.Epoch][]phase0.ValidatorIndex
+
+	subscriptionsMutex sync.Mutex
+	subscriptions      map[*chainSubscription]struct{}
+}
+
+// chainSubscription is a subscriber to the chain's events.
+type chainSubscription struct {
+	topics map[string]bool
+	events chan *apiv1.Event
+}
+
+// newChain creates a new chain with the given number of validators, all active from genesis.
+func newChain(source clock.Source, genesisTime time.Time, validators uint64) *chain {
+	c := &chain{
+		source:        source,
+		genesisTime:   genesisTime,
+		validators:    make([]*phase0.Validator, validators),
+		blockSlots:    make(map[phase0.Root]phase0.Slot),
+		stateSlots:    make(map[phase0.Root]phase0.Slot),
+		participation: make(map[phase0.Epoch]map[phase0.ValidatorIndex]bool),
+		actives:       make(map[phase0.Epoch][]phase0.ValidatorIndex),
+		shuffles:      make(map[phase0.Epoch][]phase0.ValidatorIndex),
+		subscriptions: make(map[*chainSubscription]struct{}),
+	}
+
+	balances := make([]phase0.Gwei, validators)
+	for i := uint64(0); i < validators; i++ {
+		pubKeyStart := chainHash("pubkey", i)
+		pubKeyEnd := chainHash("pubkey-end", i)
+		var pubKey phase0.BLSPubKey
+		copy(pubKey[:], pubKeyStart[:])
+		copy(pubKey[32:], pubKeyEnd[:])
+		withdrawalCredentials := chainHash("withdrawal-credentials", i)
+		withdrawalCredentials[0] = 0x00
+
+		c.validators[i] = &phase0.Validator{
+			PublicKey:                  pubKey,
+			WithdrawalCredentials:      withdrawalCredentials[:],
+			EffectiveBalance:           chainMaxEffectiveBalance,
+			ActivationEligibilityEpoch: 0,
+			ActivationEpoch:            0,
+			ExitEpoch:                  chainFarFutureEpoch,
+			WithdrawableEpoch:          chainFarFutureEpoch,
+		}
+		balances[i] = chainMaxEffectiveBalance
+	}
+	c.balances = [][]phase0.Gwei{balances}
+
+	genesisBlock := c.buildBlock(0, chainSignature("randao", 0), [32]byte{})
+	c.storeBlock(&phase0.SignedBeaconBlock{
+		Message:   genesisBlock,
+		Signature: phase0.BLSSignature{},
+	})
+
+	return c
+}
+
+// start advances the chain at the start of each slot until the context is done.
+func (c *chain) start(ctx context.Context) {
+	// Wait for the next slot before returning, so that a fake clock advanced
+	// immediately afterwards still advances the chain.
+	next := c.source.After(c.slotStart(c.currentSlot() + 1).Sub(c.source.Now()))
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-next:
+				c.advance()
+				next = c.source.After(c.slotStart(c.currentSlot() + 1).Sub(c.source.Now()))
+			}
+		}
+	}()
+}
+
+// advance builds blocks for any slots that have ended without one.
+// The mutex is released between blocks, so that catching up after the clock has
+// moved on by many slots does not hold up other callers.
+func (c *chain) advance() {
+	for {
+		c.mutex.Lock()
+		events, built := c.advanceSlotLocked()
+		c.mutex.Unlock()
+
+		c.publish(events)
+		if !built {
+			return
+		}
+	}
+}
+
+// advanceSlotLocked builds the block for the slot after the head, if that slot has ended.
+// It must be called with the mutex held, and returns the resultant events and whether a
+// block was built.
+func (c *chain) advanceSlotLocked() ([]*apiv1.Event, bool) {
+	slot := c.headSlotLocked() + 1
+	if slot >= c.currentSlot() {
+		return nil, false
+	}
+
+	block := c.buildBlock(slot, chainSignature("randao", uint64(slot)), [32]byte{})
+	events, err := c.addBlock(&phase0.SignedBeaconBlock{
+		Message:   block,
+		Signature: chainSignature("block", uint64(slot)),
+	})
+	if err != nil {
+		// Should not happen, as the block was built by the chain.
+		log.Error().Err(err).Uint64("slot", uint64(slot)).Msg("Failed to add built block")
+
+		return nil, false
+	}
+
+	return events, true
+}
+
+// subscribe feeds events with the given topics to the handler until the context is done.
+func (c *chain) subscribe(ctx context.Context, topics []string, handler client.EventHandlerFunc) {
+	subscription := &chainSubscription{
+		topics: make(map[string]bool, len(topics)),
+		events: make(chan *apiv1.Event, chainEventBuffer),
+	}
+	for _, topic := range topics {
+		subscription.topics[topic] = true
+	}
+
+	c.subscriptionsMutex.Lock()
+	c.subscriptions[subscription] = struct{}{}
+	c.subscriptionsMutex.Unlock()
+
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				c.subscriptionsMutex.Lock()
+				delete(c.subscriptions, subscription)
+				c.subscriptionsMutex.Unlock()
+
+				return
+			case event := <-subscription.events:
+				handler(event)
+			}
+		}
+	}()
+}
+
+// publish sends events to interested subscribers.
+func (c *chain) publish(events []*apiv1.Event) {
+	if len(events) == 0 {
+		return
+	}
+
+	c.subscriptionsMutex.Lock()
+	defer c.subscriptionsMutex.Unlock()
+	for subscription := range c.subscriptions {
+		for _, event := range events {
+			if !subscription.topics[event.Topic] {
+				continue
+			}
+			select {
+			case subscription.events <- event:
+			default:
+				log.Warn().Str("topic", event.Topic).Msg("Event subscriber is full; dropping event")
+			}
+		}
+	}
+}
+
+// currentSlot provides the slot of the chain's clock.
+func (c *chain) currentSlot() phase0.Slot {
+	now := c.source.Now()
+	if now.Before(c.genesisTime) {
+		return 0
+	}
+
+	return phase0.Slot(now.Sub(c.genesisTime) / chainSlotDuration)
+}
+
+// slotStart provides the time at which the given slot starts.
+func (c *chain) slotStart(slot phase0.Slot) time.Time {
+	return c.genesisTime.Add(time.Duration(slot) * chainSlotDuration)
+}
+
+// headSlot provides the slot of the head of the chain.
+func (c *chain) headSlot() phase0.Slot {
+	c.advance()
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.headSlotLocked()
+}
+
+func (c *chain) headSlotLocked() phase0.Slot {
+	return phase0.Slot(len(c.blocks) - 1)
+}
+
+func (c *chain) currentEpoch() phase0.Epoch {
+	return chainEpoch(c.currentSlot())
+}
+
+// checkpointRoot provides the root of the checkpoint block for the given epoch.
+func (c *chain) checkpointRoot(epoch phase0.Epoch) phase0.Root {
+	slot := chainEpochStart(epoch)
+	if head := c.headSlotLocked(); slot > head {
+		slot = head
+	}
+
+	return c.blockRoots[slot]
+}
+
+// finalityAt provides the finality as seen by the state at the given slot.
+func (c *chain) finalityAt(slot phase0.Slot) *apiv1.Finality {
+	epoch := chainEpoch(slot)
+	finalized := chainEpochsBefore(epoch, 2)
+	justified := chainEpochsBefore(epoch, 1)
+
+	return &apiv1.Finality{
+		Finalized: &phase0.Checkpoint{
+			Epoch: finalized,
+			Root:  c.checkpointRoot(finalized),
+		},
+		Justified: &phase0.Checkpoint{
+			Epoch: justified,
+			Root:  c.checkpointRoot(justified),
+		},
+		PreviousJustified: &phase0.Checkpoint{
+			Epoch: finalized,
+			Root:  c.checkpointRoot(finalized),
+		},
+	}
+}
+
+// stateSlot resolves a state ID to a slot.
+func (c *chain) stateSlot(stateID api.StateID) (phase0.Slot, error) {
+	headEpoch := chainEpoch(c.headSlotLocked())
+	switch stateID {
+	case api.StateIDHead:
+		return c.headSlotLocked(), nil
+	case api.StateIDGenesis:
+		return 0, nil
+	case api.StateIDJustified:
+		return chainEpochStart(chainEpochsBefore(headEpoch, 1)), nil
+	case api.StateIDFinalized:
+		return chainEpochStart(chainEpochsBefore(headEpoch, 2)), nil
+	}
+	if slot, isSlot := stateID.Slot(); isSlot {
+		if slot > c.headSlotLocked() {
+			return 0, chainNotFound(fmt.Sprintf("state %s not found", stateID))
+		}
+
+		return slot, nil
+	}
+	if root, isRoot := stateID.Root(); isRoot {
+		if slot, exists := c.stateSlots[root]; exists {
+			return slot, nil
+		}
+	}
+
+	return 0, chainNotFound(fmt.Sprintf("state %s not found", stateID))
+}
+
+// blockSlot resolves a block ID to a slot.
+func (c *chain) blockSlot(blockID api.BlockID) (phase0.Slot, error) {
+	switch blockID {
+	case api.BlockIDHead:
+		return c.headSlotLocked(), nil
+	case api.BlockIDGenesis:
+		return 0, nil
+	case api.BlockIDFinalized:
+		return chainEpochStart(chainEpochsBefore(chainEpoch(c.headSlotLocked()), 2)), nil
+	}
+	if slot, isSlot := blockID.Slot(); isSlot {
+		if slot > c.headSlotLocked() {
+			return 0, chainNotFound(fmt.Sprintf("block %s not found", blockID))
+		}
+
+		return slot, nil
+	}
+	if root, isRoot := blockID.Root(); isRoot {
+		if slot, exists := c.blockSlots[root]; exists {
+			return slot, nil
+		}
+	}
+
+	return 0, chainNotFound(fmt.Sprintf("block %s not found", blockID))
+}
+
+// balancesAt provides the validator balances for the given epoch.
+func (c *chain) balancesAt(epoch phase0.Epoch) []phase0.Gwei {
+	if int(epoch) >= len(c.balances) {
+		return c.balances[len(c.balances)-1]
+	}
+
+	return c.balances[epoch]
+}
+
+// chainNotFound provides the error returned by a beacon node when an item is not found.
+func chainNotFound(message string) error {
+	return &api.Error{
+		Method:     http.MethodGet,
+		StatusCode: http.StatusNotFound,
+		Data:       []byte(fmt.Sprintf(`{"code":404,"message":%q}`, message)),
+	}
+}
+
+// chainHash provides a deterministic hash of a label and values.
+func chainHash(label string, values ...uint64) [32]byte {
+	data := []byte(label)
+	for _, value := range values {
+		data = binary.LittleEndian.AppendUint64(data, value)
+	}
+
+	return sha256.Sum256(data)
+}
+
+// chainSignature provides a deterministic placeholder signature.
+func chainSignature(label string, value uint64) phase0.BLSSignature {
+	var signature phase0.BLSSignature
+	for i := 0; i < 3; i++ {
+		hash := chainHash(label, value, uint64(i))
+		copy(signature[i*32:], hash[:])
+	}
+
+	return signature
+}
+
+func chainEpoch(slot phase0.Slot) phase0.Epoch {
+	return phase0.Epoch(uint64(slot) / chainSlotsPerEpoch)
+}
+
+func chainEpochStart(epoch phase0.Epoch) phase0.Slot {
+	return phase0.Slot(uint64(epoch) * chainSlotsPerEpoch)
+}
+
+// chainEpochsBefore provides the epoch the given distance before the given epoch, floored at genesis.
+func chainEpochsBefore(epoch phase0.Epoch, distance phase0.Epoch) phase0.Epoch {
+	if epoch < distance {
+		return 0
+	}
+
+	return epoch - distance
+}
@@ -0,0 +1,352 @@
+// Copyright © 2023 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mock_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/attestantio/go-eth2-client/api"
+	apiv1 "github.com/attestantio/go-eth2-client/api/v1"
+	"github.com/attestantio/go-eth2-client/clock"
+	"github.com/attestantio/go-eth2-client/mock"
+	"github.com/attestantio/go-eth2-client/spec"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/prysmaticlabs/go-bitfield"
+	"github.com/stretchr/testify/require"
+)
+
+const slotDuration = 12 * time.Second
+
+func newSimulatedChain(t *testing.T) (*mock.Service, *clock.Fake) {
+	t.Helper()
+
+	source := clock.NewFake(time.Unix(1606824023, 0))
+	service, err := mock.New(context.Background(),
+		mock.WithClockSource(source),
+		mock.WithSimulatedChain(64),
+	)
+	require.NoError(t, err)
+
+	return service, source
+}
+
+func TestSimulatedChainBlocks(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	service, source := newSimulatedChain(t)
+
+	source.Advance(40 * slotDuration)
+
+	syncing, err := service.NodeSyncing(ctx)
+	require.NoError(t, err)
+	require.Equal(t, phase0.Slot(39), syncing.Data.HeadSlot)
+
+	duties := make(map[phase0.Slot]phase0.ValidatorIndex)
+	for _, epoch := range []phase0.Epoch{0, 1} {
+		proposerDuties, err := service.ProposerDuties(ctx, &api.ProposerDutiesOpts{Epoch: epoch})
+		require.NoError(t, err)
+		for _, duty := range proposerDuties.Data {
+			duties[duty.Slot] = duty.ValidatorIndex
+		}
+	}
+
+	parentRoot, err := service.BeaconBlockRoot(ctx, &api.BeaconBlockRootOpts{Block: api.BlockIDGenesis})
+	require.NoError(t, err)
+	for slot := phase0.Slot(1); slot <= 39; slot++ {
+		header, err := service.BeaconBlockHeader(ctx, &api.BeaconBlockHeaderOpts{Block: api.BlockIDFromSlot(slot)})
+		require.NoError(t, err)
+		require.Equal(t, slot, header.Data.Header.Message.Slot)
+		require.Equal(t, duties[slot], header.Data.Header.Message.ProposerIndex)
+		require.Equal(t, *parentRoot.Data, header.Data.Header.Message.ParentRoot)
+
+		block, err := service.SignedBeaconBlock(ctx, &api.SignedBeaconBlockOpts{Block: api.BlockIDFromRoot(header.Data.Root)})
+		require.NoError(t, err)
+		blockRoot, err := block.Data.Root()
+		require.NoError(t, err)
+		require.Equal(t, header.Data.Root, blockRoot)
+
+		stateRoot, err := service.BeaconStateRoot(ctx, &api.BeaconStateRootOpts{State: api.StateIDFromSlot(slot)})
+		require.NoError(t, err)
+		require.Equal(t, header.Data.Header.Message.StateRoot, *stateRoot.Data)
+
+		parentRoot = &api.Response[*phase0.Root]{Data: &blockRoot}
+	}
+
+	headRoot, err := service.BeaconBlockRoot(ctx, &api.BeaconBlockRootOpts{Block: api.BlockIDHead})
+	require.NoError(t, err)
+	require.Equal(t, *parentRoot.Data, *headRoot.Data)
+
+	_, err = service.BeaconBlockRoot(ctx, &api.BeaconBlockRootOpts{Block: api.BlockIDFromSlot(40)})
+	var apiErr *api.Error
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, 404, apiErr.StatusCode)
+}
+
+func TestSimulatedChainFinality(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	service, source := newSimulatedChain(t)
+
+	source.Advance(4*32*slotDuration + slotDuration)
+
+	finality, err := service.Finality(ctx, &api.FinalityOpts{State: api.StateIDHead})
+	require.NoError(t, err)
+	require.Equal(t, phase0.Epoch(2), finality.Data.Finalized.Epoch)
+	require.Equal(t, phase0.Epoch(3), finality.Data.Justified.Epoch)
+
+	checkpointRoot, err := service.BeaconBlockRoot(ctx, &api.BeaconBlockRootOpts{Block: api.BlockIDFromSlot(64)})
+	require.NoError(t, err)
+	require.Equal(t, *checkpointRoot.Data, finality.Data.Finalized.Root)
+
+	finalizedRoot, err := service.BeaconBlockRoot(ctx, &api.BeaconBlockRootOpts{Block: api.BlockIDFinalized})
+	require.NoError(t, err)
+	require.Equal(t, finality.Data.Finalized.Root, *finalizedRoot.Data)
+}
+
+func TestSimulatedChainCommittees(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	service, _ := newSimulatedChain(t)
+
+	committees, err := service.BeaconCommittees(ctx, &api.BeaconCommitteesOpts{State: api.StateIDHead})
+	require.NoError(t, err)
+	require.Len(t, committees.Data, 32)
+
+	members := make(map[phase0.ValidatorIndex]*apiv1.BeaconCommittee)
+	for _, committee := range committees.Data {
+		for _, index := range committee.Validators {
+			require.Nil(t, members[index])
+			members[index] = committee
+		}
+	}
+	require.Len(t, members, 64)
+
+	duties, err := service.AttesterDuties(ctx, &api.AttesterDutiesOpts{Epoch: 0, Indices: []phase0.ValidatorIndex{3, 7}})
+	require.NoError(t, err)
+	require.Len(t, duties.Data, 2)
+	for _, duty := range duties.Data {
+		committee := members[duty.ValidatorIndex]
+		require.Equal(t, committee.Slot, duty.Slot)
+		require.Equal(t, committee.Index, duty.CommitteeIndex)
+		require.Equal(t, uint64(len(committee.Validators)), duty.CommitteeLength)
+		require.Equal(t, duty.ValidatorIndex, committee.Validators[duty.ValidatorCommitteeIndex])
+	}
+
+	_, err = service.AttesterDuties(ctx, &api.AttesterDutiesOpts{Epoch: 2})
+	require.Error(t, err)
+}
+
+func TestSimulatedChainAttestations(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	service, source := newSimulatedChain(t)
+
+	duties, err := service.AttesterDuties(ctx, &api.AttesterDutiesOpts{Epoch: 0})
+	require.NoError(t, err)
+
+	// Attest for every duty at slot 1.
+	source.Advance(slotDuration)
+	attested := make(map[phase0.ValidatorIndex]bool)
+	for _, duty := range duties.Data {
+		if duty.Slot != 1 {
+			continue
+		}
+		data, err := service.AttestationData(ctx, &api.AttestationDataOpts{Slot: duty.Slot, CommitteeIndex: duty.CommitteeIndex})
+		require.NoError(t, err)
+		aggregationBits := bitfield.NewBitlist(duty.CommitteeLength)
+		aggregationBits.SetBitAt(duty.ValidatorCommitteeIndex, true)
+		require.NoError(t, service.SubmitAttestations(ctx, []*phase0.Attestation{{
+			AggregationBits: aggregationBits,
+			Data:            data.Data,
+		}}))
+		attested[duty.ValidatorIndex] = true
+	}
+	require.NotEmpty(t, attested)
+
+	pool, err := service.AttestationPool(ctx, &api.AttestationPoolOpts{Slot: 1})
+	require.NoError(t, err)
+	require.Len(t, pool.Data, len(attested))
+
+	dataRoot, err := pool.Data[0].Data.HashTreeRoot()
+	require.NoError(t, err)
+	aggregate, err := service.AggregateAttestation(ctx, &api.AggregateAttestationOpts{Slot: 1, AttestationDataRoot: dataRoot})
+	require.NoError(t, err)
+	require.Equal(t, uint64(len(attested)), aggregate.Data.AggregationBits.Count())
+
+	// The attestations are included in the block for slot 2.
+	source.Advance(2 * slotDuration)
+	block, err := service.SignedBeaconBlock(ctx, &api.SignedBeaconBlockOpts{Block: api.BlockIDFromSlot(2)})
+	require.NoError(t, err)
+	require.Len(t, block.Data.Phase0.Message.Body.Attestations, len(attested))
+	pool, err = service.AttestationPool(ctx, &api.AttestationPoolOpts{Slot: 1})
+	require.NoError(t, err)
+	require.Empty(t, pool.Data)
+
+	// Rewards and penalties for epoch 0 are applied at the start of epoch 2.
+	source.Advance(63 * slotDuration)
+	balances, err := service.ValidatorBalances(ctx, &api.ValidatorBalancesOpts{State: api.StateIDHead})
+	require.NoError(t, err)
+	require.Len(t, balances.Data, 64)
+	for index, balance := range balances.Data {
+		if attested[index] {
+			require.Greater(t, balance, phase0.Gwei(32000000000))
+		} else {
+			require.Less(t, balance, phase0.Gwei(32000000000))
+		}
+	}
+
+	genesisBalances, err := service.ValidatorBalances(ctx, &api.ValidatorBalancesOpts{State: api.StateIDGenesis})
+	require.NoError(t, err)
+	for _, balance := range genesisBalances.Data {
+		require.Equal(t, phase0.Gwei(32000000000), balance)
+	}
+}
+
+func TestSimulatedChainVoluntaryExit(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	service, source := newSimulatedChain(t)
+
+	source.Advance(slotDuration)
+	require.NoError(t, service.SubmitVoluntaryExit(ctx, &phase0.SignedVoluntaryExit{
+		Message: &phase0.VoluntaryExit{
+			ValidatorIndex: 5,
+		},
+	}))
+	pool, err := service.VoluntaryExitPool(ctx)
+	require.NoError(t, err)
+	require.Len(t, pool, 1)
+
+	source.Advance(2 * slotDuration)
+	pool, err = service.VoluntaryExitPool(ctx)
+	require.NoError(t, err)
+	require.Empty(t, pool)
+
+	validators, err := service.Validators(ctx, &api.ValidatorsOpts{State: api.StateIDHead, Indices: []phase0.ValidatorIndex{5}})
+	require.NoError(t, err)
+	require.Len(t, validators.Data, 1)
+	require.Equal(t, apiv1.ValidatorStateActiveExiting, validators.Data[5].Status)
+	require.Equal(t, phase0.Epoch(5), validators.Data[5].Validator.ExitEpoch)
+
+	// A second exit for the same validator is rejected.
+	require.Error(t, service.SubmitVoluntaryExit(ctx, &phase0.SignedVoluntaryExit{
+		Message: &phase0.VoluntaryExit{
+			ValidatorIndex: 5,
+		},
+	}))
+}
+
+func TestSimulatedChainProposal(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	service, source := newSimulatedChain(t)
+
+	source.Advance(slotDuration)
+	graffiti := [32]byte{0x01}
+	proposal, err := service.Proposal(ctx, &api.ProposalOpts{Slot: 1, Graffiti: graffiti})
+	require.NoError(t, err)
+	block := proposal.Data.Phase0
+
+	duties, err := service.ProposerDuties(ctx, &api.ProposerDutiesOpts{Epoch: 0})
+	require.NoError(t, err)
+	require.Equal(t, duties.Data[0].Slot, block.Slot)
+	require.Equal(t, duties.Data[0].ValidatorIndex, block.ProposerIndex)
+
+	_, err = service.Proposal(ctx, &api.ProposalOpts{Slot: 2})
+	require.Error(t, err)
+
+	signedProposal := &api.VersionedSignedProposal{
+		Version: spec.DataVersionPhase0,
+		Phase0: &phase0.SignedBeaconBlock{
+			Message: block,
+		},
+	}
+	require.NoError(t, service.SubmitProposal(ctx, signedProposal))
+	require.Error(t, service.SubmitProposal(ctx, signedProposal))
+
+	// The submitted block becomes the head without waiting for the slot to end.
+	head, err := service.SignedBeaconBlock(ctx, &api.SignedBeaconBlockOpts{Block: api.BlockIDHead})
+	require.NoError(t, err)
+	require.Equal(t, phase0.Slot(1), head.Data.Phase0.Message.Slot)
+	require.Equal(t, graffiti, head.Data.Phase0.Message.Body.Graffiti)
+}
+
+func TestSimulatedChainEvents(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	service, source := newSimulatedChain(t)
+
+	events := make(chan *apiv1.Event, 16)
+	require.NoError(t, service.Events(ctx, []string{"head", "voluntary_exit"}, func(event *apiv1.Event) {
+		events <- event
+	}))
+
+	source.Advance(3 * slotDuration)
+	for slot := phase0.Slot(1); slot <= 2; slot++ {
+		select {
+		case event := <-events:
+			require.Equal(t, "head", event.Topic)
+			headEvent, isHeadEvent := event.Data.(*apiv1.HeadEvent)
+			require.True(t, isHeadEvent)
+			require.Equal(t, slot, headEvent.Slot)
+			root, err := service.BeaconBlockRoot(ctx, &api.BeaconBlockRootOpts{Block: api.BlockIDFromSlot(slot)})
+			require.NoError(t, err)
+			require.Equal(t, *root.Data, headEvent.Block)
+		case <-time.After(time.Second):
+			require.Fail(t, "timed out waiting for head event")
+		}
+	}
+
+	require.NoError(t, service.SubmitVoluntaryExit(ctx, &phase0.SignedVoluntaryExit{
+		Message: &phase0.VoluntaryExit{
+			ValidatorIndex: 1,
+		},
+	}))
+	select {
+	case event := <-events:
+		require.Equal(t, "voluntary_exit", event.Topic)
+	case <-time.After(time.Second):
+		require.Fail(t, "timed out waiting for voluntary exit event")
+	}
+}
+
+func TestSimulatedChainGenesisAge(t *testing.T) {
+	source := clock.NewFake(time.Unix(1606824023, 0))
+
+	_, err := mock.New(context.Background(),
+		mock.WithClockSource(source),
+		mock.WithGenesisTime(source.Now().Add(-8193*slotDuration)),
+		mock.WithSimulatedChain(64),
+	)
+	require.EqualError(t, err, "problem with parameters: simulated chain genesis cannot be more than 8192 slots in the past")
+
+	// The limit does not apply to mocks without a simulated chain.
+	_, err = mock.New(context.Background(),
+		mock.WithClockSource(source),
+		mock.WithGenesisTime(source.Now().Add(-8193*slotDuration)),
+	)
+	require.NoError(t, err)
+
+	service, err := mock.New(context.Background(),
+		mock.WithClockSource(source),
+		mock.WithGenesisTime(source.Now().Add(-64*slotDuration)),
+		mock.WithSimulatedChain(64),
+	)
+	require.NoError(t, err)
+	syncing, err := service.NodeSyncing(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, phase0.Slot(63), syncing.Data.HeadSlot)
+}
@@ -0,0 +1,401 @@
+// Copyright © 2023 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mock
+
+import (
+	"bytes"
+	"fmt"
+
+	apiv1 "github.com/attestantio/go-eth2-client/api/v1"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/pkg/errors"
+)
+
+// buildBlock builds the block for the given slot on top of the current head.
+// It must be called with the mutex held.
+func (c *chain) buildBlock(slot phase0.Slot, randaoReveal phase0.BLSSignature, graffiti [32]byte) *phase0.BeaconBlock {
+	parentRoot := phase0.Root{}
+	if len(c.blockRoots) > 0 {
+		parentRoot = c.blockRoots[c.headSlotLocked()]
+	}
+	stateRoot := chainHash("state", uint64(slot))
+	// Fold the parent root in to the state root, as the state depends on the chain up to the block.
+	for i := range stateRoot {
+		stateRoot[i] ^= parentRoot[i]
+	}
+	eth1BlockHash := chainHash("eth1-block", uint64(slot))
+
+	attestations := make([]*phase0.Attestation, 0)
+	for _, attestation := range c.attestations {
+		if len(attestations) == chainMaxAttestations {
+			break
+		}
+		if attestation.Data.Slot < slot && uint64(attestation.Data.Slot)+chainSlotsPerEpoch >= uint64(slot) {
+			attestations = append(attestations, attestation)
+		}
+	}
+	exits := make([]*phase0.SignedVoluntaryExit, 0)
+	for _, exit := range c.exits {
+		if len(exits) == chainMaxVoluntaryExits {
+			break
+		}
+		exits = append(exits, exit)
+	}
+
+	return &phase0.BeaconBlock{
+		Slot:          slot,
+		ProposerIndex: c.proposer(slot),
+		ParentRoot:    parentRoot,
+		StateRoot:     stateRoot,
+		Body: &phase0.BeaconBlockBody{
+			RANDAOReveal: randaoReveal,
+			ETH1Data: &phase0.ETH1Data{
+				DepositRoot:  chainHash("deposit-root", uint64(len(c.validators))),
+				DepositCount: uint64(len(c.validators)),
+				BlockHash:    eth1BlockHash[:],
+			},
+			Graffiti:          graffiti,
+			ProposerSlashings: []*phase0.ProposerSlashing{},
+			AttesterSlashings: []*phase0.AttesterSlashing{},
+			Attestations:      attestations,
+			Deposits:          []*phase0.Deposit{},
+			VoluntaryExits:    exits,
+		},
+	}
+}
+
+// addBlock adds a block to the head of the chain, applying its contents.
+// It must be called with the mutex held, and returns the resultant events.
+func (c *chain) addBlock(block *phase0.SignedBeaconBlock) ([]*apiv1.Event, error) {
+	if block == nil || block.Message == nil || block.Message.Body == nil {
+		return nil, errors.New("block missing")
+	}
+	headSlot := c.headSlotLocked()
+	slot := block.Message.Slot
+	if slot <= headSlot {
+		return nil, fmt.Errorf("slot %d already has a block", slot)
+	}
+	if slot > c.currentSlot() {
+		return nil, fmt.Errorf("block for future slot %d", slot)
+	}
+	if slot != headSlot+1 {
+		return nil, fmt.Errorf("block for slot %d does not follow head slot %d", slot, headSlot)
+	}
+	if !bytes.Equal(block.Message.ParentRoot[:], c.blockRoots[headSlot][:]) {
+		return nil, errors.New("block parent root is not the head of the chain")
+	}
+	if block.Message.ProposerIndex != c.proposer(slot) {
+		return nil, fmt.Errorf("validator %d is not the proposer for slot %d", block.Message.ProposerIndex, slot)
+	}
+
+	epoch := chainEpoch(slot)
+
+	// Check the contents of the block before applying any of them.
+	for i, attestation := range block.Message.Body.Attestations {
+		if _, err := c.checkAttestation(attestation); err != nil {
+			return nil, errors.Wrap(err, fmt.Sprintf("invalid attestation %d", i))
+		}
+		if attestation.Data.Slot >= slot {
+			return nil, fmt.Errorf("attestation %d is not for an earlier slot", i)
+		}
+	}
+	exiting := make(map[phase0.ValidatorIndex]bool)
+	for i, exit := range block.Message.Body.VoluntaryExits {
+		if _, err := c.checkVoluntaryExit(exit, epoch); err != nil {
+			return nil, errors.Wrap(err, fmt.Sprintf("invalid voluntary exit %d", i))
+		}
+		if exiting[exit.Message.ValidatorIndex] {
+			return nil, fmt.Errorf("duplicate voluntary exit for validator %d", exit.Message.ValidatorIndex)
+		}
+		exiting[exit.Message.ValidatorIndex] = true
+	}
+
+	epochTransition := uint64(slot)%chainSlotsPerEpoch == 0
+	if epochTransition {
+		c.processEpoch(epoch)
+	}
+	for _, attestation := range block.Message.Body.Attestations {
+		c.applyAttestation(attestation)
+	}
+	for _, exit := range block.Message.Body.VoluntaryExits {
+		c.applyVoluntaryExit(exit, epoch)
+	}
+
+	events := make([]*apiv1.Event, 0)
+
+	root := c.storeBlock(block)
+	events = append(events,
+		&apiv1.Event{
+			Topic: "block",
+			Data: &apiv1.BlockEvent{
+				Slot:  slot,
+				Block: root,
+			},
+		},
+		&apiv1.Event{
+			Topic: "head",
+			Data: &apiv1.HeadEvent{
+				Slot:                      slot,
+				Block:                     root,
+				State:                     block.Message.StateRoot,
+				EpochTransition:           epochTransition,
+				CurrentDutyDependentRoot:  c.proposerDependentRoot(epoch),
+				PreviousDutyDependentRoot: c.attesterDependentRoot(epoch),
+			},
+		},
+	)
+	if epochTransition && epoch > 2 {
+		finalized := epoch - 2
+		checkpointSlot := chainEpochStart(finalized)
+		events = append(events, &apiv1.Event{
+			Topic: "finalized_checkpoint",
+			Data: &apiv1.FinalizedCheckpointEvent{
+				Block: c.blockRoots[checkpointSlot],
+				State: c.stateRoots[checkpointSlot],
+				Epoch: finalized,
+			},
+		})
+	}
+
+	return events, nil
+}
+
+// storeBlock stores a block as the new head of the chain, returning its root.
+// It must be called with the mutex held.
+func (c *chain) storeBlock(block *phase0.SignedBeaconBlock) phase0.Root {
+	root, err := block.Message.HashTreeRoot()
+	if err != nil {
+		// Should not happen, as blocks are checked before they are stored.
+		panic(err)
+	}
+
+	c.blocks = append(c.blocks, block)
+	c.blockRoots = append(c.blockRoots, root)
+	c.stateRoots = append(c.stateRoots, block.Message.StateRoot)
+	c.blockSlots[root] = block.Message.Slot
+	c.stateSlots[block.Message.StateRoot] = block.Message.Slot
+
+	return root
+}
+
+// applyAttestation records the participation of a checked attestation included in a block, and
+// removes attestations it covers from the pool.
+// It must be called with the mutex held.
+func (c *chain) applyAttestation(attestation *phase0.Attestation) {
+	committee, err := c.committee(attestation.Data.Slot, attestation.Data.Index)
+	if err != nil {
+		// Should not happen, as the attestation has been checked.
+		return
+	}
+
+	epoch := chainEpoch(attestation.Data.Slot)
+	if _, exists := c.participation[epoch]; !exists {
+		c.participation[epoch] = make(map[phase0.ValidatorIndex]bool)
+	}
+	for _, position := range attestation.AggregationBits.BitIndices() {
+		c.participation[epoch][committee[position]] = true
+	}
+
+	dataRoot, err := attestation.Data.HashTreeRoot()
+	if err != nil {
+		return
+	}
+	pool := make([]*phase0.Attestation, 0, len(c.attestations))
+	for _, pooled := range c.attestations {
+		pooledRoot, err := pooled.Data.HashTreeRoot()
+		if err == nil && bytes.Equal(pooledRoot[:], dataRoot[:]) {
+			if covered, err := attestation.AggregationBits.Contains(pooled.AggregationBits); err == nil && covered {
+				continue
+			}
+		}
+		pool = append(pool, pooled)
+	}
+	c.attestations = pool
+}
+
+// checkAttestation checks that an attestation matches a committee of the chain, returning the committee.
+// It must be called with the mutex held.
+func (c *chain) checkAttestation(attestation *phase0.Attestation) ([]phase0.ValidatorIndex, error) {
+	if attestation == nil || attestation.Data == nil || attestation.Data.Source == nil || attestation.Data.Target == nil {
+		return nil, errors.New("attestation missing data")
+	}
+	if attestation.Data.Slot > c.currentSlot() {
+		return nil, fmt.Errorf("attestation for future slot %d", attestation.Data.Slot)
+	}
+	if attestation.Data.Target.Epoch != chainEpoch(attestation.Data.Slot) {
+		return nil, errors.New("attestation target epoch does not match slot")
+	}
+	committee, err := c.committee(attestation.Data.Slot, attestation.Data.Index)
+	if err != nil {
+		return nil, err
+	}
+	if attestation.AggregationBits.Len() != uint64(len(committee)) {
+		return nil, fmt.Errorf("attestation has %d aggregation bits for committee of %d", attestation.AggregationBits.Len(), len(committee))
+	}
+
+	return committee, nil
+}
+
+// applyVoluntaryExit starts the exit of a validator for a checked voluntary exit, and removes the
+// exit from the pool.
+// It must be called with the mutex held.
+func (c *chain) applyVoluntaryExit(exit *phase0.SignedVoluntaryExit, epoch phase0.Epoch) {
+	validator := c.validators[exit.Message.ValidatorIndex]
+	validator.ExitEpoch = epoch + 1 + chainMaxSeedLookahead
+	validator.WithdrawableEpoch = validator.ExitEpoch + chainMinWithdrawabilityDelay
+
+	pool := make([]*phase0.SignedVoluntaryExit, 0, len(c.exits))
+	for _, pooled := range c.exits {
+		if pooled.Message.ValidatorIndex != exit.Message.ValidatorIndex {
+			pool = append(pool, pooled)
+		}
+	}
+	c.exits = pool
+}
+
+// checkVoluntaryExit checks that a voluntary exit is valid at the given epoch, returning the exiting validator.
+// It must be called with the mutex held.
+func (c *chain) checkVoluntaryExit(exit *phase0.SignedVoluntaryExit, epoch phase0.Epoch) (*phase0.Validator, error) {
+	if exit == nil || exit.Message == nil {
+		return nil, errors.New("voluntary exit missing")
+	}
+	if uint64(exit.Message.ValidatorIndex) >= uint64(len(c.validators)) {
+		return nil, fmt.Errorf("validator %d not found", exit.Message.ValidatorIndex)
+	}
+	if exit.Message.Epoch > epoch {
+		return nil, fmt.Errorf("voluntary exit for future epoch %d", exit.Message.Epoch)
+	}
+	validator := c.validators[exit.Message.ValidatorIndex]
+	if validator.ExitEpoch != chainFarFutureEpoch {
+		return nil, fmt.Errorf("validator %d is already exiting", exit.Message.ValidatorIndex)
+	}
+	if !chainIsActive(validator, epoch) {
+		return nil, fmt.Errorf("validator %d is not active", exit.Message.ValidatorIndex)
+	}
+
+	return validator, nil
+}
+
+// processEpoch applies the rewards and penalties for the epoch before last, and records the
+// balances at the start of the given epoch.
+// It must be called with the mutex held.
+func (c *chain) processEpoch(epoch phase0.Epoch) {
+	balances := make([]phase0.Gwei, len(c.validators))
+	copy(balances, c.balances[len(c.balances)-1])
+
+	if epoch >= 2 {
+		settled := epoch - 2
+		for i, validator := range c.validators {
+			if !chainIsActive(validator, settled) {
+				continue
+			}
+			switch {
+			case c.participation[settled][phase0.ValidatorIndex(i)]:
+				balances[i] += chainAttestationReward
+			case balances[i] > chainAttestationReward:
+				balances[i] -= chainAttestationReward
+			default:
+				balances[i] = 0
+			}
+		}
+		delete(c.participation, settled)
+	}
+
+	// Drop attestations that are too old to be included.
+	attestations := make([]*phase0.Attestation, 0, len(c.attestations))
+	for _, attestation := range c.attestations {
+		if uint64(attestation.Data.Slot)+chainSlotsPerEpoch >= uint64(chainEpochStart(epoch)) {
+			attestations = append(attestations, attestation)
+		}
+	}
+	c.attestations = attestations
+
+	c.balances = append(c.balances, balances)
+}
+
+// submitAttestations adds attestations to the pool.
+func (c *chain) submitAttestations(attestations []*phase0.Attestation) error {
+	c.advance()
+
+	c.mutex.Lock()
+	for i, attestation := range attestations {
+		if _, err := c.checkAttestation(attestation); err != nil {
+			c.mutex.Unlock()
+
+			return errors.Wrap(err, fmt.Sprintf("invalid attestation %d", i))
+		}
+	}
+	c.attestations = append(c.attestations, attestations...)
+	c.mutex.Unlock()
+
+	events := make([]*apiv1.Event, len(attestations))
+	for i, attestation := range attestations {
+		events[i] = &apiv1.Event{
+			Topic: "attestation",
+			Data:  attestation,
+		}
+	}
+	c.publish(events)
+
+	return nil
+}
+
+// submitVoluntaryExit adds a voluntary exit to the pool.
+func (c *chain) submitVoluntaryExit(exit *phase0.SignedVoluntaryExit) error {
+	c.advance()
+
+	c.mutex.Lock()
+	if _, err := c.checkVoluntaryExit(exit, c.currentEpoch()); err != nil {
+		c.mutex.Unlock()
+
+		return errors.Wrap(err, "invalid voluntary exit")
+	}
+	for _, pooled := range c.exits {
+		if pooled.Message.ValidatorIndex == exit.Message.ValidatorIndex {
+			c.mutex.Unlock()
+
+			return nil
+		}
+	}
+	c.exits = append(c.exits, exit)
+	c.mutex.Unlock()
+
+	c.publish([]*apiv1.Event{{
+		Topic: "voluntary_exit",
+		Data:  exit,
+	}})
+
+	return nil
+}
+
+// submitBlock adds a block for the current slot to the chain.
+func (c *chain) submitBlock(block *phase0.SignedBeaconBlock) error {
+	c.advance()
+
+	c.mutex.Lock()
+	events, err := c.addBlock(block)
+	c.mutex.Unlock()
+	if err != nil {
+		return errors.Wrap(err, "invalid block")
+	}
+
+	c.publish(events)
+
+	return nil
+}
+
+// chainIsActive returns true if the validator is active at the given epoch.
+func chainIsActive(validator *phase0.Validator, epoch phase0.Epoch) bool {
+	return validator.ActivationEpoch <= epoch && epoch < validator.ExitEpoch
+}
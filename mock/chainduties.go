@@ -0,0 +1,169 @@
+// Copyright © 2023 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mock
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/rand"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+)
+
+// activeIndices provides the indices of the validators active at the given epoch.
+// It must be called with the mutex held.
+func (c *chain) activeIndices(epoch phase0.Epoch) []phase0.ValidatorIndex {
+	if indices, exists := c.actives[epoch]; exists {
+		return indices
+	}
+
+	indices := make([]phase0.ValidatorIndex, 0, len(c.validators))
+	for i, validator := range c.validators {
+		if chainIsActive(validator, epoch) {
+			indices = append(indices, phase0.ValidatorIndex(i))
+		}
+	}
+
+	// As with the shuffle, exits cannot change the active validators up to the next epoch.
+	if epoch <= c.currentEpoch()+1 {
+		c.actives[epoch] = indices
+	}
+
+	return indices
+}
+
+// shuffle provides the active validators for the given epoch in committee order.
+// It must be called with the mutex held.
+func (c *chain) shuffle(epoch phase0.Epoch) []phase0.ValidatorIndex {
+	if shuffled, exists := c.shuffles[epoch]; exists {
+		return shuffled
+	}
+
+	active := c.activeIndices(epoch)
+	permutation := chainPermutation("shuffle", uint64(epoch), len(active))
+	shuffled := make([]phase0.ValidatorIndex, len(active))
+	for i, j := range permutation {
+		shuffled[i] = active[j]
+	}
+
+	// Exits take effect beyond the lookahead for duties, so the shuffle for an epoch
+	// up to the next cannot change and is safe to cache.
+	if epoch <= c.currentEpoch()+1 {
+		c.shuffles[epoch] = shuffled
+	}
+
+	return shuffled
+}
+
+// committeesPerSlot provides the number of committees in each slot of the given epoch.
+// It must be called with the mutex held.
+func (c *chain) committeesPerSlot(epoch phase0.Epoch) uint64 {
+	committees := uint64(len(c.shuffle(epoch))) / chainSlotsPerEpoch / chainTargetCommitteeSize
+	if committees > chainMaxCommitteesPerSlot {
+		committees = chainMaxCommitteesPerSlot
+	}
+	if committees == 0 {
+		committees = 1
+	}
+
+	return committees
+}
+
+// committee provides the validators in the given committee.
+// It must be called with the mutex held.
+func (c *chain) committee(slot phase0.Slot, index phase0.CommitteeIndex) ([]phase0.ValidatorIndex, error) {
+	epoch := chainEpoch(slot)
+	committeesPerSlot := c.committeesPerSlot(epoch)
+	if uint64(index) >= committeesPerSlot {
+		return nil, fmt.Errorf("committee index %d out of range for slot %d", index, slot)
+	}
+
+	shuffled := c.shuffle(epoch)
+	committees := committeesPerSlot * chainSlotsPerEpoch
+	position := (uint64(slot)%chainSlotsPerEpoch)*committeesPerSlot + uint64(index)
+	start := uint64(len(shuffled)) * position / committees
+	end := uint64(len(shuffled)) * (position + 1) / committees
+
+	return shuffled[start:end], nil
+}
+
+// proposer provides the proposer for the given slot.
+// It must be called with the mutex held.
+func (c *chain) proposer(slot phase0.Slot) phase0.ValidatorIndex {
+	active := c.activeIndices(chainEpoch(slot))
+	if len(active) == 0 {
+		return 0
+	}
+	seed := chainHash("proposer", uint64(slot))
+
+	return active[binary.LittleEndian.Uint64(seed[:8])%uint64(len(active))]
+}
+
+// syncCommitteeMembers provides the members of the sync committee for the given epoch.
+// Validators can appear more than once if there are fewer active validators than positions.
+// It must be called with the mutex held.
+func (c *chain) syncCommitteeMembers(epoch phase0.Epoch) []phase0.ValidatorIndex {
+	period := uint64(epoch) / chainEpochsPerSyncCommitteePeriod
+	// The committee is selected from the validators active at the start of the previous period.
+	selectionEpoch := phase0.Epoch(0)
+	if period > 0 {
+		selectionEpoch = phase0.Epoch((period - 1) * chainEpochsPerSyncCommitteePeriod)
+	}
+	active := c.activeIndices(selectionEpoch)
+	members := make([]phase0.ValidatorIndex, chainSyncCommitteeSize)
+	if len(active) == 0 {
+		return members
+	}
+
+	permutation := chainPermutation("sync-committee", period, len(active))
+	for i := range members {
+		members[i] = active[permutation[i%len(permutation)]]
+	}
+
+	return members
+}
+
+// proposerDependentRoot provides the block root on which proposer duties for the epoch depend.
+// It must be called with the mutex held.
+func (c *chain) proposerDependentRoot(epoch phase0.Epoch) phase0.Root {
+	return c.dependentRoot(chainEpochStart(epoch))
+}
+
+// attesterDependentRoot provides the block root on which attester duties for the epoch depend.
+// It must be called with the mutex held.
+func (c *chain) attesterDependentRoot(epoch phase0.Epoch) phase0.Root {
+	return c.dependentRoot(chainEpochStart(chainEpochsBefore(epoch, 1)))
+}
+
+// dependentRoot provides the root of the last block before the given slot, or the genesis
+// block if there is none.
+// It must be called with the mutex held.
+func (c *chain) dependentRoot(slot phase0.Slot) phase0.Root {
+	if slot == 0 {
+		return c.blockRoots[0]
+	}
+	slot--
+	if head := c.headSlotLocked(); slot > head {
+		slot = head
+	}
+
+	return c.blockRoots[slot]
+}
+
+// chainPermutation provides a deterministic permutation of [0,n) for a label and value.
+func chainPermutation(label string, value uint64, n int) []int {
+	seed := chainHash(label, value)
+	//nolint:gosec
+	return rand.New(rand.NewSource(int64(binary.LittleEndian.Uint64(seed[:8])))).Perm(n)
+}
@@ -0,0 +1,608 @@
+// Copyright © 2023 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mock
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/attestantio/go-eth2-client/api"
+	apiv1 "github.com/attestantio/go-eth2-client/api/v1"
+	"github.com/attestantio/go-eth2-client/spec"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/pkg/errors"
+)
+
+// beaconBlockRoot provides the root of a block of the chain.
+func (c *chain) beaconBlockRoot(opts *api.BeaconBlockRootOpts) (*api.Response[*phase0.Root], error) {
+	if opts == nil {
+		return nil, errors.New("no options specified")
+	}
+	c.advance()
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	slot, err := c.blockSlot(opts.Block)
+	if err != nil {
+		return nil, err
+	}
+	root := c.blockRoots[slot]
+
+	return &api.Response[*phase0.Root]{
+		Data:     &root,
+		Metadata: c.blockMetadata(slot),
+	}, nil
+}
+
+// beaconBlockHeader provides the header of a block of the chain.
+func (c *chain) beaconBlockHeader(opts *api.BeaconBlockHeaderOpts) (*api.Response[*apiv1.BeaconBlockHeader], error) {
+	if opts == nil {
+		return nil, errors.New("no options specified")
+	}
+	c.advance()
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	slot, err := c.blockSlot(opts.Block)
+	if err != nil {
+		return nil, err
+	}
+	block := c.blocks[slot]
+	bodyRoot, err := block.Message.Body.HashTreeRoot()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to obtain block body root")
+	}
+
+	return &api.Response[*apiv1.BeaconBlockHeader]{
+		Data: &apiv1.BeaconBlockHeader{
+			Root:      c.blockRoots[slot],
+			Canonical: true,
+			Header: &phase0.SignedBeaconBlockHeader{
+				Message: &phase0.BeaconBlockHeader{
+					Slot:          block.Message.Slot,
+					ProposerIndex: block.Message.ProposerIndex,
+					ParentRoot:    block.Message.ParentRoot,
+					StateRoot:     block.Message.StateRoot,
+					BodyRoot:      bodyRoot,
+				},
+				Signature: block.Signature,
+			},
+		},
+		Metadata: c.blockMetadata(slot),
+	}, nil
+}
+
+// signedBeaconBlock provides a block of the chain.
+func (c *chain) signedBeaconBlock(opts *api.SignedBeaconBlockOpts) (*api.Response[*spec.VersionedSignedBeaconBlock], error) {
+	if opts == nil {
+		return nil, errors.New("no options specified")
+	}
+	c.advance()
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	slot, err := c.blockSlot(opts.Block)
+	if err != nil {
+		return nil, err
+	}
+
+	return &api.Response[*spec.VersionedSignedBeaconBlock]{
+		Data: &spec.VersionedSignedBeaconBlock{
+			Version: spec.DataVersionPhase0,
+			Phase0:  c.blocks[slot],
+		},
+		Metadata: c.blockMetadata(slot),
+	}, nil
+}
+
+// beaconStateRoot provides the root of a state of the chain.
+func (c *chain) beaconStateRoot(opts *api.BeaconStateRootOpts) (*api.Response[*phase0.Root], error) {
+	if opts == nil {
+		return nil, errors.New("no options specified")
+	}
+	c.advance()
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	slot, err := c.stateSlot(opts.State)
+	if err != nil {
+		return nil, err
+	}
+	root := c.stateRoots[slot]
+
+	return &api.Response[*phase0.Root]{
+		Data:     &root,
+		Metadata: c.blockMetadata(slot),
+	}, nil
+}
+
+// finality provides the finality of a state of the chain.
+func (c *chain) finality(opts *api.FinalityOpts) (*api.Response[*apiv1.Finality], error) {
+	if opts == nil {
+		return nil, errors.New("no options specified")
+	}
+	c.advance()
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	slot, err := c.stateSlot(opts.State)
+	if err != nil {
+		return nil, err
+	}
+
+	return &api.Response[*apiv1.Finality]{
+		Data:     c.finalityAt(slot),
+		Metadata: c.blockMetadata(slot),
+	}, nil
+}
+
+// attesterDuties provides the attester duties for an epoch of the chain.
+func (c *chain) attesterDuties(opts *api.AttesterDutiesOpts) (*api.Response[[]*apiv1.AttesterDuty], error) {
+	if opts == nil {
+		return nil, errors.New("no options specified")
+	}
+	c.advance()
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if err := c.checkDutiesEpoch(opts.Epoch); err != nil {
+		return nil, err
+	}
+
+	requested := make(map[phase0.ValidatorIndex]bool, len(opts.Indices))
+	for _, index := range opts.Indices {
+		requested[index] = true
+	}
+	committeesPerSlot := c.committeesPerSlot(opts.Epoch)
+	data := make([]*apiv1.AttesterDuty, 0)
+	for slot := chainEpochStart(opts.Epoch); slot < chainEpochStart(opts.Epoch+1); slot++ {
+		for index := uint64(0); index < committeesPerSlot; index++ {
+			committee, err := c.committee(slot, phase0.CommitteeIndex(index))
+			if err != nil {
+				return nil, err
+			}
+			for position, validatorIndex := range committee {
+				if len(requested) > 0 && !requested[validatorIndex] {
+					continue
+				}
+				data = append(data, &apiv1.AttesterDuty{
+					PubKey:                  c.validators[validatorIndex].PublicKey,
+					Slot:                    slot,
+					ValidatorIndex:          validatorIndex,
+					CommitteeIndex:          phase0.CommitteeIndex(index),
+					CommitteeLength:         uint64(len(committee)),
+					CommitteesAtSlot:        committeesPerSlot,
+					ValidatorCommitteeIndex: uint64(position),
+				})
+			}
+		}
+	}
+
+	return &api.Response[[]*apiv1.AttesterDuty]{
+		Data: data,
+		Metadata: map[string]any{
+			"dependent_root": c.attesterDependentRoot(opts.Epoch),
+		},
+	}, nil
+}
+
+// proposerDuties provides the proposer duties for an epoch of the chain.
+func (c *chain) proposerDuties(opts *api.ProposerDutiesOpts) (*api.Response[[]*apiv1.ProposerDuty], error) {
+	if opts == nil {
+		return nil, errors.New("no options specified")
+	}
+	c.advance()
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if err := c.checkDutiesEpoch(opts.Epoch); err != nil {
+		return nil, err
+	}
+
+	requested := make(map[phase0.ValidatorIndex]bool, len(opts.Indices))
+	for _, index := range opts.Indices {
+		requested[index] = true
+	}
+	data := make([]*apiv1.ProposerDuty, 0)
+	for slot := chainEpochStart(opts.Epoch); slot < chainEpochStart(opts.Epoch+1); slot++ {
+		if slot == 0 {
+			// Genesis has no proposer.
+			continue
+		}
+		validatorIndex := c.proposer(slot)
+		if len(requested) > 0 && !requested[validatorIndex] {
+			continue
+		}
+		data = append(data, &apiv1.ProposerDuty{
+			PubKey:         c.validators[validatorIndex].PublicKey,
+			Slot:           slot,
+			ValidatorIndex: validatorIndex,
+		})
+	}
+
+	return &api.Response[[]*apiv1.ProposerDuty]{
+		Data: data,
+		Metadata: map[string]any{
+			"dependent_root": c.proposerDependentRoot(opts.Epoch),
+		},
+	}, nil
+}
+
+// syncCommitteeDuties provides the sync committee duties for an epoch of the chain.
+func (c *chain) syncCommitteeDuties(opts *api.SyncCommitteeDutiesOpts) (*api.Response[[]*apiv1.SyncCommitteeDuty], error) {
+	if opts == nil {
+		return nil, errors.New("no options specified")
+	}
+	c.advance()
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	period := uint64(opts.Epoch) / chainEpochsPerSyncCommitteePeriod
+	if period > uint64(c.currentEpoch())/chainEpochsPerSyncCommitteePeriod+1 {
+		return nil, fmt.Errorf("sync committee duties for epoch %d are not yet available", opts.Epoch)
+	}
+
+	positions := make(map[phase0.ValidatorIndex][]phase0.CommitteeIndex)
+	for position, validatorIndex := range c.syncCommitteeMembers(opts.Epoch) {
+		positions[validatorIndex] = append(positions[validatorIndex], phase0.CommitteeIndex(position))
+	}
+	data := make([]*apiv1.SyncCommitteeDuty, 0)
+	for _, validatorIndex := range opts.Indices {
+		if _, exists := positions[validatorIndex]; !exists {
+			continue
+		}
+		data = append(data, &apiv1.SyncCommitteeDuty{
+			PubKey:                        c.validators[validatorIndex].PublicKey,
+			ValidatorIndex:                validatorIndex,
+			ValidatorSyncCommitteeIndices: positions[validatorIndex],
+		})
+	}
+
+	return &api.Response[[]*apiv1.SyncCommitteeDuty]{
+		Data:     data,
+		Metadata: make(map[string]any),
+	}, nil
+}
+
+// beaconCommittees provides the beacon committees for an epoch of the chain.
+func (c *chain) beaconCommittees(opts *api.BeaconCommitteesOpts) (*api.Response[[]*apiv1.BeaconCommittee], error) {
+	if opts == nil {
+		return nil, errors.New("no options specified")
+	}
+	c.advance()
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	slot, err := c.stateSlot(opts.State)
+	if err != nil {
+		return nil, err
+	}
+	epoch := chainEpoch(slot)
+	if opts.Epoch != nil {
+		epoch = *opts.Epoch
+	}
+	if err := c.checkDutiesEpoch(epoch); err != nil {
+		return nil, err
+	}
+
+	committeesPerSlot := c.committeesPerSlot(epoch)
+	data := make([]*apiv1.BeaconCommittee, 0, committeesPerSlot*chainSlotsPerEpoch)
+	for committeeSlot := chainEpochStart(epoch); committeeSlot < chainEpochStart(epoch+1); committeeSlot++ {
+		for index := uint64(0); index < committeesPerSlot; index++ {
+			committee, err := c.committee(committeeSlot, phase0.CommitteeIndex(index))
+			if err != nil {
+				return nil, err
+			}
+			data = append(data, &apiv1.BeaconCommittee{
+				Slot:       committeeSlot,
+				Index:      phase0.CommitteeIndex(index),
+				Validators: append([]phase0.ValidatorIndex{}, committee...),
+			})
+		}
+	}
+
+	return &api.Response[[]*apiv1.BeaconCommittee]{
+		Data:     data,
+		Metadata: c.blockMetadata(slot),
+	}, nil
+}
+
+// syncCommittee provides the sync committee for an epoch of the chain.
+func (c *chain) syncCommittee(opts *api.SyncCommitteeOpts) (*api.Response[*apiv1.SyncCommittee], error) {
+	if opts == nil {
+		return nil, errors.New("no options specified")
+	}
+	c.advance()
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	slot, err := c.stateSlot(opts.State)
+	if err != nil {
+		return nil, err
+	}
+	epoch := chainEpoch(slot)
+	if opts.Epoch != nil {
+		epoch = *opts.Epoch
+	}
+
+	members := c.syncCommitteeMembers(epoch)
+	subcommitteeSize := chainSyncCommitteeSize / chainSyncCommitteeSubnetCount
+	aggregates := make([][]phase0.ValidatorIndex, chainSyncCommitteeSubnetCount)
+	for i := range aggregates {
+		aggregates[i] = members[uint64(i)*subcommitteeSize : uint64(i+1)*subcommitteeSize]
+	}
+
+	return &api.Response[*apiv1.SyncCommittee]{
+		Data: &apiv1.SyncCommittee{
+			Validators:          members,
+			ValidatorAggregates: aggregates,
+		},
+		Metadata: c.blockMetadata(slot),
+	}, nil
+}
+
+// validatorsAt provides the validators of a state of the chain.
+func (c *chain) validatorsAt(opts *api.ValidatorsOpts) (*api.Response[map[phase0.ValidatorIndex]*apiv1.Validator], error) {
+	if opts == nil {
+		return nil, errors.New("no options specified")
+	}
+	c.advance()
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	slot, err := c.stateSlot(opts.State)
+	if err != nil {
+		return nil, err
+	}
+	epoch := chainEpoch(slot)
+	balances := c.balancesAt(epoch)
+
+	data := make(map[phase0.ValidatorIndex]*apiv1.Validator)
+	for _, index := range c.filterValidators(opts.Indices, opts.PubKeys) {
+		validator := *c.validators[index]
+		validator.WithdrawalCredentials = append([]byte{}, validator.WithdrawalCredentials...)
+		balance := balances[index]
+		data[index] = &apiv1.Validator{
+			Index:     index,
+			Balance:   balance,
+			Status:    apiv1.ValidatorToState(&validator, &balance, epoch, chainFarFutureEpoch),
+			Validator: &validator,
+		}
+	}
+
+	return &api.Response[map[phase0.ValidatorIndex]*apiv1.Validator]{
+		Data:     data,
+		Metadata: c.blockMetadata(slot),
+	}, nil
+}
+
+// validatorBalances provides the validator balances of a state of the chain.
+func (c *chain) validatorBalances(opts *api.ValidatorBalancesOpts) (*api.Response[map[phase0.ValidatorIndex]phase0.Gwei], error) {
+	if opts == nil {
+		return nil, errors.New("no options specified")
+	}
+	c.advance()
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	slot, err := c.stateSlot(opts.State)
+	if err != nil {
+		return nil, err
+	}
+	balances := c.balancesAt(chainEpoch(slot))
+
+	data := make(map[phase0.ValidatorIndex]phase0.Gwei)
+	for _, index := range c.filterValidators(opts.Indices, nil) {
+		data[index] = balances[index]
+	}
+
+	return &api.Response[map[phase0.ValidatorIndex]phase0.Gwei]{
+		Data:     data,
+		Metadata: c.blockMetadata(slot),
+	}, nil
+}
+
+// attestationData provides the attestation data for a committee of the chain.
+func (c *chain) attestationData(opts *api.AttestationDataOpts) (*api.Response[*phase0.AttestationData], error) {
+	if opts == nil {
+		return nil, errors.New("no options specified")
+	}
+	c.advance()
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if opts.Slot > c.currentSlot() {
+		return nil, fmt.Errorf("attestation data for future slot %d", opts.Slot)
+	}
+	if _, err := c.committee(opts.Slot, opts.CommitteeIndex); err != nil {
+		return nil, err
+	}
+
+	headSlot := opts.Slot
+	if headSlot > c.headSlotLocked() {
+		headSlot = c.headSlotLocked()
+	}
+	epoch := chainEpoch(opts.Slot)
+
+	return &api.Response[*phase0.AttestationData]{
+		Data: &phase0.AttestationData{
+			Slot:            opts.Slot,
+			Index:           opts.CommitteeIndex,
+			BeaconBlockRoot: c.blockRoots[headSlot],
+			Source:          c.finalityAt(opts.Slot).Justified,
+			Target: &phase0.Checkpoint{
+				Epoch: epoch,
+				Root:  c.checkpointRoot(epoch),
+			},
+		},
+		Metadata: make(map[string]any),
+	}, nil
+}
+
+// attestationPool provides the pooled attestations for a slot of the chain.
+func (c *chain) attestationPool(opts *api.AttestationPoolOpts) (*api.Response[[]*phase0.Attestation], error) {
+	if opts == nil {
+		return nil, errors.New("no options specified")
+	}
+	c.advance()
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	data := make([]*phase0.Attestation, 0)
+	for _, attestation := range c.attestations {
+		if attestation.Data.Slot == opts.Slot {
+			data = append(data, attestation)
+		}
+	}
+
+	return &api.Response[[]*phase0.Attestation]{
+		Data:     data,
+		Metadata: make(map[string]any),
+	}, nil
+}
+
+// aggregateAttestation provides the aggregate of pooled attestations with the given data.
+func (c *chain) aggregateAttestation(opts *api.AggregateAttestationOpts) (*api.Response[*phase0.Attestation], error) {
+	if opts == nil {
+		return nil, errors.New("no options specified")
+	}
+	c.advance()
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	var aggregate *phase0.Attestation
+	for _, attestation := range c.attestations {
+		if attestation.Data.Slot != opts.Slot {
+			continue
+		}
+		dataRoot, err := attestation.Data.HashTreeRoot()
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to obtain attestation data root")
+		}
+		if !bytes.Equal(dataRoot[:], opts.AttestationDataRoot[:]) {
+			continue
+		}
+		if aggregate == nil {
+			// Signatures are not aggregated, as the chain does not check them.
+			aggregate = &phase0.Attestation{
+				AggregationBits: attestation.AggregationBits,
+				Data:            attestation.Data,
+				Signature:       attestation.Signature,
+			}
+
+			continue
+		}
+		aggregationBits, err := aggregate.AggregationBits.Or(attestation.AggregationBits)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to aggregate attestations")
+		}
+		aggregate.AggregationBits = aggregationBits
+	}
+	if aggregate == nil {
+		return nil, chainNotFound("no matching attestations")
+	}
+
+	return &api.Response[*phase0.Attestation]{
+		Data:     aggregate,
+		Metadata: make(map[string]any),
+	}, nil
+}
+
+// voluntaryExitPool provides the pooled voluntary exits of the chain.
+func (c *chain) voluntaryExitPool() []*phase0.SignedVoluntaryExit {
+	c.advance()
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return append([]*phase0.SignedVoluntaryExit{}, c.exits...)
+}
+
+// proposal provides a block for the next slot of the chain.
+func (c *chain) proposal(opts *api.ProposalOpts) (*api.Response[*api.VersionedProposal], error) {
+	if opts == nil {
+		return nil, errors.New("no options specified")
+	}
+	c.advance()
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if opts.Slot > c.currentSlot() {
+		return nil, fmt.Errorf("proposal for future slot %d", opts.Slot)
+	}
+	if opts.Slot != c.headSlotLocked()+1 {
+		return nil, fmt.Errorf("slot %d already has a block", opts.Slot)
+	}
+
+	return &api.Response[*api.VersionedProposal]{
+		Data: &api.VersionedProposal{
+			Version: spec.DataVersionPhase0,
+			Phase0:  c.buildBlock(opts.Slot, opts.RandaoReveal, opts.Graffiti),
+		},
+		Metadata: make(map[string]any),
+	}, nil
+}
+
+// checkDutiesEpoch checks that duties for the epoch are available.
+// It must be called with the mutex held.
+func (c *chain) checkDutiesEpoch(epoch phase0.Epoch) error {
+	if epoch > c.currentEpoch()+1 {
+		return fmt.Errorf("duties for epoch %d are not yet available", epoch)
+	}
+
+	return nil
+}
+
+// filterValidators provides the indices of the validators matching the given indices or public keys,
+// or all validators if neither is supplied.
+// It must be called with the mutex held.
+func (c *chain) filterValidators(indices []phase0.ValidatorIndex, pubKeys []phase0.BLSPubKey) []phase0.ValidatorIndex {
+	res := make([]phase0.ValidatorIndex, 0)
+	if len(indices) == 0 && len(pubKeys) == 0 {
+		for i := range c.validators {
+			res = append(res, phase0.ValidatorIndex(i))
+		}
+
+		return res
+	}
+
+	for _, index := range indices {
+		if uint64(index) < uint64(len(c.validators)) {
+			res = append(res, index)
+		}
+	}
+	if len(pubKeys) > 0 {
+		requested := make(map[phase0.BLSPubKey]bool, len(pubKeys))
+		for _, pubKey := range pubKeys {
+			requested[pubKey] = true
+		}
+		for i, validator := range c.validators {
+			if requested[validator.PublicKey] {
+				res = append(res, phase0.ValidatorIndex(i))
+			}
+		}
+	}
+
+	return res
+}
+
+// blockMetadata provides the metadata for a response about the block or state at the given slot.
+// It must be called with the mutex held.
+func (c *chain) blockMetadata(slot phase0.Slot) map[string]any {
+	finalizedSlot := chainEpochStart(chainEpochsBefore(chainEpoch(c.headSlotLocked()), 2))
+
+	return map[string]any{
+		"execution_optimistic": false,
+		"finalized":            slot <= finalizedSlot,
+	}
+}
@@ -23,7 +23,13 @@ import (
 // DepositContract provides details of the execution layer deposit contract for the chain.
 func (s *Service) DepositContract(_ context.Context) (*api.Response[*apiv1.DepositContract], error) {
 	return &api.Response[*apiv1.DepositContract]{
-		Data:     &apiv1.DepositContract{},
+		Data: &apiv1.DepositContract{
+			ChainID: 1,
+			Address: []byte{
+				0x00, 0x00, 0x00, 0x00, 0x21, 0x9a, 0xb5, 0x40, 0x35, 0x6c,
+				0xbb, 0x83, 0x9c, 0xbe, 0x05, 0x30, 0x3d, 0x77, 0x05, 0xfa,
+			},
+		},
 		Metadata: make(map[string]any),
 	}, nil
 }
@@ -18,16 +18,38 @@ import (
 
 	"github.com/attestantio/go-eth2-client/api"
 	apiv1 "github.com/attestantio/go-eth2-client/api/v1"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
 )
 
 // NodeSyncing provides the state of the node's synchronization with the chain.
-func (s *Service) NodeSyncing(_ context.Context) (*api.Response[*apiv1.SyncState], error) {
+func (s *Service) NodeSyncing(ctx context.Context) (*api.Response[*apiv1.SyncState], error) {
 	return &api.Response[*apiv1.SyncState]{
 		Data: &apiv1.SyncState{
-			HeadSlot:     s.HeadSlot,
+			HeadSlot:     s.headSlot(ctx),
 			SyncDistance: s.SyncDistance,
 			IsSyncing:    s.SyncDistance > 0,
 		},
 		Metadata: make(map[string]any),
 	}, nil
 }
+
+// headSlot provides the head slot of the mock, following the simulated chain or clock source if either is set.
+func (s *Service) headSlot(ctx context.Context) phase0.Slot {
+	if s.chain != nil {
+		return s.chain.headSlot()
+	}
+	if s.clockSource == nil {
+		return s.HeadSlot
+	}
+	now := s.clockSource.Now()
+	if now.Before(s.genesisTime) {
+		return 0
+	}
+
+	slotDuration, err := s.SlotDuration(ctx)
+	if err != nil {
+		return s.HeadSlot
+	}
+
+	return phase0.Slot(now.Sub(s.genesisTime) / slotDuration)
+}
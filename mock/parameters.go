@@ -15,8 +15,10 @@ package mock
 
 import (
 	"errors"
+	"fmt"
 	"time"
 
+	"github.com/attestantio/go-eth2-client/clock"
 	"github.com/rs/zerolog"
 )
 
@@ -25,6 +27,8 @@ type parameters struct {
 	name        string
 	timeout     time.Duration
 	genesisTime time.Time
+	clockSource clock.Source
+	validators  uint64
 }
 
 // Parameter is the interface for service parameters.
@@ -66,13 +70,34 @@ func WithGenesisTime(genesisTime time.Time) Parameter {
 	})
 }
 
+// WithClockSource sets the source of time for the mock.
+// If set, the mock's head slot follows the source, allowing a clock.Fake to drive
+// both the mock and any chain clock built on it.
+func WithClockSource(source clock.Source) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.clockSource = source
+	})
+}
+
+// WithSimulatedChain turns the mock into a deterministic simulated chain with the given number of validators.
+// The chain starts at the genesis time and advances with the clock source, building a block for each slot
+// that ends without one being submitted.  Duties, committees, blocks, finality and balances are consistent
+// with each other, submitted attestations, voluntary exits and blocks are applied to the chain, and events
+// are fed to subscribers.
+// As the chain builds every slot from genesis, the genesis time cannot be more than 8192 slots in the past.
+// If not set, or set to 0, the mock returns fixed data.
+func WithSimulatedChain(validators uint64) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.validators = validators
+	})
+}
+
 // parseAndCheckParameters parses and checks parameters to ensure that mandatory parameters are present and correct.
 func parseAndCheckParameters(params ...Parameter) (*parameters, error) {
 	parameters := parameters{
-		logLevel:    zerolog.GlobalLevel(),
-		name:        "mock",
-		timeout:     2 * time.Second,
-		genesisTime: time.Now(),
+		logLevel: zerolog.GlobalLevel(),
+		name:     "mock",
+		timeout:  2 * time.Second,
 	}
 	for _, p := range params {
 		if params != nil {
@@ -83,6 +108,22 @@ func parseAndCheckParameters(params ...Parameter) (*parameters, error) {
 	if parameters.name == "" {
 		return nil, errors.New("name not specified")
 	}
+	if parameters.genesisTime.IsZero() {
+		if parameters.clockSource != nil {
+			parameters.genesisTime = parameters.clockSource.Now()
+		} else {
+			parameters.genesisTime = time.Now()
+		}
+	}
+	if parameters.validators > 0 {
+		now := time.Now()
+		if parameters.clockSource != nil {
+			now = parameters.clockSource.Now()
+		}
+		if now.Sub(parameters.genesisTime) > time.Duration(chainMaxGenesisAge)*chainSlotDuration {
+			return nil, fmt.Errorf("simulated chain genesis cannot be more than %d slots in the past", chainMaxGenesisAge)
+		}
+	}
 
 	return &parameters, nil
 }
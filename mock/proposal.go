@@ -28,6 +28,10 @@ func (s *Service) Proposal(_ context.Context,
 ) (
 	*api.Response[*api.VersionedProposal], error,
 ) {
+	if s.chain != nil {
+		return s.chain.proposal(opts)
+	}
+
 	// Build a beacon block.
 
 	// Create a few attestations.
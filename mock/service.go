@@ -17,6 +17,7 @@ import (
 	"context"
 	"time"
 
+	"github.com/attestantio/go-eth2-client/clock"
 	"github.com/attestantio/go-eth2-client/spec/phase0"
 	"github.com/pkg/errors"
 	"github.com/rs/zerolog"
@@ -29,6 +30,9 @@ type Service struct {
 	timeout time.Duration
 
 	genesisTime time.Time
+	clockSource clock.Source
+	// chain is the simulated chain, if enabled.
+	chain *chain
 
 	// Various information from the node that does not change during the
 	// lifetime of a beacon node.
@@ -62,6 +66,7 @@ func New(ctx context.Context, params ...Parameter) (*Service, error) {
 	s := &Service{
 		name:        parameters.name,
 		genesisTime: parameters.genesisTime,
+		clockSource: parameters.clockSource,
 		timeout:     parameters.timeout,
 		nodeVersion: "mock",
 
@@ -69,6 +74,15 @@ func New(ctx context.Context, params ...Parameter) (*Service, error) {
 		SyncDistance: 0,
 	}
 
+	if parameters.validators > 0 {
+		source := parameters.clockSource
+		if source == nil {
+			source = clock.SystemSource()
+		}
+		s.chain = newChain(source, parameters.genesisTime, parameters.validators)
+		s.chain.start(ctx)
+	}
+
 	// Fetch static values to confirm the connection is good.
 	if err := s.fetchStaticValues(ctx); err != nil {
 		return nil, errors.Wrap(err, "failed to confirm node connection")
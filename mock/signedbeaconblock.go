@@ -22,14 +22,20 @@ import (
 )
 
 // SignedBeaconBlock fetches a signed beacon block given a block ID.
-func (s *Service) SignedBeaconBlock(_ context.Context, _ *api.SignedBeaconBlockOpts) (*api.Response[*spec.VersionedSignedBeaconBlock], error) {
+func (s *Service) SignedBeaconBlock(_ context.Context, opts *api.SignedBeaconBlockOpts) (*api.Response[*spec.VersionedSignedBeaconBlock], error) {
+	if s.chain != nil {
+		return s.chain.signedBeaconBlock(opts)
+	}
+
 	return &api.Response[*spec.VersionedSignedBeaconBlock]{
 		Data: &spec.VersionedSignedBeaconBlock{
 			Version: spec.DataVersionPhase0,
 			Phase0: &phase0.SignedBeaconBlock{
 				Message: &phase0.BeaconBlock{
 					Body: &phase0.BeaconBlockBody{
-						ETH1Data: &phase0.ETH1Data{},
+						ETH1Data: &phase0.ETH1Data{
+							BlockHash: make([]byte, 32),
+						},
 					},
 				},
 			},
@@ -24,8 +24,9 @@ import (
 // This returns various useful values.
 func (s *Service) Spec(_ context.Context) (*api.Response[map[string]any], error) {
 	data := map[string]any{
-		"SECONDS_PER_SLOT": 12 * time.Second,
-		"SLOTS_PER_EPOCH":  uint64(32),
+		"SECONDS_PER_SLOT":                 12 * time.Second,
+		"SLOTS_PER_EPOCH":                  uint64(32),
+		"EPOCHS_PER_SYNC_COMMITTEE_PERIOD": uint64(256),
 	}
 
 	return &api.Response[map[string]any]{
@@ -20,6 +20,10 @@ import (
 )
 
 // SubmitAttestations submits attestations.
-func (s *Service) SubmitAttestations(_ context.Context, _ []*spec.Attestation) error {
+func (s *Service) SubmitAttestations(_ context.Context, attestations []*spec.Attestation) error {
+	if s.chain != nil {
+		return s.chain.submitAttestations(attestations)
+	}
+
 	return nil
 }
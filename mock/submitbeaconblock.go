@@ -17,9 +17,18 @@ import (
 	"context"
 
 	spec "github.com/attestantio/go-eth2-client/spec"
+	"github.com/pkg/errors"
 )
 
 // SubmitBeaconBlock submits a beacon block.
-func (s *Service) SubmitBeaconBlock(_ context.Context, _ *spec.VersionedSignedBeaconBlock) error {
+func (s *Service) SubmitBeaconBlock(_ context.Context, block *spec.VersionedSignedBeaconBlock) error {
+	if s.chain != nil {
+		if block == nil || block.Version != spec.DataVersionPhase0 {
+			return errors.New("simulated chain only supports phase0 blocks")
+		}
+
+		return s.chain.submitBlock(block.Phase0)
+	}
+
 	return nil
 }
@@ -17,9 +17,19 @@ import (
 	"context"
 
 	"github.com/attestantio/go-eth2-client/api"
+	"github.com/attestantio/go-eth2-client/spec"
+	"github.com/pkg/errors"
 )
 
 // SubmitProposal submits a proposal.
-func (s *Service) SubmitProposal(_ context.Context, _ *api.VersionedSignedProposal) error {
+func (s *Service) SubmitProposal(_ context.Context, proposal *api.VersionedSignedProposal) error {
+	if s.chain != nil {
+		if proposal == nil || proposal.Version != spec.DataVersionPhase0 {
+			return errors.New("simulated chain only supports phase0 proposals")
+		}
+
+		return s.chain.submitBlock(proposal.Phase0)
+	}
+
 	return nil
 }
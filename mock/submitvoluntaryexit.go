@@ -20,6 +20,10 @@ import (
 )
 
 // SubmitVoluntaryExit submits a voluntary exit.
-func (s *Service) SubmitVoluntaryExit(_ context.Context, _ *spec.SignedVoluntaryExit) error {
+func (s *Service) SubmitVoluntaryExit(_ context.Context, voluntaryExit *spec.SignedVoluntaryExit) error {
+	if s.chain != nil {
+		return s.chain.submitVoluntaryExit(voluntaryExit)
+	}
+
 	return nil
 }
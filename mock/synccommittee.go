@@ -21,7 +21,11 @@ import (
 )
 
 // SyncCommittee fetches the sync committee for the given state.
-func (s *Service) SyncCommittee(_ context.Context, _ *api.SyncCommitteeOpts) (*api.Response[*apiv1.SyncCommittee], error) {
+func (s *Service) SyncCommittee(_ context.Context, opts *api.SyncCommitteeOpts) (*api.Response[*apiv1.SyncCommittee], error) {
+	if s.chain != nil {
+		return s.chain.syncCommittee(opts)
+	}
+
 	return &api.Response[*apiv1.SyncCommittee]{
 		Data:     &apiv1.SyncCommittee{},
 		Metadata: make(map[string]any),
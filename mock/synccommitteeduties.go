@@ -22,6 +22,10 @@ import (
 
 // SyncCommitteeDuties obtains sync committee duties.
 func (s *Service) SyncCommitteeDuties(_ context.Context, opts *api.SyncCommitteeDutiesOpts) (*api.Response[[]*apiv1.SyncCommitteeDuty], error) {
+	if s.chain != nil {
+		return s.chain.syncCommitteeDuties(opts)
+	}
+
 	data := make([]*apiv1.SyncCommitteeDuty, len(opts.Indices))
 	for i := range opts.Indices {
 		data[i] = &apiv1.SyncCommitteeDuty{
@@ -24,7 +24,11 @@ import (
 // stateID can be a slot number or state root, or one of the special values "genesis", "head", "justified" or "finalized".
 // validatorIndices is a list of validator indices to restrict the returned values.  If no validators are supplied no filter
 // will be applied.
-func (s *Service) ValidatorBalances(_ context.Context, _ *api.ValidatorBalancesOpts) (*api.Response[map[phase0.ValidatorIndex]phase0.Gwei], error) {
+func (s *Service) ValidatorBalances(_ context.Context, opts *api.ValidatorBalancesOpts) (*api.Response[map[phase0.ValidatorIndex]phase0.Gwei], error) {
+	if s.chain != nil {
+		return s.chain.validatorBalances(opts)
+	}
+
 	return &api.Response[map[phase0.ValidatorIndex]phase0.Gwei]{
 		Data:     map[phase0.ValidatorIndex]phase0.Gwei{},
 		Metadata: make(map[string]any),
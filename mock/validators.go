@@ -22,7 +22,11 @@ import (
 )
 
 // Validators provides the validators, with their balance and status, for a given state.
-func (s *Service) Validators(_ context.Context, _ *api.ValidatorsOpts) (*api.Response[map[phase0.ValidatorIndex]*apiv1.Validator], error) {
+func (s *Service) Validators(_ context.Context, opts *api.ValidatorsOpts) (*api.Response[map[phase0.ValidatorIndex]*apiv1.Validator], error) {
+	if s.chain != nil {
+		return s.chain.validatorsAt(opts)
+	}
+
 	return &api.Response[map[phase0.ValidatorIndex]*apiv1.Validator]{
 		Data:     map[phase0.ValidatorIndex]*apiv1.Validator{},
 		Metadata: make(map[string]any),
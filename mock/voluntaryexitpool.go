@@ -21,6 +21,10 @@ import (
 
 // VoluntaryExitPool fetches the voluntary exit pool.
 func (s *Service) VoluntaryExitPool(_ context.Context) ([]*spec.SignedVoluntaryExit, error) {
+	if s.chain != nil {
+		return s.chain.voluntaryExitPool(), nil
+	}
+
 	res := make([]*spec.SignedVoluntaryExit, 5)
 	for i := 0; i < 5; i++ {
 		res[i] = &spec.SignedVoluntaryExit{
@@ -28,7 +28,7 @@ func (s *Service) AggregateAttestation(ctx context.Context,
 	*api.Response[*phase0.Attestation],
 	error,
 ) {
-	res, err := s.doCall(ctx, func(ctx context.Context, client consensusclient.Service) (interface{}, error) {
+	res, err := s.doCall(ctx, "AggregateAttestation", func(ctx context.Context, client consensusclient.Service) (interface{}, error) {
 		aggregate, err := client.(consensusclient.AggregateAttestationProvider).AggregateAttestation(ctx, opts)
 		if err != nil {
 			return nil, err
@@ -28,7 +28,16 @@ func (s *Service) AttestationData(ctx context.Context,
 	*api.Response[*phase0.AttestationData],
 	error,
 ) {
-	res, err := s.doCall(ctx, func(ctx context.Context, client consensusclient.Service) (interface{}, error) {
+	if s.bestAttestationDataEnabled(ctx) {
+		return s.doBestAttestationData(ctx, opts)
+	}
+	if quorum, exists := s.quorums["AttestationData"]; exists {
+		return doQuorumCall(ctx, s, "AttestationData", quorum, func(ctx context.Context, client consensusclient.Service) (*api.Response[*phase0.AttestationData], error) {
+			return client.(consensusclient.AttestationDataProvider).AttestationData(ctx, opts)
+		})
+	}
+
+	res, err := s.doCall(ctx, "AttestationData", func(ctx context.Context, client consensusclient.Service) (interface{}, error) {
 		attestationData, err := client.(consensusclient.AttestationDataProvider).AttestationData(ctx, opts)
 		if err != nil {
 			return nil, err
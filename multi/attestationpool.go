@@ -28,7 +28,7 @@ func (s *Service) AttestationPool(ctx context.Context,
 	*api.Response[[]*phase0.Attestation],
 	error,
 ) {
-	res, err := s.doCall(ctx, func(ctx context.Context, client consensusclient.Service) (interface{}, error) {
+	res, err := s.doBulkCall(ctx, "AttestationPool", func(ctx context.Context, client consensusclient.Service) (interface{}, error) {
 		attestationPool, err := client.(consensusclient.AttestationPoolProvider).AttestationPool(ctx, opts)
 		if err != nil {
 			return nil, err
@@ -29,7 +29,7 @@ func (s *Service) AttesterDuties(ctx context.Context,
 	*api.Response[[]*apiv1.AttesterDuty],
 	error,
 ) {
-	res, err := s.doCall(ctx, func(ctx context.Context, client consensusclient.Service) (interface{}, error) {
+	res, err := s.doCall(ctx, "AttesterDuties", func(ctx context.Context, client consensusclient.Service) (interface{}, error) {
 		block, err := client.(consensusclient.AttesterDutiesProvider).AttesterDuties(ctx, opts)
 		if err != nil {
 			return nil, err
@@ -28,7 +28,7 @@ func (s *Service) BeaconBlockHeader(ctx context.Context,
 	*api.Response[*apiv1.BeaconBlockHeader],
 	error,
 ) {
-	res, err := s.doCall(ctx, func(ctx context.Context, client consensusclient.Service) (interface{}, error) {
+	res, err := s.doCall(ctx, "BeaconBlockHeader", func(ctx context.Context, client consensusclient.Service) (interface{}, error) {
 		beaconBlockHeader, err := client.(consensusclient.BeaconBlockHeadersProvider).BeaconBlockHeader(ctx, opts)
 		if err != nil {
 			return nil, err
@@ -28,7 +28,13 @@ func (s *Service) BeaconBlockRoot(ctx context.Context,
 	*api.Response[*phase0.Root],
 	error,
 ) {
-	res, err := s.doCall(ctx, func(ctx context.Context, client consensusclient.Service) (interface{}, error) {
+	if quorum, exists := s.quorums["BeaconBlockRoot"]; exists {
+		return doQuorumCall(ctx, s, "BeaconBlockRoot", quorum, func(ctx context.Context, client consensusclient.Service) (*api.Response[*phase0.Root], error) {
+			return client.(consensusclient.BeaconBlockRootProvider).BeaconBlockRoot(ctx, opts)
+		})
+	}
+
+	res, err := s.doCall(ctx, "BeaconBlockRoot", func(ctx context.Context, client consensusclient.Service) (interface{}, error) {
 		root, err := client.(consensusclient.BeaconBlockRootProvider).BeaconBlockRoot(ctx, opts)
 		if err != nil {
 			return nil, err
@@ -28,7 +28,7 @@ func (s *Service) BeaconCommittees(ctx context.Context,
 	*api.Response[[]*apiv1.BeaconCommittee],
 	error,
 ) {
-	res, err := s.doCall(ctx, func(ctx context.Context, client consensusclient.Service) (interface{}, error) {
+	res, err := s.doBulkCall(ctx, "BeaconCommittees", func(ctx context.Context, client consensusclient.Service) (interface{}, error) {
 		beaconCommittees, err := client.(consensusclient.BeaconCommitteesProvider).BeaconCommittees(ctx, opts)
 		if err != nil {
 			return nil, err
@@ -15,6 +15,8 @@ package multi
 
 import (
 	"context"
+	"fmt"
+	"io"
 
 	consensusclient "github.com/attestantio/go-eth2-client"
 	"github.com/attestantio/go-eth2-client/api"
@@ -23,7 +25,7 @@ import (
 
 // BeaconState fetches a beacon state.
 func (s *Service) BeaconState(ctx context.Context, opts *api.BeaconStateOpts) (*api.Response[*spec.VersionedBeaconState], error) {
-	res, err := s.doCall(ctx, func(ctx context.Context, client consensusclient.Service) (interface{}, error) {
+	res, err := s.doBulkCall(ctx, "BeaconState", func(ctx context.Context, client consensusclient.Service) (interface{}, error) {
 		beaconState, err := client.(consensusclient.BeaconStateProvider).BeaconState(ctx, opts)
 		if err != nil {
 			return nil, err
@@ -37,3 +39,31 @@ func (s *Service) BeaconState(ctx context.Context, opts *api.BeaconStateOpts) (*
 
 	return res.(*api.Response[*spec.VersionedBeaconState]), nil
 }
+
+// WriteBeaconStateSSZ writes the SSZ-encoded beacon state to the supplied writer.
+func (s *Service) WriteBeaconStateSSZ(ctx context.Context,
+	opts *api.BeaconStateOpts,
+	w io.Writer,
+) (
+	*api.Response[*api.SSZWriteResult],
+	error,
+) {
+	writer := &countingWriter{w: w}
+	res, err := s.doBulkCall(ctx, "WriteBeaconStateSSZ", func(ctx context.Context, client consensusclient.Service) (interface{}, error) {
+		provider, isProvider := client.(consensusclient.BeaconStateSSZWriter)
+		if !isProvider {
+			return nil, fmt.Errorf("%s@%s does not support this call", client.Name(), client.Address())
+		}
+		result, err := provider.WriteBeaconStateSSZ(ctx, opts, writer)
+		if err != nil {
+			return nil, err
+		}
+
+		return result, nil
+	}, writeErrHandler(writer))
+	if err != nil {
+		return nil, err
+	}
+
+	return res.(*api.Response[*api.SSZWriteResult]), nil
+}
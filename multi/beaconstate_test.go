@@ -14,13 +14,17 @@
 package multi_test
 
 import (
+	"bytes"
 	"context"
+	"errors"
+	"io"
 	"testing"
 
 	consensusclient "github.com/attestantio/go-eth2-client"
 	"github.com/attestantio/go-eth2-client/api"
 	"github.com/attestantio/go-eth2-client/mock"
 	"github.com/attestantio/go-eth2-client/multi"
+	"github.com/attestantio/go-eth2-client/spec"
 	"github.com/attestantio/go-eth2-client/testclients"
 	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/require"
@@ -58,3 +62,102 @@ func TestBeaconState(t *testing.T) {
 	// At this point we expect mock 3 to be in active (unless probability hates us).
 	require.Equal(t, "mock 3", multiClient.Address())
 }
+
+// sszWriter is a client that writes SSZ data, optionally failing part way through.
+type sszWriter struct {
+	*mock.Service
+	name    string
+	data    []byte
+	failAt  int
+	written int
+}
+
+func (s *sszWriter) Address() string {
+	return s.name
+}
+
+func (s *sszWriter) WriteBeaconStateSSZ(_ context.Context,
+	_ *api.BeaconStateOpts,
+	w io.Writer,
+) (
+	*api.Response[*api.SSZWriteResult],
+	error,
+) {
+	if s.failAt >= 0 {
+		n, err := w.Write(s.data[:s.failAt])
+		s.written += n
+		if err != nil {
+			return nil, err
+		}
+
+		return nil, errors.New("connection reset")
+	}
+	n, err := w.Write(s.data)
+	s.written += n
+	if err != nil {
+		return nil, err
+	}
+
+	return &api.Response[*api.SSZWriteResult]{
+		Data: &api.SSZWriteResult{
+			Version: spec.DataVersionCapella,
+			Length:  int64(n),
+		},
+	}, nil
+}
+
+func TestWriteBeaconStateSSZ(t *testing.T) {
+	ctx := context.Background()
+
+	data := []byte{0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08}
+
+	tests := []struct {
+		name    string
+		failAt  int
+		res     []byte
+		written int
+		err     string
+	}{
+		{
+			name:    "FailoverBeforeWrite",
+			failAt:  0,
+			res:     data,
+			written: 0,
+		},
+		{
+			name:    "NoFailoverAfterWrite",
+			failAt:  4,
+			res:     data[:4],
+			written: 4,
+			err:     "connection reset",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockClient, err := mock.New(ctx)
+			require.NoError(t, err)
+			client1 := &sszWriter{Service: mockClient, name: "writer 1", data: data, failAt: test.failAt}
+			client2 := &sszWriter{Service: mockClient, name: "writer 2", data: data, failAt: -1}
+
+			multiClient, err := multi.New(ctx,
+				multi.WithLogLevel(zerolog.Disabled),
+				multi.WithClients([]consensusclient.Service{
+					client1,
+					client2,
+				}),
+			)
+			require.NoError(t, err)
+
+			var buf bytes.Buffer
+			_, err = multiClient.(consensusclient.BeaconStateSSZWriter).WriteBeaconStateSSZ(ctx, &api.BeaconStateOpts{State: "head"}, &buf)
+			if test.err != "" {
+				require.EqualError(t, err, test.err)
+			} else {
+				require.NoError(t, err)
+			}
+			require.Equal(t, test.res, buf.Bytes())
+			require.Equal(t, test.written, client1.written)
+		})
+	}
+}
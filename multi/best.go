@@ -0,0 +1,140 @@
+// Copyright © 2023 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package multi
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	consensusclient "github.com/attestantio/go-eth2-client"
+	"github.com/attestantio/go-eth2-client/api"
+	"github.com/pkg/errors"
+)
+
+// responseScorer scores a response from a client.  An error excludes the response from selection.
+type responseScorer[T any] func(ctx context.Context, client consensusclient.Service, response *api.Response[T]) (float64, error)
+
+// scoredResponse is a response obtained from a client, along with its score.
+type scoredResponse[T any] struct {
+	client   consensusclient.Service
+	response *api.Response[T]
+	score    float64
+	// err is the error returned by the client.
+	err error
+	// timedOut is true if the client returned its error after the timeout.
+	timedOut bool
+	// rejection is the reason the response was excluded by the scorer.
+	rejection error
+}
+
+// doBest carries out a call on all active clients in parallel, returning the highest
+// scoring response received within the timeout.  The name of the call is the name of
+// the provider function, for example "Proposal".
+func doBest[T any](ctx context.Context,
+	s *Service,
+	name string,
+	timeout time.Duration,
+	call func(ctx context.Context, client consensusclient.Service) (*api.Response[T], error),
+	scorer responseScorer[T],
+) (
+	*api.Response[T],
+	error,
+) {
+	log := s.log.With().Str("call", name).Logger()
+	ctx = log.WithContext(ctx)
+
+	activeClients := s.dutyClients(name, s.currentActiveClients(ctx))
+	if len(activeClients) == 0 {
+		return nil, errors.New("no active clients to which to make call")
+	}
+
+	callCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	// The channel is large enough for every client, so late responses do not block.
+	ch := make(chan *scoredResponse[T], len(activeClients))
+	for _, client := range activeClients {
+		go func(client consensusclient.Service) {
+			started := time.Now()
+			response, err := call(callCtx, client)
+			s.recordCall(ctx, client, name, started, err)
+			scored := &scoredResponse[T]{
+				client:   client,
+				response: response,
+				err:      err,
+				timedOut: err != nil && callCtx.Err() != nil,
+			}
+			if err == nil && response != nil {
+				scored.score, scored.rejection = scorer(callCtx, client, response)
+			}
+			ch <- scored
+		}(client)
+	}
+
+	responses := make(map[consensusclient.Service]*scoredResponse[T], len(activeClients))
+collect:
+	for len(responses) < len(activeClients) {
+		select {
+		case <-callCtx.Done():
+			log.Debug().Int("responses", len(responses)).Int("clients", len(activeClients)).Msg("Timed out waiting for responses")
+
+			break collect
+		case scored := <-ch:
+			responses[scored.client] = scored
+		}
+	}
+
+	var best *scoredResponse[T]
+	var err error
+	// Iterate in client order, so that ties go to the earlier client.
+	for _, client := range activeClients {
+		scored, exists := responses[client]
+		if !exists {
+			continue
+		}
+		switch {
+		case scored.err != nil:
+			err = scored.err
+			if !errors.Is(scored.err, api.ErrNotSupported) && !scored.timedOut {
+				log.Debug().Str("client", client.Name()).Str("address", client.Address()).Err(scored.err).Msg("Call failed on client")
+				s.callFailed(ctx, client)
+			}
+		case scored.response == nil:
+			err = errors.New("empty response")
+		case scored.rejection != nil:
+			log.Debug().Str("address", client.Address()).Err(scored.rejection).Msg("Response rejected")
+			err = scored.rejection
+		default:
+			log.Trace().Str("address", client.Address()).Float64("score", scored.score).Msg("Scored response")
+			if best == nil || scored.score > best.score {
+				best = scored
+			}
+		}
+	}
+
+	if best == nil {
+		if err == nil {
+			err = fmt.Errorf("no response to %s received within %v", name, timeout)
+		}
+
+		return nil, err
+	}
+	log.Trace().Str("address", best.client.Address()).Float64("score", best.score).Msg("Selected response")
+	if session := sessionFromContext(ctx); session != nil {
+		s.useSessionClient(ctx, session, best.client, name, nil)
+	}
+
+	return best.response, nil
+}
@@ -0,0 +1,98 @@
+// Copyright © 2023 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package multi
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	consensusclient "github.com/attestantio/go-eth2-client"
+	"github.com/attestantio/go-eth2-client/api"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/pkg/errors"
+)
+
+// AttestationDataScorer scores attestation data obtained from a client.
+// The attestation data with the highest score is selected.
+type AttestationDataScorer func(ctx context.Context, client consensusclient.Service, data *phase0.AttestationData) float64
+
+// HeadAttestationDataScorer scores attestation data by the epochs of its source and target
+// checkpoints, with the highest slot for the head block breaking ties.  The slot of the head
+// block is obtained from the client that provided the data.
+func HeadAttestationDataScorer(ctx context.Context, client consensusclient.Service, data *phase0.AttestationData) float64 {
+	score := float64(data.Source.Epoch + data.Target.Epoch)
+
+	headerProvider, isProvider := client.(consensusclient.BeaconBlockHeadersProvider)
+	if !isProvider {
+		return score
+	}
+	response, err := headerProvider.BeaconBlockHeader(ctx, &api.BeaconBlockHeaderOpts{
+		Block: api.BlockIDFromRoot(data.BeaconBlockRoot),
+	})
+	if err != nil || response.Data == nil || response.Data.Header == nil || response.Data.Header.Message == nil {
+		return score
+	}
+	headSlot := response.Data.Header.Message.Slot
+	if headSlot > data.Slot {
+		return score
+	}
+
+	// Add a value between 0 and 1 that is higher the closer the head is to the attestation slot.
+	return score + 1/float64(1+data.Slot-headSlot)
+}
+
+// doBestAttestationData requests attestation data from all active clients in parallel,
+// returning the highest scoring data received before the best attestation data timeout.
+func (s *Service) doBestAttestationData(ctx context.Context,
+	opts *api.AttestationDataOpts,
+) (
+	*api.Response[*phase0.AttestationData],
+	error,
+) {
+	if opts == nil {
+		return nil, errors.New("no options specified")
+	}
+
+	slotsPerEpoch, err := s.SlotsPerEpoch(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to obtain slots per epoch")
+	}
+	epoch := phase0.Epoch(uint64(opts.Slot) / slotsPerEpoch)
+
+	return doBest(ctx, s, "AttestationData", s.bestAttestationDataTimeout,
+		func(ctx context.Context, client consensusclient.Service) (*api.Response[*phase0.AttestationData], error) {
+			return client.(consensusclient.AttestationDataProvider).AttestationData(ctx, opts)
+		},
+		func(ctx context.Context, client consensusclient.Service, response *api.Response[*phase0.AttestationData]) (float64, error) {
+			data := response.Data
+			switch {
+			case data == nil || data.Source == nil || data.Target == nil:
+				return 0, errors.New("incomplete attestation data")
+			case data.Slot != opts.Slot:
+				return 0, fmt.Errorf("attestation data for slot %d; expected %d", data.Slot, opts.Slot)
+			case data.Target.Epoch != epoch:
+				return 0, fmt.Errorf("attestation data target epoch %d; expected %d", data.Target.Epoch, epoch)
+			}
+
+			return s.attestationDataScorer(ctx, client, data), nil
+		},
+	)
+}
+
+// bestAttestationDataEnabled returns true if attestation data should be selected from all active clients.
+// It is not enabled within a session that is already pinned to a client.
+func (s *Service) bestAttestationDataEnabled(ctx context.Context) bool {
+	return s.bestAttestationDataTimeout > time.Duration(0) && !sessionPinned(ctx)
+}
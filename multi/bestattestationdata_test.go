@@ -0,0 +1,203 @@
+// Copyright © 2023 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package multi_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	consensusclient "github.com/attestantio/go-eth2-client"
+	"github.com/attestantio/go-eth2-client/api"
+	apiv1 "github.com/attestantio/go-eth2-client/api/v1"
+	"github.com/attestantio/go-eth2-client/mock"
+	"github.com/attestantio/go-eth2-client/multi"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+// attestationDataClient is a client that returns fixed attestation data, with a head block at the given slot.
+type attestationDataClient struct {
+	*mock.Service
+	data     *phase0.AttestationData
+	headSlot phase0.Slot
+}
+
+func (c *attestationDataClient) AttestationData(_ context.Context, _ *api.AttestationDataOpts) (*api.Response[*phase0.AttestationData], error) {
+	return &api.Response[*phase0.AttestationData]{
+		Data:     c.data,
+		Metadata: make(map[string]any),
+	}, nil
+}
+
+func (c *attestationDataClient) BeaconBlockHeader(_ context.Context, opts *api.BeaconBlockHeaderOpts) (*api.Response[*apiv1.BeaconBlockHeader], error) {
+	root, _ := opts.Block.Root()
+
+	return &api.Response[*apiv1.BeaconBlockHeader]{
+		Data: &apiv1.BeaconBlockHeader{
+			Root: root,
+			Header: &phase0.SignedBeaconBlockHeader{
+				Message: &phase0.BeaconBlockHeader{
+					Slot: c.headSlot,
+				},
+			},
+		},
+		Metadata: make(map[string]any),
+	}, nil
+}
+
+func newAttestationDataClient(ctx context.Context,
+	t *testing.T,
+	name string,
+	slot phase0.Slot,
+	sourceEpoch phase0.Epoch,
+	targetEpoch phase0.Epoch,
+	headSlot phase0.Slot,
+) consensusclient.Service {
+	t.Helper()
+
+	client, err := mock.New(ctx, mock.WithName(name))
+	require.NoError(t, err)
+
+	return &attestationDataClient{
+		Service: client,
+		data: &phase0.AttestationData{
+			Slot:            slot,
+			BeaconBlockRoot: phase0.Root{byte(headSlot)},
+			Source:          &phase0.Checkpoint{Epoch: sourceEpoch},
+			Target:          &phase0.Checkpoint{Epoch: targetEpoch},
+		},
+		headSlot: headSlot,
+	}
+}
+
+func TestBestAttestationData(t *testing.T) {
+	ctx := context.Background()
+
+	// Slot 100 is in epoch 3.
+	tests := []struct {
+		name    string
+		clients []consensusclient.Service
+		scorer  multi.AttestationDataScorer
+		address string
+		err     string
+	}{
+		{
+			name: "HighestHead",
+			clients: []consensusclient.Service{
+				newAttestationDataClient(ctx, t, "a", 100, 2, 3, 98),
+				newAttestationDataClient(ctx, t, "b", 100, 2, 3, 100),
+				newAttestationDataClient(ctx, t, "c", 100, 2, 3, 99),
+			},
+			address: "b",
+		},
+		{
+			name: "MostRecentCheckpoints",
+			clients: []consensusclient.Service{
+				newAttestationDataClient(ctx, t, "a", 100, 1, 3, 100),
+				newAttestationDataClient(ctx, t, "b", 100, 2, 3, 97),
+			},
+			address: "b",
+		},
+		{
+			name: "TargetMismatchSkipped",
+			clients: []consensusclient.Service{
+				newAttestationDataClient(ctx, t, "a", 100, 2, 3, 96),
+				newAttestationDataClient(ctx, t, "b", 100, 3, 4, 100),
+			},
+			address: "a",
+		},
+		{
+			name: "AllMismatched",
+			clients: []consensusclient.Service{
+				newAttestationDataClient(ctx, t, "a", 100, 3, 4, 100),
+			},
+			err: "attestation data target epoch 4; expected 3",
+		},
+		{
+			name: "CustomScorer",
+			clients: []consensusclient.Service{
+				newAttestationDataClient(ctx, t, "a", 100, 2, 3, 100),
+				newAttestationDataClient(ctx, t, "b", 100, 2, 3, 90),
+			},
+			scorer: func(_ context.Context, client consensusclient.Service, _ *phase0.AttestationData) float64 {
+				if client.Address() == "b" {
+					return 1
+				}
+
+				return 0
+			},
+			address: "b",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			params := []multi.Parameter{
+				multi.WithLogLevel(zerolog.Disabled),
+				multi.WithClients(test.clients),
+				multi.WithBestAttestationData(200 * time.Millisecond),
+			}
+			if test.scorer != nil {
+				params = append(params, multi.WithAttestationDataScorer(test.scorer))
+			}
+			multiClient, err := multi.New(ctx, params...)
+			require.NoError(t, err)
+
+			response, err := multiClient.(consensusclient.AttestationDataProvider).AttestationData(ctx, &api.AttestationDataOpts{Slot: 100})
+			if test.err != "" {
+				require.EqualError(t, err, test.err)
+
+				return
+			}
+			require.NoError(t, err)
+			for _, client := range test.clients {
+				if client.Address() == test.address {
+					require.Equal(t, client.(*attestationDataClient).data, response.Data)
+				}
+			}
+		})
+	}
+}
+
+func TestBestAttestationDataNilOpts(t *testing.T) {
+	ctx := context.Background()
+
+	multiClient, err := multi.New(ctx,
+		multi.WithLogLevel(zerolog.Disabled),
+		multi.WithClients([]consensusclient.Service{
+			newAttestationDataClient(ctx, t, "a", 100, 2, 3, 100),
+		}),
+		multi.WithBestAttestationData(200*time.Millisecond),
+	)
+	require.NoError(t, err)
+
+	_, err = multiClient.(consensusclient.AttestationDataProvider).AttestationData(ctx, nil)
+	require.EqualError(t, err, "no options specified")
+}
+
+func TestBestAttestationDataWithQuorum(t *testing.T) {
+	ctx := context.Background()
+
+	_, err := multi.New(ctx,
+		multi.WithLogLevel(zerolog.Disabled),
+		multi.WithClients([]consensusclient.Service{
+			newAttestationDataClient(ctx, t, "a", 100, 2, 3, 100),
+		}),
+		multi.WithBestAttestationData(200*time.Millisecond),
+		multi.WithQuorum("AttestationData", multi.Quorum{}),
+	)
+	require.EqualError(t, err, "problem with parameters: best attestation data and an attestation data quorum cannot both be specified")
+}
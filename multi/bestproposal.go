@@ -0,0 +1,121 @@
+// Copyright © 2023 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package multi
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	consensusclient "github.com/attestantio/go-eth2-client"
+	"github.com/attestantio/go-eth2-client/api"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+)
+
+// ScorableProposal is the information from a full or blinded proposal available to a ProposalScorer.
+type ScorableProposal interface {
+	// Slot returns the slot of the proposal.
+	Slot() (phase0.Slot, error)
+	// Attestations returns the attestations of the proposal.
+	Attestations() ([]*phase0.Attestation, error)
+}
+
+// ProposalScorer scores a proposal given its data and the metadata of its response.
+// The proposal with the highest score is selected.
+type ProposalScorer func(ctx context.Context, proposal ScorableProposal, metadata map[string]any) float64
+
+// metadata keys, from headers and JSON respectively, for the values of a proposal.
+var (
+	executionPayloadValueKeys = []string{"Eth-Execution-Payload-Value", "execution_payload_value"}
+	consensusBlockValueKeys   = []string{"Eth-Consensus-Block-Value", "consensus_block_value"}
+)
+
+// ValueProposalScorer scores a proposal by the sum of its execution payload value and
+// consensus block value, in Wei, as provided in the metadata of the response.
+func ValueProposalScorer(_ context.Context, _ ScorableProposal, metadata map[string]any) float64 {
+	value := new(big.Int)
+	for _, keys := range [][]string{executionPayloadValueKeys, consensusBlockValueKeys} {
+		if keyValue := metadataValue(metadata, keys); keyValue != nil {
+			value.Add(value, keyValue)
+		}
+	}
+	score, _ := new(big.Float).SetInt(value).Float64()
+
+	return score
+}
+
+// metadataValue obtains the first decimal value present in the metadata under any of the keys.
+func metadataValue(metadata map[string]any, keys []string) *big.Int {
+	for _, key := range keys {
+		tmp, exists := metadata[key]
+		if !exists {
+			continue
+		}
+		var str string
+		switch v := tmp.(type) {
+		case string:
+			str = v
+		case fmt.Stringer:
+			str = v.String()
+		default:
+			str = fmt.Sprintf("%v", v)
+		}
+		value, success := new(big.Int).SetString(strings.TrimSpace(str), 10)
+		if success {
+			return value
+		}
+	}
+
+	return nil
+}
+
+// AttestationCountProposalScorer scores a proposal by the number of attestations it includes,
+// counting each attesting validator.
+func AttestationCountProposalScorer(_ context.Context, proposal ScorableProposal, _ map[string]any) float64 {
+	attestations, err := proposal.Attestations()
+	if err != nil {
+		return 0
+	}
+	count := uint64(0)
+	for _, attestation := range attestations {
+		count += attestation.AggregationBits.Count()
+	}
+
+	return float64(count)
+}
+
+// doBestProposal requests a proposal from all active clients in parallel, returning the
+// highest scoring proposal received before the best proposal timeout.
+func doBestProposal[T ScorableProposal](ctx context.Context,
+	s *Service,
+	name string,
+	call func(ctx context.Context, client consensusclient.Service) (*api.Response[T], error),
+) (
+	*api.Response[T],
+	error,
+) {
+	return doBest(ctx, s, name, s.bestProposalTimeout, call,
+		func(ctx context.Context, _ consensusclient.Service, response *api.Response[T]) (float64, error) {
+			return s.proposalScorer(ctx, response.Data, response.Metadata), nil
+		},
+	)
+}
+
+// bestProposalEnabled returns true if proposals should be selected from all active clients.
+// It is not enabled within a session that is already pinned to a client.
+func (s *Service) bestProposalEnabled(ctx context.Context) bool {
+	return s.bestProposalTimeout > time.Duration(0) && !sessionPinned(ctx)
+}
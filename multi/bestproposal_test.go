@@ -0,0 +1,198 @@
+// Copyright © 2023 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package multi_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	consensusclient "github.com/attestantio/go-eth2-client"
+	"github.com/attestantio/go-eth2-client/api"
+	"github.com/attestantio/go-eth2-client/mock"
+	"github.com/attestantio/go-eth2-client/multi"
+	"github.com/attestantio/go-eth2-client/spec"
+	"github.com/attestantio/go-eth2-client/spec/capella"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/prysmaticlabs/go-bitfield"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+// valuedProposalClient is a client that returns proposals with the given values.
+type valuedProposalClient struct {
+	*mock.Service
+	metadata map[string]any
+	delay    time.Duration
+}
+
+func (c *valuedProposalClient) Proposal(ctx context.Context, opts *api.ProposalOpts) (*api.Response[*api.VersionedProposal], error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-time.After(c.delay):
+	}
+
+	response, err := c.Service.Proposal(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	response.Metadata = c.metadata
+
+	return response, nil
+}
+
+func newValuedProposalClient(ctx context.Context, t *testing.T, name string, metadata map[string]any, delay time.Duration) consensusclient.Service {
+	t.Helper()
+
+	client, err := mock.New(ctx, mock.WithName(name))
+	require.NoError(t, err)
+
+	return &valuedProposalClient{
+		Service:  client,
+		metadata: metadata,
+		delay:    delay,
+	}
+}
+
+func TestBestProposal(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		clients []consensusclient.Service
+		scorer  multi.ProposalScorer
+		address string
+	}{
+		{
+			name: "HighestValue",
+			clients: []consensusclient.Service{
+				newValuedProposalClient(ctx, t, "a", map[string]any{
+					"Eth-Execution-Payload-Value": "1000000000",
+					"Eth-Consensus-Block-Value":   "1000",
+				}, 0),
+				newValuedProposalClient(ctx, t, "b", map[string]any{
+					"Eth-Execution-Payload-Value": "2000000000",
+					"Eth-Consensus-Block-Value":   "1000",
+				}, 0),
+				newValuedProposalClient(ctx, t, "c", map[string]any{
+					"execution_payload_value": "1500000000",
+				}, 0),
+			},
+			address: "b",
+		},
+		{
+			name: "ConsensusValue",
+			clients: []consensusclient.Service{
+				newValuedProposalClient(ctx, t, "a", map[string]any{
+					"Eth-Execution-Payload-Value": "1000",
+					"Eth-Consensus-Block-Value":   "1000",
+				}, 0),
+				newValuedProposalClient(ctx, t, "b", map[string]any{
+					"Eth-Execution-Payload-Value": "1000",
+					"Eth-Consensus-Block-Value":   "2000",
+				}, 0),
+			},
+			address: "b",
+		},
+		{
+			name: "Tie",
+			clients: []consensusclient.Service{
+				newValuedProposalClient(ctx, t, "a", map[string]any{}, 0),
+				newValuedProposalClient(ctx, t, "b", map[string]any{}, 0),
+			},
+			address: "a",
+		},
+		{
+			name: "SlowClientIgnored",
+			clients: []consensusclient.Service{
+				newValuedProposalClient(ctx, t, "a", map[string]any{
+					"Eth-Execution-Payload-Value": "1000",
+				}, 0),
+				newValuedProposalClient(ctx, t, "b", map[string]any{
+					"Eth-Execution-Payload-Value": "2000",
+				}, 5*time.Second),
+			},
+			address: "a",
+		},
+		{
+			name: "CustomScorer",
+			clients: []consensusclient.Service{
+				newValuedProposalClient(ctx, t, "a", map[string]any{
+					"Eth-Execution-Payload-Value": "2000",
+				}, 0),
+				newValuedProposalClient(ctx, t, "b", map[string]any{
+					"Eth-Execution-Payload-Value": "1000",
+					"preferred":                   true,
+				}, 0),
+			},
+			scorer: func(_ context.Context, _ multi.ScorableProposal, metadata map[string]any) float64 {
+				if _, exists := metadata["preferred"]; exists {
+					return 1
+				}
+
+				return 0
+			},
+			address: "b",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			params := []multi.Parameter{
+				multi.WithLogLevel(zerolog.Disabled),
+				multi.WithClients(test.clients),
+				multi.WithBestProposal(200 * time.Millisecond),
+			}
+			if test.scorer != nil {
+				params = append(params, multi.WithProposalScorer(test.scorer))
+			}
+			multiClient, err := multi.New(ctx, params...)
+			require.NoError(t, err)
+
+			response, err := multiClient.(consensusclient.ProposalProvider).Proposal(ctx, &api.ProposalOpts{Slot: 1})
+			require.NoError(t, err)
+			// The metadata identifies the client that provided the proposal.
+			for _, client := range test.clients {
+				if client.Address() == test.address {
+					require.Equal(t, client.(*valuedProposalClient).metadata, response.Metadata)
+				}
+			}
+		})
+	}
+}
+
+func TestAttestationCountProposalScorer(t *testing.T) {
+	ctx := context.Background()
+
+	aggregationBits := bitfield.NewBitlist(128)
+	aggregationBits.SetBitAt(1, true)
+	aggregationBits.SetBitAt(5, true)
+	singleBits := bitfield.NewBitlist(128)
+	singleBits.SetBitAt(2, true)
+	proposal := &api.VersionedProposal{
+		Version: spec.DataVersionCapella,
+		Capella: &capella.BeaconBlock{
+			Body: &capella.BeaconBlockBody{
+				Attestations: []*phase0.Attestation{
+					{AggregationBits: aggregationBits},
+					{AggregationBits: singleBits},
+				},
+			},
+		},
+	}
+
+	require.Equal(t, float64(3), multi.AttestationCountProposalScorer(ctx, proposal, nil))
+	require.Equal(t, float64(0), multi.AttestationCountProposalScorer(ctx, &api.VersionedProposal{}, nil))
+}
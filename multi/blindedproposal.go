@@ -27,7 +27,13 @@ func (s *Service) BlindedProposal(ctx context.Context,
 	*api.Response[*api.VersionedBlindedProposal],
 	error,
 ) {
-	res, err := s.doCall(ctx, func(ctx context.Context, client consensusclient.Service) (interface{}, error) {
+	if s.bestProposalEnabled(ctx) {
+		return doBestProposal(ctx, s, "BlindedProposal", func(ctx context.Context, client consensusclient.Service) (*api.Response[*api.VersionedBlindedProposal], error) {
+			return client.(consensusclient.BlindedProposalProvider).BlindedProposal(ctx, opts)
+		})
+	}
+
+	res, err := s.doCall(ctx, "BlindedProposal", func(ctx context.Context, client consensusclient.Service) (interface{}, error) {
 		block, err := client.(consensusclient.BlindedProposalProvider).BlindedProposal(ctx, opts)
 		if err != nil {
 			return nil, err
@@ -23,7 +23,7 @@ import (
 
 // BlobSidecars fetches the blob sidecars given options.
 func (s *Service) BlobSidecars(ctx context.Context, opts *api.BlobSidecarsOpts) ([]*deneb.BlobSidecar, error) {
-	res, err := s.doCall(ctx, func(ctx context.Context, client consensusclient.Service) (interface{}, error) {
+	res, err := s.doBulkCall(ctx, "BlobSidecars", func(ctx context.Context, client consensusclient.Service) (interface{}, error) {
 		blobSidecars, err := client.(consensusclient.BlobSidecarsProvider).BlobSidecars(ctx, opts)
 		if err != nil {
 			return nil, err
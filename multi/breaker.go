@@ -0,0 +1,383 @@
+// Copyright © 2023 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package multi
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	consensusclient "github.com/attestantio/go-eth2-client"
+	"github.com/attestantio/go-eth2-client/api"
+	"github.com/rs/zerolog"
+)
+
+// BreakerState is the state of a circuit breaker.
+type BreakerState int
+
+const (
+	// BreakerClosed is a breaker that allows calls to its client.
+	BreakerClosed BreakerState = iota
+	// BreakerHalfOpen is a breaker whose client has passed its probe calls, and is on
+	// trial; the breaker closes after enough successful calls, and opens on any failure.
+	BreakerHalfOpen
+	// BreakerOpen is a breaker that stops calls to its client.
+	BreakerOpen
+)
+
+// String returns a string representation of the state.
+func (s BreakerState) String() string {
+	switch s {
+	case BreakerClosed:
+		return "closed"
+	case BreakerHalfOpen:
+		return "half-open"
+	case BreakerOpen:
+		return "open"
+	default:
+		return "unknown"
+	}
+}
+
+// CircuitBreaker configures the per-client circuit breakers.  Zero values take defaults.
+type CircuitBreaker struct {
+	// Window is the number of most recent calls over which the failure ratio is calculated.
+	// Defaults to 20.
+	Window int
+	// MinCalls is the number of calls required in the window before the breaker can open.
+	// Defaults to 5.
+	MinCalls int
+	// FailureRatio is the ratio of failed calls in the window, between 0 and 1, at or
+	// above which the breaker opens.  Defaults to 0.5.
+	FailureRatio float64
+	// OpenDuration is the time for which the breaker stays open before its client is
+	// probed.  Defaults to 30 seconds.
+	OpenDuration time.Duration
+	// HalfOpenCalls is the number of successful calls required to close a half-open breaker.
+	// Defaults to 3.
+	HalfOpenCalls int
+}
+
+// withDefaults returns the configuration with defaults applied.
+func (c CircuitBreaker) withDefaults() CircuitBreaker {
+	if c.Window == 0 {
+		c.Window = 20
+	}
+	if c.MinCalls == 0 {
+		c.MinCalls = minHealthSamples
+	}
+	if c.MinCalls > c.Window {
+		c.MinCalls = c.Window
+	}
+	if c.FailureRatio == 0 {
+		c.FailureRatio = 0.5
+	}
+	if c.OpenDuration == 0 {
+		c.OpenDuration = 30 * time.Second
+	}
+	if c.HalfOpenCalls == 0 {
+		c.HalfOpenCalls = 3
+	}
+
+	return c
+}
+
+// BreakerInfo is information about the circuit breaker of a client.
+type BreakerInfo struct {
+	// Address is the address of the client.
+	Address string
+	// State is the state of the breaker.
+	State BreakerState
+	// Calls is the number of calls in the window.
+	Calls int
+	// FailureRatio is the ratio of failed calls in the window.
+	FailureRatio float64
+	// OpenedAt is the time at which the breaker last opened, or zero if it has not.
+	OpenedAt time.Time
+}
+
+// breaker is the circuit breaker for a single client.
+type breaker struct {
+	mu        sync.Mutex
+	config    CircuitBreaker
+	state     BreakerState
+	outcomes  []bool
+	next      int
+	calls     int
+	failures  int
+	successes int
+	openedAt  time.Time
+}
+
+func newBreaker(config CircuitBreaker) *breaker {
+	return &breaker{
+		config:   config,
+		outcomes: make([]bool, config.Window),
+	}
+}
+
+// record records the outcome of a call, returning the new state if it changed.
+func (b *breaker) record(failed bool) (BreakerState, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case BreakerOpen:
+		// Calls already in flight when the breaker opened are ignored.
+		return b.state, false
+	case BreakerHalfOpen:
+		if failed {
+			b.open()
+
+			return b.state, true
+		}
+		b.successes++
+		if b.successes >= b.config.HalfOpenCalls {
+			b.close()
+
+			return b.state, true
+		}
+
+		return b.state, false
+	default:
+		if b.calls == len(b.outcomes) {
+			if b.outcomes[b.next] {
+				b.failures--
+			}
+		} else {
+			b.calls++
+		}
+		b.outcomes[b.next] = failed
+		if failed {
+			b.failures++
+		}
+		b.next = (b.next + 1) % len(b.outcomes)
+		if b.calls >= b.config.MinCalls && b.ratio() >= b.config.FailureRatio {
+			b.open()
+
+			return b.state, true
+		}
+
+		return b.state, false
+	}
+}
+
+// probeDue returns true if the breaker has been open for long enough to probe its client.
+func (b *breaker) probeDue(now time.Time) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.state == BreakerOpen && now.Sub(b.openedAt) >= b.config.OpenDuration
+}
+
+// probed records the result of probing the client of an open breaker.
+func (b *breaker) probed(passed bool) BreakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if passed {
+		b.state = BreakerHalfOpen
+		b.successes = 0
+	} else {
+		b.open()
+	}
+
+	return b.state
+}
+
+func (b *breaker) open() {
+	b.state = BreakerOpen
+	b.openedAt = time.Now()
+}
+
+func (b *breaker) close() {
+	b.state = BreakerClosed
+	b.calls = 0
+	b.failures = 0
+	b.next = 0
+}
+
+// ratio returns the failure ratio of the window; the lock must be held.
+func (b *breaker) ratio() float64 {
+	if b.calls == 0 {
+		return 0
+	}
+
+	return float64(b.failures) / float64(b.calls)
+}
+
+func (b *breaker) info(address string) *BreakerInfo {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return &BreakerInfo{
+		Address:      address,
+		State:        b.state,
+		Calls:        b.calls,
+		FailureRatio: b.ratio(),
+		OpenedAt:     b.openedAt,
+	}
+}
+
+// clientBreaker provides the circuit breaker for a client.
+// It returns nil if circuit breakers are not enabled.
+func (s *Service) clientBreaker(client consensusclient.Service) *breaker {
+	if s.circuitBreaker == nil {
+		return nil
+	}
+
+	s.breakersMu.Lock()
+	defer s.breakersMu.Unlock()
+	b, exists := s.breakers[client]
+	if !exists {
+		// Client has been removed; provide a breaker that is not retained.
+		return newBreaker(*s.circuitBreaker)
+	}
+
+	return b
+}
+
+// breakerState returns the state of the circuit breaker for a client.
+func (s *Service) breakerState(client consensusclient.Service) BreakerState {
+	b := s.clientBreaker(client)
+	if b == nil {
+		return BreakerClosed
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.state
+}
+
+// CircuitBreakers provides information about the circuit breakers of the clients, ordered
+// by address.  It returns nil if circuit breakers are not enabled.
+func (s *Service) CircuitBreakers() []*BreakerInfo {
+	if s.circuitBreaker == nil {
+		return nil
+	}
+
+	s.clientsMu.RLock()
+	clients := make([]consensusclient.Service, 0, len(s.activeClients)+len(s.inactiveClients))
+	clients = append(clients, s.activeClients...)
+	clients = append(clients, s.inactiveClients...)
+	s.clientsMu.RUnlock()
+
+	infos := make([]*BreakerInfo, 0, len(clients))
+	for _, client := range clients {
+		infos = append(infos, s.clientBreaker(client).info(client.Address()))
+	}
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].Address < infos[j].Address
+	})
+
+	return infos
+}
+
+// callSucceeded records a successful call to a client with its circuit breaker.
+func (s *Service) callSucceeded(ctx context.Context, client consensusclient.Service) {
+	b := s.clientBreaker(client)
+	if b == nil {
+		return
+	}
+	if state, changed := b.record(false); changed {
+		s.breakerChanged(ctx, client, state)
+	}
+}
+
+// callFailed handles a call to a client that failed such that the call moved on to the
+// next client.  Without circuit breakers the client is deactivated; with circuit breakers
+// the failure is recorded, and the client is deactivated only if its breaker opens,
+// otherwise being moved behind the other active clients.
+func (s *Service) callFailed(ctx context.Context, client consensusclient.Service) {
+	b := s.clientBreaker(client)
+	if b == nil {
+		s.deactivateClient(ctx, client)
+
+		return
+	}
+	state, changed := b.record(true)
+	if changed {
+		s.breakerChanged(ctx, client, state)
+	}
+	if state != BreakerOpen {
+		s.demoteClient(ctx, client)
+	}
+}
+
+// breakerChanged acts on the change of state of a client's circuit breaker.
+func (s *Service) breakerChanged(ctx context.Context, client consensusclient.Service, state BreakerState) {
+	zerolog.Ctx(ctx).Debug().Str("address", client.Address()).Stringer("state", state).Msg("Circuit breaker changed state")
+	setBreakerMetric(client.Address(), state)
+	if state == BreakerOpen {
+		s.deactivateClient(ctx, client)
+	}
+}
+
+// probeBreakers probes the clients whose circuit breakers have been open for long enough,
+// moving their breakers to half-open if the probes pass.  Half-open clients are then
+// reactivated by the health check.
+func (s *Service) probeBreakers(ctx context.Context) {
+	if s.circuitBreaker == nil {
+		return
+	}
+
+	s.clientsMu.RLock()
+	inactiveClients := s.inactiveClients
+	s.clientsMu.RUnlock()
+
+	now := time.Now()
+	for _, client := range inactiveClients {
+		b := s.clientBreaker(client)
+		if !b.probeDue(now) {
+			continue
+		}
+		err := s.probe(ctx, client)
+		if err != nil {
+			zerolog.Ctx(ctx).Debug().Str("address", client.Address()).Err(err).Msg("Circuit breaker probe failed")
+		}
+		s.breakerChanged(ctx, client, b.probed(err == nil))
+	}
+}
+
+// probe makes calls to real endpoints of a client, returning an error if any fail.
+// Only calls that are not cached by clients are used, so that each probe reaches the node.
+func (s *Service) probe(ctx context.Context, client consensusclient.Service) error {
+	if provider, isProvider := client.(consensusclient.NodeSyncingProvider); isProvider {
+		started := time.Now()
+		_, err := provider.NodeSyncing(ctx)
+		s.recordHealth(ctx, client, "NodeSyncing", started, err)
+		if err != nil {
+			return err
+		}
+	}
+	if provider, isProvider := client.(consensusclient.FinalityProvider); isProvider {
+		started := time.Now()
+		_, err := provider.Finality(ctx, &api.FinalityOpts{State: "head"})
+		s.recordHealth(ctx, client, "Finality", started, err)
+		if err != nil {
+			return err
+		}
+	}
+	if provider, isProvider := client.(consensusclient.BeaconBlockHeadersProvider); isProvider {
+		started := time.Now()
+		_, err := provider.BeaconBlockHeader(ctx, &api.BeaconBlockHeaderOpts{Block: "head"})
+		s.recordHealth(ctx, client, "BeaconBlockHeader", started, err)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,201 @@
+// Copyright © 2023 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package multi
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	consensusclient "github.com/attestantio/go-eth2-client"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBreaker(t *testing.T) {
+	b := newBreaker(CircuitBreaker{
+		Window:        4,
+		MinCalls:      2,
+		FailureRatio:  0.5,
+		HalfOpenCalls: 2,
+		OpenDuration:  time.Hour,
+	}.withDefaults())
+
+	// Not enough calls to open.
+	_, changed := b.record(true)
+	require.False(t, changed)
+	b.close()
+
+	// Failures leave the window as it slides.
+	for i := 0; i < 3; i++ {
+		_, changed = b.record(false)
+		require.False(t, changed)
+	}
+	_, changed = b.record(true)
+	require.False(t, changed)
+	for i := 0; i < 4; i++ {
+		state, changed := b.record(false)
+		require.False(t, changed)
+		require.Equal(t, BreakerClosed, state)
+	}
+	require.Equal(t, 0.0, b.info("").FailureRatio)
+
+	_, changed = b.record(true)
+	require.False(t, changed)
+	state, changed := b.record(true)
+	require.True(t, changed)
+	require.Equal(t, BreakerOpen, state)
+
+	// Calls in flight whilst open are ignored.
+	_, changed = b.record(false)
+	require.False(t, changed)
+
+	require.False(t, b.probeDue(time.Now()))
+	require.True(t, b.probeDue(time.Now().Add(time.Hour)))
+
+	// A failed probe reopens the breaker.
+	require.Equal(t, BreakerOpen, b.probed(false))
+
+	// A failure whilst half-open reopens the breaker.
+	require.Equal(t, BreakerHalfOpen, b.probed(true))
+	state, changed = b.record(true)
+	require.True(t, changed)
+	require.Equal(t, BreakerOpen, state)
+
+	// Enough successes whilst half-open close the breaker, with a clean window.
+	require.Equal(t, BreakerHalfOpen, b.probed(true))
+	state, changed = b.record(false)
+	require.False(t, changed)
+	require.Equal(t, BreakerHalfOpen, state)
+	state, changed = b.record(false)
+	require.True(t, changed)
+	require.Equal(t, BreakerClosed, state)
+	require.Equal(t, 0, b.info("").Calls)
+}
+
+func TestBreakerParameters(t *testing.T) {
+	ctx := context.Background()
+
+	_, err := New(ctx,
+		WithClients(newOrderingService(ctx, t).activeClients),
+		WithCircuitBreaker(CircuitBreaker{FailureRatio: 1.5}),
+	)
+	require.EqualError(t, err, "problem with parameters: circuit breaker failure ratio must be between 0 and 1")
+
+	_, err = New(ctx,
+		WithClients(newOrderingService(ctx, t).activeClients),
+		WithCircuitBreaker(CircuitBreaker{Window: -1}),
+	)
+	require.EqualError(t, err, "problem with parameters: circuit breaker counts cannot be negative")
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	ctx := context.Background()
+
+	openDuration := time.Hour
+	s := newOrderingService(ctx, t,
+		WithOrdering(OrderingPriority),
+		WithPriorities(map[string]int{"a": 1, "b": 2, "c": 3}),
+		WithRecheckInterval(10*time.Millisecond),
+		WithCircuitBreaker(CircuitBreaker{
+			Window:        4,
+			MinCalls:      2,
+			HalfOpenCalls: 2,
+			OpenDuration:  openDuration,
+		}),
+	)
+	breakerState := func(address string) BreakerState {
+		for _, info := range s.CircuitBreakers() {
+			if info.Address == address {
+				return info.State
+			}
+		}
+		require.Fail(t, "no breaker for client")
+
+		return BreakerOpen
+	}
+
+	failA := true
+	call := func(_ context.Context, client consensusclient.Service) (interface{}, error) {
+		if failA && client.Address() == "a" {
+			return nil, errors.New("failed")
+		}
+
+		return client.Address(), nil
+	}
+
+	// A single failure fails over, but leaves the client active.
+	res, err := s.doCall(ctx, "Test", call, nil)
+	require.NoError(t, err)
+	require.Equal(t, "b", res)
+	require.Equal(t, BreakerClosed, breakerState("a"))
+	require.Equal(t, "a", firstAddress(ctx, t, s, false))
+
+	// Enough failures open the breaker and deactivate the client.
+	_, err = s.doCall(ctx, "Test", call, nil)
+	require.NoError(t, err)
+	require.Equal(t, BreakerOpen, breakerState("a"))
+	require.Equal(t, "b", firstAddress(ctx, t, s, false))
+
+	// Successful health checks do not reactivate a client with an open breaker.
+	time.Sleep(50 * time.Millisecond)
+	require.Equal(t, BreakerOpen, breakerState("a"))
+	require.Equal(t, "b", firstAddress(ctx, t, s, false))
+
+	// Once the breaker is due a probe, the client is probed and reactivated on trial.
+	failA = false
+	s.breakersMu.Lock()
+	for client, b := range s.breakers {
+		if client.Address() == "a" {
+			b.mu.Lock()
+			b.openedAt = b.openedAt.Add(-openDuration)
+			b.mu.Unlock()
+		}
+	}
+	s.breakersMu.Unlock()
+	require.Eventually(t, func() bool {
+		return breakerState("a") == BreakerHalfOpen && firstAddress(ctx, t, s, false) == "a"
+	}, time.Second, 10*time.Millisecond)
+
+	// Successful calls close the breaker.
+	firstAddress(ctx, t, s, false)
+	require.Equal(t, BreakerClosed, breakerState("a"))
+}
+
+func TestCircuitBreakerDemotes(t *testing.T) {
+	ctx := context.Background()
+
+	s := newOrderingService(ctx, t,
+		WithCircuitBreaker(CircuitBreaker{
+			Window:   4,
+			MinCalls: 4,
+		}),
+	)
+
+	call := func(_ context.Context, client consensusclient.Service) (interface{}, error) {
+		if client.Address() == "a" {
+			return nil, errors.New("failed")
+		}
+
+		return client.Address(), nil
+	}
+
+	// A failure with the breaker closed leaves the client active, but behind the others.
+	res, err := s.doCall(ctx, "Test", call, nil)
+	require.NoError(t, err)
+	require.Equal(t, "b", res)
+	require.Equal(t, BreakerClosed, s.breakerState(s.activeClients[2]))
+	require.Equal(t, []string{"b", "c", "a"}, addresses(s.activeClients))
+	require.Equal(t, "b", firstAddress(ctx, t, s, false))
+}
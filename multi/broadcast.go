@@ -0,0 +1,155 @@
+// Copyright © 2023 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package multi
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	consensusclient "github.com/attestantio/go-eth2-client"
+	"github.com/attestantio/go-eth2-client/api"
+	"github.com/pkg/errors"
+)
+
+// BroadcastResult is the result of a broadcast submission to a single client.
+type BroadcastResult struct {
+	// Name is the name of the client.
+	Name string
+	// Address is the address of the client.
+	Address string
+	// Err is the error returned by the client, or nil if the submission succeeded.
+	Err error
+	// Accepted is true if the client returned an error that is classified as ErrorActionReturn,
+	// for example because it already had the message.  Accepted submissions count as successes.
+	Accepted bool
+	// Failover is true if the error resulted in the client being deactivated.
+	Failover bool
+}
+
+// BroadcastSummary summarises the results of a broadcast submission.
+type BroadcastSummary struct {
+	// Submitter is the submitter that was called, for example "SubmitAttestations".
+	Submitter string
+	// Threshold is the number of successful submissions that was required.
+	Threshold int
+	// Successes is the number of successful submissions, including those accepted.
+	Successes int
+	// Accepted is the number of submissions that returned an error classified as
+	// ErrorActionReturn, and so counted as successes.
+	Accepted int
+	// Results are the results of the submission to each client.
+	Results []*BroadcastResult
+}
+
+// BroadcastError is returned when a broadcast submission does not succeed on enough clients.
+type BroadcastError struct {
+	*BroadcastSummary
+}
+
+// Error implements error.
+func (e *BroadcastError) Error() string {
+	return fmt.Sprintf("%s succeeded on %d of %d clients, %d required",
+		e.Submitter, e.Successes, len(e.Results), e.Threshold)
+}
+
+// BroadcastHandlerFunc is called with the summary of each broadcast submission.
+type BroadcastHandlerFunc func(ctx context.Context, summary *BroadcastSummary)
+
+// doSubmission carries out a submission.  If broadcast is enabled the submission is sent
+// to all active clients in parallel, otherwise it is sent to the active clients in turn
+// until one succeeds.
+func (s *Service) doSubmission(ctx context.Context, submitter string, call callFunc, errHandler errHandlerFunc) error {
+	if s.broadcastThreshold == 0 {
+		_, err := s.doCall(ctx, submitter, call, errHandler)
+
+		return err
+	}
+
+	return s.doBroadcast(ctx, submitter, call, errHandler)
+}
+
+// doBroadcast carries out a submission on all active clients in parallel, succeeding
+// if the submission succeeds on at least the broadcast threshold of clients.
+func (s *Service) doBroadcast(ctx context.Context, submitter string, call callFunc, errHandler errHandlerFunc) error {
+	log := s.log.With().Str("submitter", submitter).Logger()
+	ctx = log.WithContext(ctx)
+
+	activeClients := s.currentActiveClients(ctx)
+	if len(activeClients) == 0 {
+		return errors.New("no active clients to which to make call")
+	}
+
+	results := make([]*BroadcastResult, len(activeClients))
+	var wg sync.WaitGroup
+	for i, client := range activeClients {
+		wg.Add(1)
+		go func(i int, client consensusclient.Service) {
+			defer wg.Done()
+			result := &BroadcastResult{
+				Name:    client.Name(),
+				Address: client.Address(),
+			}
+			results[i] = result
+
+			_, action, err := s.callWithRetries(ctx, client, submitter, false, call)
+			if err == nil {
+				return
+			}
+			// Errors such as duplicate messages show that the client has the submission.
+			result.Accepted = action == ErrorActionReturn
+			if errors.Is(err, api.ErrNotSupported) {
+				// The client does not support this call, but that does not make it unhealthy.
+				result.Err = err
+
+				return
+			}
+			action, err = s.handleError(ctx, client, action, err, errHandler)
+			result.Err = err
+			if action != ErrorActionReturn {
+				log.Debug().Str("client", client.Name()).Str("address", client.Address()).Err(err).Msg("Call failed on client")
+				result.Failover = true
+				s.callFailed(ctx, client)
+			}
+		}(i, client)
+	}
+	wg.Wait()
+
+	summary := &BroadcastSummary{
+		Submitter: submitter,
+		Threshold: s.broadcastThreshold,
+		Results:   results,
+	}
+	for _, result := range results {
+		if result.Err == nil || result.Accepted {
+			summary.Successes++
+		}
+		if result.Accepted {
+			summary.Accepted++
+		}
+	}
+	log.Trace().Int("successes", summary.Successes).Int("clients", len(results)).Msg("Broadcast complete")
+
+	if s.broadcastHandler != nil {
+		s.broadcastHandler(ctx, summary)
+	}
+
+	if summary.Successes < summary.Threshold {
+		return &BroadcastError{
+			BroadcastSummary: summary,
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,231 @@
+// Copyright © 2023 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package multi_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+
+	consensusclient "github.com/attestantio/go-eth2-client"
+	"github.com/attestantio/go-eth2-client/mock"
+	"github.com/attestantio/go-eth2-client/multi"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+// submissionClient is a client that records attestation submissions, optionally failing them.
+type submissionClient struct {
+	*mock.Service
+	fail        bool
+	submissions atomic.Int32
+}
+
+func (c *submissionClient) SubmitAttestations(_ context.Context, _ []*phase0.Attestation) error {
+	c.submissions.Add(1)
+	if c.fail {
+		return errors.New("submission failed")
+	}
+
+	return nil
+}
+
+func newSubmissionClient(ctx context.Context, t *testing.T, name string, fail bool) *submissionClient {
+	t.Helper()
+
+	client, err := mock.New(ctx, mock.WithName(name))
+	require.NoError(t, err)
+
+	return &submissionClient{
+		Service: client,
+		fail:    fail,
+	}
+}
+
+func TestBroadcastParameters(t *testing.T) {
+	ctx := context.Background()
+
+	clients := []consensusclient.Service{
+		newSubmissionClient(ctx, t, "a", false),
+		newSubmissionClient(ctx, t, "b", false),
+	}
+
+	tests := []struct {
+		name      string
+		threshold int
+		err       string
+	}{
+		{
+			name:      "Negative",
+			threshold: -1,
+			err:       "problem with parameters: broadcast threshold cannot be negative",
+		},
+		{
+			name:      "TooHigh",
+			threshold: 3,
+			err:       "problem with parameters: broadcast threshold exceeds number of clients",
+		},
+		{
+			name:      "Good",
+			threshold: 2,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := multi.New(ctx,
+				multi.WithLogLevel(zerolog.Disabled),
+				multi.WithClients(clients),
+				multi.WithBroadcast(test.threshold),
+			)
+			if test.err != "" {
+				require.EqualError(t, err, test.err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestBroadcast(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name      string
+		fails     []bool
+		threshold int
+		err       string
+		successes int
+	}{
+		{
+			name:      "AllSucceed",
+			fails:     []bool{false, false, false},
+			threshold: 3,
+			successes: 3,
+		},
+		{
+			name:      "ThresholdMet",
+			fails:     []bool{true, false, false},
+			threshold: 2,
+			successes: 2,
+		},
+		{
+			name:      "ThresholdNotMet",
+			fails:     []bool{true, true, false},
+			threshold: 2,
+			successes: 1,
+			err:       "SubmitAttestations succeeded on 1 of 3 clients, 2 required",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			submissionClients := make([]*submissionClient, 0, len(test.fails))
+			clients := make([]consensusclient.Service, 0, len(test.fails))
+			for i, fail := range test.fails {
+				client := newSubmissionClient(ctx, t, string(rune('a'+i)), fail)
+				submissionClients = append(submissionClients, client)
+				clients = append(clients, client)
+			}
+
+			var summary *multi.BroadcastSummary
+			multiClient, err := multi.New(ctx,
+				multi.WithLogLevel(zerolog.Disabled),
+				multi.WithClients(clients),
+				multi.WithBroadcast(test.threshold),
+				multi.WithBroadcastHandler(func(_ context.Context, s *multi.BroadcastSummary) {
+					summary = s
+				}),
+			)
+			require.NoError(t, err)
+
+			err = multiClient.(consensusclient.AttestationsSubmitter).SubmitAttestations(ctx, []*phase0.Attestation{})
+			if test.err != "" {
+				require.EqualError(t, err, test.err)
+				var broadcastErr *multi.BroadcastError
+				require.True(t, errors.As(err, &broadcastErr))
+				require.Equal(t, test.successes, broadcastErr.Successes)
+			} else {
+				require.NoError(t, err)
+			}
+
+			// Every client should have received the submission.
+			for _, client := range submissionClients {
+				require.Equal(t, int32(1), client.submissions.Load())
+			}
+
+			require.NotNil(t, summary)
+			require.Equal(t, "SubmitAttestations", summary.Submitter)
+			require.Equal(t, test.successes, summary.Successes)
+			require.Len(t, summary.Results, len(test.fails))
+			for i, result := range summary.Results {
+				require.Equal(t, string(rune('a'+i)), result.Address)
+				require.Equal(t, test.fails[i], result.Err != nil)
+				// Failed clients are deactivated through the usual error handling.
+				require.Equal(t, test.fails[i], result.Failover)
+			}
+		})
+	}
+}
+
+func TestBroadcastAccepted(t *testing.T) {
+	ctx := context.Background()
+
+	clients := make([]consensusclient.Service, 0, 3)
+	for _, name := range []string{"a", "b", "c"} {
+		clients = append(clients, newSubmissionClient(ctx, t, name, name != "a"))
+	}
+
+	var summary *multi.BroadcastSummary
+	multiClient, err := multi.New(ctx,
+		multi.WithLogLevel(zerolog.Disabled),
+		multi.WithClients(clients),
+		multi.WithBroadcast(3),
+		// Treat the failure as a client that already has the message.
+		multi.WithErrorClassifiers(multi.ErrorRules{{Message: "submission failed", Action: multi.ErrorActionReturn}}),
+		multi.WithBroadcastHandler(func(_ context.Context, s *multi.BroadcastSummary) {
+			summary = s
+		}),
+	)
+	require.NoError(t, err)
+
+	// Submissions rejected with errors classified as return count towards the threshold.
+	require.NoError(t, multiClient.(consensusclient.AttestationsSubmitter).SubmitAttestations(ctx, []*phase0.Attestation{}))
+	require.NotNil(t, summary)
+	require.Equal(t, 3, summary.Successes)
+	require.Equal(t, 2, summary.Accepted)
+	for _, result := range summary.Results {
+		require.Equal(t, result.Address != "a", result.Accepted)
+		require.Equal(t, result.Address != "a", result.Err != nil)
+		require.False(t, result.Failover)
+	}
+}
+
+func TestBroadcastDisabled(t *testing.T) {
+	ctx := context.Background()
+
+	client1 := newSubmissionClient(ctx, t, "a", false)
+	client2 := newSubmissionClient(ctx, t, "b", false)
+	multiClient, err := multi.New(ctx,
+		multi.WithLogLevel(zerolog.Disabled),
+		multi.WithClients([]consensusclient.Service{client1, client2}),
+	)
+	require.NoError(t, err)
+
+	require.NoError(t, multiClient.(consensusclient.AttestationsSubmitter).SubmitAttestations(ctx, []*phase0.Attestation{}))
+	require.Equal(t, int32(1), client1.submissions.Load())
+	require.Equal(t, int32(0), client2.submissions.Load())
+}
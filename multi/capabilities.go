@@ -0,0 +1,93 @@
+// Copyright © 2023 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package multi
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	consensusclient "github.com/attestantio/go-eth2-client"
+	"github.com/attestantio/go-eth2-client/api"
+	"github.com/rs/zerolog"
+)
+
+// Capabilities provides the capabilities of the node.
+func (s *Service) Capabilities(ctx context.Context) (*api.Response[*api.Capabilities], error) {
+	res, err := s.doCall(ctx, "Capabilities", func(ctx context.Context, client consensusclient.Service) (interface{}, error) {
+		provider, isProvider := client.(consensusclient.CapabilitiesProvider)
+		if !isProvider {
+			return nil, fmt.Errorf("%s@%s does not support this call", client.Name(), client.Address())
+		}
+		capabilities, err := provider.Capabilities(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		return capabilities, nil
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return res.(*api.Response[*api.Capabilities]), nil
+}
+
+// refreshCapabilities obtains the capabilities of the clients, and caches them for use when
+// selecting the clients to which to send calls.  Obtaining capabilities can require probing
+// a node, so this is carried out when clients are added and by the monitor rather than
+// when making calls.
+func (s *Service) refreshCapabilities(ctx context.Context, clients []consensusclient.Service) {
+	var wg sync.WaitGroup
+	for _, client := range clients {
+		provider, isProvider := client.(consensusclient.CapabilitiesProvider)
+		if !isProvider {
+			continue
+		}
+		wg.Add(1)
+		go func(client consensusclient.Service, provider consensusclient.CapabilitiesProvider) {
+			defer wg.Done()
+			response, err := provider.Capabilities(ctx)
+			if err != nil || response.Data == nil {
+				// Keep any capabilities already obtained.
+				zerolog.Ctx(ctx).Trace().Str("provider", client.Address()).Err(err).Msg("Failed to obtain capabilities")
+
+				return
+			}
+			s.setCapabilities(client, response.Data)
+		}(client, provider)
+	}
+	wg.Wait()
+}
+
+// setCapabilities records the capabilities of a client.
+func (s *Service) setCapabilities(client consensusclient.Service, capabilities *api.Capabilities) {
+	s.healthMu.Lock()
+	defer s.healthMu.Unlock()
+
+	if _, exists := s.health[client]; !exists {
+		// Client has been removed.
+		return
+	}
+	s.capabilities[client] = capabilities
+}
+
+// clientCapabilities returns the capabilities of a client when last obtained, or nil if
+// they are not known.
+func (s *Service) clientCapabilities(client consensusclient.Service) *api.Capabilities {
+	s.healthMu.RLock()
+	defer s.healthMu.RUnlock()
+
+	return s.capabilities[client]
+}
@@ -0,0 +1,179 @@
+// Copyright © 2023 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package multi_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+
+	consensusclient "github.com/attestantio/go-eth2-client"
+	"github.com/attestantio/go-eth2-client/api"
+	"github.com/attestantio/go-eth2-client/mock"
+	"github.com/attestantio/go-eth2-client/multi"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+// unsupporting is a client that does not support fetching its node version.
+type unsupporting struct {
+	*mock.Service
+}
+
+func (*unsupporting) NodeVersion(_ context.Context) (*api.Response[string], error) {
+	return nil, errors.Wrap(api.ErrNotSupported, "NodeVersion")
+}
+
+// incapable is a client that reports that it does not support fetching its node version.
+type incapable struct {
+	*mock.Service
+	calls atomic.Int32
+}
+
+func (*incapable) Capabilities(_ context.Context) (*api.Response[*api.Capabilities], error) {
+	return &api.Response[*api.Capabilities]{
+		Data: &api.Capabilities{
+			Client: "mock",
+			Endpoints: map[string]api.EndpointCapabilities{
+				"NodeVersion": {Supported: false},
+			},
+		},
+		Metadata: make(map[string]any),
+	}, nil
+}
+
+func (c *incapable) NodeVersion(ctx context.Context) (*api.Response[string], error) {
+	c.calls.Add(1)
+
+	return c.Service.NodeVersion(ctx)
+}
+
+func TestCapabilities(t *testing.T) {
+	ctx := context.Background()
+
+	client1, err := mock.New(ctx, mock.WithName("mock 1"))
+	require.NoError(t, err)
+
+	multiClient, err := multi.New(ctx,
+		multi.WithLogLevel(zerolog.Disabled),
+		multi.WithClients([]consensusclient.Service{
+			client1,
+		}),
+	)
+	require.NoError(t, err)
+
+	res, err := multiClient.(consensusclient.CapabilitiesProvider).Capabilities(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "mock", res.Data.Client)
+}
+
+func TestUnsupportedCall(t *testing.T) {
+	ctx := context.Background()
+
+	client1, err := mock.New(ctx, mock.WithName("mock 1"))
+	require.NoError(t, err)
+	client2, err := mock.New(ctx, mock.WithName("mock 2"))
+	require.NoError(t, err)
+
+	multiClient, err := multi.New(ctx,
+		multi.WithLogLevel(zerolog.Disabled),
+		multi.WithClients([]consensusclient.Service{
+			&unsupporting{Service: client1},
+			client2,
+		}),
+	)
+	require.NoError(t, err)
+
+	res, err := multiClient.(consensusclient.NodeVersionProvider).NodeVersion(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "mock", res.Data)
+	// The client that does not support the call should still be active.
+	require.Equal(t, "mock 1", multiClient.Address())
+}
+
+func TestIncapableClientSkipped(t *testing.T) {
+	ctx := context.Background()
+
+	client1, err := mock.New(ctx, mock.WithName("mock 1"))
+	require.NoError(t, err)
+	client2, err := mock.New(ctx, mock.WithName("mock 2"))
+	require.NoError(t, err)
+	incapable1 := &incapable{Service: client1}
+
+	multiClient, err := multi.New(ctx,
+		multi.WithLogLevel(zerolog.Disabled),
+		multi.WithClients([]consensusclient.Service{
+			incapable1,
+			client2,
+		}),
+	)
+	require.NoError(t, err)
+
+	res, err := multiClient.(consensusclient.NodeVersionProvider).NodeVersion(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "mock", res.Data)
+	// The call should not have been sent to the client that does not support it.
+	require.Equal(t, int32(0), incapable1.calls.Load())
+	require.Equal(t, "mock 1", multiClient.Address())
+
+	// If no client supports the call it fails without being sent.
+	onlyIncapable, err := multi.New(ctx,
+		multi.WithLogLevel(zerolog.Disabled),
+		multi.WithClients([]consensusclient.Service{
+			incapable1,
+		}),
+	)
+	require.NoError(t, err)
+	_, err = onlyIncapable.(consensusclient.NodeVersionProvider).NodeVersion(ctx)
+	require.ErrorIs(t, err, api.ErrNotSupported)
+	require.Equal(t, int32(0), incapable1.calls.Load())
+}
+
+// unprobeable is a client that fails to provide its capabilities, as a node that is down would.
+type unprobeable struct {
+	*mock.Service
+	calls atomic.Int32
+}
+
+func (c *unprobeable) Capabilities(_ context.Context) (*api.Response[*api.Capabilities], error) {
+	c.calls.Add(1)
+
+	return nil, errors.New("timeout")
+}
+
+func TestCapabilitiesNotProbedOnCall(t *testing.T) {
+	ctx := context.Background()
+
+	client1, err := mock.New(ctx, mock.WithName("mock 1"))
+	require.NoError(t, err)
+	unprobeable1 := &unprobeable{Service: client1}
+
+	multiClient, err := multi.New(ctx,
+		multi.WithLogLevel(zerolog.Disabled),
+		multi.WithClients([]consensusclient.Service{
+			unprobeable1,
+		}),
+	)
+	require.NoError(t, err)
+	probes := unprobeable1.calls.Load()
+
+	// Calls are sent to clients whose capabilities are not known, without probing them.
+	for i := 0; i < 5; i++ {
+		res, err := multiClient.(consensusclient.NodeVersionProvider).NodeVersion(ctx)
+		require.NoError(t, err)
+		require.Equal(t, "mock", res.Data)
+	}
+	require.Equal(t, probes, unprobeable1.calls.Load())
+}
@@ -19,6 +19,7 @@ import (
 	"time"
 
 	consensusclient "github.com/attestantio/go-eth2-client"
+	"github.com/attestantio/go-eth2-client/api"
 	"github.com/pkg/errors"
 	"github.com/rs/zerolog"
 )
@@ -36,29 +37,30 @@ func (s *Service) monitor(ctx context.Context) {
 			log.Trace().Msg("Context done; monitor stopping")
 
 			return
-		case <-time.After(30 * time.Second):
+		case <-time.After(s.recheckInterval):
+			s.retryPendingAddresses(ctx)
+			s.probeBreakers(ctx)
 			s.recheck(ctx)
+			s.refreshCapabilities(ctx, s.allClients())
 		}
 	}
 }
 
 // recheck checks clients to update their state.
 func (s *Service) recheck(ctx context.Context) {
-	// Fetch all clients.
-	clients := make([]consensusclient.Service, 0, len(s.activeClients)+len(s.inactiveClients))
+	s.checkHealth(ctx, s.allClients())
+}
+
+// allClients provides a local copy of the active and inactive clients.
+func (s *Service) allClients() []consensusclient.Service {
 	s.clientsMu.RLock()
+	defer s.clientsMu.RUnlock()
+
+	clients := make([]consensusclient.Service, 0, len(s.activeClients)+len(s.inactiveClients))
 	clients = append(clients, s.activeClients...)
 	clients = append(clients, s.inactiveClients...)
-	s.clientsMu.RUnlock()
 
-	// Ping each client to update its state.
-	for _, client := range clients {
-		if ping(ctx, client) {
-			s.activateClient(ctx, client)
-		} else {
-			s.deactivateClient(ctx, client)
-		}
-	}
+	return clients
 }
 
 // deactivateClient deactivates a client, moving it to the inactive list if not currently on it.
@@ -115,26 +117,43 @@ func (s *Service) activateClient(ctx context.Context, client consensusclient.Ser
 	setProvidersMetric(ctx, "inactive", len(s.inactiveClients))
 }
 
-// ping pings a client, returning true if it is ready to serve requests and
-// false otherwise.
-func ping(ctx context.Context, client consensusclient.Service) bool {
+// demoteClient moves an active client behind the other active clients.
+func (s *Service) demoteClient(ctx context.Context, client consensusclient.Service) {
 	log := zerolog.Ctx(ctx)
 
-	provider, isProvider := client.(consensusclient.NodeSyncingProvider)
-	if !isProvider {
-		log.Debug().Str("provider", client.Address()).Msg("Client does not provide sync state")
+	s.clientsMu.Lock()
+	defer s.clientsMu.Unlock()
 
-		return false
+	found := false
+	activeClients := make([]consensusclient.Service, 0, len(s.activeClients))
+	for _, activeClient := range s.activeClients {
+		if activeClient == client {
+			found = true
+		} else {
+			activeClients = append(activeClients, activeClient)
+		}
+	}
+	if !found {
+		return
 	}
+	log.Trace().Str("client", client.Address()).Msg("Client demoted")
+
+	s.activeClients = append(activeClients, client)
+}
 
-	response, err := provider.NodeSyncing(ctx)
+// ping pings a client, returning true if it is ready to serve requests and
+// false otherwise, along with whether it is optimistic.
+func ping(ctx context.Context, client consensusclient.Service) (bool, bool) {
+	log := zerolog.Ctx(ctx)
+
+	state, err := syncState(ctx, client)
 	if err != nil {
-		log.Warn().Err(err).Msg("Failed to obtain sync state from node")
+		log.Warn().Str("provider", client.Address()).Err(err).Msg("Failed to obtain sync state from node")
 
-		return false
+		return false, false
 	}
 
-	return (!response.Data.IsSyncing) || (response.Data.HeadSlot == 0 && response.Data.SyncDistance == 0)
+	return ready(state), state.IsOptimistic
 }
 
 // callFunc is the definition for a call function.  It provides a generic return interface
@@ -146,12 +165,10 @@ type callFunc func(ctx context.Context, client consensusclient.Service) (interfa
 // result in a provider failover.
 type errHandlerFunc func(ctx context.Context, client consensusclient.Service, err error) (bool, error)
 
-// doCall carries out a call on the active clients in turn until one succeeds.
-func (s *Service) doCall(ctx context.Context, call callFunc, errHandler errHandlerFunc) (interface{}, error) {
-	log := s.log.With().Logger()
-	ctx = log.WithContext(ctx)
-
-	// Grab local copy of active clients in case it is updated whilst we are using it.
+// currentActiveClients provides a local copy of the active clients, so that it can be used
+// whilst the list is updated.  If there are no active clients it attempts to re-enable the
+// inactive clients first.
+func (s *Service) currentActiveClients(ctx context.Context) []consensusclient.Service {
 	s.clientsMu.RLock()
 	activeClients := s.activeClients
 	s.clientsMu.RUnlock()
@@ -164,47 +181,154 @@ func (s *Service) doCall(ctx context.Context, call callFunc, errHandler errHandl
 		s.clientsMu.RUnlock()
 	}
 
+	return activeClients
+}
+
+// doCall carries out a call on the active clients in turn until one succeeds.
+func (s *Service) doCall(ctx context.Context, name string, call callFunc, errHandler errHandlerFunc) (interface{}, error) {
+	return s.doOrderedCall(ctx, name, false, call, errHandler)
+}
+
+// doBulkCall carries out a call that returns large amounts of data on the active clients
+// in turn until one succeeds, using the ordering for bulk calls.
+func (s *Service) doBulkCall(ctx context.Context, name string, call callFunc, errHandler errHandlerFunc) (interface{}, error) {
+	return s.doOrderedCall(ctx, name, true, call, errHandler)
+}
+
+// doOrderedCall carries out a call on the active clients in order until one succeeds, using
+// the ordering for bulk calls if bulk is true.
+// The name of the call is the name of the provider function, for example "AttestationData".
+func (s *Service) doOrderedCall(ctx context.Context, name string, bulk bool, call callFunc, errHandler errHandlerFunc) (interface{}, error) {
+	log := s.log.With().Logger()
+	ctx = log.WithContext(ctx)
+
+	ordering := s.ordering
+	if bulk {
+		ordering = s.bulkOrdering
+	}
+	activeClients := s.orderClients(s.dutyClients(name, s.currentActiveClients(ctx)), ordering)
+	session := sessionFromContext(ctx)
+	if session != nil {
+		activeClients = s.sessionClients(session, activeClients)
+	}
 	if len(activeClients) == 0 {
+		monitorCallExhausted(name)
+
 		return nil, errors.New("no active clients to which to make call")
 	}
+	activeClients = s.supportingClients(name, activeClients)
+	if len(activeClients) == 0 {
+		monitorCallExhausted(name)
+
+		return nil, errors.Wrap(api.ErrNotSupported, name)
+	}
 
 	var err error
 	var res interface{}
 	for _, client := range activeClients {
-		res, err = call(ctx, client)
+		// Keep hold of the error from the previous client, in case the session switches.
+		previousErr := err
+		var action ErrorAction
+		res, action, err = s.callWithRetries(ctx, client, name, bulk, call)
 		if err != nil {
-			failover := true
-			if errHandler != nil {
-				failover, err = errHandler(ctx, client, err)
-			}
+			if errors.Is(err, api.ErrNotSupported) {
+				// The client does not support this call, but that does not make it unhealthy.
+				log.Trace().Str("client", client.Name()).Str("address", client.Address()).Err(err).Msg("Client does not support call; trying next")
+				monitorCallFailover(client.Address(), name)
 
-			if failover {
-				log.Debug().Str("client", client.Name()).Str("address", client.Address()).Err(err).Msg("Deactivating client on error")
+				continue
+			}
+			action, err = s.handleError(ctx, client, action, err, errHandler)
+			if action != ErrorActionReturn {
+				log.Debug().Str("client", client.Name()).Str("address", client.Address()).Err(err).Msg("Call failed on client")
 				// Failed with this client; try the next.
-				s.deactivateClient(ctx, client)
+				s.callFailed(ctx, client)
+				monitorCallFailover(client.Address(), name)
 
 				continue
 			}
 
 			// No failover required, return.
+			if session != nil {
+				s.useSessionClient(ctx, session, client, name, previousErr)
+			}
+
 			return res, err
 		}
 		if res == nil {
 			// No response from this client; try the next.
 			err = errors.New("empty response")
+			monitorCallEmptyResponse(client.Address(), name)
+			monitorCallFailover(client.Address(), name)
 
 			continue
 		}
+		if session != nil {
+			s.useSessionClient(ctx, session, client, name, previousErr)
+		}
 
 		return res, nil
 	}
+	monitorCallExhausted(name)
 
 	return nil, err
 }
 
+// supportingClients returns the clients that are not known to lack support for the call,
+// so that calls are not sent to clients that will reject them.  Only the capabilities
+// already obtained are used, so that calls do not wait for nodes to be probed; clients
+// whose capabilities are not known are assumed to support the call.
+func (s *Service) supportingClients(name string, clients []consensusclient.Service) []consensusclient.Service {
+	res := make([]consensusclient.Service, 0, len(clients))
+	for _, client := range clients {
+		if capabilities := s.clientCapabilities(client); capabilities != nil && !capabilities.Supports(name) {
+			continue
+		}
+		res = append(res, client)
+	}
+
+	return res
+}
+
+// callWithRetries carries out a call on a client, retrying errors classified as
+// ErrorActionRetry up to maxErrorRetries times.  It returns the classification of the
+// final error, if any.
+func (s *Service) callWithRetries(ctx context.Context,
+	client consensusclient.Service,
+	name string,
+	bulk bool,
+	call callFunc,
+) (
+	interface{},
+	ErrorAction,
+	error,
+) {
+	for retries := 0; ; retries++ {
+		started := time.Now()
+		res, err := call(ctx, client)
+		if bulk {
+			s.recordBulkCall(ctx, client, name, started, err)
+		} else {
+			s.recordCall(ctx, client, name, started, err)
+		}
+		if err == nil || errors.Is(err, api.ErrNotSupported) {
+			return res, ErrorActionFailover, err
+		}
+		action := s.classifyError(ctx, client, name, err)
+		if action != ErrorActionRetry || retries == maxErrorRetries {
+			return res, action, err
+		}
+		s.log.Trace().Str("client", client.Name()).Str("address", client.Address()).Err(err).Msg("Retrying call on same client")
+	}
+}
+
 // providerInfo returns information on the provider.
 // Currently this just returns the name of the service (lighthouse/teku/etc.).
 func (s *Service) providerInfo(ctx context.Context, provider consensusclient.Service) string {
+	if capabilities := s.clientCapabilities(provider); capabilities != nil && capabilities.Client != "" {
+		return capabilities.Client
+	}
+
 	providerName := "<unknown>"
 	nodeVersionProvider, isNodeVersionProvider := provider.(consensusclient.NodeVersionProvider)
 	if isNodeVersionProvider {
@@ -219,6 +343,8 @@ func (s *Service) providerInfo(ctx context.Context, provider consensusclient.Ser
 				providerName = "teku"
 			case strings.Contains(strings.ToLower(response.Data), "nimbus"):
 				providerName = "nimbus"
+			case strings.Contains(strings.ToLower(response.Data), "lodestar"):
+				providerName = "lodestar"
 			}
 		}
 	}
@@ -0,0 +1,365 @@
+// Copyright © 2023 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package multi
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	consensusclient "github.com/attestantio/go-eth2-client"
+	"github.com/attestantio/go-eth2-client/http"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+)
+
+// ClientState is the state of a client in the multi client.
+type ClientState int
+
+const (
+	// ClientStateActive is a client that is in use.
+	ClientStateActive ClientState = iota
+	// ClientStateInactive is a client that is unhealthy, and will be used again once it recovers.
+	ClientStateInactive
+	// ClientStatePending is an address for which a client could not be created, which is retried.
+	ClientStatePending
+)
+
+var clientStateStrings = [...]string{
+	"active",
+	"inactive",
+	"pending",
+}
+
+// String returns a string representation of the state.
+func (s ClientState) String() string {
+	if s < 0 || int(s) >= len(clientStateStrings) {
+		return "unknown"
+	}
+
+	return clientStateStrings[s]
+}
+
+// ClientInfo is a snapshot of the state of a client in the multi client.
+type ClientInfo struct {
+	// Client is the client; this is nil for pending addresses.
+	Client consensusclient.Service
+	// Address is the address of the client.
+	Address string
+	// State is the state of the client.
+	State ClientState
+	// Latency is the rolling average latency of calls to the client.
+	Latency time.Duration
+	// ErrorRate is the rolling average error rate of calls to the client, between 0 and 1.
+	ErrorRate float64
+}
+
+// newHTTPClient creates an HTTP client for the address, along with a function that stops it.
+func newHTTPClient(ctx context.Context, httpParams []http.Parameter, address string) (consensusclient.Service, context.CancelFunc, error) {
+	params := make([]http.Parameter, 0, len(httpParams)+1)
+	params = append(params, httpParams...)
+	params = append(params, http.WithAddress(address))
+
+	ctx, cancel := context.WithCancel(ctx)
+	client, err := http.New(ctx, params...)
+	if err != nil {
+		cancel()
+
+		return nil, nil, err
+	}
+
+	return client, cancel, nil
+}
+
+// Clients provides a snapshot of the clients, active clients first in the order in which
+// they are used, followed by inactive clients and pending addresses.
+func (s *Service) Clients() []*ClientInfo {
+	s.clientsMu.RLock()
+	activeClients := s.activeClients
+	inactiveClients := s.inactiveClients
+	pendingAddresses := s.pendingAddresses
+	s.clientsMu.RUnlock()
+
+	infos := make([]*ClientInfo, 0, len(activeClients)+len(inactiveClients)+len(pendingAddresses))
+	for _, client := range s.orderClients(activeClients, s.ordering) {
+		infos = append(infos, s.clientInfo(client, ClientStateActive))
+	}
+	for _, client := range inactiveClients {
+		infos = append(infos, s.clientInfo(client, ClientStateInactive))
+	}
+	for _, address := range pendingAddresses {
+		infos = append(infos, &ClientInfo{
+			Address: address,
+			State:   ClientStatePending,
+		})
+	}
+
+	return infos
+}
+
+func (s *Service) clientInfo(client consensusclient.Service, state ClientState) *ClientInfo {
+	latency, errorRate, _ := s.clientHealth(client).values()
+
+	return &ClientInfo{
+		Client:    client,
+		Address:   client.Address(),
+		State:     state,
+		Latency:   latency,
+		ErrorRate: errorRate,
+	}
+}
+
+// AddClient adds a client, which becomes active immediately if it is ready to serve requests.
+func (s *Service) AddClient(ctx context.Context, client consensusclient.Service) error {
+	if client == nil {
+		return errors.New("no client supplied")
+	}
+	if s.hasAddress(client.Address()) {
+		return fmt.Errorf("client %s already present", client.Address())
+	}
+
+	return s.addClient(ctx, client, nil)
+}
+
+// AddAddress adds a client for the address, which becomes active immediately if it is ready
+// to serve requests.  If the client cannot be created it is retried in the background.
+// The context governs the lifetime of the client, which is also stopped if it is removed.
+func (s *Service) AddAddress(ctx context.Context, address string) error {
+	if s.hasAddress(address) {
+		return fmt.Errorf("client %s already present", address)
+	}
+
+	client, stop, err := newHTTPClient(ctx, s.httpParams, address)
+	if err != nil {
+		s.log.Warn().Str("provider", address).Err(err).Msg("Provider not present; will retry in background")
+		s.clientsMu.Lock()
+		defer s.clientsMu.Unlock()
+		// Check again, as the address could have been added whilst we were creating the client.
+		if s.hasAddressLocked(address) {
+			return fmt.Errorf("client %s already present", address)
+		}
+		s.pendingAddresses = append(s.pendingAddresses, address)
+		setProvidersMetric(ctx, "pending", len(s.pendingAddresses))
+
+		return nil
+	}
+
+	return s.addClient(ctx, client, stop)
+}
+
+// RemoveClient removes the client, or pending address, with the given address.
+// Clients created by the multi client from an address are stopped.
+func (s *Service) RemoveClient(ctx context.Context, address string) error {
+	s.clientsMu.Lock()
+	defer s.clientsMu.Unlock()
+
+	var removed consensusclient.Service
+	activeClients := make([]consensusclient.Service, 0, len(s.activeClients))
+	for _, client := range s.activeClients {
+		if client.Address() == address {
+			removed = client
+		} else {
+			activeClients = append(activeClients, client)
+		}
+	}
+	inactiveClients := make([]consensusclient.Service, 0, len(s.inactiveClients))
+	for _, client := range s.inactiveClients {
+		if client.Address() == address {
+			removed = client
+		} else {
+			inactiveClients = append(inactiveClients, client)
+		}
+	}
+	pendingAddresses := make([]string, 0, len(s.pendingAddresses))
+	for _, pendingAddress := range s.pendingAddresses {
+		if pendingAddress != address {
+			pendingAddresses = append(pendingAddresses, pendingAddress)
+		}
+	}
+	if removed == nil && len(pendingAddresses) == len(s.pendingAddresses) {
+		return fmt.Errorf("client %s not present", address)
+	}
+
+	s.activeClients = activeClients
+	s.inactiveClients = inactiveClients
+	s.pendingAddresses = pendingAddresses
+	if removed != nil {
+		if stop, exists := s.stops[removed]; exists {
+			stop()
+			delete(s.stops, removed)
+		}
+		s.healthMu.Lock()
+		delete(s.health, removed)
+		delete(s.optimistic, removed)
+		delete(s.capabilities, removed)
+		s.healthMu.Unlock()
+		s.breakersMu.Lock()
+		delete(s.breakers, removed)
+		s.breakersMu.Unlock()
+	}
+	s.providerNamesMu.Lock()
+	delete(s.providerNames, address)
+	s.providerNamesMu.Unlock()
+	s.quarantineMu.Lock()
+	delete(s.quarantined, address)
+	for _, dissents := range s.dissents {
+		delete(dissents, address)
+	}
+	s.quarantineMu.Unlock()
+	removeProviderMetrics(ctx, address)
+	setProvidersMetric(ctx, "active", len(s.activeClients))
+	setProvidersMetric(ctx, "inactive", len(s.inactiveClients))
+	setProvidersMetric(ctx, "pending", len(s.pendingAddresses))
+	s.log.Trace().Str("provider", address).Msg("Client removed")
+
+	return nil
+}
+
+// hasAddress returns true if there is a client or pending address with the given address.
+func (s *Service) hasAddress(address string) bool {
+	s.clientsMu.RLock()
+	defer s.clientsMu.RUnlock()
+
+	return s.hasAddressLocked(address)
+}
+
+// hasAddressLocked returns true if there is a client or pending address with the given address.
+// It must be called with the clients lock held.
+func (s *Service) hasAddressLocked(address string) bool {
+	for _, client := range s.activeClients {
+		if client.Address() == address {
+			return true
+		}
+	}
+	for _, client := range s.inactiveClients {
+		if client.Address() == address {
+			return true
+		}
+	}
+	for _, pendingAddress := range s.pendingAddresses {
+		if pendingAddress == address {
+			return true
+		}
+	}
+
+	return false
+}
+
+// addClient adds a client to the active or inactive list, depending on its readiness.
+// It returns an error if a client with the same address was added whilst the client was
+// being checked.
+// If the client was created by the multi client, stop is the function that stops it; this
+// is called when the client is removed, or immediately if it cannot be added.
+func (s *Service) addClient(ctx context.Context, client consensusclient.Service, stop context.CancelFunc) error {
+	ctx = s.log.WithContext(ctx)
+	active, optimistic := ping(ctx, client)
+
+	s.clientsMu.Lock()
+	if s.hasAddressLocked(client.Address()) {
+		s.clientsMu.Unlock()
+		if stop != nil {
+			stop()
+		}
+
+		return fmt.Errorf("client %s already present", client.Address())
+	}
+
+	if stop != nil {
+		s.stops[client] = stop
+	}
+	s.trackClient(client)
+	s.setOptimistic(client, optimistic)
+	// Copy rather than append, as callers may hold the current lists.
+	if active {
+		activeClients := make([]consensusclient.Service, 0, len(s.activeClients)+1)
+		activeClients = append(activeClients, s.activeClients...)
+		s.activeClients = append(activeClients, client)
+		setProviderActiveMetric(ctx, client.Address(), "active")
+	} else {
+		inactiveClients := make([]consensusclient.Service, 0, len(s.inactiveClients)+1)
+		inactiveClients = append(inactiveClients, s.inactiveClients...)
+		s.inactiveClients = append(inactiveClients, client)
+		setProviderActiveMetric(ctx, client.Address(), "inactive")
+	}
+	setProvidersMetric(ctx, "active", len(s.activeClients))
+	setProvidersMetric(ctx, "inactive", len(s.inactiveClients))
+	s.log.Trace().Str("provider", client.Address()).Bool("active", active).Msg("Client added")
+	s.clientsMu.Unlock()
+
+	s.refreshCapabilities(ctx, []consensusclient.Service{client})
+
+	return nil
+}
+
+// trackClient creates the health tracker and circuit breaker for a client.  These are
+// only created here, so that calls in flight to a client when it is removed do not
+// recreate them.
+func (s *Service) trackClient(client consensusclient.Service) {
+	s.healthMu.Lock()
+	if _, exists := s.health[client]; !exists {
+		s.health[client] = &clientHealth{}
+	}
+	s.healthMu.Unlock()
+
+	if s.circuitBreaker != nil {
+		s.breakersMu.Lock()
+		if _, exists := s.breakers[client]; !exists {
+			s.breakers[client] = newBreaker(*s.circuitBreaker)
+		}
+		s.breakersMu.Unlock()
+	}
+}
+
+// retryPendingAddresses attempts to create clients for pending addresses.
+func (s *Service) retryPendingAddresses(ctx context.Context) {
+	log := zerolog.Ctx(ctx)
+
+	s.clientsMu.RLock()
+	pendingAddresses := s.pendingAddresses
+	s.clientsMu.RUnlock()
+
+	for _, address := range pendingAddresses {
+		client, stop, err := newHTTPClient(ctx, s.httpParams, address)
+		if err != nil {
+			log.Debug().Str("provider", address).Err(err).Msg("Provider still not present")
+
+			continue
+		}
+
+		s.clientsMu.Lock()
+		stillPending := false
+		remainingAddresses := make([]string, 0, len(s.pendingAddresses))
+		for _, pendingAddress := range s.pendingAddresses {
+			if pendingAddress == address {
+				stillPending = true
+			} else {
+				remainingAddresses = append(remainingAddresses, pendingAddress)
+			}
+		}
+		s.pendingAddresses = remainingAddresses
+		setProvidersMetric(ctx, "pending", len(s.pendingAddresses))
+		s.clientsMu.Unlock()
+
+		if !stillPending {
+			// Removed whilst we were creating the client.
+			stop()
+
+			continue
+		}
+		log.Info().Str("provider", address).Msg("Provider now present")
+		if err := s.addClient(ctx, client, stop); err != nil {
+			log.Warn().Str("provider", address).Err(err).Msg("Failed to add provider")
+		}
+	}
+}
@@ -0,0 +1,76 @@
+// Copyright © 2023 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package multi
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	consensusclient "github.com/attestantio/go-eth2-client"
+	"github.com/attestantio/go-eth2-client/mock"
+	"github.com/attestantio/go-eth2-client/server"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRemoveClientStops(t *testing.T) {
+	ctx := context.Background()
+
+	mockClient, err := mock.New(ctx)
+	require.NoError(t, err)
+	srv, err := server.New(ctx,
+		server.WithLogLevel(zerolog.Disabled),
+		server.WithService(mockClient),
+	)
+	require.NoError(t, err)
+	httpServer := httptest.NewServer(srv)
+	defer httpServer.Close()
+
+	client, err := mock.New(ctx, mock.WithName("a"))
+	require.NoError(t, err)
+	s, err := New(ctx,
+		WithLogLevel(zerolog.Disabled),
+		WithClients([]consensusclient.Service{client}),
+	)
+	require.NoError(t, err)
+	multi := s.(*Service)
+
+	// Clients created from addresses are stopped when they are removed.
+	require.NoError(t, multi.AddAddress(ctx, httpServer.URL))
+	require.Len(t, multi.stops, 1)
+	stopped := 0
+	for created, stop := range multi.stops {
+		multi.stops[created] = func(stop context.CancelFunc) context.CancelFunc {
+			return func() {
+				stopped++
+				stop()
+			}
+		}(stop)
+	}
+	require.NoError(t, multi.RemoveClient(ctx, httpServer.URL))
+	require.Equal(t, 1, stopped)
+	require.Empty(t, multi.stops)
+
+	// Clients created for an address that is already present are stopped immediately.
+	require.NoError(t, multi.AddAddress(ctx, httpServer.URL))
+	duplicate, stop, err := newHTTPClient(ctx, multi.httpParams, httpServer.URL)
+	require.NoError(t, err)
+	require.Error(t, multi.addClient(ctx, duplicate, func() {
+		stopped++
+		stop()
+	}))
+	require.Equal(t, 2, stopped)
+	require.Len(t, multi.stops, 1)
+}
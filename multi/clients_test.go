@@ -0,0 +1,142 @@
+// Copyright © 2023 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package multi_test
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	consensusclient "github.com/attestantio/go-eth2-client"
+	"github.com/attestantio/go-eth2-client/mock"
+	"github.com/attestantio/go-eth2-client/multi"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+func clientStates(infos []*multi.ClientInfo) map[string]multi.ClientState {
+	states := make(map[string]multi.ClientState, len(infos))
+	for _, info := range infos {
+		states[info.Address] = info.State
+	}
+
+	return states
+}
+
+func TestAddRemoveClient(t *testing.T) {
+	ctx := context.Background()
+
+	client1, err := mock.New(ctx, mock.WithName("mock 1"))
+	require.NoError(t, err)
+	client2, err := mock.New(ctx, mock.WithName("mock 2"))
+	require.NoError(t, err)
+
+	s, err := multi.New(ctx,
+		multi.WithLogLevel(zerolog.Disabled),
+		multi.WithClients([]consensusclient.Service{client1}),
+	)
+	require.NoError(t, err)
+	multiClient := s.(*multi.Service)
+
+	require.Equal(t, map[string]multi.ClientState{
+		"mock 1": multi.ClientStateActive,
+	}, clientStates(multiClient.Clients()))
+
+	require.NoError(t, multiClient.AddClient(ctx, client2))
+	require.EqualError(t, multiClient.AddClient(ctx, client2), "client mock 2 already present")
+	require.EqualError(t, multiClient.AddClient(ctx, nil), "no client supplied")
+	require.Equal(t, map[string]multi.ClientState{
+		"mock 1": multi.ClientStateActive,
+		"mock 2": multi.ClientStateActive,
+	}, clientStates(multiClient.Clients()))
+
+	// Remove the first client; calls go to the second.
+	require.NoError(t, multiClient.RemoveClient(ctx, "mock 1"))
+	require.EqualError(t, multiClient.RemoveClient(ctx, "mock 1"), "client mock 1 not present")
+	require.Equal(t, "mock 2", multiClient.Address())
+	_, err = multiClient.Genesis(ctx)
+	require.NoError(t, err)
+
+	infos := multiClient.Clients()
+	require.Len(t, infos, 1)
+	require.Equal(t, client2, infos[0].Client)
+	require.Equal(t, "active", infos[0].State.String())
+}
+
+func TestAddClientConcurrent(t *testing.T) {
+	ctx := context.Background()
+
+	client1, err := mock.New(ctx, mock.WithName("mock 1"))
+	require.NoError(t, err)
+	s, err := multi.New(ctx,
+		multi.WithLogLevel(zerolog.Disabled),
+		multi.WithClients([]consensusclient.Service{client1}),
+	)
+	require.NoError(t, err)
+	multiClient := s.(*multi.Service)
+
+	// Add clients with the same address concurrently; only one should be added.
+	var added atomic.Int32
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		client, err := mock.New(ctx, mock.WithName("mock 2"))
+		require.NoError(t, err)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if multiClient.AddClient(ctx, client) == nil {
+				added.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	require.Equal(t, int32(1), added.Load())
+	require.Len(t, multiClient.Clients(), 2)
+}
+
+func TestPendingAddress(t *testing.T) {
+	ctx := context.Background()
+
+	client, err := mock.New(ctx, mock.WithName("mock 1"))
+	require.NoError(t, err)
+
+	// The address cannot be reached, so is held as pending rather than discarded.
+	s, err := multi.New(ctx,
+		multi.WithLogLevel(zerolog.Disabled),
+		multi.WithClients([]consensusclient.Service{client}),
+		multi.WithAddresses([]string{"http://localhost:1"}),
+	)
+	require.NoError(t, err)
+	multiClient := s.(*multi.Service)
+
+	require.Equal(t, map[string]multi.ClientState{
+		"mock 1":             multi.ClientStateActive,
+		"http://localhost:1": multi.ClientStatePending,
+	}, clientStates(multiClient.Clients()))
+	require.EqualError(t, multiClient.AddAddress(ctx, "http://localhost:1"), "client http://localhost:1 already present")
+
+	require.NoError(t, multiClient.RemoveClient(ctx, "http://localhost:1"))
+	require.Equal(t, map[string]multi.ClientState{
+		"mock 1": multi.ClientStateActive,
+	}, clientStates(multiClient.Clients()))
+
+	// Adding an unreachable address leaves it pending.
+	require.NoError(t, multiClient.AddAddress(ctx, "http://localhost:2"))
+	require.Equal(t, map[string]multi.ClientState{
+		"mock 1":             multi.ClientStateActive,
+		"http://localhost:2": multi.ClientStatePending,
+	}, clientStates(multiClient.Clients()))
+}
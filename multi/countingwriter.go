@@ -0,0 +1,45 @@
+// Copyright © 2023 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package multi
+
+import (
+	"context"
+	"io"
+	"sync/atomic"
+
+	consensusclient "github.com/attestantio/go-eth2-client"
+)
+
+// countingWriter wraps a writer and keeps track of the number of bytes written.
+type countingWriter struct {
+	w       io.Writer
+	written atomic.Int64
+}
+
+// Write writes to the underlying writer.
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.written.Add(int64(n))
+
+	return n, err
+}
+
+// writeErrHandler returns an error handler for calls that write to a writer.
+// Once data has been written it cannot be recalled, so failover is only
+// possible if the failed client has not written anything.
+func writeErrHandler(writer *countingWriter) errHandlerFunc {
+	return func(_ context.Context, _ consensusclient.Service, err error) (bool, error) {
+		return writer.written.Load() == 0, err
+	}
+}
@@ -23,7 +23,7 @@ import (
 
 // DepositContract provides details of the Ethereum 1 deposit contract for the chain.
 func (s *Service) DepositContract(ctx context.Context) (*api.Response[*apiv1.DepositContract], error) {
-	res, err := s.doCall(ctx, func(ctx context.Context, client consensusclient.Service) (interface{}, error) {
+	res, err := s.doCall(ctx, "DepositContract", func(ctx context.Context, client consensusclient.Service) (interface{}, error) {
 		aggregate, err := client.(consensusclient.DepositContractProvider).DepositContract(ctx)
 		if err != nil {
 			return nil, err
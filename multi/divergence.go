@@ -0,0 +1,399 @@
+// Copyright © 2023 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package multi
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	consensusclient "github.com/attestantio/go-eth2-client"
+	"github.com/attestantio/go-eth2-client/api"
+	apiv1 "github.com/attestantio/go-eth2-client/api/v1"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/rs/zerolog"
+)
+
+// divergenceBlockRootChecks is the number of consecutive checks for which a client must
+// disagree about block roots before the divergence is reported.
+const divergenceBlockRootChecks = 3
+
+// Divergence is a disagreement between clients about the chain.
+type Divergence struct {
+	// Check is the check that found the divergence: "Finality", "Genesis", "ForkSchedule"
+	// or "BeaconBlockRoot".
+	Check string
+	// Slot is the slot at which block roots were compared, for the "BeaconBlockRoot" check.
+	Slot phase0.Slot
+	// Votes are the addresses of the clients, keyed by the root of their responses.
+	Votes map[phase0.Root][]string
+	// Majority is the root of the responses of a majority of clients, or zero if
+	// there is no majority.
+	Majority phase0.Root
+	// Minority are the addresses of the clients that disagree with the majority.  For block
+	// roots, only clients that have disagreed for several consecutive checks are included.
+	Minority []string
+	// Quarantined are the addresses of the clients quarantined as a result of the divergence.
+	Quarantined []string
+}
+
+// DivergenceHandlerFunc is called with each divergence found.
+type DivergenceHandlerFunc func(ctx context.Context, divergence *Divergence)
+
+// monitorDivergence periodically compares the chain as seen by each client.
+func (s *Service) monitorDivergence(ctx context.Context) {
+	log := s.log.With().Str("monitor", "divergence").Logger()
+	ctx = log.WithContext(ctx)
+
+	log.Trace().Msg("Divergence monitor starting")
+	for {
+		select {
+		case <-ctx.Done():
+			log.Trace().Msg("Context done; divergence monitor stopping")
+
+			return
+		case <-time.After(s.divergenceInterval):
+			s.checkDivergence(ctx)
+		}
+	}
+}
+
+// checkDivergence compares finality, genesis, fork schedule and block roots across the
+// active clients, along with any quarantined clients so that they can be released once
+// they agree again.
+func (s *Service) checkDivergence(ctx context.Context) {
+	// Copy the active clients, as appending to them directly could write to the shared list.
+	activeClients := s.currentActiveClients(ctx)
+	quarantinedClients := s.quarantinedClients()
+	clients := make([]consensusclient.Service, 0, len(activeClients)+len(quarantinedClients))
+	clients = append(clients, activeClients...)
+	clients = append(clients, quarantinedClients...)
+	if len(clients) < 2 {
+		return
+	}
+
+	s.checkFinalityDivergence(ctx, clients)
+	s.checkGenesisDivergence(ctx, clients)
+	s.checkForkScheduleDivergence(ctx, clients)
+	s.checkBeaconBlockRootDivergence(ctx, clients)
+}
+
+// divergenceRoots obtains the root of each client's response to a call.  Clients that
+// fail to respond do not take part in the comparison.
+func divergenceRoots[T any](ctx context.Context,
+	s *Service,
+	check string,
+	clients []consensusclient.Service,
+	call func(ctx context.Context, client consensusclient.Service) (*api.Response[T], error),
+) map[string]phase0.Root {
+	log := zerolog.Ctx(ctx)
+
+	roots := make(map[string]phase0.Root, len(clients))
+	for _, client := range clients {
+		started := time.Now()
+		response, err := call(ctx, client)
+		s.recordCall(ctx, client, check, started, err)
+		if err != nil {
+			log.Debug().Str("address", client.Address()).Str("check", check).Err(err).Msg("Failed to obtain response for divergence check")
+
+			continue
+		}
+		if response == nil {
+			continue
+		}
+		root, err := quorumRoot(response.Data)
+		if err != nil {
+			log.Debug().Str("address", client.Address()).Str("check", check).Err(err).Msg("Failed to obtain root of response")
+
+			continue
+		}
+		roots[client.Address()] = root
+	}
+
+	return roots
+}
+
+func (s *Service) checkFinalityDivergence(ctx context.Context, clients []consensusclient.Service) {
+	// Clients may be at different finalized epochs for a short while, which is lag rather
+	// than divergence, so only clients at the most common finalized epoch are compared.
+	epochs := make(map[string]phase0.Epoch, len(clients))
+	roots := divergenceRoots(ctx, s, "Finality", clients,
+		func(ctx context.Context, client consensusclient.Service) (*api.Response[*phase0.Root], error) {
+			provider, isProvider := client.(consensusclient.FinalityProvider)
+			if !isProvider {
+				return nil, api.ErrNotSupported
+			}
+			response, err := provider.Finality(ctx, &api.FinalityOpts{State: "head"})
+			if err != nil {
+				return nil, err
+			}
+			if response.Data == nil || response.Data.Finalized == nil {
+				return nil, nil
+			}
+			epochs[client.Address()] = response.Data.Finalized.Epoch
+
+			return &api.Response[*phase0.Root]{Data: &response.Data.Finalized.Root}, nil
+		},
+	)
+
+	epochCounts := make(map[phase0.Epoch]int)
+	var epoch phase0.Epoch
+	for _, clientEpoch := range epochs {
+		epochCounts[clientEpoch]++
+		if epochCounts[clientEpoch] > epochCounts[epoch] ||
+			(epochCounts[clientEpoch] == epochCounts[epoch] && clientEpoch > epoch) {
+			epoch = clientEpoch
+		}
+	}
+	for address := range roots {
+		if epochs[address] != epoch {
+			delete(roots, address)
+		}
+	}
+
+	s.compareRoots(ctx, "Finality", 0, roots)
+}
+
+func (s *Service) checkGenesisDivergence(ctx context.Context, clients []consensusclient.Service) {
+	roots := divergenceRoots(ctx, s, "Genesis", clients,
+		func(ctx context.Context, client consensusclient.Service) (*api.Response[*apiv1.Genesis], error) {
+			provider, isProvider := client.(consensusclient.GenesisProvider)
+			if !isProvider {
+				return nil, api.ErrNotSupported
+			}
+
+			return provider.Genesis(ctx)
+		},
+	)
+	s.compareRoots(ctx, "Genesis", 0, roots)
+}
+
+func (s *Service) checkForkScheduleDivergence(ctx context.Context, clients []consensusclient.Service) {
+	roots := divergenceRoots(ctx, s, "ForkSchedule", clients,
+		func(ctx context.Context, client consensusclient.Service) (*api.Response[[]*phase0.Fork], error) {
+			provider, isProvider := client.(consensusclient.ForkScheduleProvider)
+			if !isProvider {
+				return nil, api.ErrNotSupported
+			}
+
+			return provider.ForkSchedule(ctx)
+		},
+	)
+	s.compareRoots(ctx, "ForkSchedule", 0, roots)
+}
+
+func (s *Service) checkBeaconBlockRootDivergence(ctx context.Context, clients []consensusclient.Service) {
+	// Compare the block root at the slot before the lowest head, which all clients should have.
+	var slot phase0.Slot
+	found := false
+	for _, client := range clients {
+		state, err := syncState(ctx, client)
+		if err != nil {
+			continue
+		}
+		if !found || state.HeadSlot < slot {
+			slot = state.HeadSlot
+			found = true
+		}
+	}
+	if !found || slot == 0 {
+		return
+	}
+	slot--
+
+	roots := divergenceRoots(ctx, s, "BeaconBlockRoot", clients,
+		func(ctx context.Context, client consensusclient.Service) (*api.Response[*phase0.Root], error) {
+			provider, isProvider := client.(consensusclient.BeaconBlockRootProvider)
+			if !isProvider {
+				return nil, api.ErrNotSupported
+			}
+
+			return provider.BeaconBlockRoot(ctx, &api.BeaconBlockRootOpts{Block: api.BlockIDFromSlot(slot)})
+		},
+	)
+	s.compareRoots(ctx, "BeaconBlockRoot", slot, roots)
+}
+
+// compareRoots compares the roots of the clients' responses for a check, reporting a
+// divergence if they disagree and quarantining the minority if configured to do so.
+func (s *Service) compareRoots(ctx context.Context, check string, slot phase0.Slot, roots map[string]phase0.Root) {
+	log := zerolog.Ctx(ctx)
+
+	votes := make(map[phase0.Root][]string)
+	for address, root := range roots {
+		votes[root] = append(votes[root], address)
+	}
+	if len(votes) < 2 {
+		// Clients agree; release any quarantined for this check.
+		s.persistentDissenters(check, roots, nil)
+		for address := range roots {
+			s.releaseQuarantine(ctx, address, check)
+		}
+
+		return
+	}
+
+	divergence := &Divergence{
+		Check:    check,
+		Slot:     slot,
+		Votes:    votes,
+		Minority: make([]string, 0),
+	}
+	for root, addresses := range votes {
+		sort.Strings(addresses)
+		if len(addresses)*2 > len(roots) {
+			divergence.Majority = root
+		}
+	}
+	for root, addresses := range votes {
+		if root == divergence.Majority {
+			for _, address := range addresses {
+				s.releaseQuarantine(ctx, address, check)
+			}
+
+			continue
+		}
+		divergence.Minority = append(divergence.Minority, addresses...)
+	}
+	sort.Strings(divergence.Minority)
+	divergence.Minority = s.persistentDissenters(check, roots, divergence.Minority)
+	if len(divergence.Minority) == 0 {
+		return
+	}
+
+	if s.quarantine && divergence.Majority != (phase0.Root{}) {
+		for _, address := range divergence.Minority {
+			s.quarantineClient(ctx, address, check)
+		}
+		divergence.Quarantined = divergence.Minority
+	}
+
+	log.Warn().Str("check", check).Uint64("slot", uint64(slot)).Strs("minority", divergence.Minority).Strs("quarantined", divergence.Quarantined).Msg("Clients diverge")
+	monitorDivergence(check, divergence.Minority)
+	if s.divergenceHandler != nil {
+		s.divergenceHandler(ctx, divergence)
+	}
+}
+
+// persistentDissenters records the clients in the minority for a check, and returns those
+// that have been in the minority for enough consecutive checks to be reported.
+// Clients can briefly disagree about blocks that are not yet finalized, for example during
+// reorganisations or when a block is late, so clients must disagree about block roots for
+// divergenceBlockRootChecks consecutive checks; other checks report disagreement at once.
+func (s *Service) persistentDissenters(check string, roots map[string]phase0.Root, minority []string) []string {
+	required := 1
+	if check == "BeaconBlockRoot" {
+		required = divergenceBlockRootChecks
+	}
+
+	s.quarantineMu.Lock()
+	defer s.quarantineMu.Unlock()
+
+	dissents, exists := s.dissents[check]
+	if !exists {
+		dissents = make(map[string]int)
+		s.dissents[check] = dissents
+	}
+	inMinority := make(map[string]bool, len(minority))
+	for _, address := range minority {
+		inMinority[address] = true
+	}
+	for address := range roots {
+		if !inMinority[address] {
+			delete(dissents, address)
+		}
+	}
+
+	persistent := make([]string, 0, len(minority))
+	for _, address := range minority {
+		dissents[address]++
+		if dissents[address] >= required {
+			persistent = append(persistent, address)
+		}
+	}
+
+	return persistent
+}
+
+// quarantinedClients returns the clients that are quarantined.
+func (s *Service) quarantinedClients() []consensusclient.Service {
+	// Copy the quarantined addresses before obtaining the clients, as the clients lock
+	// must be obtained before the quarantine lock.
+	s.quarantineMu.RLock()
+	quarantined := make(map[string]bool, len(s.quarantined))
+	for address := range s.quarantined {
+		quarantined[address] = true
+	}
+	s.quarantineMu.RUnlock()
+	if len(quarantined) == 0 {
+		return nil
+	}
+
+	s.clientsMu.RLock()
+	defer s.clientsMu.RUnlock()
+	clients := make([]consensusclient.Service, 0, len(quarantined))
+	for _, client := range s.inactiveClients {
+		if quarantined[client.Address()] {
+			clients = append(clients, client)
+		}
+	}
+
+	return clients
+}
+
+// quarantineReason returns the check for which a client is quarantined, or an empty
+// string if it is not quarantined.
+func (s *Service) quarantineReason(address string) string {
+	s.quarantineMu.RLock()
+	defer s.quarantineMu.RUnlock()
+
+	return s.quarantined[address]
+}
+
+// quarantineClient deactivates a client, and stops it from being reactivated until it
+// agrees with the majority for the check.
+func (s *Service) quarantineClient(ctx context.Context, address string, check string) {
+	s.quarantineMu.Lock()
+	if _, exists := s.quarantined[address]; !exists {
+		s.quarantined[address] = check
+	}
+	s.quarantineMu.Unlock()
+
+	s.clientsMu.RLock()
+	var quarantined consensusclient.Service
+	for _, client := range s.activeClients {
+		if client.Address() == address {
+			quarantined = client
+
+			break
+		}
+	}
+	s.clientsMu.RUnlock()
+	if quarantined != nil {
+		zerolog.Ctx(ctx).Warn().Str("address", address).Str("check", check).Msg("Quarantining client")
+		s.deactivateClient(ctx, quarantined)
+	}
+}
+
+// releaseQuarantine releases a client from quarantine if it was quarantined for the check.
+// The client is reactivated by the next health check.
+func (s *Service) releaseQuarantine(ctx context.Context, address string, check string) {
+	s.quarantineMu.Lock()
+	defer s.quarantineMu.Unlock()
+
+	if s.quarantined[address] == check {
+		zerolog.Ctx(ctx).Info().Str("address", address).Str("check", check).Msg("Releasing client from quarantine")
+		delete(s.quarantined, address)
+	}
+}
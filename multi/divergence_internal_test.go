@@ -0,0 +1,181 @@
+// Copyright © 2023 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package multi
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	consensusclient "github.com/attestantio/go-eth2-client"
+	"github.com/attestantio/go-eth2-client/api"
+	"github.com/attestantio/go-eth2-client/mock"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/attestantio/go-eth2-client/testclients"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDivergenceConcurrentActivation(t *testing.T) {
+	ctx := context.Background()
+
+	clients := make([]consensusclient.Service, 0, 4)
+	for _, name := range []string{"a", "b", "c", "d"} {
+		client, err := mock.New(ctx, mock.WithName(name))
+		require.NoError(t, err)
+		clients = append(clients, client)
+	}
+	s, err := New(ctx,
+		WithLogLevel(zerolog.Disabled),
+		WithClients(clients),
+	)
+	require.NoError(t, err)
+	multi := s.(*Service)
+	// Quarantine a client for a check that is never run, so it is always included.
+	multi.quarantined["d"] = "Test"
+	multi.deactivateClient(ctx, clients[3])
+
+	// Run divergence checks, which include quarantined clients, whilst clients are
+	// deactivated and reactivated, which leaves spare capacity in the active client list.
+	// Run with -race to detect writes to the shared list.
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			multi.checkDivergence(ctx)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			multi.deactivateClient(ctx, clients[i%3])
+			// Give the check a chance to obtain the list with spare capacity.
+			time.Sleep(100 * time.Microsecond)
+			multi.activateClient(ctx, clients[i%3])
+		}
+	}()
+	wg.Wait()
+
+	require.ElementsMatch(t, []string{"a", "b", "c"}, addresses(multi.activeClients))
+}
+
+// forkedClient is a client that reports a different block root.
+type forkedClient struct {
+	*mock.Service
+}
+
+func (*forkedClient) BeaconBlockRoot(_ context.Context,
+	_ *api.BeaconBlockRootOpts,
+) (
+	*api.Response[*phase0.Root],
+	error,
+) {
+	return &api.Response[*phase0.Root]{
+		Data:     &phase0.Root{0xff},
+		Metadata: make(map[string]any),
+	}, nil
+}
+
+func TestDivergenceBlockRootPersistence(t *testing.T) {
+	ctx := context.Background()
+
+	clients := make([]consensusclient.Service, 0, 3)
+	for _, name := range []string{"a", "b", "c"} {
+		client, err := mock.New(ctx, mock.WithName(name))
+		require.NoError(t, err)
+		clients = append(clients, client)
+	}
+	var mu sync.Mutex
+	divergences := make([]*Divergence, 0)
+	s, err := New(ctx,
+		WithLogLevel(zerolog.Disabled),
+		WithClients(clients),
+		WithDivergenceHandler(func(_ context.Context, divergence *Divergence) {
+			mu.Lock()
+			divergences = append(divergences, divergence)
+			mu.Unlock()
+		}),
+	)
+	require.NoError(t, err)
+	multi := s.(*Service)
+	reported := func() int {
+		mu.Lock()
+		defer mu.Unlock()
+
+		return len(divergences)
+	}
+
+	agree := map[string]phase0.Root{"a": {0x01}, "b": {0x01}, "c": {0x01}}
+	disagree := map[string]phase0.Root{"a": {0x01}, "b": {0x01}, "c": {0x02}}
+
+	// Block root disagreements that do not persist, such as during a reorganisation, are not reported.
+	for i := 0; i < divergenceBlockRootChecks-1; i++ {
+		multi.compareRoots(ctx, "BeaconBlockRoot", 1, disagree)
+	}
+	multi.compareRoots(ctx, "BeaconBlockRoot", 1, agree)
+	multi.compareRoots(ctx, "BeaconBlockRoot", 1, disagree)
+	require.Zero(t, reported())
+
+	// Block root disagreements that persist are reported.
+	for i := 0; i < divergenceBlockRootChecks-1; i++ {
+		multi.compareRoots(ctx, "BeaconBlockRoot", 1, disagree)
+	}
+	require.Equal(t, 1, reported())
+	require.Equal(t, []string{"c"}, divergences[0].Minority)
+
+	// Other disagreements are reported immediately.
+	multi.compareRoots(ctx, "Genesis", 0, disagree)
+	require.Equal(t, 2, reported())
+}
+
+func TestDivergenceFirstClientUnavailable(t *testing.T) {
+	ctx := context.Background()
+
+	unavailable, err := mock.New(ctx, mock.WithName("a"))
+	require.NoError(t, err)
+	erroring, err := testclients.NewErroring(ctx, 1, unavailable)
+	require.NoError(t, err)
+	b, err := mock.New(ctx, mock.WithName("b"))
+	require.NoError(t, err)
+	c, err := mock.New(ctx, mock.WithName("c"))
+	require.NoError(t, err)
+	d, err := mock.New(ctx, mock.WithName("d"))
+	require.NoError(t, err)
+	clients := []consensusclient.Service{erroring, b, c, &forkedClient{Service: d}}
+
+	var mu sync.Mutex
+	divergences := make([]*Divergence, 0)
+	s, err := New(ctx,
+		WithLogLevel(zerolog.Disabled),
+		WithClients(clients[1:]),
+		WithDivergenceHandler(func(_ context.Context, divergence *Divergence) {
+			mu.Lock()
+			divergences = append(divergences, divergence)
+			mu.Unlock()
+		}),
+	)
+	require.NoError(t, err)
+	multi := s.(*Service)
+
+	// The first client failing to provide its sync state does not stop the check.
+	for i := 0; i < divergenceBlockRootChecks; i++ {
+		multi.checkBeaconBlockRootDivergence(ctx, clients)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, divergences, 1)
+	require.Equal(t, []string{"d"}, divergences[0].Minority)
+}
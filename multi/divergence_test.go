@@ -0,0 +1,139 @@
+// Copyright © 2023 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package multi_test
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	consensusclient "github.com/attestantio/go-eth2-client"
+	"github.com/attestantio/go-eth2-client/api"
+	"github.com/attestantio/go-eth2-client/mock"
+	"github.com/attestantio/go-eth2-client/multi"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+// divergentClient is a client that can be made to report a different block root.
+type divergentClient struct {
+	*mock.Service
+	diverge atomic.Bool
+}
+
+func (c *divergentClient) BeaconBlockRoot(ctx context.Context,
+	opts *api.BeaconBlockRootOpts,
+) (
+	*api.Response[*phase0.Root],
+	error,
+) {
+	if c.diverge.Load() {
+		return &api.Response[*phase0.Root]{
+			Data:     &phase0.Root{0xff},
+			Metadata: make(map[string]any),
+		}, nil
+	}
+
+	return c.Service.BeaconBlockRoot(ctx, opts)
+}
+
+func TestDivergence(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	genesisTime := time.Now().Add(-time.Hour)
+	clients := make([]consensusclient.Service, 0, 3)
+	var divergent *divergentClient
+	for _, name := range []string{"a", "b", "c"} {
+		client, err := mock.New(context.Background(), mock.WithName(name), mock.WithGenesisTime(genesisTime))
+		require.NoError(t, err)
+		divergent = &divergentClient{Service: client}
+		clients = append(clients, divergent)
+	}
+
+	var mu sync.Mutex
+	divergences := make([]*multi.Divergence, 0)
+	s, err := multi.New(ctx,
+		multi.WithLogLevel(zerolog.Disabled),
+		multi.WithClients(clients),
+		multi.WithRecheckInterval(10*time.Millisecond),
+		multi.WithDivergenceCheck(10*time.Millisecond),
+		multi.WithQuarantine(true),
+		multi.WithDivergenceHandler(func(_ context.Context, divergence *multi.Divergence) {
+			mu.Lock()
+			divergences = append(divergences, divergence)
+			mu.Unlock()
+		}),
+	)
+	require.NoError(t, err)
+	service := s.(*multi.Service)
+
+	state := func(address string) multi.ClientState {
+		for _, info := range service.Clients() {
+			if info.Address == address {
+				return info.State
+			}
+		}
+
+		return multi.ClientStatePending
+	}
+
+	// No divergences whilst the clients agree.
+	time.Sleep(50 * time.Millisecond)
+	mu.Lock()
+	require.Empty(t, divergences)
+	mu.Unlock()
+
+	// Client c diverges, and is quarantined.
+	divergent.diverge.Store(true)
+	require.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+
+		return len(divergences) > 0
+	}, time.Second, 10*time.Millisecond)
+	mu.Lock()
+	divergence := divergences[0]
+	mu.Unlock()
+	require.Equal(t, "BeaconBlockRoot", divergence.Check)
+	require.Equal(t, []string{"c"}, divergence.Minority)
+	require.Equal(t, []string{"c"}, divergence.Quarantined)
+	require.Len(t, divergence.Votes, 2)
+	require.Equal(t, []string{"a", "b"}, divergence.Votes[divergence.Majority])
+	require.Eventually(t, func() bool { return state("c") == multi.ClientStateInactive }, time.Second, 10*time.Millisecond)
+
+	// Client c stays quarantined whilst it diverges, despite being healthy.
+	time.Sleep(50 * time.Millisecond)
+	require.Equal(t, multi.ClientStateInactive, state("c"))
+
+	// Client c agrees again, and is released.
+	divergent.diverge.Store(false)
+	require.Eventually(t, func() bool { return state("c") == multi.ClientStateActive }, time.Second, 10*time.Millisecond)
+}
+
+func TestDivergenceParameters(t *testing.T) {
+	ctx := context.Background()
+
+	client, err := mock.New(ctx)
+	require.NoError(t, err)
+
+	_, err = multi.New(ctx,
+		multi.WithClients([]consensusclient.Service{client}),
+		multi.WithDivergenceCheck(-time.Second),
+	)
+	require.EqualError(t, err, "problem with parameters: divergence check interval cannot be negative")
+}
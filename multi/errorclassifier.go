@@ -0,0 +1,206 @@
+// Copyright © 2023 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package multi
+
+import (
+	"context"
+	"strings"
+
+	consensusclient "github.com/attestantio/go-eth2-client"
+	"github.com/attestantio/go-eth2-client/api"
+	"github.com/pkg/errors"
+)
+
+// maxErrorRetries is the number of times that a call is retried on the same client
+// when an error is classified as ErrorActionRetry, before failing over.
+const maxErrorRetries = 1
+
+// ErrorAction is the action to take when a client returns an error.
+type ErrorAction int
+
+const (
+	// ErrorActionFailover deactivates the client and tries the next.  This is the default.
+	ErrorActionFailover ErrorAction = iota
+	// ErrorActionRetry retries the call on the same client, failing over if it errors again.
+	ErrorActionRetry
+	// ErrorActionReturn returns the error to the caller without deactivating the client.
+	// This takes precedence over the error handler of the call.
+	ErrorActionReturn
+)
+
+// String returns a string representation of the action.
+func (a ErrorAction) String() string {
+	switch a {
+	case ErrorActionFailover:
+		return "failover"
+	case ErrorActionRetry:
+		return "retry"
+	case ErrorActionReturn:
+		return "return"
+	default:
+		return "unknown"
+	}
+}
+
+// ErrorClassifier decides the action to take when a client returns an error.
+type ErrorClassifier interface {
+	// ClassifyError classifies an error returned by a client in response to a call.
+	// The provider is the client implementation, for example "lighthouse", and the
+	// call is the name of the provider function, for example "SubmitAttestations".
+	// It returns false if it has no opinion on the error.
+	ClassifyError(ctx context.Context, provider string, call string, err error) (ErrorAction, bool)
+}
+
+// ErrorRule is a rule that matches an error.  Empty fields match anything.
+type ErrorRule struct {
+	// Provider is the client implementation, for example "lighthouse".
+	Provider string
+	// Call is the name of the provider function, for example "SubmitAttestations".
+	Call string
+	// StatusCode is the HTTP status code of the error.
+	StatusCode int
+	// Message is text contained in the error.
+	Message string
+	// Action is the action to take if the rule matches.
+	Action ErrorAction
+}
+
+// matches returns true if the rule matches the error.
+func (r *ErrorRule) matches(provider string, call string, err error) bool {
+	if r.Provider != "" && r.Provider != provider {
+		return false
+	}
+	if r.Call != "" && r.Call != call {
+		return false
+	}
+	if r.StatusCode != 0 {
+		var apiErr *api.Error
+		if !errors.As(err, &apiErr) || apiErr.StatusCode != r.StatusCode {
+			return false
+		}
+	}
+	if r.Message != "" && !strings.Contains(err.Error(), r.Message) {
+		return false
+	}
+
+	return true
+}
+
+// ErrorRules is an ordered list of rules, the first matching rule of which classifies an error.
+type ErrorRules []*ErrorRule
+
+// ClassifyError classifies an error using the first matching rule.
+func (r ErrorRules) ClassifyError(_ context.Context, provider string, call string, err error) (ErrorAction, bool) {
+	for _, rule := range r {
+		if rule.matches(provider, call, err) {
+			return rule.Action, true
+		}
+	}
+
+	return ErrorActionFailover, false
+}
+
+// BuiltinErrorRules are the rules used to classify errors from known clients.  They are
+// consulted after any classifiers supplied with WithErrorClassifiers.
+var BuiltinErrorRules = ErrorRules{
+	// Lighthouse rejects duplicate messages.  It is possible that a message sent to another
+	// node already propagated to this node, or the caller is attempting to resend an existing
+	// message, but either way it is not a failover-worthy error.
+	{Provider: "lighthouse", Call: "SubmitAttestations", Message: "PriorAttestationKnown", Action: ErrorActionReturn},
+	{Provider: "lighthouse", Call: "SubmitAggregateAttestations", Message: "AttestationSupersetKnown", Action: ErrorActionReturn},
+	{Provider: "lighthouse", Call: "SubmitAggregateAttestations", Message: "AggregatorAlreadyKnown", Action: ErrorActionReturn},
+	{Provider: "lighthouse", Call: "SubmitSyncCommitteeMessages", Message: "PriorSyncCommitteeMessageKnown", Action: ErrorActionReturn},
+	{Provider: "lighthouse", Call: "SubmitSyncCommitteeContributions", Message: "SyncContributionSupersetKnown", Action: ErrorActionReturn},
+	{Provider: "lighthouse", Call: "SubmitSyncCommitteeContributions", Message: "AggregatorAlreadyKnown", Action: ErrorActionReturn},
+	// Lighthouse rejects an attestation for a block that is not its current head.  We assume
+	// that the request is valid and it is the node that is somehow out of sync, so failover.
+	{Provider: "lighthouse", Call: "SubmitAttestations", Message: "UnknownHeadBlock", Action: ErrorActionFailover},
+
+	// Lodestar rejects duplicate messages with error codes ending "_ALREADY_KNOWN".
+	{Provider: "lodestar", Message: "_ALREADY_KNOWN", Action: ErrorActionReturn},
+
+	// Teku and Prysm reject duplicate messages as already seen or already known.
+	{Provider: "teku", Message: "already seen", Action: ErrorActionReturn},
+	{Provider: "teku", Message: "already known", Action: ErrorActionReturn},
+	{Provider: "prysm", Message: "already seen", Action: ErrorActionReturn},
+	{Provider: "prysm", Message: "already known", Action: ErrorActionReturn},
+
+	// Nimbus rejects duplicate messages as already seen, or because the validator has already
+	// voted or aggregated.
+	{Provider: "nimbus", Message: "already seen", Action: ErrorActionReturn},
+	{Provider: "nimbus", Message: "has already voted", Action: ErrorActionReturn},
+	{Provider: "nimbus", Message: "has already aggregated", Action: ErrorActionReturn},
+
+	// Other errors, including 503 responses from syncing nodes, result in the default of failover.
+}
+
+// classifyError decides the action to take for an error returned by a client in response to a call.
+func (s *Service) classifyError(ctx context.Context, client consensusclient.Service, call string, err error) ErrorAction {
+	provider := s.providerName(ctx, client)
+	for _, classifier := range s.errorClassifiers {
+		if action, classified := classifier.ClassifyError(ctx, provider, call, err); classified {
+			return action
+		}
+	}
+	if action, classified := BuiltinErrorRules.ClassifyError(ctx, provider, call, err); classified {
+		return action
+	}
+
+	return ErrorActionFailover
+}
+
+// handleError decides the action to take for an error returned by a client in response
+// to a call, given its classification and taking into account the call's own error handler
+// if present.
+// The error handler can only prevent failover: if it requests failover for an error that
+// is classified as ErrorActionReturn, the error is still returned.
+func (s *Service) handleError(ctx context.Context,
+	client consensusclient.Service,
+	action ErrorAction,
+	err error,
+	errHandler errHandlerFunc,
+) (
+	ErrorAction,
+	error,
+) {
+	if errHandler != nil {
+		var failover bool
+		failover, err = errHandler(ctx, client, err)
+		if !failover {
+			action = ErrorActionReturn
+		}
+	}
+
+	return action, err
+}
+
+// providerName returns the client implementation of the provider, caching the result.
+func (s *Service) providerName(ctx context.Context, client consensusclient.Service) string {
+	s.providerNamesMu.RLock()
+	name, exists := s.providerNames[client.Address()]
+	s.providerNamesMu.RUnlock()
+	if exists {
+		return name
+	}
+
+	name = s.providerInfo(ctx, client)
+	if name != "<unknown>" {
+		// Only cache known names, as an unknown name may be due to a transient failure.
+		s.providerNamesMu.Lock()
+		s.providerNames[client.Address()] = name
+		s.providerNamesMu.Unlock()
+	}
+
+	return name
+}
@@ -0,0 +1,121 @@
+// Copyright © 2023 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package multi
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+
+	consensusclient "github.com/attestantio/go-eth2-client"
+	"github.com/attestantio/go-eth2-client/api"
+	"github.com/attestantio/go-eth2-client/mock"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleErrorPrecedence(t *testing.T) {
+	ctx := context.Background()
+
+	client, err := mock.New(ctx, mock.WithName("a"))
+	require.NoError(t, err)
+	s, err := New(ctx,
+		WithLogLevel(zerolog.Disabled),
+		WithClients([]consensusclient.Service{client}),
+		WithErrorClassifiers(ErrorRules{{Message: "known", Action: ErrorActionReturn}}),
+	)
+	require.NoError(t, err)
+	multi := s.(*Service)
+
+	failover := func(_ context.Context, _ consensusclient.Service, err error) (bool, error) {
+		return true, err
+	}
+	noFailover := func(_ context.Context, _ consensusclient.Service, err error) (bool, error) {
+		return false, err
+	}
+
+	// The error handler cannot override a classification of return.
+	known := errors.New("known")
+	action, _ := multi.handleError(ctx, client, multi.classifyError(ctx, client, "Test", known), known, failover)
+	require.Equal(t, ErrorActionReturn, action)
+
+	// The error handler can prevent failover.
+	other := errors.New("other")
+	action, _ = multi.handleError(ctx, client, multi.classifyError(ctx, client, "Test", other), other, noFailover)
+	require.Equal(t, ErrorActionReturn, action)
+	action, _ = multi.handleError(ctx, client, multi.classifyError(ctx, client, "Test", other), other, failover)
+	require.Equal(t, ErrorActionFailover, action)
+}
+
+// versionedClient is a client that reports a node version but not its capabilities.
+type versionedClient struct {
+	consensusclient.Service
+	version string
+}
+
+func (c *versionedClient) NodeVersion(_ context.Context) (*api.Response[string], error) {
+	return &api.Response[string]{Data: c.version, Metadata: make(map[string]any)}, nil
+}
+
+func TestProviderInfoNodeVersion(t *testing.T) {
+	ctx := context.Background()
+
+	s := &Service{
+		health:       make(map[consensusclient.Service]*clientHealth),
+		capabilities: make(map[consensusclient.Service]*api.Capabilities),
+	}
+	for version, expected := range map[string]string{
+		"Lighthouse/v4.5.0":               "lighthouse",
+		"Prysm/v4.1.1":                    "prysm",
+		"teku/v23.10.0":                   "teku",
+		"Nimbus/v23.10.0":                 "nimbus",
+		"Lodestar/v1.12.0/linux-x64/node": "lodestar",
+		"Other/v1.0.0":                    "<unknown>",
+	} {
+		require.Equal(t, expected, s.providerInfo(ctx, &versionedClient{version: version}), version)
+	}
+}
+
+// countingClassifier is an error classifier that counts the errors it classifies.
+type countingClassifier struct {
+	classified atomic.Int32
+}
+
+func (c *countingClassifier) ClassifyError(_ context.Context, _ string, _ string, _ error) (ErrorAction, bool) {
+	c.classified.Add(1)
+
+	return ErrorActionFailover, true
+}
+
+func TestErrorClassifiedOnce(t *testing.T) {
+	ctx := context.Background()
+
+	client, err := mock.New(ctx, mock.WithName("a"))
+	require.NoError(t, err)
+	classifier := &countingClassifier{}
+	s, err := New(ctx,
+		WithLogLevel(zerolog.Disabled),
+		WithClients([]consensusclient.Service{client}),
+		WithErrorClassifiers(classifier),
+	)
+	require.NoError(t, err)
+	multi := s.(*Service)
+
+	_, err = multi.doCall(ctx, "Test", func(_ context.Context, _ consensusclient.Service) (interface{}, error) {
+		return nil, errors.New("failed")
+	}, nil)
+	require.Error(t, err)
+	require.Equal(t, int32(1), classifier.classified.Load())
+}
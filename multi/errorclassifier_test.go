@@ -0,0 +1,321 @@
+// Copyright © 2023 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package multi_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+
+	consensusclient "github.com/attestantio/go-eth2-client"
+	"github.com/attestantio/go-eth2-client/api"
+	"github.com/attestantio/go-eth2-client/mock"
+	"github.com/attestantio/go-eth2-client/multi"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+// erroringClient is a client of a given implementation that fails a number of attestation submissions.
+type erroringClient struct {
+	*mock.Service
+	provider    string
+	err         error
+	failures    int32
+	submissions atomic.Int32
+}
+
+func (c *erroringClient) Capabilities(_ context.Context) (*api.Response[*api.Capabilities], error) {
+	return &api.Response[*api.Capabilities]{
+		Data: &api.Capabilities{
+			Client:    c.provider,
+			Endpoints: make(map[string]api.EndpointCapabilities),
+		},
+		Metadata: make(map[string]any),
+	}, nil
+}
+
+func (c *erroringClient) SubmitAttestations(_ context.Context, _ []*phase0.Attestation) error {
+	if c.submissions.Add(1) <= c.failures {
+		return c.err
+	}
+
+	return nil
+}
+
+func newErroringClient(ctx context.Context, t *testing.T, name string, provider string, err error, failures int32) *erroringClient {
+	t.Helper()
+
+	client, e := mock.New(ctx, mock.WithName(name))
+	require.NoError(t, e)
+
+	return &erroringClient{
+		Service:  client,
+		provider: provider,
+		err:      err,
+		failures: failures,
+	}
+}
+
+func TestErrorRules(t *testing.T) {
+	ctx := context.Background()
+
+	rules := multi.ErrorRules{
+		{Provider: "teku", StatusCode: 400, Action: multi.ErrorActionReturn},
+		{Call: "SubmitAttestations", Message: "busy", Action: multi.ErrorActionRetry},
+	}
+
+	tests := []struct {
+		name       string
+		provider   string
+		call       string
+		err        error
+		action     multi.ErrorAction
+		classified bool
+	}{
+		{
+			name:       "StatusCode",
+			provider:   "teku",
+			call:       "SubmitAttestations",
+			err:        &api.Error{StatusCode: 400},
+			action:     multi.ErrorActionReturn,
+			classified: true,
+		},
+		{
+			name:     "StatusCodeMismatch",
+			provider: "teku",
+			call:     "Proposal",
+			err:      &api.Error{StatusCode: 500},
+		},
+		{
+			name:     "StatusCodeNotAPIError",
+			provider: "teku",
+			call:     "Proposal",
+			err:      errors.New("failed"),
+		},
+		{
+			name:       "Message",
+			provider:   "nimbus",
+			call:       "SubmitAttestations",
+			err:        errors.New("node busy"),
+			action:     multi.ErrorActionRetry,
+			classified: true,
+		},
+		{
+			name:     "CallMismatch",
+			provider: "nimbus",
+			call:     "SubmitAggregateAttestations",
+			err:      errors.New("node busy"),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			action, classified := rules.ClassifyError(ctx, test.provider, test.call, test.err)
+			require.Equal(t, test.classified, classified)
+			require.Equal(t, test.action, action)
+		})
+	}
+}
+
+func TestBuiltinErrorRules(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		provider string
+		call     string
+		err      error
+		action   multi.ErrorAction
+	}{
+		{
+			name:     "Lighthouse",
+			provider: "lighthouse",
+			call:     "SubmitAttestations",
+			err:      &api.Error{StatusCode: 400, Data: []byte(`{"message":"PriorAttestationKnown"}`)},
+			action:   multi.ErrorActionReturn,
+		},
+		{
+			name:     "LighthouseUnknownHead",
+			provider: "lighthouse",
+			call:     "SubmitAttestations",
+			err:      &api.Error{StatusCode: 400, Data: []byte(`{"message":"UnknownHeadBlock"}`)},
+			action:   multi.ErrorActionFailover,
+		},
+		{
+			name:     "Lodestar",
+			provider: "lodestar",
+			call:     "SubmitAttestations",
+			err:      &api.Error{StatusCode: 400, Data: []byte(`{"message":"ATTESTATION_ERROR_ATTESTATION_ALREADY_KNOWN"}`)},
+			action:   multi.ErrorActionReturn,
+		},
+		{
+			name:     "TekuKnown",
+			provider: "teku",
+			call:     "SubmitSyncCommitteeMessages",
+			err:      &api.Error{StatusCode: 400, Data: []byte(`{"message":"Sync committee message already known"}`)},
+			action:   multi.ErrorActionReturn,
+		},
+		{
+			name:     "TekuSeen",
+			provider: "teku",
+			call:     "SubmitAggregateAttestations",
+			err:      &api.Error{StatusCode: 400, Data: []byte(`{"message":"Aggregate already seen"}`)},
+			action:   multi.ErrorActionReturn,
+		},
+		{
+			name:     "Prysm",
+			provider: "prysm",
+			call:     "SubmitAttestations",
+			err:      &api.Error{StatusCode: 400, Data: []byte(`{"message":"attestation already seen"}`)},
+			action:   multi.ErrorActionReturn,
+		},
+		{
+			name:     "PrysmKnown",
+			provider: "prysm",
+			call:     "SubmitAggregateAttestations",
+			err:      &api.Error{StatusCode: 400, Data: []byte(`{"message":"aggregate already known"}`)},
+			action:   multi.ErrorActionReturn,
+		},
+		{
+			name:     "NimbusVoted",
+			provider: "nimbus",
+			call:     "SubmitAttestations",
+			err:      &api.Error{StatusCode: 400, Data: []byte(`{"message":"Attestation: Validator has already voted in epoch"}`)},
+			action:   multi.ErrorActionReturn,
+		},
+		{
+			name:     "NimbusAggregated",
+			provider: "nimbus",
+			call:     "SubmitAggregateAttestations",
+			err:      &api.Error{StatusCode: 400, Data: []byte(`{"message":"Aggregate: Validator has already aggregated in epoch"}`)},
+			action:   multi.ErrorActionReturn,
+		},
+		{
+			name:     "NimbusSeen",
+			provider: "nimbus",
+			call:     "SubmitSyncCommitteeContributions",
+			err:      &api.Error{StatusCode: 400, Data: []byte(`{"message":"Contribution: already seen"}`)},
+			action:   multi.ErrorActionReturn,
+		},
+		{
+			name:     "Unmatched",
+			provider: "nimbus",
+			call:     "SubmitAttestations",
+			err:      &api.Error{StatusCode: 503, Data: []byte(`{"message":"Beacon node is currently syncing"}`)},
+			action:   multi.ErrorActionFailover,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			action, _ := multi.BuiltinErrorRules.ClassifyError(ctx, test.provider, test.call, test.err)
+			require.Equal(t, test.action, action)
+		})
+	}
+}
+
+func TestErrorClassification(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name        string
+		provider    string
+		err         error
+		failures    int32
+		classifiers []multi.ErrorClassifier
+		expectedErr string
+		submissions []int32
+		state       multi.ClientState
+	}{
+		{
+			name:        "Failover",
+			provider:    "teku",
+			err:         errors.New("failed"),
+			failures:    1,
+			submissions: []int32{1, 1},
+			state:       multi.ClientStateInactive,
+		},
+		{
+			name:        "BuiltinReturn",
+			provider:    "lighthouse",
+			err:         &api.Error{StatusCode: 400, Data: []byte(`{"message":"PriorAttestationKnown"}`)},
+			failures:    1,
+			expectedErr: `failed with status 400: {"message":"PriorAttestationKnown"}`,
+			submissions: []int32{1, 0},
+			state:       multi.ClientStateActive,
+		},
+		{
+			name:     "BuiltinOverridden",
+			provider: "lighthouse",
+			err:      &api.Error{StatusCode: 400, Data: []byte(`{"message":"PriorAttestationKnown"}`)},
+			failures: 1,
+			classifiers: []multi.ErrorClassifier{
+				multi.ErrorRules{{Provider: "lighthouse", Action: multi.ErrorActionFailover}},
+			},
+			submissions: []int32{1, 1},
+			state:       multi.ClientStateInactive,
+		},
+		{
+			name:     "RetrySucceeds",
+			provider: "nimbus",
+			err:      errors.New("busy"),
+			failures: 1,
+			classifiers: []multi.ErrorClassifier{
+				multi.ErrorRules{{Message: "busy", Action: multi.ErrorActionRetry}},
+			},
+			submissions: []int32{2, 0},
+			state:       multi.ClientStateActive,
+		},
+		{
+			name:     "RetryFails",
+			provider: "nimbus",
+			err:      errors.New("busy"),
+			failures: 2,
+			classifiers: []multi.ErrorClassifier{
+				multi.ErrorRules{{Message: "busy", Action: multi.ErrorActionRetry}},
+			},
+			submissions: []int32{2, 1},
+			state:       multi.ClientStateInactive,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			first := newErroringClient(ctx, t, "first", test.provider, test.err, test.failures)
+			second := newErroringClient(ctx, t, "second", test.provider, test.err, 0)
+
+			s, err := multi.New(ctx,
+				multi.WithLogLevel(zerolog.Disabled),
+				multi.WithClients([]consensusclient.Service{first, second}),
+				multi.WithErrorClassifiers(test.classifiers...),
+			)
+			require.NoError(t, err)
+
+			err = s.(consensusclient.AttestationsSubmitter).SubmitAttestations(ctx, []*phase0.Attestation{})
+			if test.expectedErr != "" {
+				require.ErrorContains(t, err, test.expectedErr)
+			} else {
+				require.NoError(t, err)
+			}
+			require.Equal(t, test.submissions, []int32{first.submissions.Load(), second.submissions.Load()})
+			for _, info := range s.(*multi.Service).Clients() {
+				if info.Address == "first" {
+					require.Equal(t, test.state, info.State)
+				}
+			}
+		})
+	}
+}
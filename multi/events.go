@@ -25,6 +25,8 @@ import (
 )
 
 // Events feeds requested events with the given topics to the supplied handler.
+// If event merging is enabled, events from all active clients are merged and
+// de-duplicated; otherwise only events from the primary active client are forwarded.
 func (s *Service) Events(ctx context.Context,
 	topics []string,
 	handler consensusclient.EventHandlerFunc,
@@ -32,6 +34,10 @@ func (s *Service) Events(ctx context.Context,
 	// #nosec G404
 	log := s.log.With().Str("id", fmt.Sprintf("%02x", rand.Int31())).Logger()
 
+	if s.mergedEvents {
+		return s.mergeEvents(ctx, log, topics, handler)
+	}
+
 	// Because events are streams we treat them differently from all other calls.
 	// We listen to all active clients, and only pass along events from the currently active provider.
 
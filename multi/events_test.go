@@ -15,12 +15,18 @@ package multi_test
 
 import (
 	"context"
+	"errors"
+	"sync"
 	"testing"
+	"time"
 
 	consensusclient "github.com/attestantio/go-eth2-client"
+	apiv1 "github.com/attestantio/go-eth2-client/api/v1"
 	"github.com/attestantio/go-eth2-client/mock"
 	"github.com/attestantio/go-eth2-client/multi"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
 	"github.com/attestantio/go-eth2-client/testclients"
+	"github.com/prysmaticlabs/go-bitfield"
 	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/require"
 )
@@ -51,3 +57,152 @@ func TestEvents(t *testing.T) {
 
 	require.NoError(t, multiClient.(consensusclient.EventsProvider).Events(ctx, []string{}, nil))
 }
+
+// eventsClient is a client that allows events to be sent to its subscribers.
+type eventsClient struct {
+	*mock.Service
+	err           error
+	mu            sync.Mutex
+	handlers      []consensusclient.EventHandlerFunc
+	subscriptions []context.Context
+}
+
+func (c *eventsClient) Events(ctx context.Context, _ []string, handler consensusclient.EventHandlerFunc) error {
+	if c.err != nil {
+		return c.err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.handlers = append(c.handlers, handler)
+	c.subscriptions = append(c.subscriptions, ctx)
+
+	return nil
+}
+
+// send sends an event to all live subscribers.
+func (c *eventsClient) send(event *apiv1.Event) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i, handler := range c.handlers {
+		if c.subscriptions[i].Err() == nil {
+			handler(event)
+		}
+	}
+}
+
+// liveSubscriptions returns the number of subscriptions that have not been cancelled.
+func (c *eventsClient) liveSubscriptions() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	live := 0
+	for _, ctx := range c.subscriptions {
+		if ctx.Err() == nil {
+			live++
+		}
+	}
+
+	return live
+}
+
+func newEventsClient(ctx context.Context, t *testing.T, name string) *eventsClient {
+	t.Helper()
+
+	client, err := mock.New(ctx, mock.WithName(name))
+	require.NoError(t, err)
+
+	return &eventsClient{Service: client}
+}
+
+func TestMergedEvents(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	client1 := newEventsClient(context.Background(), t, "mock 1")
+	client2 := newEventsClient(context.Background(), t, "mock 2")
+	client3 := newEventsClient(context.Background(), t, "mock 3")
+
+	multiClient, err := multi.New(ctx,
+		multi.WithLogLevel(zerolog.Disabled),
+		multi.WithClients([]consensusclient.Service{client1, client2}),
+		multi.WithMergedEvents(true),
+		multi.WithRecheckInterval(10*time.Millisecond),
+	)
+	require.NoError(t, err)
+
+	var mu sync.Mutex
+	received := make([]*apiv1.Event, 0)
+	require.NoError(t, multiClient.(consensusclient.EventsProvider).Events(ctx, []string{"head", "attestation"}, func(event *apiv1.Event) {
+		mu.Lock()
+		received = append(received, event)
+		mu.Unlock()
+	}))
+	receivedCount := func() int {
+		mu.Lock()
+		defer mu.Unlock()
+
+		return len(received)
+	}
+	require.Equal(t, 1, client1.liveSubscriptions())
+	require.Equal(t, 1, client2.liveSubscriptions())
+
+	head1 := &apiv1.Event{Topic: "head", Data: &apiv1.HeadEvent{Slot: 1, Block: phase0.Root{0x01}}}
+	head2 := &apiv1.Event{Topic: "head", Data: &apiv1.HeadEvent{Slot: 2, Block: phase0.Root{0x02}}}
+	attestation := &apiv1.Event{Topic: "attestation", Data: &phase0.Attestation{
+		AggregationBits: bitfield.NewBitlist(8),
+		Data: &phase0.AttestationData{
+			Source: &phase0.Checkpoint{},
+			Target: &phase0.Checkpoint{},
+		},
+	}}
+
+	// Events received from both clients are forwarded once.
+	client1.send(head1)
+	client2.send(head1)
+	client2.send(attestation)
+	client1.send(attestation)
+	require.Equal(t, 2, receivedCount())
+
+	// Removing a client cancels its subscription; events continue from the remaining client.
+	require.NoError(t, multiClient.(*multi.Service).RemoveClient(ctx, "mock 1"))
+	require.Eventually(t, func() bool { return client1.liveSubscriptions() == 0 }, time.Second, 10*time.Millisecond)
+	client2.send(head2)
+	require.Equal(t, 3, receivedCount())
+
+	// A new client is subscribed to, and its events de-duplicated.
+	require.NoError(t, multiClient.(*multi.Service).AddClient(ctx, client3))
+	require.Eventually(t, func() bool { return client3.liveSubscriptions() == 1 }, time.Second, 10*time.Millisecond)
+	client3.send(head2)
+	require.Equal(t, 3, receivedCount())
+
+	// Subscriptions end with the context.
+	cancel()
+	require.Equal(t, 0, client2.liveSubscriptions())
+	require.Equal(t, 0, client3.liveSubscriptions())
+}
+
+func TestMergedEventsErrors(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	client1 := newEventsClient(context.Background(), t, "mock 1")
+	client1.err = errors.New("failed")
+	client2 := newEventsClient(context.Background(), t, "mock 2")
+	client2.err = errors.New("failed")
+
+	multiClient, err := multi.New(ctx,
+		multi.WithLogLevel(zerolog.Disabled),
+		multi.WithClients([]consensusclient.Service{client1, client2}),
+		multi.WithMergedEvents(true),
+	)
+	require.NoError(t, err)
+	provider := multiClient.(consensusclient.EventsProvider)
+
+	require.EqualError(t, provider.Events(ctx, []string{}, nil), "no topics supplied")
+	require.EqualError(t, provider.Events(ctx, []string{"head", "bad"}, nil), "unsupported event topic bad")
+	require.EqualError(t, provider.Events(ctx, []string{"head"}, nil), "failed to set up events handler on any client")
+
+	// Subscription succeeds if any client accepts it.
+	client2.err = nil
+	require.NoError(t, provider.Events(ctx, []string{"head"}, nil))
+	require.Equal(t, 1, client2.liveSubscriptions())
+}
@@ -23,7 +23,13 @@ import (
 
 // Finality provides the finality given a state ID.
 func (s *Service) Finality(ctx context.Context, opts *api.FinalityOpts) (*api.Response[*apiv1.Finality], error) {
-	res, err := s.doCall(ctx, func(ctx context.Context, client consensusclient.Service) (interface{}, error) {
+	if quorum, exists := s.quorums["Finality"]; exists {
+		return doQuorumCall(ctx, s, "Finality", quorum, func(ctx context.Context, client consensusclient.Service) (*api.Response[*apiv1.Finality], error) {
+			return client.(consensusclient.FinalityProvider).Finality(ctx, opts)
+		})
+	}
+
+	res, err := s.doCall(ctx, "Finality", func(ctx context.Context, client consensusclient.Service) (interface{}, error) {
 		finality, err := client.(consensusclient.FinalityProvider).Finality(ctx, opts)
 		if err != nil {
 			return nil, err
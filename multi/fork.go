@@ -28,7 +28,13 @@ func (s *Service) Fork(ctx context.Context,
 	*api.Response[*phase0.Fork],
 	error,
 ) {
-	res, err := s.doCall(ctx, func(ctx context.Context, client consensusclient.Service) (interface{}, error) {
+	if quorum, exists := s.quorums["Fork"]; exists {
+		return doQuorumCall(ctx, s, "Fork", quorum, func(ctx context.Context, client consensusclient.Service) (*api.Response[*phase0.Fork], error) {
+			return client.(consensusclient.ForkProvider).Fork(ctx, opts)
+		})
+	}
+
+	res, err := s.doCall(ctx, "Fork", func(ctx context.Context, client consensusclient.Service) (interface{}, error) {
 		fork, err := client.(consensusclient.ForkProvider).Fork(ctx, opts)
 		if err != nil {
 			return nil, err
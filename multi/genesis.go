@@ -23,7 +23,13 @@ import (
 
 // Genesis provides the genesis for the chain.
 func (s *Service) Genesis(ctx context.Context) (*api.Response[*apiv1.Genesis], error) {
-	res, err := s.doCall(ctx, func(ctx context.Context, client consensusclient.Service) (interface{}, error) {
+	if quorum, exists := s.quorums["Genesis"]; exists {
+		return doQuorumCall(ctx, s, "Genesis", quorum, func(ctx context.Context, client consensusclient.Service) (*api.Response[*apiv1.Genesis], error) {
+			return client.(consensusclient.GenesisProvider).Genesis(ctx)
+		})
+	}
+
+	res, err := s.doCall(ctx, "Genesis", func(ctx context.Context, client consensusclient.Service) (interface{}, error) {
 		genesis, err := client.(consensusclient.GenesisProvider).Genesis(ctx)
 		if err != nil {
 			return nil, err
@@ -0,0 +1,298 @@
+// Copyright © 2023 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package multi
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	consensusclient "github.com/attestantio/go-eth2-client"
+	"github.com/attestantio/go-eth2-client/api"
+	apiv1 "github.com/attestantio/go-eth2-client/api/v1"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+)
+
+// ewmaWeight is the weight given to each new sample in the rolling averages.
+const ewmaWeight = 0.1
+
+// minHealthSamples is the number of samples required before rolling averages are used
+// to demote a client.
+const minHealthSamples = 5
+
+// dutyCalls are the calls that provide data used to carry out validator duties.  These
+// are not sent to optimistic clients, which must not be used to produce blocks or attestations.
+var dutyCalls = map[string]bool{
+	"AggregateAttestation":      true,
+	"AttestationData":           true,
+	"BlindedProposal":           true,
+	"Proposal":                  true,
+	"SyncCommitteeContribution": true,
+}
+
+// clientHealth tracks the rolling latency and error rate of a client.
+type clientHealth struct {
+	mu             sync.Mutex
+	latency        time.Duration
+	latencySamples uint64
+	errorRate      float64
+	samples        uint64
+}
+
+// record records the result of a call to the client.
+func (h *clientHealth) record(latency time.Duration, failed bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.latencySamples == 0 {
+		h.latency = latency
+	} else {
+		h.latency = time.Duration((1-ewmaWeight)*float64(h.latency) + ewmaWeight*float64(latency))
+	}
+	h.latencySamples++
+	h.recordResult(failed)
+}
+
+// recordFailure records the result of a call to the client without its latency, for calls
+// whose latency is not comparable with that of other calls.
+func (h *clientHealth) recordFailure(failed bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.recordResult(failed)
+}
+
+// recordResult records the result of a call.  It must be called with the lock held.
+func (h *clientHealth) recordResult(failed bool) {
+	errorSample := 0.0
+	if failed {
+		errorSample = 1.0
+	}
+	if h.samples == 0 {
+		h.errorRate = errorSample
+	} else {
+		h.errorRate = (1-ewmaWeight)*h.errorRate + ewmaWeight*errorSample
+	}
+	h.samples++
+}
+
+// values provides the rolling latency and error rate of the client, along with the number of samples.
+func (h *clientHealth) values() (time.Duration, float64, uint64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return h.latency, h.errorRate, h.samples
+}
+
+// averageLatency provides the rolling latency of the client, and if any latency samples have been recorded.
+func (h *clientHealth) averageLatency() (time.Duration, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return h.latency, h.latencySamples > 0
+}
+
+// clientHealth provides the health tracker for a client.  If the client is not present,
+// for example because it was removed whilst a call was in flight, a tracker that is not
+// retained is returned.
+func (s *Service) clientHealth(client consensusclient.Service) *clientHealth {
+	s.healthMu.RLock()
+	health, exists := s.health[client]
+	s.healthMu.RUnlock()
+	if exists {
+		return health
+	}
+
+	return &clientHealth{}
+}
+
+// recordCall records the latency and result of a call to a client.
+func (s *Service) recordCall(ctx context.Context, client consensusclient.Service, call string, started time.Time, err error) {
+	s.recordHealth(ctx, client, call, started, err)
+	if err == nil {
+		s.callSucceeded(ctx, client)
+	}
+}
+
+// recordBulkCall records the result of a bulk call to a client.  The latency of bulk calls
+// depends on the amount of data returned rather than the responsiveness of the client, so
+// it does not count towards the client's rolling latency.
+func (s *Service) recordBulkCall(ctx context.Context, client consensusclient.Service, call string, started time.Time, err error) {
+	s.recordSample(ctx, client, call, started, err, false)
+	if err == nil {
+		s.callSucceeded(ctx, client)
+	}
+}
+
+// recordHealth records the latency and result of a call to a client for its health.
+// Unlike recordCall it does not count towards the client's circuit breaker, so is used
+// for health checks and probes.
+func (s *Service) recordHealth(ctx context.Context, client consensusclient.Service, call string, started time.Time, err error) {
+	s.recordSample(ctx, client, call, started, err, true)
+}
+
+// recordSample records the result of a call to a client, and optionally its latency, for its health.
+func (s *Service) recordSample(ctx context.Context, client consensusclient.Service, call string, started time.Time, err error, sampleLatency bool) {
+	duration := time.Since(started)
+	monitorCall(client.Address(), call, duration, err != nil && !errors.Is(err, api.ErrNotSupported))
+	if errors.Is(err, api.ErrNotSupported) {
+		// Not a reflection on the health of the client.
+		return
+	}
+	health := s.clientHealth(client)
+	if sampleLatency {
+		health.record(duration, err != nil)
+	} else {
+		health.recordFailure(err != nil)
+	}
+	latency, errorRate, _ := health.values()
+	setProviderHealthMetrics(ctx, client.Address(), latency, errorRate)
+}
+
+// setOptimistic records if a client is optimistic.
+func (s *Service) setOptimistic(client consensusclient.Service, optimistic bool) {
+	s.healthMu.Lock()
+	defer s.healthMu.Unlock()
+
+	if _, exists := s.health[client]; !exists {
+		// Client has been removed.
+		return
+	}
+	if optimistic {
+		s.optimistic[client] = true
+	} else {
+		delete(s.optimistic, client)
+	}
+}
+
+// dutyClients returns the clients that can be used for the call.  Optimistic clients
+// are excluded from duty calls, but remain available for all other calls.
+func (s *Service) dutyClients(name string, clients []consensusclient.Service) []consensusclient.Service {
+	if !dutyCalls[name] {
+		return clients
+	}
+
+	s.healthMu.RLock()
+	defer s.healthMu.RUnlock()
+
+	res := make([]consensusclient.Service, 0, len(clients))
+	for _, client := range clients {
+		if !s.optimistic[client] {
+			res = append(res, client)
+		}
+	}
+
+	return res
+}
+
+// syncState obtains the sync state of a client.
+func syncState(ctx context.Context, client consensusclient.Service) (*apiv1.SyncState, error) {
+	provider, isProvider := client.(consensusclient.NodeSyncingProvider)
+	if !isProvider {
+		return nil, errors.New("client does not provide sync state")
+	}
+
+	response, err := provider.NodeSyncing(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if response.Data == nil {
+		return nil, errors.New("no sync state returned")
+	}
+
+	return response.Data, nil
+}
+
+// ready returns true if the sync state shows a client that is able to serve requests.
+// Optimistic clients are ready, as they can serve read-only requests; they are excluded
+// from duty calls separately.
+func ready(state *apiv1.SyncState) bool {
+	return (!state.IsSyncing) || (state.HeadSlot == 0 && state.SyncDistance == 0)
+}
+
+// unhealthyReason provides the reason that a client is unhealthy, given its sync state and
+// the median head slot of all clients, or an empty string if it is healthy.
+func (s *Service) unhealthyReason(client consensusclient.Service, state *apiv1.SyncState, medianSlot phase0.Slot) string {
+	if state == nil || !ready(state) {
+		return "not ready"
+	}
+	if s.breakerState(client) == BreakerOpen {
+		return "circuit breaker open"
+	}
+	if check := s.quarantineReason(client.Address()); check != "" {
+		return fmt.Sprintf("quarantined after diverging on %s", check)
+	}
+	if s.maxHeadLag > 0 && state.HeadSlot+s.maxHeadLag < medianSlot {
+		return fmt.Sprintf("head slot %d lags median head slot %d", state.HeadSlot, medianSlot)
+	}
+
+	latency, errorRate, samples := s.clientHealth(client).values()
+	if samples < minHealthSamples {
+		return ""
+	}
+	if s.maxLatency > 0 && latency > s.maxLatency {
+		return fmt.Sprintf("latency %v exceeds maximum", latency)
+	}
+	if s.maxErrorRate > 0 && errorRate > s.maxErrorRate {
+		return fmt.Sprintf("error rate %.2f exceeds maximum", errorRate)
+	}
+
+	return ""
+}
+
+// checkHealth checks the health of the clients, activating healthy clients and deactivating the rest.
+func (s *Service) checkHealth(ctx context.Context, clients []consensusclient.Service) {
+	log := zerolog.Ctx(ctx)
+
+	states := make([]*apiv1.SyncState, len(clients))
+	headSlots := make([]phase0.Slot, 0, len(clients))
+	for i, client := range clients {
+		started := time.Now()
+		state, err := syncState(ctx, client)
+		s.recordHealth(ctx, client, "NodeSyncing", started, err)
+		if err != nil {
+			log.Warn().Str("provider", client.Address()).Err(err).Msg("Failed to obtain sync state from node")
+
+			continue
+		}
+		states[i] = state
+		s.setOptimistic(client, state.IsOptimistic)
+		if ready(state) {
+			headSlots = append(headSlots, state.HeadSlot)
+		}
+	}
+
+	// Lag is measured against the median head slot rather than the highest, so that a
+	// single client reporting a head slot far in the future cannot demote the others.
+	// The lower median is used, so that with two clients neither is demoted on the word
+	// of the other.
+	medianSlot := phase0.Slot(0)
+	if len(headSlots) > 0 {
+		sort.Slice(headSlots, func(i, j int) bool { return headSlots[i] < headSlots[j] })
+		medianSlot = headSlots[(len(headSlots)-1)/2]
+	}
+
+	for i, client := range clients {
+		if reason := s.unhealthyReason(client, states[i], medianSlot); reason != "" {
+			log.Trace().Str("provider", client.Address()).Str("reason", reason).Msg("Client unhealthy")
+			s.deactivateClient(ctx, client)
+		} else {
+			s.activateClient(ctx, client)
+		}
+	}
+}
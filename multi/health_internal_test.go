@@ -0,0 +1,292 @@
+// Copyright © 2023 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package multi
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	consensusclient "github.com/attestantio/go-eth2-client"
+	"github.com/attestantio/go-eth2-client/api"
+	apiv1 "github.com/attestantio/go-eth2-client/api/v1"
+	"github.com/attestantio/go-eth2-client/mock"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+// syncStateClient is a client that returns a configurable sync state.
+type syncStateClient struct {
+	*mock.Service
+	state *apiv1.SyncState
+}
+
+func (c *syncStateClient) NodeSyncing(_ context.Context) (*api.Response[*apiv1.SyncState], error) {
+	return &api.Response[*apiv1.SyncState]{
+		Data:     c.state,
+		Metadata: make(map[string]any),
+	}, nil
+}
+
+func newSyncStateClient(ctx context.Context, t *testing.T, name string, headSlot phase0.Slot) *syncStateClient {
+	t.Helper()
+
+	client, err := mock.New(ctx, mock.WithName(name))
+	require.NoError(t, err)
+
+	return &syncStateClient{
+		Service: client,
+		state: &apiv1.SyncState{
+			HeadSlot: headSlot,
+		},
+	}
+}
+
+func addresses(clients []consensusclient.Service) []string {
+	res := make([]string, 0, len(clients))
+	for _, client := range clients {
+		res = append(res, client.Address())
+	}
+
+	return res
+}
+
+func TestHealthOptimistic(t *testing.T) {
+	ctx := context.Background()
+
+	client1 := newSyncStateClient(ctx, t, "a", 100)
+	client2 := newSyncStateClient(ctx, t, "b", 100)
+	s, err := New(ctx,
+		WithLogLevel(zerolog.Disabled),
+		WithClients([]consensusclient.Service{client1, client2}),
+	)
+	require.NoError(t, err)
+	multi := s.(*Service)
+	require.Equal(t, []string{"a", "b"}, addresses(multi.activeClients))
+
+	client1.state = &apiv1.SyncState{HeadSlot: 100, IsOptimistic: true}
+	multi.recheck(ctx)
+	// Optimistic clients remain active for read-only calls, but are not used for duties.
+	require.Equal(t, []string{"a", "b"}, addresses(multi.activeClients))
+	require.Equal(t, []string{"a", "b"}, addresses(multi.dutyClients("Genesis", multi.activeClients)))
+	require.Equal(t, []string{"b"}, addresses(multi.dutyClients("AttestationData", multi.activeClients)))
+
+	client1.state = &apiv1.SyncState{HeadSlot: 100}
+	multi.recheck(ctx)
+	require.Equal(t, []string{"a", "b"}, addresses(multi.dutyClients("AttestationData", multi.activeClients)))
+}
+
+func TestHealthHeadLag(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name       string
+		maxHeadLag phase0.Slot
+		active     []string
+		inactive   []string
+	}{
+		{
+			name:     "Disabled",
+			active:   []string{"a", "b", "c"},
+			inactive: []string{},
+		},
+		{
+			name:       "WithinThreshold",
+			maxHeadLag: 5,
+			active:     []string{"a", "b", "c"},
+			inactive:   []string{},
+		},
+		{
+			name:       "BeyondThreshold",
+			maxHeadLag: 2,
+			active:     []string{"a", "c"},
+			inactive:   []string{"b"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s, err := New(ctx,
+				WithLogLevel(zerolog.Disabled),
+				WithClients([]consensusclient.Service{
+					newSyncStateClient(ctx, t, "a", 100),
+					newSyncStateClient(ctx, t, "b", 95),
+					newSyncStateClient(ctx, t, "c", 99),
+				}),
+				WithMaxHeadLag(test.maxHeadLag),
+			)
+			require.NoError(t, err)
+			multi := s.(*Service)
+
+			multi.recheck(ctx)
+			require.Equal(t, test.active, addresses(multi.activeClients))
+			require.Equal(t, test.inactive, addresses(multi.inactiveClients))
+		})
+	}
+}
+
+func TestHealthHeadLagFarAhead(t *testing.T) {
+	ctx := context.Background()
+
+	s, err := New(ctx,
+		WithLogLevel(zerolog.Disabled),
+		WithClients([]consensusclient.Service{
+			newSyncStateClient(ctx, t, "a", 100),
+			newSyncStateClient(ctx, t, "b", 99),
+			newSyncStateClient(ctx, t, "c", 1000000),
+		}),
+		WithMaxHeadLag(2),
+	)
+	require.NoError(t, err)
+	multi := s.(*Service)
+
+	// A client reporting a head slot far ahead of the others does not demote them.
+	multi.recheck(ctx)
+	require.Equal(t, []string{"a", "b", "c"}, addresses(multi.activeClients))
+	require.Empty(t, addresses(multi.inactiveClients))
+}
+
+func TestHealthErrorRate(t *testing.T) {
+	ctx := context.Background()
+
+	client1 := newSyncStateClient(ctx, t, "a", 100)
+	client2 := newSyncStateClient(ctx, t, "b", 100)
+	s, err := New(ctx,
+		WithLogLevel(zerolog.Disabled),
+		WithClients([]consensusclient.Service{client1, client2}),
+		WithMaxErrorRate(0.5),
+	)
+	require.NoError(t, err)
+	multi := s.(*Service)
+
+	for i := 0; i < 10; i++ {
+		multi.recordCall(ctx, client1, "Test", time.Now(), errors.New("failed"))
+		multi.recordCall(ctx, client2, "Test", time.Now(), nil)
+	}
+	// Calls that are not supported do not count against a client.
+	for i := 0; i < 10; i++ {
+		multi.recordCall(ctx, client2, "Test", time.Now(), api.ErrNotSupported)
+	}
+	_, errorRate, _ := multi.clientHealth(client2).values()
+	require.Zero(t, errorRate)
+
+	multi.recheck(ctx)
+	require.Equal(t, []string{"b"}, addresses(multi.activeClients))
+	require.Equal(t, []string{"a"}, addresses(multi.inactiveClients))
+}
+
+func TestHealthLatency(t *testing.T) {
+	ctx := context.Background()
+
+	client1 := newSyncStateClient(ctx, t, "a", 100)
+	client2 := newSyncStateClient(ctx, t, "b", 100)
+	s, err := New(ctx,
+		WithLogLevel(zerolog.Disabled),
+		WithClients([]consensusclient.Service{client1, client2}),
+		WithMaxLatency(time.Second),
+	)
+	require.NoError(t, err)
+	multi := s.(*Service)
+
+	// Too few samples to demote.
+	multi.recordCall(ctx, client1, "Test", time.Now().Add(-5*time.Second), nil)
+	multi.recheck(ctx)
+	require.Equal(t, []string{"a", "b"}, addresses(multi.activeClients))
+
+	for i := 0; i < minHealthSamples; i++ {
+		multi.recordCall(ctx, client1, "Test", time.Now().Add(-5*time.Second), nil)
+	}
+	multi.recheck(ctx)
+	require.Equal(t, []string{"b"}, addresses(multi.activeClients))
+	require.Equal(t, []string{"a"}, addresses(multi.inactiveClients))
+}
+
+func TestHealthBulkLatency(t *testing.T) {
+	ctx := context.Background()
+
+	client := newSyncStateClient(ctx, t, "a", 100)
+	s, err := New(ctx,
+		WithLogLevel(zerolog.Disabled),
+		WithClients([]consensusclient.Service{client}),
+	)
+	require.NoError(t, err)
+	multi := s.(*Service)
+
+	_, err = multi.doCall(ctx, "Test", func(_ context.Context, _ consensusclient.Service) (interface{}, error) {
+		return true, nil
+	}, nil)
+	require.NoError(t, err)
+	latency, _, samples := multi.clientHealth(client).values()
+
+	// A slow bulk call counts towards the error rate but not the latency.
+	_, err = multi.doBulkCall(ctx, "Test", func(_ context.Context, _ consensusclient.Service) (interface{}, error) {
+		time.Sleep(50 * time.Millisecond)
+
+		return nil, errors.New("failed")
+	}, nil)
+	require.Error(t, err)
+	bulkLatency, errorRate, bulkSamples := multi.clientHealth(client).values()
+	require.Equal(t, latency, bulkLatency)
+	require.Positive(t, errorRate)
+	require.Equal(t, samples+1, bulkSamples)
+}
+
+func TestRemovedClientHealth(t *testing.T) {
+	ctx := context.Background()
+
+	client1 := newSyncStateClient(ctx, t, "a", 100)
+	client2 := newSyncStateClient(ctx, t, "b", 100)
+	s, err := New(ctx,
+		WithLogLevel(zerolog.Disabled),
+		WithClients([]consensusclient.Service{client1, client2}),
+		WithCircuitBreaker(CircuitBreaker{}),
+	)
+	require.NoError(t, err)
+	multi := s.(*Service)
+	require.NoError(t, multi.RemoveClient(ctx, "a"))
+
+	// Calls that were in flight when the client was removed do not recreate its state.
+	multi.recordCall(ctx, client1, "Test", time.Now(), nil)
+	multi.callFailed(ctx, client1)
+	multi.setOptimistic(client1, true)
+	require.NotContains(t, multi.health, client1)
+	require.NotContains(t, multi.breakers, client1)
+	require.NotContains(t, multi.optimistic, client1)
+	require.Contains(t, multi.health, consensusclient.Service(client2))
+	require.Contains(t, multi.breakers, consensusclient.Service(client2))
+}
+
+func TestClientHealthRecord(t *testing.T) {
+	health := &clientHealth{}
+
+	health.record(100*time.Millisecond, false)
+	latency, errorRate, samples := health.values()
+	require.Equal(t, 100*time.Millisecond, latency)
+	require.Zero(t, errorRate)
+	require.Equal(t, uint64(1), samples)
+
+	health.record(200*time.Millisecond, true)
+	latency, errorRate, samples = health.values()
+	require.Equal(t, 110*time.Millisecond, latency)
+	require.InDelta(t, 0.1, errorRate, 0.0001)
+	require.Equal(t, uint64(2), samples)
+
+	health.recordFailure(false)
+	latency, errorRate, samples = health.values()
+	require.Equal(t, 110*time.Millisecond, latency)
+	require.InDelta(t, 0.09, errorRate, 0.0001)
+	require.Equal(t, uint64(3), samples)
+}
@@ -0,0 +1,208 @@
+// Copyright © 2023 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package multi
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	consensusclient "github.com/attestantio/go-eth2-client"
+	api "github.com/attestantio/go-eth2-client/api/v1"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+)
+
+// mergedEventsCacheSize is the number of recent event keys remembered for de-duplication.
+const mergedEventsCacheSize = 16384
+
+// mergedEvents merges the event streams of all active clients into a single stream,
+// de-duplicating events that are received from more than one client.
+// The handler is called for one event at a time, with de-duplication blocked until it
+// returns, so a slow handler delays events from every client; handlers that carry out
+// lengthy work should hand events off rather than process them inline.
+type mergedEvents struct {
+	s       *Service
+	log     zerolog.Logger
+	topics  []string
+	handler consensusclient.EventHandlerFunc
+
+	subscriptionsMu sync.Mutex
+	subscriptions   map[string]context.CancelFunc
+
+	// eventMu serialises calls to the handler, as well as protecting the cache.
+	eventMu   sync.Mutex
+	seen      map[string]struct{}
+	seenKeys  []string
+	seenIndex int
+}
+
+// mergeEvents subscribes to events from all active clients, and keeps the subscriptions
+// in line with the active clients until the context is done.  It returns an error if
+// the topics are invalid, or if no client could be subscribed to.
+func (s *Service) mergeEvents(ctx context.Context,
+	log zerolog.Logger,
+	topics []string,
+	handler consensusclient.EventHandlerFunc,
+) error {
+	if len(topics) == 0 {
+		return errors.New("no topics supplied")
+	}
+	for _, topic := range topics {
+		if !api.SupportedEventTopics[topic] {
+			return fmt.Errorf("unsupported event topic %s", topic)
+		}
+	}
+
+	m := &mergedEvents{
+		s:             s,
+		log:           log,
+		topics:        topics,
+		handler:       handler,
+		subscriptions: make(map[string]context.CancelFunc),
+		seen:          make(map[string]struct{}, mergedEventsCacheSize),
+		seenKeys:      make([]string, mergedEventsCacheSize),
+	}
+	if m.resubscribe(ctx) == 0 {
+		return errors.New("failed to set up events handler on any client")
+	}
+
+	go func() {
+		ticker := time.NewTicker(s.recheckInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				log.Trace().Msg("Context done; stopping merged events")
+
+				return
+			case <-ticker.C:
+				m.resubscribe(ctx)
+			}
+		}
+	}()
+
+	return nil
+}
+
+// resubscribe subscribes to active clients without a subscription, and cancels
+// subscriptions to clients that are no longer active.  It returns the number of
+// subscriptions.
+func (m *mergedEvents) resubscribe(ctx context.Context) int {
+	m.s.clientsMu.RLock()
+	activeClients := m.s.activeClients
+	m.s.clientsMu.RUnlock()
+
+	m.subscriptionsMu.Lock()
+	defer m.subscriptionsMu.Unlock()
+
+	active := make(map[string]bool, len(activeClients))
+	for _, client := range activeClients {
+		address := client.Address()
+		active[address] = true
+		if _, exists := m.subscriptions[address]; exists {
+			continue
+		}
+		provider, isProvider := client.(consensusclient.EventsProvider)
+		if !isProvider {
+			continue
+		}
+		subscriptionCtx, cancel := context.WithCancel(ctx)
+		if err := provider.Events(subscriptionCtx, m.topics, func(event *api.Event) {
+			m.handleEvent(address, event)
+		}); err != nil {
+			m.log.Debug().Str("address", address).Strs("topics", m.topics).Err(err).Msg("Failed to set up events handler; will retry")
+			cancel()
+
+			continue
+		}
+		m.subscriptions[address] = cancel
+		m.log.Trace().Str("address", address).Strs("topics", m.topics).Msg("Events handler active")
+	}
+
+	for address, cancel := range m.subscriptions {
+		if !active[address] {
+			cancel()
+			delete(m.subscriptions, address)
+			m.log.Trace().Str("address", address).Strs("topics", m.topics).Msg("Events handler cancelled")
+		}
+	}
+
+	return len(m.subscriptions)
+}
+
+// handleEvent forwards an event to the handler if it has not already been seen.
+func (m *mergedEvents) handleEvent(address string, event *api.Event) {
+	if m.handler == nil || event == nil {
+		return
+	}
+
+	m.eventMu.Lock()
+	defer m.eventMu.Unlock()
+
+	key, err := eventKey(event)
+	if err != nil {
+		m.log.Debug().Str("address", address).Str("topic", event.Topic).Err(err).Msg("Failed to obtain event key; forwarding")
+	}
+	if key != "" {
+		if _, exists := m.seen[key]; exists {
+			m.log.Trace().Str("address", address).Str("topic", event.Topic).Msg("Duplicate event; ignoring")
+
+			return
+		}
+		// Evict the oldest key to make room.
+		delete(m.seen, m.seenKeys[m.seenIndex])
+		m.seenKeys[m.seenIndex] = key
+		m.seenIndex = (m.seenIndex + 1) % len(m.seenKeys)
+		m.seen[key] = struct{}{}
+	}
+
+	m.log.Trace().Str("address", address).Str("topic", event.Topic).Msg("Forwarding event")
+	m.handler(event)
+}
+
+// eventKey provides a key that identifies an event regardless of the client from which
+// it was received.  It returns an empty key if the event cannot be identified.
+func eventKey(event *api.Event) (string, error) {
+	var key string
+	switch data := event.Data.(type) {
+	case *api.HeadEvent:
+		key = fmt.Sprintf("%d:%#x", data.Slot, data.Block)
+	case *api.BlockEvent:
+		key = fmt.Sprintf("%d:%#x", data.Slot, data.Block)
+	case *api.FinalizedCheckpointEvent:
+		key = fmt.Sprintf("%d:%#x", data.Epoch, data.Block)
+	case *api.ChainReorgEvent:
+		key = fmt.Sprintf("%d:%#x:%#x", data.Slot, data.OldHeadBlock, data.NewHeadBlock)
+	case *api.PayloadAttributesEvent:
+		if data.Data == nil {
+			return "", nil
+		}
+		key = fmt.Sprintf("%d:%#x", data.Data.ProposalSlot, data.Data.ParentBlockRoot)
+	case *api.BlobSidecarEvent:
+		key = fmt.Sprintf("%#x:%d", data.BlockRoot, data.Index)
+	case interface{ HashTreeRoot() ([32]byte, error) }:
+		// Attestations, voluntary exits, contributions and proofs.
+		root, err := data.HashTreeRoot()
+		if err != nil {
+			return "", err
+		}
+		key = fmt.Sprintf("%#x", root)
+	default:
+		return "", nil
+	}
+
+	return fmt.Sprintf("%s:%s", event.Topic, key), nil
+}
@@ -15,6 +15,7 @@ package multi
 
 import (
 	"context"
+	"time"
 
 	"github.com/attestantio/go-eth2-client/metrics"
 	"github.com/pkg/errors"
@@ -22,8 +23,23 @@ import (
 )
 
 var (
-	providersMetric      *prometheus.GaugeVec
-	providerActiveMetric *prometheus.GaugeVec
+	providersMetric         *prometheus.GaugeVec
+	providerActiveMetric    *prometheus.GaugeVec
+	providerLatencyMetric   *prometheus.GaugeVec
+	providerErrorRateMetric *prometheus.GaugeVec
+	quorumDisagreements     *prometheus.CounterVec
+	quorumDissents          *prometheus.CounterVec
+	callRequests            *prometheus.CounterVec
+	callErrors              *prometheus.CounterVec
+	callFailovers           *prometheus.CounterVec
+	callEmptyResponses      *prometheus.CounterVec
+	callDuration            *prometheus.HistogramVec
+	callsExhausted          *prometheus.CounterVec
+	sessionSwitches         *prometheus.CounterVec
+	divergences             *prometheus.CounterVec
+	divergentProviders      *prometheus.CounterVec
+	breakerStateMetric      *prometheus.GaugeVec
+	breakerTransitions      *prometheus.CounterVec
 )
 
 func registerMetrics(ctx context.Context, monitor metrics.Service) error {
@@ -61,6 +77,147 @@ func registerPrometheusMetrics(_ context.Context) error {
 	if err := prometheus.Register(providerActiveMetric); err != nil {
 		return errors.Wrap(err, "failed to register provider_state")
 	}
+	providerLatencyMetric = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "consensusclient",
+		Subsystem: "multi",
+		Name:      "provider_latency_seconds",
+		Help:      "Rolling average latency of provider",
+	}, []string{"provider"})
+	if err := prometheus.Register(providerLatencyMetric); err != nil {
+		return errors.Wrap(err, "failed to register provider_latency_seconds")
+	}
+	providerErrorRateMetric = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "consensusclient",
+		Subsystem: "multi",
+		Name:      "provider_error_rate",
+		Help:      "Rolling average error rate of provider",
+	}, []string{"provider"})
+	if err := prometheus.Register(providerErrorRateMetric); err != nil {
+		return errors.Wrap(err, "failed to register provider_error_rate")
+	}
+	quorumDisagreements = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "consensusclient",
+		Subsystem: "multi",
+		Name:      "quorum_disagreements_total",
+		Help:      "Number of quorum reads in which clients disagreed",
+	}, []string{"provider", "outcome"})
+	if err := prometheus.Register(quorumDisagreements); err != nil {
+		return errors.Wrap(err, "failed to register quorum_disagreements_total")
+	}
+	quorumDissents = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "consensusclient",
+		Subsystem: "multi",
+		Name:      "quorum_dissents_total",
+		Help:      "Number of quorum reads in which a client disagreed with the quorum",
+	}, []string{"provider", "address"})
+	if err := prometheus.Register(quorumDissents); err != nil {
+		return errors.Wrap(err, "failed to register quorum_dissents_total")
+	}
+	callRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "consensusclient",
+		Subsystem: "multi",
+		Name:      "requests_total",
+		Help:      "Number of calls made to a provider",
+	}, []string{"provider", "call"})
+	if err := prometheus.Register(callRequests); err != nil {
+		return errors.Wrap(err, "failed to register requests_total")
+	}
+	callErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "consensusclient",
+		Subsystem: "multi",
+		Name:      "errors_total",
+		Help:      "Number of calls to a provider that returned an error",
+	}, []string{"provider", "call"})
+	if err := prometheus.Register(callErrors); err != nil {
+		return errors.Wrap(err, "failed to register errors_total")
+	}
+	callFailovers = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "consensusclient",
+		Subsystem: "multi",
+		Name:      "failovers_total",
+		Help:      "Number of calls that moved on from a provider to the next",
+	}, []string{"provider", "call"})
+	if err := prometheus.Register(callFailovers); err != nil {
+		return errors.Wrap(err, "failed to register failovers_total")
+	}
+	callEmptyResponses = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "consensusclient",
+		Subsystem: "multi",
+		Name:      "empty_responses_total",
+		Help:      "Number of calls to a provider that returned an empty response",
+	}, []string{"provider", "call"})
+	if err := prometheus.Register(callEmptyResponses); err != nil {
+		return errors.Wrap(err, "failed to register empty_responses_total")
+	}
+	callDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "consensusclient",
+		Subsystem: "multi",
+		Name:      "request_duration_seconds",
+		Help:      "Time taken for a provider to respond to a call",
+		Buckets: []float64{
+			0.01, 0.02, 0.05,
+			0.1, 0.2, 0.5,
+			1.0, 2.0, 5.0,
+			10.0,
+		},
+	}, []string{"provider", "call"})
+	if err := prometheus.Register(callDuration); err != nil {
+		return errors.Wrap(err, "failed to register request_duration_seconds")
+	}
+	callsExhausted = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "consensusclient",
+		Subsystem: "multi",
+		Name:      "exhausted_total",
+		Help:      "Number of calls that failed on every provider",
+	}, []string{"call"})
+	if err := prometheus.Register(callsExhausted); err != nil {
+		return errors.Wrap(err, "failed to register exhausted_total")
+	}
+	sessionSwitches = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "consensusclient",
+		Subsystem: "multi",
+		Name:      "session_switches_total",
+		Help:      "Number of times a session moved away from a provider",
+	}, []string{"provider"})
+	if err := prometheus.Register(sessionSwitches); err != nil {
+		return errors.Wrap(err, "failed to register session_switches_total")
+	}
+	divergences = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "consensusclient",
+		Subsystem: "multi",
+		Name:      "divergences_total",
+		Help:      "Number of divergence checks in which providers disagreed",
+	}, []string{"check"})
+	if err := prometheus.Register(divergences); err != nil {
+		return errors.Wrap(err, "failed to register divergences_total")
+	}
+	divergentProviders = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "consensusclient",
+		Subsystem: "multi",
+		Name:      "divergent_providers_total",
+		Help:      "Number of divergence checks in which a provider disagreed with the majority",
+	}, []string{"check", "provider"})
+	if err := prometheus.Register(divergentProviders); err != nil {
+		return errors.Wrap(err, "failed to register divergent_providers_total")
+	}
+	breakerStateMetric = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "consensusclient",
+		Subsystem: "multi",
+		Name:      "circuit_breaker_state",
+		Help:      "State of the circuit breaker of provider (0 closed, 1 half-open, 2 open)",
+	}, []string{"provider"})
+	if err := prometheus.Register(breakerStateMetric); err != nil {
+		return errors.Wrap(err, "failed to register circuit_breaker_state")
+	}
+	breakerTransitions = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "consensusclient",
+		Subsystem: "multi",
+		Name:      "circuit_breaker_transitions_total",
+		Help:      "Number of times the circuit breaker of provider changed state",
+	}, []string{"provider", "state"})
+	if err := prometheus.Register(breakerTransitions); err != nil {
+		return errors.Wrap(err, "failed to register circuit_breaker_transitions_total")
+	}
 
 	return nil
 }
@@ -75,8 +232,122 @@ func setProviderActiveMetric(_ context.Context, provider string, state string) {
 	}
 }
 
+func removeProviderMetrics(_ context.Context, provider string) {
+	if providerActiveMetric != nil {
+		providerActiveMetric.DeleteLabelValues(provider)
+	}
+	if providerLatencyMetric != nil {
+		providerLatencyMetric.DeleteLabelValues(provider)
+	}
+	if providerErrorRateMetric != nil {
+		providerErrorRateMetric.DeleteLabelValues(provider)
+	}
+	if breakerStateMetric != nil {
+		breakerStateMetric.DeleteLabelValues(provider)
+	}
+	labels := prometheus.Labels{"provider": provider}
+	for _, metric := range []*prometheus.CounterVec{callRequests, callErrors, callFailovers, callEmptyResponses, sessionSwitches, divergentProviders, breakerTransitions} {
+		if metric != nil {
+			metric.DeletePartialMatch(labels)
+		}
+	}
+	if callDuration != nil {
+		callDuration.DeletePartialMatch(labels)
+	}
+	if quorumDissents != nil {
+		quorumDissents.DeletePartialMatch(prometheus.Labels{"address": provider})
+	}
+}
+
 func setProvidersMetric(_ context.Context, state string, count int) {
 	if providersMetric != nil {
 		providersMetric.WithLabelValues(state).Set(float64(count))
 	}
 }
+
+func setProviderHealthMetrics(_ context.Context, provider string, latency time.Duration, errorRate float64) {
+	if providerLatencyMetric != nil {
+		providerLatencyMetric.WithLabelValues(provider).Set(latency.Seconds())
+	}
+	if providerErrorRateMetric != nil {
+		providerErrorRateMetric.WithLabelValues(provider).Set(errorRate)
+	}
+}
+
+// monitorQuorumDisagreement records a quorum read in which clients disagreed.  The outcome
+// is "overruled" if a quorum was still reached, and "failed" if not.
+func monitorQuorumDisagreement(provider string, outcome string) {
+	if quorumDisagreements != nil {
+		quorumDisagreements.WithLabelValues(provider, outcome).Inc()
+	}
+}
+
+// monitorQuorumDissent records a client disagreeing with the quorum.
+func monitorQuorumDissent(provider string, address string) {
+	if quorumDissents != nil {
+		quorumDissents.WithLabelValues(provider, address).Inc()
+	}
+}
+
+// monitorCall records a call to a provider.
+func monitorCall(provider string, call string, duration time.Duration, failed bool) {
+	if callRequests != nil {
+		callRequests.WithLabelValues(provider, call).Inc()
+	}
+	if callDuration != nil {
+		callDuration.WithLabelValues(provider, call).Observe(duration.Seconds())
+	}
+	if failed && callErrors != nil {
+		callErrors.WithLabelValues(provider, call).Inc()
+	}
+}
+
+// monitorCallFailover records a call moving on from a provider to the next.
+func monitorCallFailover(provider string, call string) {
+	if callFailovers != nil {
+		callFailovers.WithLabelValues(provider, call).Inc()
+	}
+}
+
+// monitorCallEmptyResponse records a provider returning an empty response to a call.
+func monitorCallEmptyResponse(provider string, call string) {
+	if callEmptyResponses != nil {
+		callEmptyResponses.WithLabelValues(provider, call).Inc()
+	}
+}
+
+// monitorCallExhausted records a call that failed on every provider.
+func monitorCallExhausted(call string) {
+	if callsExhausted != nil {
+		callsExhausted.WithLabelValues(call).Inc()
+	}
+}
+
+// monitorSessionSwitch records a session moving away from a provider.
+func monitorSessionSwitch(provider string) {
+	if sessionSwitches != nil {
+		sessionSwitches.WithLabelValues(provider).Inc()
+	}
+}
+
+// monitorDivergence records a divergence check in which providers disagreed.
+func monitorDivergence(check string, minority []string) {
+	if divergences != nil {
+		divergences.WithLabelValues(check).Inc()
+	}
+	if divergentProviders != nil {
+		for _, provider := range minority {
+			divergentProviders.WithLabelValues(check, provider).Inc()
+		}
+	}
+}
+
+// setBreakerMetric records the change of state of the circuit breaker of a provider.
+func setBreakerMetric(provider string, state BreakerState) {
+	if breakerStateMetric != nil {
+		breakerStateMetric.WithLabelValues(provider).Set(float64(state))
+	}
+	if breakerTransitions != nil {
+		breakerTransitions.WithLabelValues(provider, state.String()).Inc()
+	}
+}
@@ -0,0 +1,109 @@
+// Copyright © 2023 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package multi
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+
+	consensusclient "github.com/attestantio/go-eth2-client"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/require"
+)
+
+// TestMain installs unregistered call metrics before any test runs, as services
+// started by tests monitor their clients in the background and so record metrics
+// for the lifetime of the test binary.
+func TestMain(m *testing.M) {
+	newCounter := func(labels ...string) *prometheus.CounterVec {
+		return prometheus.NewCounterVec(prometheus.CounterOpts{Name: "test"}, labels)
+	}
+	callRequests = newCounter("provider", "call")
+	callErrors = newCounter("provider", "call")
+	callFailovers = newCounter("provider", "call")
+	callEmptyResponses = newCounter("provider", "call")
+	callsExhausted = newCounter("call")
+	quorumDissents = newCounter("provider", "address")
+	callDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{Name: "test"}, []string{"provider", "call"})
+
+	os.Exit(m.Run())
+}
+
+func TestCallMetrics(t *testing.T) {
+	ctx := context.Background()
+	// Reset the metrics so that the test can be repeated with -count.
+	for _, vec := range []*prometheus.CounterVec{callRequests, callErrors, callFailovers, callEmptyResponses, callsExhausted} {
+		vec.Reset()
+	}
+	callDuration.Reset()
+	s := newOrderingService(ctx, t, WithOrdering(OrderingPriority), WithPriorities(map[string]int{"a": 1, "b": 2, "c": 3}))
+
+	// Client a errors, client b returns an empty response, client c succeeds.
+	call := func(_ context.Context, client consensusclient.Service) (interface{}, error) {
+		switch client.Address() {
+		case "a":
+			return nil, errors.New("failed")
+		case "b":
+			return nil, nil
+		default:
+			return client.Address(), nil
+		}
+	}
+	res, err := s.doCall(ctx, "TestCallMetrics", call, nil)
+	require.NoError(t, err)
+	require.Equal(t, "c", res)
+
+	for _, address := range []string{"a", "b", "c"} {
+		require.Equal(t, float64(1), testutil.ToFloat64(callRequests.WithLabelValues(address, "TestCallMetrics")), address)
+	}
+	require.Equal(t, float64(1), testutil.ToFloat64(callErrors.WithLabelValues("a", "TestCallMetrics")))
+	require.Equal(t, float64(0), testutil.ToFloat64(callErrors.WithLabelValues("b", "TestCallMetrics")))
+	require.Equal(t, float64(1), testutil.ToFloat64(callEmptyResponses.WithLabelValues("b", "TestCallMetrics")))
+	require.Equal(t, float64(1), testutil.ToFloat64(callFailovers.WithLabelValues("a", "TestCallMetrics")))
+	require.Equal(t, float64(1), testutil.ToFloat64(callFailovers.WithLabelValues("b", "TestCallMetrics")))
+	require.Equal(t, float64(0), testutil.ToFloat64(callFailovers.WithLabelValues("c", "TestCallMetrics")))
+	for _, address := range []string{"a", "b", "c"} {
+		metric := &dto.Metric{}
+		require.NoError(t, callDuration.WithLabelValues(address, "TestCallMetrics").(prometheus.Histogram).Write(metric))
+		require.Equal(t, uint64(1), metric.GetHistogram().GetSampleCount(), address)
+	}
+	require.Equal(t, float64(0), testutil.ToFloat64(callsExhausted.WithLabelValues("TestCallMetrics")))
+
+	// Client a has been deactivated; fail the call on the remaining clients.
+	_, err = s.doCall(ctx, "TestCallMetrics", func(_ context.Context, _ consensusclient.Service) (interface{}, error) {
+		return nil, nil
+	}, nil)
+	require.EqualError(t, err, "empty response")
+	require.Equal(t, float64(1), testutil.ToFloat64(callsExhausted.WithLabelValues("TestCallMetrics")))
+	require.Equal(t, float64(2), testutil.ToFloat64(callEmptyResponses.WithLabelValues("b", "TestCallMetrics")))
+}
+
+func TestRemoveProviderMetrics(t *testing.T) {
+	ctx := context.Background()
+	quorumDissents.Reset()
+
+	monitorQuorumDissent("Genesis", "a")
+	monitorQuorumDissent("Finality", "a")
+	monitorQuorumDissent("Genesis", "b")
+	monitorCall("a", "Genesis", 0, false)
+	removeProviderMetrics(ctx, "a")
+
+	require.Equal(t, 1, testutil.CollectAndCount(quorumDissents))
+	require.Equal(t, float64(1), testutil.ToFloat64(quorumDissents.WithLabelValues("Genesis", "b")))
+	require.Zero(t, testutil.ToFloat64(callRequests.WithLabelValues("a", "Genesis")))
+}
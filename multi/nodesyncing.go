@@ -23,7 +23,7 @@ import (
 
 // NodeSyncing provides the syncing information for the node.
 func (s *Service) NodeSyncing(ctx context.Context) (*api.Response[*apiv1.SyncState], error) {
-	res, err := s.doCall(ctx, func(ctx context.Context, client consensusclient.Service) (interface{}, error) {
+	res, err := s.doCall(ctx, "NodeSyncing", func(ctx context.Context, client consensusclient.Service) (interface{}, error) {
 		nodeSyncing, err := client.(consensusclient.NodeSyncingProvider).NodeSyncing(ctx)
 		if err != nil {
 			return nil, err
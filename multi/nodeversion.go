@@ -22,7 +22,7 @@ import (
 
 // NodeVersion provides the version information of the node.
 func (s *Service) NodeVersion(ctx context.Context) (*api.Response[string], error) {
-	res, err := s.doCall(ctx, func(ctx context.Context, client consensusclient.Service) (interface{}, error) {
+	res, err := s.doCall(ctx, "NodeVersion", func(ctx context.Context, client consensusclient.Service) (interface{}, error) {
 		aggregate, err := client.(consensusclient.NodeVersionProvider).NodeVersion(ctx)
 		if err != nil {
 			return nil, err
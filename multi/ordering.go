@@ -0,0 +1,161 @@
+// Copyright © 2023 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package multi
+
+import (
+	"math"
+	"sort"
+	"sync"
+
+	consensusclient "github.com/attestantio/go-eth2-client"
+)
+
+// Ordering is a strategy for ordering the active clients when making a call.
+type Ordering int
+
+const (
+	// OrderingActive uses the active clients in the order in which they became active,
+	// so a client that fails moves behind the others.  This is the default.
+	OrderingActive Ordering = iota
+	// OrderingPriority uses the active clients in the order of their priorities.
+	OrderingPriority
+	// OrderingLatency uses the active clients in order of their rolling average latency,
+	// lowest first.  Clients without latency samples follow those with samples.  Bulk calls
+	// do not contribute to the rolling average latency.
+	OrderingLatency
+	// OrderingWeightedRoundRobin spreads calls across the active clients in proportion to
+	// their weights.  It is intended for bulk calls, which are not time-critical.
+	OrderingWeightedRoundRobin
+)
+
+var orderingStrings = [...]string{
+	"active",
+	"priority",
+	"latency",
+	"weighted round robin",
+}
+
+// String returns a string representation of the ordering.
+func (o Ordering) String() string {
+	if o < 0 || int(o) >= len(orderingStrings) {
+		return "unknown"
+	}
+
+	return orderingStrings[o]
+}
+
+// roundRobin carries out smooth weighted round-robin selection between clients.
+type roundRobin struct {
+	mu      sync.Mutex
+	weights map[string]int
+	current map[string]int
+}
+
+func newRoundRobin(weights map[string]int) *roundRobin {
+	return &roundRobin{
+		weights: weights,
+		current: make(map[string]int),
+	}
+}
+
+// weight provides the weight of a client; clients without a weight have a weight of 1.
+func (r *roundRobin) weight(client consensusclient.Service) int {
+	if weight, exists := r.weights[client.Address()]; exists {
+		return weight
+	}
+
+	return 1
+}
+
+// next provides the index of the next client to use.
+func (r *roundRobin) next(clients []consensusclient.Service) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Drop state for clients that are no longer present, so that it does not grow as clients
+	// are removed, and a client that returns starts afresh.
+	present := make(map[string]bool, len(clients))
+	for _, client := range clients {
+		present[client.Address()] = true
+	}
+	for address := range r.current {
+		if !present[address] {
+			delete(r.current, address)
+		}
+	}
+
+	selected := 0
+	total := 0
+	for i, client := range clients {
+		weight := r.weight(client)
+		total += weight
+		r.current[client.Address()] += weight
+		if r.current[client.Address()] > r.current[clients[selected].Address()] {
+			selected = i
+		}
+	}
+	r.current[clients[selected].Address()] -= total
+
+	return selected
+}
+
+// orderClients orders the clients according to the given strategy.  The supplied slice is not altered.
+func (s *Service) orderClients(clients []consensusclient.Service, ordering Ordering) []consensusclient.Service {
+	if len(clients) < 2 {
+		return clients
+	}
+
+	ordered := make([]consensusclient.Service, len(clients))
+	copy(ordered, clients)
+
+	switch ordering {
+	case OrderingPriority:
+		sort.SliceStable(ordered, func(i, j int) bool {
+			return s.priority(ordered[i]) < s.priority(ordered[j])
+		})
+	case OrderingLatency:
+		// Clients without latency samples, for example those recently added, follow
+		// those with samples rather than appearing to be the fastest.
+		latencies := make(map[consensusclient.Service]int64, len(ordered))
+		for _, client := range ordered {
+			latency, sampled := s.clientHealth(client).averageLatency()
+			if !sampled {
+				latencies[client] = math.MaxInt64
+
+				continue
+			}
+			latencies[client] = int64(latency)
+		}
+		sort.SliceStable(ordered, func(i, j int) bool {
+			return latencies[ordered[i]] < latencies[ordered[j]]
+		})
+	case OrderingWeightedRoundRobin:
+		// Start with the selected client, and follow with the rest for failover.
+		selected := s.roundRobin.next(ordered)
+		ordered = append(ordered[selected:], ordered[:selected]...)
+	default:
+		// Leave in active order.
+	}
+
+	return ordered
+}
+
+// priority provides the priority of a client; clients without a priority come after those with one.
+func (s *Service) priority(client consensusclient.Service) int {
+	if priority, exists := s.priorities[client.Address()]; exists {
+		return priority
+	}
+
+	return int(^uint(0) >> 1)
+}
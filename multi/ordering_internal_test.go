@@ -0,0 +1,192 @@
+// Copyright © 2023 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package multi
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	consensusclient "github.com/attestantio/go-eth2-client"
+	"github.com/attestantio/go-eth2-client/mock"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+func newOrderingService(ctx context.Context, t *testing.T, params ...Parameter) *Service {
+	t.Helper()
+
+	clients := make([]consensusclient.Service, 0, 3)
+	for _, name := range []string{"a", "b", "c"} {
+		client, err := mock.New(ctx, mock.WithName(name))
+		require.NoError(t, err)
+		clients = append(clients, client)
+	}
+	params = append([]Parameter{
+		WithLogLevel(zerolog.Disabled),
+		WithClients(clients),
+	}, params...)
+	s, err := New(ctx, params...)
+	require.NoError(t, err)
+
+	return s.(*Service)
+}
+
+// firstAddress provides the address of the client used for a call.
+func firstAddress(ctx context.Context, t *testing.T, s *Service, bulk bool) string {
+	t.Helper()
+
+	call := func(_ context.Context, client consensusclient.Service) (interface{}, error) {
+		return client.Address(), nil
+	}
+	var res interface{}
+	var err error
+	if bulk {
+		res, err = s.doBulkCall(ctx, "Test", call, nil)
+	} else {
+		res, err = s.doCall(ctx, "Test", call, nil)
+	}
+	require.NoError(t, err)
+
+	return res.(string)
+}
+
+func TestOrderingParameters(t *testing.T) {
+	ctx := context.Background()
+
+	client, err := mock.New(ctx)
+	require.NoError(t, err)
+
+	_, err = New(ctx,
+		WithClients([]consensusclient.Service{client}),
+		WithOrdering(Ordering(99)),
+	)
+	require.EqualError(t, err, "problem with parameters: unknown ordering")
+
+	_, err = New(ctx,
+		WithClients([]consensusclient.Service{client}),
+		WithBulkOrdering(Ordering(-1)),
+	)
+	require.EqualError(t, err, "problem with parameters: unknown ordering")
+
+	_, err = New(ctx,
+		WithClients([]consensusclient.Service{client}),
+		WithWeights(map[string]int{"a": 0}),
+	)
+	require.EqualError(t, err, "problem with parameters: weight for a must be positive")
+}
+
+func TestOrderingActive(t *testing.T) {
+	ctx := context.Background()
+
+	s := newOrderingService(ctx, t)
+	require.Equal(t, "a", firstAddress(ctx, t, s, false))
+	require.Equal(t, "a", firstAddress(ctx, t, s, true))
+}
+
+func TestOrderingPriority(t *testing.T) {
+	ctx := context.Background()
+
+	s := newOrderingService(ctx, t,
+		WithOrdering(OrderingPriority),
+		WithPriorities(map[string]int{"b": 2, "c": 1}),
+	)
+	require.Equal(t, []string{"c", "b", "a"}, addresses(s.orderClients(s.activeClients, OrderingPriority)))
+	require.Equal(t, "c", firstAddress(ctx, t, s, false))
+
+	// Priority is retained through deactivation and reactivation.
+	s.deactivateClient(ctx, s.activeClients[2])
+	s.activateClient(ctx, s.inactiveClients[0])
+	require.Equal(t, "c", firstAddress(ctx, t, s, false))
+}
+
+func TestOrderingLatency(t *testing.T) {
+	ctx := context.Background()
+
+	s := newOrderingService(ctx, t, WithOrdering(OrderingLatency))
+	for _, client := range s.activeClients {
+		switch client.Address() {
+		case "a":
+			s.recordCall(ctx, client, "Test", time.Now().Add(-300*time.Millisecond), nil)
+		case "b":
+			s.recordCall(ctx, client, "Test", time.Now().Add(-100*time.Millisecond), nil)
+		case "c":
+			s.recordCall(ctx, client, "Test", time.Now().Add(-200*time.Millisecond), nil)
+		}
+	}
+	require.Equal(t, []string{"b", "c", "a"}, addresses(s.orderClients(s.activeClients, OrderingLatency)))
+}
+
+func TestOrderingLatencyUnsampled(t *testing.T) {
+	ctx := context.Background()
+
+	s := newOrderingService(ctx, t, WithOrdering(OrderingLatency))
+	for _, client := range s.activeClients {
+		switch client.Address() {
+		case "b":
+			s.recordCall(ctx, client, "Test", time.Now().Add(-300*time.Millisecond), nil)
+		case "c":
+			s.recordCall(ctx, client, "Test", time.Now().Add(-100*time.Millisecond), nil)
+		}
+	}
+	// a has no latency samples so follows the sampled clients.
+	require.Equal(t, []string{"c", "b", "a"}, addresses(s.orderClients(s.activeClients, OrderingLatency)))
+}
+
+func TestOrderingWeightedRoundRobin(t *testing.T) {
+	ctx := context.Background()
+
+	s := newOrderingService(ctx, t,
+		WithBulkOrdering(OrderingWeightedRoundRobin),
+		WithWeights(map[string]int{"a": 3}),
+	)
+
+	counts := make(map[string]int)
+	for i := 0; i < 50; i++ {
+		counts[firstAddress(ctx, t, s, true)]++
+	}
+	require.Equal(t, map[string]int{"a": 30, "b": 10, "c": 10}, counts)
+
+	// Non-bulk calls are unaffected.
+	for i := 0; i < 5; i++ {
+		require.Equal(t, "a", firstAddress(ctx, t, s, false))
+	}
+
+	// The remaining clients follow the selected client, for failover.
+	ordered := s.orderClients(s.activeClients, OrderingWeightedRoundRobin)
+	require.Len(t, ordered, 3)
+	require.ElementsMatch(t, []string{"a", "b", "c"}, addresses(ordered))
+}
+
+func TestRoundRobinPrune(t *testing.T) {
+	ctx := context.Background()
+
+	s := newOrderingService(ctx, t)
+	r := newRoundRobin(nil)
+
+	r.next(s.activeClients)
+	require.Len(t, r.current, 3)
+
+	// Entries for clients that are no longer present are removed.
+	r.next(s.activeClients[1:])
+	require.Len(t, r.current, 2)
+	_, exists := r.current["a"]
+	require.False(t, exists)
+}
+
+func TestOrderingString(t *testing.T) {
+	require.Equal(t, "active", OrderingActive.String())
+	require.Equal(t, "weighted round robin", OrderingWeightedRoundRobin.String())
+	require.Equal(t, "unknown", Ordering(99).String())
+}
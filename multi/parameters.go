@@ -14,22 +14,51 @@
 package multi
 
 import (
+	"fmt"
 	"time"
 
 	consensusclient "github.com/attestantio/go-eth2-client"
+	"github.com/attestantio/go-eth2-client/http"
 	"github.com/attestantio/go-eth2-client/metrics"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
 	"github.com/pkg/errors"
 	"github.com/rs/zerolog"
 )
 
 type parameters struct {
-	logLevel     zerolog.Level
-	monitor      metrics.Service
-	clients      []consensusclient.Service
-	addresses    []string
-	timeout      time.Duration
-	extraHeaders map[string]string
-	enforceJSON  bool
+	logLevel                   zerolog.Level
+	monitor                    metrics.Service
+	clients                    []consensusclient.Service
+	addresses                  []string
+	timeout                    time.Duration
+	endpointTimeouts           map[string]time.Duration
+	extraHeaders               map[string]string
+	enforceJSON                bool
+	compression                bool
+	limits                     http.Limits
+	classLimits                map[http.EndpointClass]http.Limits
+	dialContext                http.DialContextFunc
+	recheckInterval            time.Duration
+	maxHeadLag                 phase0.Slot
+	maxLatency                 time.Duration
+	maxErrorRate               float64
+	ordering                   Ordering
+	bulkOrdering               *Ordering
+	priorities                 map[string]int
+	weights                    map[string]int
+	quorums                    map[string]Quorum
+	circuitBreaker             *CircuitBreaker
+	errorClassifiers           []ErrorClassifier
+	mergedEvents               bool
+	divergenceInterval         time.Duration
+	divergenceHandler          DivergenceHandlerFunc
+	quarantine                 bool
+	broadcastThreshold         int
+	broadcastHandler           BroadcastHandlerFunc
+	bestProposalTimeout        time.Duration
+	proposalScorer             ProposalScorer
+	bestAttestationDataTimeout time.Duration
+	attestationDataScorer      AttestationDataScorer
 }
 
 // Parameter is the interface for service parameters.
@@ -57,6 +86,15 @@ func WithTimeout(timeout time.Duration) Parameter {
 	})
 }
 
+// WithEndpointTimeouts sets the timeouts for requests to specific endpoints, overriding the timeout
+// set by WithTimeout.  Endpoints are referenced by the name of their provider function, for example
+// "AttestationData" or "BeaconState".
+func WithEndpointTimeouts(timeouts map[string]time.Duration) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.endpointTimeouts = timeouts
+	})
+}
+
 // WithMonitor sets the monitor for the service.
 func WithMonitor(monitor metrics.Service) Parameter {
 	return parameterFunc(func(p *parameters) {
@@ -92,12 +130,227 @@ func WithExtraHeaders(headers map[string]string) Parameter {
 	})
 }
 
+// WithCompression requests compressed responses from the beacon nodes created from addresses.
+func WithCompression(compression bool) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.compression = compression
+	})
+}
+
+// WithLimits sets rate and concurrency limits for each of the beacon nodes created from addresses.
+func WithLimits(limits http.Limits) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.limits = limits
+	})
+}
+
+// WithEndpointClassLimits sets rate and concurrency limits for specific classes of endpoint
+// for each of the beacon nodes created from addresses.
+func WithEndpointClassLimits(limits map[http.EndpointClass]http.Limits) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.classLimits = limits
+	})
+}
+
+// WithDialContext sets a custom function to create connections to the beacon nodes created from addresses.
+func WithDialContext(dialContext http.DialContextFunc) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.dialContext = dialContext
+	})
+}
+
+// WithRecheckInterval sets the interval between checks of the health of the clients.
+func WithRecheckInterval(interval time.Duration) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.recheckInterval = interval
+	})
+}
+
+// WithMaxHeadLag demotes clients whose head slot is more than the given number of slots
+// behind the median head slot of all clients.  A value of 0 disables the check.
+func WithMaxHeadLag(slots phase0.Slot) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.maxHeadLag = slots
+	})
+}
+
+// WithMaxLatency demotes clients whose rolling average latency exceeds the given duration.
+// A value of 0 disables the check.
+func WithMaxLatency(latency time.Duration) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.maxLatency = latency
+	})
+}
+
+// WithMaxErrorRate demotes clients whose rolling average error rate, between 0 and 1,
+// exceeds the given rate.  A value of 0 disables the check.
+func WithMaxErrorRate(rate float64) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.maxErrorRate = rate
+	})
+}
+
+// WithOrdering sets the order in which active clients are used for calls.
+// This defaults to OrderingActive.
+func WithOrdering(ordering Ordering) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.ordering = ordering
+	})
+}
+
+// WithBulkOrdering sets the order in which active clients are used for calls that return
+// large amounts of data, such as Validators and BeaconState, allowing their load to be spread
+// with OrderingWeightedRoundRobin.  This defaults to the ordering set by WithOrdering.
+func WithBulkOrdering(ordering Ordering) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.bulkOrdering = &ordering
+	})
+}
+
+// WithPriorities sets the priorities of clients, keyed by address, for OrderingPriority.
+// Clients with lower values are used first; clients without a priority are used last.
+func WithPriorities(priorities map[string]int) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.priorities = priorities
+	})
+}
+
+// WithWeights sets the weights of clients, keyed by address, for OrderingWeightedRoundRobin.
+// Clients without a weight have a weight of 1.
+func WithWeights(weights map[string]int) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.weights = weights
+	})
+}
+
+// WithQuorum requires calls to the given provider, for example "AttestationData", to be
+// answered by a quorum of clients.  Quorum reads are available for AttestationData,
+// BeaconBlockRoot, Finality, Fork and Genesis.
+func WithQuorum(provider string, quorum Quorum) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.quorums[provider] = quorum
+	})
+}
+
+// WithBroadcast sends submissions to all active clients in parallel rather than to the
+// first that accepts them, succeeding if at least threshold clients accept.  Clients that
+// return an error classified as ErrorActionReturn, such as for a duplicate message, are
+// considered to accept the submission.  A threshold of 0 disables broadcast; it cannot
+// exceed the number of clients supplied.
+func WithBroadcast(threshold int) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.broadcastThreshold = threshold
+	})
+}
+
+// WithCircuitBreaker enables per-client circuit breakers.  A client whose breaker opens
+// is deactivated, and is only reactivated once it passes probe calls to real endpoints
+// after the open duration.  Without circuit breakers a client is deactivated on its
+// first failed call.
+func WithCircuitBreaker(breaker CircuitBreaker) Parameter {
+	return parameterFunc(func(p *parameters) {
+		breaker = breaker.withDefaults()
+		p.circuitBreaker = &breaker
+	})
+}
+
+// WithErrorClassifiers adds classifiers that decide whether an error returned by a client
+// results in a retry, a failover or the error being returned.  Classifiers are consulted in
+// order, before BuiltinErrorRules.
+func WithErrorClassifiers(classifiers ...ErrorClassifier) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.errorClassifiers = append(p.errorClassifiers, classifiers...)
+	})
+}
+
+// WithMergedEvents merges the event streams of all active clients, de-duplicating events
+// received from more than one client, rather than forwarding events from only the primary
+// active client.  Subscriptions follow the active clients as they change.
+// Events are passed to the handler one at a time, so a slow handler delays all events.
+func WithMergedEvents(merged bool) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.mergedEvents = merged
+	})
+}
+
+// WithDivergenceCheck periodically compares finality, genesis, fork schedule and block roots
+// across clients, reporting any divergence.  An interval of 0 disables the check.
+func WithDivergenceCheck(interval time.Duration) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.divergenceInterval = interval
+	})
+}
+
+// WithDivergenceHandler sets a function that is called with each divergence found.
+func WithDivergenceHandler(handler DivergenceHandlerFunc) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.divergenceHandler = handler
+	})
+}
+
+// WithQuarantine deactivates clients that disagree with the majority when a divergence is
+// found, keeping them inactive until they agree again.
+func WithQuarantine(quarantine bool) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.quarantine = quarantine
+	})
+}
+
+// WithBroadcastHandler sets a function that is called with the per-client results of
+// each broadcast submission.
+func WithBroadcastHandler(handler BroadcastHandlerFunc) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.broadcastHandler = handler
+	})
+}
+
+// WithBestProposal requests proposals from all active clients in parallel, selecting the
+// highest scoring proposal received within the timeout.  A timeout of 0 disables this, in
+// which case the proposal is obtained from the first client that provides one.
+func WithBestProposal(timeout time.Duration) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.bestProposalTimeout = timeout
+	})
+}
+
+// WithProposalScorer sets the function used to score proposals when selecting the best
+// proposal.  This defaults to ValueProposalScorer.
+func WithProposalScorer(scorer ProposalScorer) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.proposalScorer = scorer
+	})
+}
+
+// WithBestAttestationData requests attestation data from all active clients in parallel,
+// selecting the highest scoring data received within the timeout.  Data with a target
+// epoch that does not match the epoch of the requested slot is ignored.  A timeout of 0
+// disables this, in which case the data is obtained from the first client that provides it.
+// This cannot be combined with a quorum for AttestationData.
+func WithBestAttestationData(timeout time.Duration) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.bestAttestationDataTimeout = timeout
+	})
+}
+
+// WithAttestationDataScorer sets the function used to score attestation data when selecting
+// the best attestation data.  This defaults to HeadAttestationDataScorer.
+func WithAttestationDataScorer(scorer AttestationDataScorer) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.attestationDataScorer = scorer
+	})
+}
+
 // parseAndCheckParameters parses and checks parameters to ensure that mandatory parameters are present and correct.
 func parseAndCheckParameters(params ...Parameter) (*parameters, error) {
 	parameters := parameters{
-		logLevel:     zerolog.GlobalLevel(),
-		timeout:      2 * time.Second,
-		extraHeaders: make(map[string]string),
+		logLevel:              zerolog.GlobalLevel(),
+		timeout:               2 * time.Second,
+		extraHeaders:          make(map[string]string),
+		endpointTimeouts:      make(map[string]time.Duration),
+		recheckInterval:       30 * time.Second,
+		quorums:               make(map[string]Quorum),
+		proposalScorer:        ValueProposalScorer,
+		attestationDataScorer: HeadAttestationDataScorer,
 	}
 	for _, p := range params {
 		if params != nil {
@@ -111,6 +364,84 @@ func parseAndCheckParameters(params ...Parameter) (*parameters, error) {
 	if len(parameters.clients)+len(parameters.addresses) == 0 {
 		return nil, errors.New("no Ethereum 2 clients specified")
 	}
+	if err := checkOrdering(parameters.ordering); err != nil {
+		return nil, err
+	}
+	if parameters.bulkOrdering == nil {
+		parameters.bulkOrdering = &parameters.ordering
+	}
+	if err := checkOrdering(*parameters.bulkOrdering); err != nil {
+		return nil, err
+	}
+	for address, weight := range parameters.weights {
+		if weight <= 0 {
+			return nil, fmt.Errorf("weight for %s must be positive", address)
+		}
+	}
+	if parameters.circuitBreaker != nil {
+		if parameters.circuitBreaker.Window < 0 || parameters.circuitBreaker.MinCalls < 0 || parameters.circuitBreaker.HalfOpenCalls < 0 {
+			return nil, errors.New("circuit breaker counts cannot be negative")
+		}
+		if parameters.circuitBreaker.FailureRatio < 0 || parameters.circuitBreaker.FailureRatio > 1 {
+			return nil, errors.New("circuit breaker failure ratio must be between 0 and 1")
+		}
+		if parameters.circuitBreaker.OpenDuration < 0 {
+			return nil, errors.New("circuit breaker open duration cannot be negative")
+		}
+	}
+	if parameters.divergenceInterval < 0 {
+		return nil, errors.New("divergence check interval cannot be negative")
+	}
+	if parameters.recheckInterval <= 0 {
+		return nil, errors.New("recheck interval must be positive")
+	}
+	if parameters.maxLatency < 0 {
+		return nil, errors.New("maximum latency cannot be negative")
+	}
+	if parameters.maxErrorRate < 0 || parameters.maxErrorRate > 1 {
+		return nil, errors.New("maximum error rate must be between 0 and 1")
+	}
+	if parameters.bestProposalTimeout < 0 {
+		return nil, errors.New("best proposal timeout cannot be negative")
+	}
+	if parameters.proposalScorer == nil {
+		return nil, errors.New("no proposal scorer specified")
+	}
+	if parameters.bestAttestationDataTimeout < 0 {
+		return nil, errors.New("best attestation data timeout cannot be negative")
+	}
+	if _, exists := parameters.quorums["AttestationData"]; exists && parameters.bestAttestationDataTimeout > 0 {
+		return nil, errors.New("best attestation data and an attestation data quorum cannot both be specified")
+	}
+	if parameters.attestationDataScorer == nil {
+		return nil, errors.New("no attestation data scorer specified")
+	}
+	if parameters.broadcastThreshold < 0 {
+		return nil, errors.New("broadcast threshold cannot be negative")
+	}
+	if parameters.broadcastThreshold > len(parameters.clients)+len(parameters.addresses) {
+		return nil, errors.New("broadcast threshold exceeds number of clients")
+	}
+	for provider, quorum := range parameters.quorums {
+		if !quorumProviders[provider] {
+			return nil, fmt.Errorf("quorum not available for %s", provider)
+		}
+		if quorum.Clients < 0 || quorum.Threshold < 0 {
+			return nil, fmt.Errorf("quorum for %s cannot be negative", provider)
+		}
+		if quorum.Clients != 0 && quorum.Threshold > quorum.Clients {
+			return nil, fmt.Errorf("quorum threshold for %s exceeds clients queried", provider)
+		}
+	}
 
 	return &parameters, nil
 }
+
+func checkOrdering(ordering Ordering) error {
+	switch ordering {
+	case OrderingActive, OrderingPriority, OrderingLatency, OrderingWeightedRoundRobin:
+		return nil
+	default:
+		return errors.New("unknown ordering")
+	}
+}
@@ -27,7 +27,13 @@ func (s *Service) Proposal(ctx context.Context,
 	*api.Response[*api.VersionedProposal],
 	error,
 ) {
-	res, err := s.doCall(ctx, func(ctx context.Context, client consensusclient.Service) (interface{}, error) {
+	if s.bestProposalEnabled(ctx) {
+		return doBestProposal(ctx, s, "Proposal", func(ctx context.Context, client consensusclient.Service) (*api.Response[*api.VersionedProposal], error) {
+			return client.(consensusclient.ProposalProvider).Proposal(ctx, opts)
+		})
+	}
+
+	res, err := s.doCall(ctx, "Proposal", func(ctx context.Context, client consensusclient.Service) (interface{}, error) {
 		block, err := client.(consensusclient.ProposalProvider).Proposal(ctx, opts)
 		if err != nil {
 			return nil, err
@@ -29,7 +29,7 @@ func (s *Service) ProposerDuties(ctx context.Context,
 	*api.Response[[]*apiv1.ProposerDuty],
 	error,
 ) {
-	res, err := s.doCall(ctx, func(ctx context.Context, client consensusclient.Service) (interface{}, error) {
+	res, err := s.doCall(ctx, "ProposerDuties", func(ctx context.Context, client consensusclient.Service) (interface{}, error) {
 		block, err := client.(consensusclient.ProposerDutiesProvider).ProposerDuties(ctx, opts)
 		if err != nil {
 			return nil, err
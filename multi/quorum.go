@@ -0,0 +1,309 @@
+// Copyright © 2023 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package multi
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	consensusclient "github.com/attestantio/go-eth2-client"
+	"github.com/attestantio/go-eth2-client/api"
+	apiv1 "github.com/attestantio/go-eth2-client/api/v1"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	ssz "github.com/ferranbt/fastssz"
+	"github.com/pkg/errors"
+)
+
+// Quorum configures quorum reads for a provider.  With a quorum in place a call
+// is sent to multiple active clients in parallel, and a response is only returned
+// if enough of them agree on its hash tree root.
+type Quorum struct {
+	// Clients is the number of active clients to query.  If 0, all active clients are queried.
+	Clients int
+	// Threshold is the number of matching responses required.  If 0, a majority of the
+	// clients queried is required.
+	Threshold int
+}
+
+// quorumProviders are the providers for which quorum reads are available.
+var quorumProviders = map[string]bool{
+	"AttestationData": true,
+	"BeaconBlockRoot": true,
+	"Finality":        true,
+	"Fork":            true,
+	"Genesis":         true,
+}
+
+// DisagreementError is returned when the clients queried for a quorum read
+// do not provide enough matching responses.
+type DisagreementError struct {
+	// Provider is the provider that was called.
+	Provider string
+	// Threshold is the number of matching responses that was required.
+	Threshold int
+	// Votes are the addresses of the clients that returned each distinct response,
+	// keyed by the hash tree root of the response.
+	Votes map[phase0.Root][]string
+}
+
+// Error implements error.
+func (e *DisagreementError) Error() string {
+	best := 0
+	for _, addresses := range e.Votes {
+		if len(addresses) > best {
+			best = len(addresses)
+		}
+	}
+
+	return fmt.Sprintf("no quorum for %s: %d distinct responses, at most %d matching of %d required",
+		e.Provider, len(e.Votes), best, e.Threshold)
+}
+
+// quorumVote is a single client's response to a quorum call.
+type quorumVote[T any] struct {
+	address  string
+	response *api.Response[T]
+	root     phase0.Root
+}
+
+// doQuorumCall carries out a call on multiple active clients in parallel, returning the
+// response on which a quorum of them agree.
+func doQuorumCall[T any](ctx context.Context,
+	s *Service,
+	provider string,
+	quorum Quorum,
+	call func(ctx context.Context, client consensusclient.Service) (*api.Response[T], error),
+) (
+	*api.Response[T],
+	error,
+) {
+	log := s.log.With().Str("provider", provider).Logger()
+	ctx = log.WithContext(ctx)
+
+	activeClients := s.orderClients(s.dutyClients(provider, s.currentActiveClients(ctx)), s.ordering)
+	if len(activeClients) == 0 {
+		return nil, errors.New("no active clients to which to make call")
+	}
+	clients := quorum.Clients
+	if clients == 0 || clients > len(activeClients) {
+		clients = len(activeClients)
+	}
+	threshold := quorum.Threshold
+	if threshold == 0 {
+		threshold = quorum.Clients
+		if threshold == 0 {
+			threshold = clients
+		}
+		threshold = threshold/2 + 1
+	}
+	if clients < threshold {
+		return nil, fmt.Errorf("insufficient active clients for quorum: %d available, %d required", clients, threshold)
+	}
+
+	votes := make([]*quorumVote[T], 0, clients)
+	var votesMu sync.Mutex
+	var wg sync.WaitGroup
+	for _, client := range activeClients[:clients] {
+		wg.Add(1)
+		go func(client consensusclient.Service) {
+			defer wg.Done()
+			started := time.Now()
+			response, err := call(ctx, client)
+			s.recordCall(ctx, client, provider, started, err)
+			if err != nil {
+				if !errors.Is(err, api.ErrNotSupported) {
+					log.Debug().Str("client", client.Name()).Str("address", client.Address()).Err(err).Msg("Call failed on client")
+					s.callFailed(ctx, client)
+				}
+
+				return
+			}
+			if response == nil {
+				return
+			}
+			root, err := quorumRoot(response.Data)
+			if err != nil {
+				log.Warn().Str("address", client.Address()).Err(err).Msg("Failed to obtain root of response")
+
+				return
+			}
+			votesMu.Lock()
+			votes = append(votes, &quorumVote[T]{
+				address:  client.Address(),
+				response: response,
+				root:     root,
+			})
+			votesMu.Unlock()
+		}(client)
+	}
+	wg.Wait()
+
+	tally := make(map[phase0.Root][]string)
+	for _, vote := range votes {
+		tally[vote.root] = append(tally[vote.root], vote.address)
+	}
+	// Ties are broken by choosing the lowest root, so that the result does not depend on
+	// map iteration order.
+	var winner phase0.Root
+	winnerVotes := 0
+	for root, addresses := range tally {
+		if len(addresses) > winnerVotes ||
+			(len(addresses) == winnerVotes && bytes.Compare(root[:], winner[:]) < 0) {
+			winner = root
+			winnerVotes = len(addresses)
+		}
+	}
+
+	if len(tally[winner]) < threshold {
+		if len(tally) > 1 {
+			monitorQuorumDisagreement(provider, "failed")
+			for _, addresses := range tally {
+				sort.Strings(addresses)
+			}
+
+			return nil, &DisagreementError{
+				Provider:  provider,
+				Threshold: threshold,
+				Votes:     tally,
+			}
+		}
+
+		return nil, fmt.Errorf("insufficient responses for quorum: %d received, %d required", len(votes), threshold)
+	}
+
+	if len(tally) > 1 {
+		monitorQuorumDisagreement(provider, "overruled")
+		for root, addresses := range tally {
+			if root == winner {
+				continue
+			}
+			for _, address := range addresses {
+				log.Warn().Str("address", address).Msg("Client response disagrees with quorum")
+				monitorQuorumDissent(provider, address)
+			}
+		}
+	}
+
+	for _, vote := range votes {
+		if vote.root == winner {
+			return vote.response, nil
+		}
+	}
+
+	// Unreachable, as the winner has at least one vote.
+	return nil, errors.New("no response for quorum")
+}
+
+// quorumRoot provides the root used to compare responses for quorum reads.
+func quorumRoot(data any) (phase0.Root, error) {
+	switch v := data.(type) {
+	case *phase0.Root:
+		if v == nil {
+			return phase0.Root{}, errors.New("no root")
+		}
+
+		return *v, nil
+	case *apiv1.Finality:
+		return hashWith(&finalityRooter{finality: v})
+	case *apiv1.Genesis:
+		return hashWith(&genesisRooter{genesis: v})
+	case []*phase0.Fork:
+		return hashWith(&forkScheduleRooter{forks: v})
+	case interface{ HashTreeRoot() ([32]byte, error) }:
+		return v.HashTreeRoot()
+	default:
+		return phase0.Root{}, fmt.Errorf("unhandled type %T", data)
+	}
+}
+
+// hashWith provides the hash tree root of a container that cannot generate it directly.
+func hashWith(container interface{ HashTreeRootWith(hh ssz.HashWalker) error }) (phase0.Root, error) {
+	hh := ssz.DefaultHasherPool.Get()
+	defer ssz.DefaultHasherPool.Put(hh)
+	if err := container.HashTreeRootWith(hh); err != nil {
+		return phase0.Root{}, err
+	}
+
+	return hh.HashRoot()
+}
+
+// finalityRooter hashes finality as a container of its checkpoints.
+type finalityRooter struct {
+	finality *apiv1.Finality
+}
+
+func (r *finalityRooter) HashTreeRootWith(hh ssz.HashWalker) error {
+	if r.finality == nil {
+		return errors.New("no finality")
+	}
+	indx := hh.Index()
+	for _, checkpoint := range []*phase0.Checkpoint{
+		r.finality.Finalized,
+		r.finality.Justified,
+		r.finality.PreviousJustified,
+	} {
+		if checkpoint == nil {
+			checkpoint = &phase0.Checkpoint{}
+		}
+		if err := checkpoint.HashTreeRootWith(hh); err != nil {
+			return err
+		}
+	}
+	hh.Merkleize(indx)
+
+	return nil
+}
+
+// genesisRooter hashes genesis as a container of its fields.
+type genesisRooter struct {
+	genesis *apiv1.Genesis
+}
+
+func (r *genesisRooter) HashTreeRootWith(hh ssz.HashWalker) error {
+	if r.genesis == nil {
+		return errors.New("no genesis")
+	}
+	indx := hh.Index()
+	hh.PutUint64(uint64(r.genesis.GenesisTime.Unix()))
+	hh.PutBytes(r.genesis.GenesisValidatorsRoot[:])
+	hh.PutBytes(r.genesis.GenesisForkVersion[:])
+	hh.Merkleize(indx)
+
+	return nil
+}
+
+// forkScheduleRooter hashes a fork schedule as a list of forks.
+type forkScheduleRooter struct {
+	forks []*phase0.Fork
+}
+
+func (r *forkScheduleRooter) HashTreeRootWith(hh ssz.HashWalker) error {
+	indx := hh.Index()
+	for _, fork := range r.forks {
+		if fork == nil {
+			return errors.New("nil fork")
+		}
+		if err := fork.HashTreeRootWith(hh); err != nil {
+			return err
+		}
+	}
+	num := uint64(len(r.forks))
+	hh.MerkleizeWithMixin(indx, num, num)
+
+	return nil
+}
@@ -0,0 +1,254 @@
+// Copyright © 2023 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package multi_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	consensusclient "github.com/attestantio/go-eth2-client"
+	"github.com/attestantio/go-eth2-client/api"
+	"github.com/attestantio/go-eth2-client/mock"
+	"github.com/attestantio/go-eth2-client/multi"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+// rootClient is a client that returns a fixed block root.
+type rootClient struct {
+	*mock.Service
+	root phase0.Root
+}
+
+func (c *rootClient) BeaconBlockRoot(_ context.Context, _ *api.BeaconBlockRootOpts) (*api.Response[*phase0.Root], error) {
+	root := c.root
+
+	return &api.Response[*phase0.Root]{
+		Data:     &root,
+		Metadata: make(map[string]any),
+	}, nil
+}
+
+func newRootClient(ctx context.Context, t *testing.T, name string, root byte) consensusclient.Service {
+	t.Helper()
+
+	client, err := mock.New(ctx, mock.WithName(name))
+	require.NoError(t, err)
+
+	return &rootClient{
+		Service: client,
+		root:    phase0.Root{root},
+	}
+}
+
+func TestQuorumParameters(t *testing.T) {
+	ctx := context.Background()
+
+	client, err := mock.New(ctx, mock.WithName("mock 1"))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		quorum multi.Quorum
+		call   string
+		err    string
+	}{
+		{
+			name: "UnknownProvider",
+			call: "BeaconState",
+			err:  "problem with parameters: quorum not available for BeaconState",
+		},
+		{
+			name:   "Negative",
+			call:   "Finality",
+			quorum: multi.Quorum{Clients: -1},
+			err:    "problem with parameters: quorum for Finality cannot be negative",
+		},
+		{
+			name:   "ThresholdTooHigh",
+			call:   "Finality",
+			quorum: multi.Quorum{Clients: 2, Threshold: 3},
+			err:    "problem with parameters: quorum threshold for Finality exceeds clients queried",
+		},
+		{
+			name:   "Good",
+			call:   "Finality",
+			quorum: multi.Quorum{Clients: 3, Threshold: 2},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := multi.New(ctx,
+				multi.WithLogLevel(zerolog.Disabled),
+				multi.WithClients([]consensusclient.Service{client}),
+				multi.WithQuorum(test.call, test.quorum),
+			)
+			if test.err != "" {
+				require.EqualError(t, err, test.err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestQuorum(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name   string
+		roots  []byte
+		quorum multi.Quorum
+		root   phase0.Root
+		err    string
+	}{
+		{
+			name:  "Unanimous",
+			roots: []byte{0x01, 0x01, 0x01},
+			root:  phase0.Root{0x01},
+		},
+		{
+			name:  "Majority",
+			roots: []byte{0x02, 0x01, 0x01},
+			root:  phase0.Root{0x01},
+		},
+		{
+			name:  "NoMajority",
+			roots: []byte{0x01, 0x02, 0x03},
+			err:   "no quorum for BeaconBlockRoot: 3 distinct responses, at most 1 matching of 2 required",
+		},
+		{
+			name:   "Threshold",
+			roots:  []byte{0x01, 0x01, 0x02},
+			quorum: multi.Quorum{Threshold: 3},
+			err:    "no quorum for BeaconBlockRoot: 2 distinct responses, at most 2 matching of 3 required",
+		},
+		{
+			name:   "Tie",
+			roots:  []byte{0x02, 0x01, 0x02, 0x01},
+			quorum: multi.Quorum{Threshold: 2},
+			root:   phase0.Root{0x01},
+		},
+		{
+			name:   "InsufficientClients",
+			roots:  []byte{0x01, 0x01},
+			quorum: multi.Quorum{Clients: 4, Threshold: 3},
+			err:    "insufficient active clients for quorum: 2 available, 3 required",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			clients := make([]consensusclient.Service, 0, len(test.roots))
+			for i, root := range test.roots {
+				clients = append(clients, newRootClient(ctx, t, string(rune('a'+i)), root))
+			}
+			multiClient, err := multi.New(ctx,
+				multi.WithLogLevel(zerolog.Disabled),
+				multi.WithClients(clients),
+				multi.WithQuorum("BeaconBlockRoot", test.quorum),
+			)
+			require.NoError(t, err)
+
+			response, err := multiClient.(consensusclient.BeaconBlockRootProvider).BeaconBlockRoot(ctx, &api.BeaconBlockRootOpts{Block: "head"})
+			if test.err != "" {
+				require.EqualError(t, err, test.err)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, test.root, *response.Data)
+			}
+		})
+	}
+}
+
+func TestQuorumTieDeterministic(t *testing.T) {
+	ctx := context.Background()
+
+	multiClient, err := multi.New(ctx,
+		multi.WithLogLevel(zerolog.Disabled),
+		multi.WithClients([]consensusclient.Service{
+			newRootClient(ctx, t, "a", 0x03),
+			newRootClient(ctx, t, "b", 0x02),
+			newRootClient(ctx, t, "c", 0x03),
+			newRootClient(ctx, t, "d", 0x02),
+		}),
+		multi.WithQuorum("BeaconBlockRoot", multi.Quorum{Threshold: 2}),
+	)
+	require.NoError(t, err)
+
+	// Map iteration order varies between calls, so repeat to ensure the same root always wins.
+	for i := 0; i < 32; i++ {
+		response, err := multiClient.(consensusclient.BeaconBlockRootProvider).BeaconBlockRoot(ctx, &api.BeaconBlockRootOpts{Block: "head"})
+		require.NoError(t, err)
+		require.Equal(t, phase0.Root{0x02}, *response.Data)
+	}
+}
+
+func TestQuorumDisagreementError(t *testing.T) {
+	ctx := context.Background()
+
+	multiClient, err := multi.New(ctx,
+		multi.WithLogLevel(zerolog.Disabled),
+		multi.WithClients([]consensusclient.Service{
+			newRootClient(ctx, t, "a", 0x01),
+			newRootClient(ctx, t, "b", 0x02),
+		}),
+		multi.WithQuorum("BeaconBlockRoot", multi.Quorum{}),
+	)
+	require.NoError(t, err)
+
+	_, err = multiClient.(consensusclient.BeaconBlockRootProvider).BeaconBlockRoot(ctx, &api.BeaconBlockRootOpts{Block: "head"})
+	var disagreementErr *multi.DisagreementError
+	require.True(t, errors.As(err, &disagreementErr))
+	require.Equal(t, "BeaconBlockRoot", disagreementErr.Provider)
+	require.Equal(t, 2, disagreementErr.Threshold)
+	require.Equal(t, map[phase0.Root][]string{
+		{0x01}: {"a"},
+		{0x02}: {"b"},
+	}, disagreementErr.Votes)
+}
+
+func TestQuorumTypes(t *testing.T) {
+	ctx := context.Background()
+
+	genesisTime := time.Unix(1606824023, 0)
+	clients := make([]consensusclient.Service, 0, 3)
+	for _, name := range []string{"mock 1", "mock 2", "mock 3"} {
+		client, err := mock.New(ctx, mock.WithName(name), mock.WithGenesisTime(genesisTime))
+		require.NoError(t, err)
+		clients = append(clients, client)
+	}
+	multiClient, err := multi.New(ctx,
+		multi.WithLogLevel(zerolog.Disabled),
+		multi.WithClients(clients),
+		multi.WithQuorum("AttestationData", multi.Quorum{}),
+		multi.WithQuorum("Finality", multi.Quorum{}),
+		multi.WithQuorum("Fork", multi.Quorum{}),
+		multi.WithQuorum("Genesis", multi.Quorum{}),
+	)
+	require.NoError(t, err)
+
+	_, err = multiClient.(consensusclient.AttestationDataProvider).AttestationData(ctx, &api.AttestationDataOpts{})
+	require.NoError(t, err)
+	_, err = multiClient.(consensusclient.FinalityProvider).Finality(ctx, &api.FinalityOpts{State: "head"})
+	require.NoError(t, err)
+	_, err = multiClient.(consensusclient.ForkProvider).Fork(ctx, &api.ForkOpts{State: "head"})
+	require.NoError(t, err)
+	_, err = multiClient.(consensusclient.GenesisProvider).Genesis(ctx)
+	require.NoError(t, err)
+}
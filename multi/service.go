@@ -16,9 +16,12 @@ package multi
 import (
 	"context"
 	"sync"
+	"time"
 
 	consensusclient "github.com/attestantio/go-eth2-client"
+	"github.com/attestantio/go-eth2-client/api"
 	"github.com/attestantio/go-eth2-client/http"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
 	"github.com/pkg/errors"
 	"github.com/rs/zerolog"
 	zerologger "github.com/rs/zerolog/log"
@@ -28,9 +31,61 @@ import (
 type Service struct {
 	log zerolog.Logger
 
+	// clientsMu must be obtained before any of the other locks when more than one is held.
 	clientsMu       sync.RWMutex
 	activeClients   []consensusclient.Service
 	inactiveClients []consensusclient.Service
+	// pendingAddresses are addresses for which a client could not be created, and are retried.
+	pendingAddresses []string
+	// stops are the functions that stop the clients created by the multi client.
+	stops      map[consensusclient.Service]context.CancelFunc
+	httpParams []http.Parameter
+
+	recheckInterval time.Duration
+	maxHeadLag      phase0.Slot
+	maxLatency      time.Duration
+	maxErrorRate    float64
+	healthMu        sync.RWMutex
+	health          map[consensusclient.Service]*clientHealth
+	// optimistic are the clients that were optimistic when last checked.
+	optimistic map[consensusclient.Service]bool
+	// capabilities are the capabilities of the clients when last obtained.
+	capabilities map[consensusclient.Service]*api.Capabilities
+
+	ordering     Ordering
+	bulkOrdering Ordering
+	priorities   map[string]int
+	roundRobin   *roundRobin
+
+	quorums map[string]Quorum
+
+	circuitBreaker *CircuitBreaker
+	breakersMu     sync.Mutex
+	breakers       map[consensusclient.Service]*breaker
+
+	mergedEvents bool
+
+	divergenceInterval time.Duration
+	divergenceHandler  DivergenceHandlerFunc
+	quarantine         bool
+	quarantineMu       sync.RWMutex
+	quarantined        map[string]string
+	// dissents are the number of consecutive checks for which each client has been in the
+	// minority, keyed by check and address.
+	dissents map[string]map[string]int
+
+	errorClassifiers []ErrorClassifier
+	providerNamesMu  sync.RWMutex
+	providerNames    map[string]string
+
+	broadcastThreshold int
+	broadcastHandler   BroadcastHandlerFunc
+
+	bestProposalTimeout time.Duration
+	proposalScorer      ProposalScorer
+
+	bestAttestationDataTimeout time.Duration
+	attestationDataScorer      AttestationDataScorer
 }
 
 // New creates a new Ethereum 2 client with multiple endpoints.
@@ -56,30 +111,50 @@ func New(ctx context.Context, params ...Parameter) (consensusclient.Service, err
 		}
 	}
 
+	httpParams := []http.Parameter{
+		http.WithLogLevel(parameters.logLevel),
+		http.WithTimeout(parameters.timeout),
+		http.WithEndpointTimeouts(parameters.endpointTimeouts),
+		http.WithEnforceJSON(parameters.enforceJSON),
+		http.WithExtraHeaders(parameters.extraHeaders),
+		http.WithCompression(parameters.compression),
+		http.WithLimits(parameters.limits),
+		http.WithEndpointClassLimits(parameters.classLimits),
+		http.WithDialContext(parameters.dialContext),
+		http.WithMonitor(parameters.monitor),
+	}
+
 	// Check the state of each client and put it in an active or inactive list, accordingly.
 	activeClients := make([]consensusclient.Service, 0, len(parameters.clients))
 	inactiveClients := make([]consensusclient.Service, 0, len(parameters.clients))
+	pendingAddresses := make([]string, 0)
+	optimistic := make(map[consensusclient.Service]bool)
+	stops := make(map[consensusclient.Service]context.CancelFunc)
 	for _, client := range parameters.clients {
-		if ping(ctx, client) {
+		active, isOptimistic := ping(ctx, client)
+		if isOptimistic {
+			optimistic[client] = true
+		}
+		if active {
 			activeClients = append(activeClients, client)
 		} else {
 			inactiveClients = append(inactiveClients, client)
 		}
 	}
 	for _, address := range parameters.addresses {
-		client, err := http.New(ctx,
-			http.WithLogLevel(parameters.logLevel),
-			http.WithTimeout(parameters.timeout),
-			http.WithAddress(address),
-			http.WithEnforceJSON(parameters.enforceJSON),
-			http.WithExtraHeaders(parameters.extraHeaders),
-		)
+		client, stop, err := newHTTPClient(ctx, httpParams, address)
 		if err != nil {
-			log.Error().Str("provider", address).Msg("Provider not present; dropping from rotation")
+			log.Error().Str("provider", address).Err(err).Msg("Provider not present; will retry in background")
+			pendingAddresses = append(pendingAddresses, address)
 
 			continue
 		}
-		if ping(ctx, client) {
+		stops[client] = stop
+		active, isOptimistic := ping(ctx, client)
+		if isOptimistic {
+			optimistic[client] = true
+		}
+		if active {
 			activeClients = append(activeClients, client)
 			setProviderActiveMetric(ctx, client.Address(), "active")
 		} else {
@@ -88,20 +163,74 @@ func New(ctx context.Context, params ...Parameter) (consensusclient.Service, err
 		}
 	}
 	if len(activeClients) == 0 {
+		for _, stop := range stops {
+			stop()
+		}
+
 		return nil, errors.New("No providers active, cannot proceed")
 	}
-	log.Trace().Int("active", len(activeClients)).Int("inactive", len(inactiveClients)).Msg("Initial providers")
+	log.Trace().Int("active", len(activeClients)).Int("inactive", len(inactiveClients)).Int("pending", len(pendingAddresses)).Msg("Initial providers")
 	setProvidersMetric(ctx, "active", len(activeClients))
 	setProvidersMetric(ctx, "inactive", len(inactiveClients))
+	setProvidersMetric(ctx, "pending", len(pendingAddresses))
 
 	s := &Service{
-		log:             log,
-		activeClients:   activeClients,
-		inactiveClients: inactiveClients,
+		log:              log,
+		activeClients:    activeClients,
+		inactiveClients:  inactiveClients,
+		pendingAddresses: pendingAddresses,
+		stops:            stops,
+		httpParams:       httpParams,
+		recheckInterval:  parameters.recheckInterval,
+		maxHeadLag:       parameters.maxHeadLag,
+		maxLatency:       parameters.maxLatency,
+		maxErrorRate:     parameters.maxErrorRate,
+		health:           make(map[consensusclient.Service]*clientHealth),
+		optimistic:       optimistic,
+		capabilities:     make(map[consensusclient.Service]*api.Capabilities),
+		ordering:         parameters.ordering,
+		bulkOrdering:     *parameters.bulkOrdering,
+		priorities:       parameters.priorities,
+		roundRobin:       newRoundRobin(parameters.weights),
+		quorums:          parameters.quorums,
+
+		circuitBreaker: parameters.circuitBreaker,
+		breakers:       make(map[consensusclient.Service]*breaker),
+
+		mergedEvents: parameters.mergedEvents,
+
+		divergenceInterval: parameters.divergenceInterval,
+		divergenceHandler:  parameters.divergenceHandler,
+		quarantine:         parameters.quarantine,
+		quarantined:        make(map[string]string),
+		dissents:           make(map[string]map[string]int),
+
+		errorClassifiers: parameters.errorClassifiers,
+		providerNames:    make(map[string]string),
+
+		broadcastThreshold: parameters.broadcastThreshold,
+		broadcastHandler:   parameters.broadcastHandler,
+
+		bestProposalTimeout: parameters.bestProposalTimeout,
+		proposalScorer:      parameters.proposalScorer,
+
+		bestAttestationDataTimeout: parameters.bestAttestationDataTimeout,
+		attestationDataScorer:      parameters.attestationDataScorer,
 	}
 
+	for _, client := range activeClients {
+		s.trackClient(client)
+	}
+	for _, client := range inactiveClients {
+		s.trackClient(client)
+	}
+	s.refreshCapabilities(ctx, append(append([]consensusclient.Service{}, activeClients...), inactiveClients...))
+
 	// Kick off monitor.
 	go s.monitor(ctx)
+	if s.divergenceInterval > 0 {
+		go s.monitorDivergence(ctx)
+	}
 
 	return s, nil
 }
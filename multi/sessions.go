@@ -0,0 +1,160 @@
+// Copyright © 2023 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package multi
+
+import (
+	"context"
+	"sync"
+
+	consensusclient "github.com/attestantio/go-eth2-client"
+	"github.com/rs/zerolog"
+)
+
+// sessionKey is the context key for a session.
+type sessionKey struct{}
+
+// Session pins a sequence of related calls to a single client.  The first client to
+// respond to a call within the session is used for all subsequent calls, and another
+// client is used only if that client fails.
+//
+// Calls that select responses from all clients in parallel are pinned to the session's
+// client once it is set.  Quorum reads and broadcast submissions ignore sessions.
+type Session struct {
+	mu       sync.Mutex
+	address  string
+	switches []*SessionSwitch
+}
+
+// SessionSwitch records a session moving from one client to another.
+type SessionSwitch struct {
+	// From is the address of the client previously used by the session.
+	From string
+	// To is the address of the client now used by the session.
+	To string
+	// Call is the name of the call that resulted in the switch.
+	Call string
+	// Err is the error returned by the previous client, if any.
+	Err error
+}
+
+// WithSession returns a context that pins calls made with it to a single client,
+// along with the session itself.
+func (s *Service) WithSession(ctx context.Context) (context.Context, *Session) {
+	session := &Session{}
+
+	return context.WithValue(ctx, sessionKey{}, session), session
+}
+
+// Address returns the address of the client to which the session is pinned, or an empty
+// string if the session has not yet made a call.
+func (s *Session) Address() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.address
+}
+
+// Switches returns the switches the session has made between clients.
+func (s *Session) Switches() []*SessionSwitch {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switches := make([]*SessionSwitch, len(s.switches))
+	copy(switches, s.switches)
+
+	return switches
+}
+
+// sessionFromContext returns the session in the context, or nil if there is none.
+func sessionFromContext(ctx context.Context) *Session {
+	session, isSession := ctx.Value(sessionKey{}).(*Session)
+	if !isSession {
+		return nil
+	}
+
+	return session
+}
+
+// sessionPinned returns true if the context has a session that is pinned to a client.
+func sessionPinned(ctx context.Context) bool {
+	session := sessionFromContext(ctx)
+
+	return session != nil && session.Address() != ""
+}
+
+// sessionClients moves the session's client, if any, to the front of the list of clients.
+// The session's client is used even if it is inactive, as it only loses the session by
+// failing a call made within it.
+func (s *Service) sessionClients(session *Session, clients []consensusclient.Service) []consensusclient.Service {
+	address := session.Address()
+	if address == "" {
+		return clients
+	}
+
+	var pinned consensusclient.Service
+	sessionClients := make([]consensusclient.Service, 1, len(clients)+1)
+	for _, client := range clients {
+		if client.Address() == address {
+			pinned = client
+		} else {
+			sessionClients = append(sessionClients, client)
+		}
+	}
+	if pinned == nil {
+		s.clientsMu.RLock()
+		for _, client := range s.inactiveClients {
+			if client.Address() == address {
+				pinned = client
+
+				break
+			}
+		}
+		s.clientsMu.RUnlock()
+	}
+	if pinned == nil {
+		// The client has been removed.
+		return clients
+	}
+	sessionClients[0] = pinned
+
+	return sessionClients
+}
+
+// useSessionClient records the client used for a call within the session, recording a switch if it
+// is not the session's client.
+func (s *Service) useSessionClient(ctx context.Context,
+	session *Session,
+	client consensusclient.Service,
+	call string,
+	err error,
+) {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+
+	address := client.Address()
+	if session.address == address {
+		return
+	}
+	if session.address != "" {
+		zerolog.Ctx(ctx).Debug().Str("from", session.address).Str("to", address).Str("call", call).AnErr("reason", err).Msg("Session switched client")
+		session.switches = append(session.switches, &SessionSwitch{
+			From: session.address,
+			To:   address,
+			Call: call,
+			Err:  err,
+		})
+		monitorSessionSwitch(session.address)
+	}
+	session.address = address
+}
@@ -0,0 +1,123 @@
+// Copyright © 2023 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package multi_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	consensusclient "github.com/attestantio/go-eth2-client"
+	"github.com/attestantio/go-eth2-client/api"
+	"github.com/attestantio/go-eth2-client/mock"
+	"github.com/attestantio/go-eth2-client/multi"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+// sessionClient is a client that counts attestation data calls, optionally failing them.
+type sessionClient struct {
+	*mock.Service
+	fail  atomic.Bool
+	calls atomic.Int32
+}
+
+func (c *sessionClient) AttestationData(ctx context.Context,
+	opts *api.AttestationDataOpts,
+) (
+	*api.Response[*phase0.AttestationData],
+	error,
+) {
+	c.calls.Add(1)
+	if c.fail.Load() {
+		return nil, errors.New("attestation data failed")
+	}
+
+	return c.Service.AttestationData(ctx, opts)
+}
+
+func newSessionClient(ctx context.Context, t *testing.T, name string) *sessionClient {
+	t.Helper()
+
+	client, err := mock.New(ctx, mock.WithName(name))
+	require.NoError(t, err)
+
+	return &sessionClient{Service: client}
+}
+
+func TestSession(t *testing.T) {
+	ctx := context.Background()
+
+	clientA := newSessionClient(ctx, t, "a")
+	clientB := newSessionClient(ctx, t, "b")
+
+	s, err := multi.New(ctx,
+		multi.WithLogLevel(zerolog.Disabled),
+		multi.WithClients([]consensusclient.Service{clientA, clientB}),
+		multi.WithOrdering(multi.OrderingPriority),
+		multi.WithPriorities(map[string]int{"a": 1, "b": 2}),
+		multi.WithRecheckInterval(10*time.Millisecond),
+	)
+	require.NoError(t, err)
+	service := s.(*multi.Service)
+	opts := &api.AttestationDataOpts{Slot: 1}
+
+	sessionCtx, session := service.WithSession(ctx)
+	require.Equal(t, "", session.Address())
+
+	// Client a fails, so the session is pinned to client b.
+	clientA.fail.Store(true)
+	_, err = service.AttestationData(sessionCtx, opts)
+	require.NoError(t, err)
+	require.Equal(t, "b", session.Address())
+	require.Empty(t, session.Switches())
+
+	// Client a recovers.
+	clientA.fail.Store(false)
+	require.Eventually(t, func() bool {
+		for _, info := range service.Clients() {
+			if info.Address == "a" && info.State != multi.ClientStateActive {
+				return false
+			}
+		}
+
+		return true
+	}, time.Second, 10*time.Millisecond)
+
+	// Calls outside the session go to client a, calls within the session stay with client b.
+	callsA := clientA.calls.Load()
+	callsB := clientB.calls.Load()
+	_, err = service.AttestationData(ctx, opts)
+	require.NoError(t, err)
+	_, err = service.AttestationData(sessionCtx, opts)
+	require.NoError(t, err)
+	require.Equal(t, callsA+1, clientA.calls.Load())
+	require.Equal(t, callsB+1, clientB.calls.Load())
+	require.Equal(t, "b", session.Address())
+
+	// Client b fails, so the session switches to client a.
+	clientB.fail.Store(true)
+	_, err = service.AttestationData(sessionCtx, opts)
+	require.NoError(t, err)
+	require.Equal(t, "a", session.Address())
+	switches := session.Switches()
+	require.Len(t, switches, 1)
+	require.Equal(t, "b", switches[0].From)
+	require.Equal(t, "a", switches[0].To)
+	require.Equal(t, "AttestationData", switches[0].Call)
+	require.EqualError(t, switches[0].Err, "attestation data failed")
+}
@@ -15,6 +15,8 @@ package multi
 
 import (
 	"context"
+	"fmt"
+	"io"
 
 	consensusclient "github.com/attestantio/go-eth2-client"
 	"github.com/attestantio/go-eth2-client/api"
@@ -28,7 +30,7 @@ func (s *Service) SignedBeaconBlock(ctx context.Context,
 	*api.Response[*spec.VersionedSignedBeaconBlock],
 	error,
 ) {
-	res, err := s.doCall(ctx, func(ctx context.Context, client consensusclient.Service) (interface{}, error) {
+	res, err := s.doBulkCall(ctx, "SignedBeaconBlock", func(ctx context.Context, client consensusclient.Service) (interface{}, error) {
 		block, err := client.(consensusclient.SignedBeaconBlockProvider).SignedBeaconBlock(ctx, opts)
 		if err != nil {
 			return nil, err
@@ -42,3 +44,31 @@ func (s *Service) SignedBeaconBlock(ctx context.Context,
 
 	return res.(*api.Response[*spec.VersionedSignedBeaconBlock]), nil
 }
+
+// WriteSignedBeaconBlockSSZ writes the SSZ-encoded signed beacon block to the supplied writer.
+func (s *Service) WriteSignedBeaconBlockSSZ(ctx context.Context,
+	opts *api.SignedBeaconBlockOpts,
+	w io.Writer,
+) (
+	*api.Response[*api.SSZWriteResult],
+	error,
+) {
+	writer := &countingWriter{w: w}
+	res, err := s.doBulkCall(ctx, "WriteSignedBeaconBlockSSZ", func(ctx context.Context, client consensusclient.Service) (interface{}, error) {
+		provider, isProvider := client.(consensusclient.SignedBeaconBlockSSZWriter)
+		if !isProvider {
+			return nil, fmt.Errorf("%s@%s does not support this call", client.Name(), client.Address())
+		}
+		result, err := provider.WriteSignedBeaconBlockSSZ(ctx, opts, writer)
+		if err != nil {
+			return nil, err
+		}
+
+		return result, nil
+	}, writeErrHandler(writer))
+	if err != nil {
+		return nil, err
+	}
+
+	return res.(*api.Response[*api.SSZWriteResult]), nil
+}
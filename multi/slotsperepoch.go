@@ -22,7 +22,7 @@ import (
 
 // SlotsPerEpoch provides the slots per epoch of the chain.
 func (s *Service) SlotsPerEpoch(ctx context.Context) (uint64, error) {
-	res, err := s.doCall(ctx, func(ctx context.Context, client consensusclient.Service) (interface{}, error) {
+	res, err := s.doCall(ctx, "SlotsPerEpoch", func(ctx context.Context, client consensusclient.Service) (interface{}, error) {
 		slotsPerEpoch, err := client.(consensusclient.SlotsPerEpochProvider).SlotsPerEpoch(ctx)
 		if err != nil {
 			return nil, err
@@ -22,7 +22,7 @@ import (
 
 // Spec provides the spec information of the chain.
 func (s *Service) Spec(ctx context.Context) (*api.Response[map[string]any], error) {
-	res, err := s.doCall(ctx, func(ctx context.Context, client consensusclient.Service) (interface{}, error) {
+	res, err := s.doCall(ctx, "Spec", func(ctx context.Context, client consensusclient.Service) (interface{}, error) {
 		aggregate, err := client.(consensusclient.SpecProvider).Spec(ctx)
 		if err != nil {
 			return nil, err
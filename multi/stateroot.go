@@ -28,7 +28,7 @@ func (s *Service) BeaconStateRoot(ctx context.Context,
 	*api.Response[*phase0.Root],
 	error,
 ) {
-	res, err := s.doCall(ctx, func(ctx context.Context, client consensusclient.Service) (interface{}, error) {
+	res, err := s.doCall(ctx, "BeaconStateRoot", func(ctx context.Context, client consensusclient.Service) (interface{}, error) {
 		stateRoot, err := client.(consensusclient.BeaconStateRootProvider).BeaconStateRoot(ctx, opts)
 		if err != nil {
 			return nil, err
@@ -24,7 +24,7 @@ import (
 //
 // Deprecated: this will not work from the deneb hard-fork onwards.  Use SubmitBlindedProposal() instead.
 func (s *Service) SubmitBlindedBeaconBlock(ctx context.Context, block *api.VersionedSignedBlindedBeaconBlock) error {
-	_, err := s.doCall(ctx, func(ctx context.Context, client consensusclient.Service) (interface{}, error) {
+	err := s.doSubmission(ctx, "SubmitBlindedBeaconBlock", func(ctx context.Context, client consensusclient.Service) (interface{}, error) {
 		err := client.(consensusclient.BlindedBeaconBlockSubmitter).SubmitBlindedBeaconBlock(ctx, block)
 		if err != nil {
 			return nil, err
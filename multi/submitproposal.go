@@ -22,7 +22,7 @@ import (
 
 // SubmitProposal submits a beacon block.
 func (s *Service) SubmitProposal(ctx context.Context, proposal *api.VersionedSignedProposal) error {
-	_, err := s.doCall(ctx, func(ctx context.Context, client consensusclient.Service) (interface{}, error) {
+	err := s.doSubmission(ctx, "SubmitProposal", func(ctx context.Context, client consensusclient.Service) (interface{}, error) {
 		err := client.(consensusclient.ProposalSubmitter).SubmitProposal(ctx, proposal)
 		if err != nil {
 			return nil, err
@@ -25,7 +25,7 @@ import (
 func (s *Service) SubmitProposalPreparations(ctx context.Context,
 	preparations []*apiv1.ProposalPreparation,
 ) error {
-	_, err := s.doCall(ctx, func(ctx context.Context, client consensusclient.Service) (interface{}, error) {
+	err := s.doSubmission(ctx, "SubmitProposalPreparations", func(ctx context.Context, client consensusclient.Service) (interface{}, error) {
 		err := client.(consensusclient.ProposalPreparationsSubmitter).SubmitProposalPreparations(ctx, preparations)
 		if err != nil {
 			return nil, err
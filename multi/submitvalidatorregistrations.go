@@ -22,7 +22,7 @@ import (
 
 // SubmitValidatorRegistrations submits a validator registration.
 func (s *Service) SubmitValidatorRegistrations(ctx context.Context, registrations []*api.VersionedSignedValidatorRegistration) error {
-	_, err := s.doCall(ctx, func(ctx context.Context, client consensusclient.Service) (interface{}, error) {
+	err := s.doSubmission(ctx, "SubmitValidatorRegistrations", func(ctx context.Context, client consensusclient.Service) (interface{}, error) {
 		err := client.(consensusclient.ValidatorRegistrationsSubmitter).SubmitValidatorRegistrations(ctx, registrations)
 		if err != nil {
 			return nil, err
@@ -28,7 +28,7 @@ func (s *Service) SyncCommitteeContribution(ctx context.Context,
 	*api.Response[*altair.SyncCommitteeContribution],
 	error,
 ) {
-	res, err := s.doCall(ctx, func(ctx context.Context, client consensusclient.Service) (interface{}, error) {
+	res, err := s.doCall(ctx, "SyncCommitteeContribution", func(ctx context.Context, client consensusclient.Service) (interface{}, error) {
 		block, err := client.(consensusclient.SyncCommitteeContributionProvider).SyncCommitteeContribution(ctx, opts)
 		if err != nil {
 			return nil, err
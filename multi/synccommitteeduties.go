@@ -29,7 +29,7 @@ func (s *Service) SyncCommitteeDuties(ctx context.Context,
 	*api.Response[[]*apiv1.SyncCommitteeDuty],
 	error,
 ) {
-	res, err := s.doCall(ctx, func(ctx context.Context, client consensusclient.Service) (interface{}, error) {
+	res, err := s.doCall(ctx, "SyncCommitteeDuties", func(ctx context.Context, client consensusclient.Service) (interface{}, error) {
 		response, err := client.(consensusclient.SyncCommitteeDutiesProvider).SyncCommitteeDuties(ctx, opts)
 		if err != nil {
 			return nil, err
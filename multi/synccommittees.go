@@ -23,7 +23,7 @@ import (
 
 // SyncCommittee fetches the sync committee for the given state.
 func (s *Service) SyncCommittee(ctx context.Context, opts *api.SyncCommitteeOpts) (*api.Response[*apiv1.SyncCommittee], error) {
-	res, err := s.doCall(ctx, func(ctx context.Context, client consensusclient.Service) (interface{}, error) {
+	res, err := s.doBulkCall(ctx, "SyncCommittee", func(ctx context.Context, client consensusclient.Service) (interface{}, error) {
 		block, err := client.(consensusclient.SyncCommitteesProvider).SyncCommittee(ctx, opts)
 		if err != nil {
 			return nil, err
@@ -28,7 +28,7 @@ func (s *Service) ValidatorBalances(ctx context.Context,
 	*api.Response[map[phase0.ValidatorIndex]phase0.Gwei],
 	error,
 ) {
-	res, err := s.doCall(ctx, func(ctx context.Context, client consensusclient.Service) (interface{}, error) {
+	res, err := s.doBulkCall(ctx, "ValidatorBalances", func(ctx context.Context, client consensusclient.Service) (interface{}, error) {
 		block, err := client.(consensusclient.ValidatorBalancesProvider).ValidatorBalances(ctx, opts)
 		if err != nil {
 			return nil, err
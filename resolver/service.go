@@ -0,0 +1,230 @@
+// Copyright © 2023 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resolver
+
+import (
+	"context"
+
+	consensusclient "github.com/attestantio/go-eth2-client"
+	"github.com/attestantio/go-eth2-client/api"
+	apiv1 "github.com/attestantio/go-eth2-client/api/v1"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/pkg/errors"
+)
+
+// Resolution is a state or block ID resolved to a concrete slot and root.
+type Resolution struct {
+	// Slot is the slot of the state or block.
+	Slot phase0.Slot
+	// Root is the state root for state IDs, and the block root for block IDs.
+	Root phase0.Root
+}
+
+// Service resolves state and block IDs to concrete slots and roots.
+type Service struct {
+	client consensusclient.Service
+}
+
+// New creates a new resolver.
+func New(_ context.Context, params ...Parameter) (*Service, error) {
+	parameters, err := parseAndCheckParameters(params...)
+	if err != nil {
+		return nil, errors.Wrap(err, "problem with parameters")
+	}
+
+	return &Service{
+		client: parameters.client,
+	}, nil
+}
+
+// Block resolves a block ID to the slot and root of the block.
+func (s *Service) Block(ctx context.Context, id api.BlockID) (*Resolution, error) {
+	if err := id.Validate(); err != nil {
+		return nil, err
+	}
+
+	header, err := s.header(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Resolution{
+		Slot: header.Header.Message.Slot,
+		Root: header.Root,
+	}, nil
+}
+
+// State resolves a state ID to the slot and root of the state.
+func (s *Service) State(ctx context.Context, id api.StateID) (*Resolution, error) {
+	if err := id.Validate(); err != nil {
+		return nil, err
+	}
+
+	if id == api.StateIDHead {
+		// Obtain both values from the same header, as the head can change between calls.
+		header, err := s.header(ctx, api.BlockIDHead)
+		if err != nil {
+			return nil, err
+		}
+
+		return &Resolution{
+			Slot: header.Header.Message.Slot,
+			Root: header.Header.Message.StateRoot,
+		}, nil
+	}
+
+	slot, err := s.StateSlot(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if root, isRoot := id.Root(); isRoot {
+		return &Resolution{
+			Slot: slot,
+			Root: root,
+		}, nil
+	}
+
+	provider, isProvider := s.client.(consensusclient.BeaconStateRootProvider)
+	if !isProvider {
+		return nil, errors.New("client does not provide state roots")
+	}
+	response, err := provider.BeaconStateRoot(ctx, &api.BeaconStateRootOpts{
+		State: api.StateIDFromSlot(slot),
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to obtain state root")
+	}
+
+	return &Resolution{
+		Slot: slot,
+		Root: *response.Data,
+	}, nil
+}
+
+// StateSlot resolves a state ID to the slot of the state.
+// Resolving a state root requires the full state to be fetched, so is expensive.
+func (s *Service) StateSlot(ctx context.Context, id api.StateID) (phase0.Slot, error) {
+	if err := id.Validate(); err != nil {
+		return 0, err
+	}
+
+	if slot, isSlot := id.Slot(); isSlot {
+		return slot, nil
+	}
+
+	switch id {
+	case api.StateIDGenesis:
+		return 0, nil
+	case api.StateIDHead:
+		header, err := s.header(ctx, api.BlockIDHead)
+		if err != nil {
+			return 0, err
+		}
+
+		return header.Header.Message.Slot, nil
+	case api.StateIDJustified, api.StateIDFinalized:
+		epoch, err := s.checkpointEpoch(ctx, id)
+		if err != nil {
+			return 0, err
+		}
+		slotsPerEpoch, err := s.slotsPerEpoch(ctx)
+		if err != nil {
+			return 0, err
+		}
+
+		return phase0.Slot(uint64(epoch) * slotsPerEpoch), nil
+	default:
+		// A state root.
+		provider, isProvider := s.client.(consensusclient.BeaconStateProvider)
+		if !isProvider {
+			return 0, errors.New("client does not provide beacon states")
+		}
+		response, err := provider.BeaconState(ctx, &api.BeaconStateOpts{State: id})
+		if err != nil {
+			return 0, errors.Wrap(err, "failed to obtain state")
+		}
+
+		return response.Data.Slot()
+	}
+}
+
+// StateEpoch resolves a state ID to the epoch of the state.
+func (s *Service) StateEpoch(ctx context.Context, id api.StateID) (phase0.Epoch, error) {
+	if id == api.StateIDJustified || id == api.StateIDFinalized {
+		return s.checkpointEpoch(ctx, id)
+	}
+
+	slot, err := s.StateSlot(ctx, id)
+	if err != nil {
+		return 0, err
+	}
+	slotsPerEpoch, err := s.slotsPerEpoch(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return phase0.Epoch(uint64(slot) / slotsPerEpoch), nil
+}
+
+// header obtains the header for the given block ID.
+func (s *Service) header(ctx context.Context, id api.BlockID) (*apiv1.BeaconBlockHeader, error) {
+	provider, isProvider := s.client.(consensusclient.BeaconBlockHeadersProvider)
+	if !isProvider {
+		return nil, errors.New("client does not provide block headers")
+	}
+	response, err := provider.BeaconBlockHeader(ctx, &api.BeaconBlockHeaderOpts{Block: id})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to obtain block header")
+	}
+	if response.Data == nil || response.Data.Header == nil || response.Data.Header.Message == nil {
+		return nil, errors.New("block header not returned")
+	}
+
+	return response.Data, nil
+}
+
+// checkpointEpoch obtains the epoch of the justified or finalized checkpoint.
+func (s *Service) checkpointEpoch(ctx context.Context, id api.StateID) (phase0.Epoch, error) {
+	provider, isProvider := s.client.(consensusclient.FinalityProvider)
+	if !isProvider {
+		return 0, errors.New("client does not provide finality")
+	}
+	response, err := provider.Finality(ctx, &api.FinalityOpts{State: api.StateIDHead})
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to obtain finality")
+	}
+
+	if id == api.StateIDJustified {
+		return response.Data.Justified.Epoch, nil
+	}
+
+	return response.Data.Finalized.Epoch, nil
+}
+
+// slotsPerEpoch obtains the number of slots per epoch.
+func (s *Service) slotsPerEpoch(ctx context.Context) (uint64, error) {
+	provider, isProvider := s.client.(consensusclient.SlotsPerEpochProvider)
+	if !isProvider {
+		return 0, errors.New("client does not provide slots per epoch")
+	}
+	slotsPerEpoch, err := provider.SlotsPerEpoch(ctx)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to obtain slots per epoch")
+	}
+	if slotsPerEpoch == 0 {
+		return 0, errors.New("slots per epoch is 0")
+	}
+
+	return slotsPerEpoch, nil
+}
@@ -0,0 +1,153 @@
+// Copyright © 2023 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resolver_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/attestantio/go-eth2-client/api"
+	apiv1 "github.com/attestantio/go-eth2-client/api/v1"
+	"github.com/attestantio/go-eth2-client/mock"
+	"github.com/attestantio/go-eth2-client/resolver"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/stretchr/testify/require"
+)
+
+// headerClient is a client that returns a fixed block header.
+type headerClient struct {
+	*mock.Service
+}
+
+func (*headerClient) BeaconBlockHeader(_ context.Context,
+	_ *api.BeaconBlockHeaderOpts,
+) (
+	*api.Response[*apiv1.BeaconBlockHeader],
+	error,
+) {
+	return &api.Response[*apiv1.BeaconBlockHeader]{
+		Data: &apiv1.BeaconBlockHeader{
+			Root: phase0.Root{0x01},
+			Header: &phase0.SignedBeaconBlockHeader{
+				Message: &phase0.BeaconBlockHeader{
+					Slot:      250,
+					StateRoot: phase0.Root{0x02},
+				},
+			},
+		},
+		Metadata: make(map[string]any),
+	}, nil
+}
+
+func TestService(t *testing.T) {
+	ctx := context.Background()
+
+	_, err := resolver.New(ctx)
+	require.EqualError(t, err, "problem with parameters: no client specified")
+}
+
+func TestStateSlot(t *testing.T) {
+	ctx := context.Background()
+
+	client, err := mock.New(ctx)
+	require.NoError(t, err)
+	r, err := resolver.New(ctx, resolver.WithClient(&headerClient{Service: client}))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name  string
+		id    api.StateID
+		slot  phase0.Slot
+		epoch phase0.Epoch
+		err   string
+	}{
+		{
+			name: "Invalid",
+			id:   "latest",
+			err:  "invalid state ID latest",
+		},
+		{
+			name: "Genesis",
+			id:   api.StateIDGenesis,
+		},
+		{
+			name:  "Slot",
+			id:    api.StateIDFromSlot(100),
+			slot:  100,
+			epoch: 3,
+		},
+		{
+			name:  "Head",
+			id:    api.StateIDHead,
+			slot:  250,
+			epoch: 7,
+		},
+		{
+			name:  "Justified",
+			id:    api.StateIDJustified,
+			slot:  224,
+			epoch: 7,
+		},
+		{
+			name:  "Finalized",
+			id:    api.StateIDFinalized,
+			slot:  192,
+			epoch: 6,
+		},
+		{
+			name: "Root",
+			id:   api.StateIDFromRoot(phase0.Root{0x03}),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			slot, err := r.StateSlot(ctx, test.id)
+			if test.err != "" {
+				require.EqualError(t, err, test.err)
+
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.slot, slot)
+			epoch, err := r.StateEpoch(ctx, test.id)
+			require.NoError(t, err)
+			require.Equal(t, test.epoch, epoch)
+		})
+	}
+}
+
+func TestResolve(t *testing.T) {
+	ctx := context.Background()
+
+	client, err := mock.New(ctx)
+	require.NoError(t, err)
+	r, err := resolver.New(ctx, resolver.WithClient(&headerClient{Service: client}))
+	require.NoError(t, err)
+
+	block, err := r.Block(ctx, api.BlockIDHead)
+	require.NoError(t, err)
+	require.Equal(t, &resolver.Resolution{Slot: 250, Root: phase0.Root{0x01}}, block)
+
+	state, err := r.State(ctx, api.StateIDHead)
+	require.NoError(t, err)
+	require.Equal(t, &resolver.Resolution{Slot: 250, Root: phase0.Root{0x02}}, state)
+
+	state, err = r.State(ctx, api.StateIDFromRoot(phase0.Root{0x03}))
+	require.NoError(t, err)
+	require.Equal(t, &resolver.Resolution{Slot: 0, Root: phase0.Root{0x03}}, state)
+
+	_, err = r.State(ctx, api.StateIDFinalized)
+	require.NoError(t, err)
+}
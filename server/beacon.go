@@ -0,0 +1,504 @@
+// Copyright © 2023 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	consensusclient "github.com/attestantio/go-eth2-client"
+	"github.com/attestantio/go-eth2-client/api"
+	apiv1 "github.com/attestantio/go-eth2-client/api/v1"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/pkg/errors"
+)
+
+// rootJSON is the spec representation of a root response.
+type rootJSON struct {
+	Root phase0.Root `json:"root"`
+}
+
+// randaoJSON is the spec representation of a RANDAO response.
+type randaoJSON struct {
+	Randao phase0.Root `json:"randao"`
+}
+
+// validatorBalanceJSON is the spec representation of a validator balance.
+type validatorBalanceJSON struct {
+	Index   string `json:"index"`
+	Balance string `json:"balance"`
+}
+
+// validatorsRequestJSON is the spec representation of a POST request for validators.
+type validatorsRequestJSON struct {
+	IDs      []string `json:"ids"`
+	Statuses []string `json:"statuses"`
+}
+
+func (s *Service) genesis(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	provider, isProvider := s.service.(consensusclient.GenesisProvider)
+	if !isProvider {
+		s.writeNotImplemented(w, "Genesis")
+
+		return
+	}
+
+	response, err := provider.Genesis(r.Context())
+	if err != nil {
+		s.writeServiceError(w, "Genesis", err)
+
+		return
+	}
+	s.writeResponse(w, r, response.Data, response.Metadata)
+}
+
+func (s *Service) beaconStateRoot(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	provider, isProvider := s.service.(consensusclient.BeaconStateRootProvider)
+	if !isProvider {
+		s.writeNotImplemented(w, "BeaconStateRoot")
+
+		return
+	}
+	state, err := stateID(params)
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, err.Error())
+
+		return
+	}
+
+	response, err := provider.BeaconStateRoot(r.Context(), &api.BeaconStateRootOpts{State: state})
+	if err != nil {
+		s.writeServiceError(w, "BeaconStateRoot", err)
+
+		return
+	}
+	s.writeResponse(w, r, &rootJSON{Root: *response.Data}, response.Metadata)
+}
+
+func (s *Service) fork(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	provider, isProvider := s.service.(consensusclient.ForkProvider)
+	if !isProvider {
+		s.writeNotImplemented(w, "Fork")
+
+		return
+	}
+	state, err := stateID(params)
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, err.Error())
+
+		return
+	}
+
+	response, err := provider.Fork(r.Context(), &api.ForkOpts{State: state})
+	if err != nil {
+		s.writeServiceError(w, "Fork", err)
+
+		return
+	}
+	s.writeResponse(w, r, response.Data, response.Metadata)
+}
+
+func (s *Service) finality(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	provider, isProvider := s.service.(consensusclient.FinalityProvider)
+	if !isProvider {
+		s.writeNotImplemented(w, "Finality")
+
+		return
+	}
+	state, err := stateID(params)
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, err.Error())
+
+		return
+	}
+
+	response, err := provider.Finality(r.Context(), &api.FinalityOpts{State: state})
+	if err != nil {
+		s.writeServiceError(w, "Finality", err)
+
+		return
+	}
+	s.writeResponse(w, r, response.Data, response.Metadata)
+}
+
+func (s *Service) validators(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	provider, isProvider := s.service.(consensusclient.ValidatorsProvider)
+	if !isProvider {
+		s.writeNotImplemented(w, "Validators")
+
+		return
+	}
+	state, err := stateID(params)
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, err.Error())
+
+		return
+	}
+	ids, statuses, err := validatorsRequest(r)
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, err.Error())
+
+		return
+	}
+	indices, pubKeys, err := validatorIDs(ids)
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, err.Error())
+
+		return
+	}
+
+	response, err := provider.Validators(r.Context(), &api.ValidatorsOpts{
+		State:   state,
+		Indices: indices,
+		PubKeys: pubKeys,
+	})
+	if err != nil {
+		s.writeServiceError(w, "Validators", err)
+
+		return
+	}
+
+	validators := make([]*apiv1.Validator, 0, len(response.Data))
+	for _, validator := range response.Data {
+		if matchesStatus(validator.Status, statuses) {
+			validators = append(validators, validator)
+		}
+	}
+	sort.Slice(validators, func(i, j int) bool {
+		return validators[i].Index < validators[j].Index
+	})
+	s.writeResponse(w, r, validators, response.Metadata)
+}
+
+// validatorsRequest returns the validator IDs and statuses from a request, which
+// are supplied as query parameters for GET and in the body for POST.
+func validatorsRequest(r *http.Request) ([]string, []string, error) {
+	if r.Method != http.MethodPost {
+		return queryList(r, "id"), queryList(r, "status"), nil
+	}
+
+	body, err := readBody(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(body) == 0 {
+		return nil, nil, nil
+	}
+	request := &validatorsRequestJSON{}
+	if err := json.Unmarshal(body, request); err != nil {
+		return nil, nil, errors.Wrap(err, "invalid JSON body")
+	}
+
+	return request.IDs, request.Statuses, nil
+}
+
+// matchesStatus returns true if the state matches one of the statuses, or if no statuses are supplied.
+// Statuses can be full states such as "active_ongoing" or general states such as "active".
+func matchesStatus(state apiv1.ValidatorState, statuses []string) bool {
+	if len(statuses) == 0 {
+		return true
+	}
+	for _, status := range statuses {
+		if state.String() == status || strings.HasPrefix(state.String(), fmt.Sprintf("%s_", status)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *Service) validatorBalances(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	provider, isProvider := s.service.(consensusclient.ValidatorBalancesProvider)
+	if !isProvider {
+		s.writeNotImplemented(w, "ValidatorBalances")
+
+		return
+	}
+	state, err := stateID(params)
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, err.Error())
+
+		return
+	}
+	ids := queryList(r, "id")
+	if r.Method == http.MethodPost {
+		body, err := readBody(r)
+		if err != nil {
+			s.writeError(w, http.StatusBadRequest, err.Error())
+
+			return
+		}
+		if len(body) > 0 {
+			if err := json.Unmarshal(body, &ids); err != nil {
+				s.writeError(w, http.StatusBadRequest, errors.Wrap(err, "invalid JSON body").Error())
+
+				return
+			}
+		}
+	}
+	indices, pubKeys, err := validatorIDs(ids)
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, err.Error())
+
+		return
+	}
+	if len(pubKeys) > 0 {
+		// Balances are keyed by index, so public keys need to be resolved first.
+		resolved, err := s.validatorIndices(r, state, pubKeys)
+		if err != nil {
+			s.writeServiceError(w, "ValidatorBalances", err)
+
+			return
+		}
+		indices = append(indices, resolved...)
+		if len(indices) == 0 {
+			s.writeResponse(w, r, []*validatorBalanceJSON{}, nil)
+
+			return
+		}
+	}
+
+	response, err := provider.ValidatorBalances(r.Context(), &api.ValidatorBalancesOpts{
+		State:   state,
+		Indices: indices,
+	})
+	if err != nil {
+		s.writeServiceError(w, "ValidatorBalances", err)
+
+		return
+	}
+
+	sortedIndices := make([]phase0.ValidatorIndex, 0, len(response.Data))
+	for index := range response.Data {
+		sortedIndices = append(sortedIndices, index)
+	}
+	sort.Slice(sortedIndices, func(i, j int) bool {
+		return sortedIndices[i] < sortedIndices[j]
+	})
+	balances := make([]*validatorBalanceJSON, len(sortedIndices))
+	for i, index := range sortedIndices {
+		balances[i] = &validatorBalanceJSON{
+			Index:   fmt.Sprintf("%d", index),
+			Balance: fmt.Sprintf("%d", response.Data[index]),
+		}
+	}
+	s.writeResponse(w, r, balances, response.Metadata)
+}
+
+// validatorIndices resolves validator public keys to indices.
+func (s *Service) validatorIndices(r *http.Request, state api.StateID, pubKeys []phase0.BLSPubKey) ([]phase0.ValidatorIndex, error) {
+	provider, isProvider := s.service.(consensusclient.ValidatorsProvider)
+	if !isProvider {
+		return nil, api.ErrNotSupported
+	}
+
+	response, err := provider.Validators(r.Context(), &api.ValidatorsOpts{
+		State:   state,
+		PubKeys: pubKeys,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	indices := make([]phase0.ValidatorIndex, 0, len(response.Data))
+	for index := range response.Data {
+		indices = append(indices, index)
+	}
+
+	return indices, nil
+}
+
+func (s *Service) beaconCommittees(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	provider, isProvider := s.service.(consensusclient.BeaconCommitteesProvider)
+	if !isProvider {
+		s.writeNotImplemented(w, "BeaconCommittees")
+
+		return
+	}
+	state, err := stateID(params)
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, err.Error())
+
+		return
+	}
+	opts := &api.BeaconCommitteesOpts{State: state}
+	if r.URL.Query().Has("epoch") {
+		epoch, err := queryUint64(r, "epoch")
+		if err != nil {
+			s.writeError(w, http.StatusBadRequest, err.Error())
+
+			return
+		}
+		opts.Epoch = (*phase0.Epoch)(&epoch)
+	}
+	filters := make(map[string]uint64)
+	for _, name := range []string{"index", "slot"} {
+		if !r.URL.Query().Has(name) {
+			continue
+		}
+		filters[name], err = queryUint64(r, name)
+		if err != nil {
+			s.writeError(w, http.StatusBadRequest, err.Error())
+
+			return
+		}
+	}
+
+	response, err := provider.BeaconCommittees(r.Context(), opts)
+	if err != nil {
+		s.writeServiceError(w, "BeaconCommittees", err)
+
+		return
+	}
+
+	committees := make([]*apiv1.BeaconCommittee, 0, len(response.Data))
+	for _, committee := range response.Data {
+		if index, exists := filters["index"]; exists && uint64(committee.Index) != index {
+			continue
+		}
+		if slot, exists := filters["slot"]; exists && uint64(committee.Slot) != slot {
+			continue
+		}
+		committees = append(committees, committee)
+	}
+	s.writeResponse(w, r, committees, response.Metadata)
+}
+
+func (s *Service) syncCommittee(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	provider, isProvider := s.service.(consensusclient.SyncCommitteesProvider)
+	if !isProvider {
+		s.writeNotImplemented(w, "SyncCommittee")
+
+		return
+	}
+	state, err := stateID(params)
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, err.Error())
+
+		return
+	}
+	opts := &api.SyncCommitteeOpts{State: state}
+	if r.URL.Query().Has("epoch") {
+		epoch, err := queryUint64(r, "epoch")
+		if err != nil {
+			s.writeError(w, http.StatusBadRequest, err.Error())
+
+			return
+		}
+		opts.Epoch = (*phase0.Epoch)(&epoch)
+	}
+
+	response, err := provider.SyncCommittee(r.Context(), opts)
+	if err != nil {
+		s.writeServiceError(w, "SyncCommittee", err)
+
+		return
+	}
+	s.writeResponse(w, r, response.Data, response.Metadata)
+}
+
+func (s *Service) beaconStateRandao(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	provider, isProvider := s.service.(consensusclient.BeaconStateRandaoProvider)
+	if !isProvider {
+		s.writeNotImplemented(w, "BeaconStateRandao")
+
+		return
+	}
+	state, err := stateID(params)
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, err.Error())
+
+		return
+	}
+
+	response, err := provider.BeaconStateRandao(r.Context(), &api.BeaconStateRandaoOpts{State: state})
+	if err != nil {
+		s.writeServiceError(w, "BeaconStateRandao", err)
+
+		return
+	}
+	s.writeResponse(w, r, &randaoJSON{Randao: *response.Data}, response.Metadata)
+}
+
+func (s *Service) beaconBlockHeader(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	provider, isProvider := s.service.(consensusclient.BeaconBlockHeadersProvider)
+	if !isProvider {
+		s.writeNotImplemented(w, "BeaconBlockHeader")
+
+		return
+	}
+	block, err := blockID(params)
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, err.Error())
+
+		return
+	}
+
+	response, err := provider.BeaconBlockHeader(r.Context(), &api.BeaconBlockHeaderOpts{Block: block})
+	if err != nil {
+		s.writeServiceError(w, "BeaconBlockHeader", err)
+
+		return
+	}
+	s.writeResponse(w, r, response.Data, response.Metadata)
+}
+
+func (s *Service) beaconBlockRoot(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	provider, isProvider := s.service.(consensusclient.BeaconBlockRootProvider)
+	if !isProvider {
+		s.writeNotImplemented(w, "BeaconBlockRoot")
+
+		return
+	}
+	block, err := blockID(params)
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, err.Error())
+
+		return
+	}
+
+	response, err := provider.BeaconBlockRoot(r.Context(), &api.BeaconBlockRootOpts{Block: block})
+	if err != nil {
+		s.writeServiceError(w, "BeaconBlockRoot", err)
+
+		return
+	}
+	s.writeResponse(w, r, &rootJSON{Root: *response.Data}, response.Metadata)
+}
+
+func (s *Service) blobSidecars(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	provider, isProvider := s.service.(consensusclient.BlobSidecarsProvider)
+	if !isProvider {
+		s.writeNotImplemented(w, "BlobSidecars")
+
+		return
+	}
+	block, err := blockID(params)
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, err.Error())
+
+		return
+	}
+
+	response, err := provider.BlobSidecars(r.Context(), &api.BlobSidecarsOpts{Block: block})
+	if err != nil {
+		s.writeServiceError(w, "BlobSidecars", err)
+
+		return
+	}
+	s.writeResponse(w, r, response.Data, response.Metadata)
+}
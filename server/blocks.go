@@ -0,0 +1,381 @@
+// Copyright © 2023 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	consensusclient "github.com/attestantio/go-eth2-client"
+	"github.com/attestantio/go-eth2-client/api"
+	apiv1bellatrix "github.com/attestantio/go-eth2-client/api/v1/bellatrix"
+	apiv1capella "github.com/attestantio/go-eth2-client/api/v1/capella"
+	apiv1deneb "github.com/attestantio/go-eth2-client/api/v1/deneb"
+	"github.com/attestantio/go-eth2-client/spec"
+	"github.com/attestantio/go-eth2-client/spec/altair"
+	"github.com/attestantio/go-eth2-client/spec/bellatrix"
+	"github.com/attestantio/go-eth2-client/spec/capella"
+	"github.com/attestantio/go-eth2-client/spec/deneb"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/pkg/errors"
+)
+
+// sszUnmarshaler is the interface for data that can be supplied as SSZ.
+type sszUnmarshaler interface {
+	UnmarshalSSZ(buf []byte) error
+}
+
+func (s *Service) signedBeaconBlock(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	provider, isProvider := s.service.(consensusclient.SignedBeaconBlockProvider)
+	if !isProvider {
+		s.writeNotImplemented(w, "SignedBeaconBlock")
+
+		return
+	}
+	block, err := blockID(params)
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, err.Error())
+
+		return
+	}
+
+	response, err := provider.SignedBeaconBlock(r.Context(), &api.SignedBeaconBlockOpts{Block: block})
+	if err != nil {
+		s.writeServiceError(w, "SignedBeaconBlock", err)
+
+		return
+	}
+
+	var data any
+	switch response.Data.Version {
+	case spec.DataVersionPhase0:
+		data = response.Data.Phase0
+	case spec.DataVersionAltair:
+		data = response.Data.Altair
+	case spec.DataVersionBellatrix:
+		data = response.Data.Bellatrix
+	case spec.DataVersionCapella:
+		data = response.Data.Capella
+	case spec.DataVersionDeneb:
+		data = response.Data.Deneb
+	default:
+		s.writeError(w, http.StatusInternalServerError, fmt.Sprintf("unhandled block version %s", response.Data.Version))
+
+		return
+	}
+	s.writeVersionedResponse(w, r, data, response.Data.Version, response.Metadata)
+}
+
+func (s *Service) submitBeaconBlock(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	submitter, isSubmitter := s.service.(consensusclient.BeaconBlockSubmitter)
+	if !isSubmitter {
+		s.writeNotImplemented(w, "SubmitBeaconBlock")
+
+		return
+	}
+
+	block := &spec.VersionedSignedBeaconBlock{}
+	if err := decodeVersionedBody(r, spec.DataVersionPhase0, func(version spec.DataVersion) (any, error) {
+		*block = spec.VersionedSignedBeaconBlock{Version: version}
+		switch version {
+		case spec.DataVersionPhase0:
+			block.Phase0 = &phase0.SignedBeaconBlock{}
+
+			return block.Phase0, nil
+		case spec.DataVersionAltair:
+			block.Altair = &altair.SignedBeaconBlock{}
+
+			return block.Altair, nil
+		case spec.DataVersionBellatrix:
+			block.Bellatrix = &bellatrix.SignedBeaconBlock{}
+
+			return block.Bellatrix, nil
+		case spec.DataVersionCapella:
+			block.Capella = &capella.SignedBeaconBlock{}
+
+			return block.Capella, nil
+		case spec.DataVersionDeneb:
+			block.Deneb = &deneb.SignedBeaconBlock{}
+
+			return block.Deneb, nil
+		default:
+			return nil, fmt.Errorf("unsupported version %s", version)
+		}
+	}); err != nil {
+		s.writeError(w, http.StatusBadRequest, err.Error())
+
+		return
+	}
+
+	if err := submitter.SubmitBeaconBlock(r.Context(), block); err != nil {
+		s.writeServiceError(w, "SubmitBeaconBlock", err)
+
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Service) submitProposal(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	submitter, isSubmitter := s.service.(consensusclient.ProposalSubmitter)
+	if !isSubmitter {
+		s.writeNotImplemented(w, "SubmitProposal")
+
+		return
+	}
+	if r.Header.Get("Eth-Consensus-Version") == "" {
+		s.writeError(w, http.StatusBadRequest, "Eth-Consensus-Version header missing")
+
+		return
+	}
+
+	proposal := &api.VersionedSignedProposal{}
+	if err := decodeVersionedBody(r, spec.DataVersionPhase0, func(version spec.DataVersion) (any, error) {
+		*proposal = api.VersionedSignedProposal{Version: version}
+		switch version {
+		case spec.DataVersionPhase0:
+			proposal.Phase0 = &phase0.SignedBeaconBlock{}
+
+			return proposal.Phase0, nil
+		case spec.DataVersionAltair:
+			proposal.Altair = &altair.SignedBeaconBlock{}
+
+			return proposal.Altair, nil
+		case spec.DataVersionBellatrix:
+			proposal.Bellatrix = &bellatrix.SignedBeaconBlock{}
+
+			return proposal.Bellatrix, nil
+		case spec.DataVersionCapella:
+			proposal.Capella = &capella.SignedBeaconBlock{}
+
+			return proposal.Capella, nil
+		case spec.DataVersionDeneb:
+			proposal.Deneb = &apiv1deneb.SignedBlockContents{}
+
+			return proposal.Deneb, nil
+		default:
+			return nil, fmt.Errorf("unsupported version %s", version)
+		}
+	}); err != nil {
+		s.writeError(w, http.StatusBadRequest, err.Error())
+
+		return
+	}
+
+	if err := submitter.SubmitProposal(r.Context(), proposal); err != nil {
+		s.writeServiceError(w, "SubmitProposal", err)
+
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Service) submitBlindedBeaconBlock(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	submitter, isSubmitter := s.service.(consensusclient.BlindedBeaconBlockSubmitter)
+	if !isSubmitter {
+		s.writeNotImplemented(w, "SubmitBlindedBeaconBlock")
+
+		return
+	}
+
+	block := &api.VersionedSignedBlindedBeaconBlock{}
+	if err := decodeVersionedBody(r, spec.DataVersionBellatrix, func(version spec.DataVersion) (any, error) {
+		*block = api.VersionedSignedBlindedBeaconBlock{Version: version}
+		switch version {
+		case spec.DataVersionBellatrix:
+			block.Bellatrix = &apiv1bellatrix.SignedBlindedBeaconBlock{}
+
+			return block.Bellatrix, nil
+		case spec.DataVersionCapella:
+			block.Capella = &apiv1capella.SignedBlindedBeaconBlock{}
+
+			return block.Capella, nil
+		case spec.DataVersionDeneb:
+			block.Deneb = &apiv1deneb.SignedBlindedBeaconBlock{}
+
+			return block.Deneb, nil
+		default:
+			return nil, fmt.Errorf("unsupported version %s", version)
+		}
+	}); err != nil {
+		s.writeError(w, http.StatusBadRequest, err.Error())
+
+		return
+	}
+
+	if err := submitter.SubmitBlindedBeaconBlock(r.Context(), block); err != nil {
+		s.writeServiceError(w, "SubmitBlindedBeaconBlock", err)
+
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Service) submitBlindedProposal(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	submitter, isSubmitter := s.service.(consensusclient.BlindedProposalSubmitter)
+	if !isSubmitter {
+		s.writeNotImplemented(w, "SubmitBlindedProposal")
+
+		return
+	}
+	if r.Header.Get("Eth-Consensus-Version") == "" {
+		s.writeError(w, http.StatusBadRequest, "Eth-Consensus-Version header missing")
+
+		return
+	}
+
+	proposal := &api.VersionedSignedBlindedProposal{}
+	if err := decodeVersionedBody(r, spec.DataVersionBellatrix, func(version spec.DataVersion) (any, error) {
+		*proposal = api.VersionedSignedBlindedProposal{Version: version}
+		switch version {
+		case spec.DataVersionBellatrix:
+			proposal.Bellatrix = &apiv1bellatrix.SignedBlindedBeaconBlock{}
+
+			return proposal.Bellatrix, nil
+		case spec.DataVersionCapella:
+			proposal.Capella = &apiv1capella.SignedBlindedBeaconBlock{}
+
+			return proposal.Capella, nil
+		case spec.DataVersionDeneb:
+			proposal.Deneb = &apiv1deneb.SignedBlindedBlockContents{}
+
+			return proposal.Deneb, nil
+		default:
+			return nil, fmt.Errorf("unsupported version %s", version)
+		}
+	}); err != nil {
+		s.writeError(w, http.StatusBadRequest, err.Error())
+
+		return
+	}
+
+	if err := submitter.SubmitBlindedProposal(r.Context(), proposal); err != nil {
+		s.writeServiceError(w, "SubmitBlindedProposal", err)
+
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// decodeVersionedBody decodes the body of a request for versioned data.
+// The target function resets the versioned value and returns the field in to which data
+// of the given version is decoded.
+// If the request does not state its version in the Eth-Consensus-Version header then
+// JSON bodies are decoded as the most recent version that they match exactly, down to
+// the minimum version supplied.  A version matches exactly if it decodes every field of
+// the body, so a body is never decoded as an earlier version that ignores some of its
+// fields.
+func decodeVersionedBody(r *http.Request,
+	minVersion spec.DataVersion,
+	target func(version spec.DataVersion) (any, error),
+) error {
+	body, err := readBody(r)
+	if err != nil {
+		return err
+	}
+	version, err := consensusVersion(r)
+	if err != nil {
+		return err
+	}
+
+	if version != spec.DataVersionUnknown {
+		dst, err := target(version)
+		if err != nil {
+			return err
+		}
+
+		return decodeBody(r, body, dst)
+	}
+
+	if isSSZBody(r) {
+		return errors.New("Eth-Consensus-Version header required for SSZ body")
+	}
+	for version = spec.DataVersionDeneb; version >= minVersion; version-- {
+		dst, err := target(version)
+		if err != nil {
+			return err
+		}
+		if json.Unmarshal(body, dst) == nil && decodedAllFields(body, dst) {
+			return nil
+		}
+	}
+
+	return errors.New("Eth-Consensus-Version header missing and version could not be inferred from body")
+}
+
+// decodedAllFields returns true if re-encoding the decoded value provides all of the
+// fields present in the original JSON body.
+func decodedAllFields(body []byte, decoded any) bool {
+	encoded, err := json.Marshal(decoded)
+	if err != nil {
+		return false
+	}
+	var original any
+	if err := json.Unmarshal(body, &original); err != nil {
+		return false
+	}
+	var reencoded any
+	if err := json.Unmarshal(encoded, &reencoded); err != nil {
+		return false
+	}
+
+	originalFields := make(map[string]bool)
+	jsonFields(original, "", originalFields)
+	reencodedFields := make(map[string]bool)
+	jsonFields(reencoded, "", reencodedFields)
+	for field := range originalFields {
+		if !reencodedFields[field] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// jsonFields adds the paths of the fields of a decoded JSON value to the supplied map.
+func jsonFields(value any, prefix string, fields map[string]bool) {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, child := range v {
+			path := prefix + "." + key
+			fields[path] = true
+			jsonFields(child, path, fields)
+		}
+	case []any:
+		for _, child := range v {
+			jsonFields(child, prefix+"[]", fields)
+		}
+	}
+}
+
+// decodeBody decodes the body of a request as SSZ or JSON, according to its content type.
+func decodeBody(r *http.Request, body []byte, dst any) error {
+	if !isSSZBody(r) {
+		if err := json.Unmarshal(body, dst); err != nil {
+			return errors.Wrap(err, "invalid JSON body")
+		}
+
+		return nil
+	}
+
+	unmarshaler, isUnmarshaler := dst.(sszUnmarshaler)
+	if !isUnmarshaler {
+		return errors.New("SSZ not supported for this request")
+	}
+	if err := unmarshaler.UnmarshalSSZ(body); err != nil {
+		return errors.Wrap(err, "invalid SSZ body")
+	}
+
+	return nil
+}
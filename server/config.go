@@ -0,0 +1,100 @@
+// Copyright © 2023 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	consensusclient "github.com/attestantio/go-eth2-client"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+)
+
+func (s *Service) spec(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	provider, isProvider := s.service.(consensusclient.SpecProvider)
+	if !isProvider {
+		s.writeNotImplemented(w, "Spec")
+
+		return
+	}
+
+	response, err := provider.Spec(r.Context())
+	if err != nil {
+		s.writeServiceError(w, "Spec", err)
+
+		return
+	}
+
+	data := make(map[string]string, len(response.Data))
+	for k, v := range response.Data {
+		data[k] = specValue(v)
+	}
+	s.writeResponse(w, r, data, response.Metadata)
+}
+
+// specValue returns the spec string representation of a configuration value,
+// reversing the conversion carried out by clients when they parse the spec.
+func specValue(value any) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case phase0.DomainType:
+		return fmt.Sprintf("%#x", v[:])
+	case phase0.Version:
+		return fmt.Sprintf("%#x", v[:])
+	case []byte:
+		return fmt.Sprintf("%#x", v)
+	case time.Time:
+		return fmt.Sprintf("%d", v.Unix())
+	case time.Duration:
+		return fmt.Sprintf("%d", int64(v/time.Second))
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+func (s *Service) forkSchedule(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	provider, isProvider := s.service.(consensusclient.ForkScheduleProvider)
+	if !isProvider {
+		s.writeNotImplemented(w, "ForkSchedule")
+
+		return
+	}
+
+	response, err := provider.ForkSchedule(r.Context())
+	if err != nil {
+		s.writeServiceError(w, "ForkSchedule", err)
+
+		return
+	}
+	s.writeResponse(w, r, response.Data, response.Metadata)
+}
+
+func (s *Service) depositContract(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	provider, isProvider := s.service.(consensusclient.DepositContractProvider)
+	if !isProvider {
+		s.writeNotImplemented(w, "DepositContract")
+
+		return
+	}
+
+	response, err := provider.DepositContract(r.Context())
+	if err != nil {
+		s.writeServiceError(w, "DepositContract", err)
+
+		return
+	}
+	s.writeResponse(w, r, response.Data, response.Metadata)
+}
@@ -0,0 +1,91 @@
+// Copyright © 2023 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	consensusclient "github.com/attestantio/go-eth2-client"
+	"github.com/attestantio/go-eth2-client/api"
+	"github.com/attestantio/go-eth2-client/spec"
+	"github.com/pkg/errors"
+)
+
+func (s *Service) beaconState(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	provider, isProvider := s.service.(consensusclient.BeaconStateProvider)
+	if !isProvider {
+		s.writeNotImplemented(w, "BeaconState")
+
+		return
+	}
+	state, err := stateID(params)
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, err.Error())
+
+		return
+	}
+
+	response, err := provider.BeaconState(r.Context(), &api.BeaconStateOpts{State: state})
+	if err != nil {
+		s.writeServiceError(w, "BeaconState", err)
+
+		return
+	}
+
+	var data any
+	switch response.Data.Version {
+	case spec.DataVersionPhase0:
+		data = response.Data.Phase0
+	case spec.DataVersionAltair:
+		data = response.Data.Altair
+	case spec.DataVersionBellatrix:
+		data = response.Data.Bellatrix
+	case spec.DataVersionCapella:
+		data = response.Data.Capella
+	case spec.DataVersionDeneb:
+		data = response.Data.Deneb
+	default:
+		s.writeError(w, http.StatusInternalServerError, fmt.Sprintf("unhandled state version %s", response.Data.Version))
+
+		return
+	}
+	s.writeVersionedResponse(w, r, data, response.Data.Version, response.Metadata)
+}
+
+func (s *Service) forkChoice(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	provider, isProvider := s.service.(consensusclient.ForkChoiceProvider)
+	if !isProvider {
+		s.writeNotImplemented(w, "ForkChoice")
+
+		return
+	}
+
+	response, err := provider.ForkChoice(r.Context())
+	if err != nil {
+		s.writeServiceError(w, "ForkChoice", err)
+
+		return
+	}
+
+	// Fork choice is not wrapped in a data object.
+	data, err := json.Marshal(response.Data)
+	if err != nil {
+		s.writeError(w, http.StatusInternalServerError, errors.Wrap(err, "failed to marshal JSON").Error())
+
+		return
+	}
+	s.write(w, http.StatusOK, mediaTypeJSON, data)
+}
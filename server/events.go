@@ -0,0 +1,100 @@
+// Copyright © 2023 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	consensusclient "github.com/attestantio/go-eth2-client"
+	apiv1 "github.com/attestantio/go-eth2-client/api/v1"
+)
+
+// eventBufferSize is the number of events buffered for each stream before events are dropped.
+const eventBufferSize = 256
+
+// events streams events from the service as server-sent events.
+func (s *Service) events(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	provider, isProvider := s.service.(consensusclient.EventsProvider)
+	if !isProvider {
+		s.writeNotImplemented(w, "Events")
+
+		return
+	}
+	topics := queryList(r, "topics")
+	if len(topics) == 0 {
+		s.writeError(w, http.StatusBadRequest, "topics missing")
+
+		return
+	}
+	for _, topic := range topics {
+		if !apiv1.SupportedEventTopics[topic] {
+			s.writeError(w, http.StatusBadRequest, fmt.Sprintf("unsupported topic %s", topic))
+
+			return
+		}
+	}
+	flusher, isFlusher := w.(http.Flusher)
+	if !isFlusher {
+		s.writeError(w, http.StatusInternalServerError, "streaming not supported")
+
+		return
+	}
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+
+	// The handler must not block the service, so events are buffered and dropped
+	// if the client cannot keep up.
+	events := make(chan *apiv1.Event, eventBufferSize)
+	if err := provider.Events(ctx, topics, func(event *apiv1.Event) {
+		select {
+		case events <- event:
+		default:
+			s.log.Debug().Str("topic", event.Topic).Msg("Event stream full; dropping event")
+		}
+	}); err != nil {
+		s.writeServiceError(w, "Events", err)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event := <-events:
+			data, err := json.Marshal(event.Data)
+			if err != nil {
+				s.log.Warn().Str("topic", event.Topic).Err(err).Msg("Failed to marshal event")
+
+				continue
+			}
+			if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Topic, data); err != nil {
+				s.log.Debug().Err(err).Msg("Failed to write event; closing stream")
+
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
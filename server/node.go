@@ -0,0 +1,80 @@
+// Copyright © 2023 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"net/http"
+
+	consensusclient "github.com/attestantio/go-eth2-client"
+	"github.com/attestantio/go-eth2-client/api"
+)
+
+// versionJSON is the spec representation of a node version response.
+type versionJSON struct {
+	Version string `json:"version"`
+}
+
+func (s *Service) nodeVersion(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	provider, isProvider := s.service.(consensusclient.NodeVersionProvider)
+	if !isProvider {
+		s.writeNotImplemented(w, "NodeVersion")
+
+		return
+	}
+
+	response, err := provider.NodeVersion(r.Context())
+	if err != nil {
+		s.writeServiceError(w, "NodeVersion", err)
+
+		return
+	}
+	s.writeResponse(w, r, &versionJSON{Version: response.Data}, response.Metadata)
+}
+
+func (s *Service) nodeSyncing(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	provider, isProvider := s.service.(consensusclient.NodeSyncingProvider)
+	if !isProvider {
+		s.writeNotImplemented(w, "NodeSyncing")
+
+		return
+	}
+
+	response, err := provider.NodeSyncing(r.Context())
+	if err != nil {
+		s.writeServiceError(w, "NodeSyncing", err)
+
+		return
+	}
+	s.writeResponse(w, r, response.Data, response.Metadata)
+}
+
+func (s *Service) nodePeers(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	provider, isProvider := s.service.(consensusclient.NodePeersProvider)
+	if !isProvider {
+		s.writeNotImplemented(w, "NodePeers")
+
+		return
+	}
+
+	response, err := provider.NodePeers(r.Context(), &api.PeerOpts{
+		State:     queryList(r, "state"),
+		Direction: queryList(r, "direction"),
+	})
+	if err != nil {
+		s.writeServiceError(w, "NodePeers", err)
+
+		return
+	}
+	s.writeResponse(w, r, response.Data, response.Metadata)
+}
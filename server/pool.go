@@ -0,0 +1,199 @@
+// Copyright © 2023 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"net/http"
+
+	consensusclient "github.com/attestantio/go-eth2-client"
+	"github.com/attestantio/go-eth2-client/api"
+	"github.com/attestantio/go-eth2-client/spec/altair"
+	"github.com/attestantio/go-eth2-client/spec/capella"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+)
+
+func (s *Service) attestationPool(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	provider, isProvider := s.service.(consensusclient.AttestationPoolProvider)
+	if !isProvider {
+		s.writeNotImplemented(w, "AttestationPool")
+
+		return
+	}
+	slot, err := queryUint64(r, "slot")
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, err.Error())
+
+		return
+	}
+
+	response, err := provider.AttestationPool(r.Context(), &api.AttestationPoolOpts{Slot: phase0.Slot(slot)})
+	if err != nil {
+		s.writeServiceError(w, "AttestationPool", err)
+
+		return
+	}
+	s.writeResponse(w, r, response.Data, response.Metadata)
+}
+
+func (s *Service) submitAttestations(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	submitter, isSubmitter := s.service.(consensusclient.AttestationsSubmitter)
+	if !isSubmitter {
+		s.writeNotImplemented(w, "SubmitAttestations")
+
+		return
+	}
+	var attestations []*phase0.Attestation
+	if err := decodeJSONBody(r, &attestations); err != nil {
+		s.writeError(w, http.StatusBadRequest, err.Error())
+
+		return
+	}
+
+	if err := submitter.SubmitAttestations(r.Context(), attestations); err != nil {
+		s.writeServiceError(w, "SubmitAttestations", err)
+
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Service) submitSyncCommitteeMessages(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	submitter, isSubmitter := s.service.(consensusclient.SyncCommitteeMessagesSubmitter)
+	if !isSubmitter {
+		s.writeNotImplemented(w, "SubmitSyncCommitteeMessages")
+
+		return
+	}
+	var messages []*altair.SyncCommitteeMessage
+	if err := decodeJSONBody(r, &messages); err != nil {
+		s.writeError(w, http.StatusBadRequest, err.Error())
+
+		return
+	}
+
+	if err := submitter.SubmitSyncCommitteeMessages(r.Context(), messages); err != nil {
+		s.writeServiceError(w, "SubmitSyncCommitteeMessages", err)
+
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Service) voluntaryExitPool(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	provider, isProvider := s.service.(consensusclient.VoluntaryExitPoolProvider)
+	if !isProvider {
+		s.writeNotImplemented(w, "VoluntaryExitPool")
+
+		return
+	}
+
+	exits, err := provider.VoluntaryExitPool(r.Context())
+	if err != nil {
+		s.writeServiceError(w, "VoluntaryExitPool", err)
+
+		return
+	}
+	if exits == nil {
+		exits = make([]*phase0.SignedVoluntaryExit, 0)
+	}
+	s.writeResponse(w, r, exits, nil)
+}
+
+func (s *Service) submitVoluntaryExit(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	submitter, isSubmitter := s.service.(consensusclient.VoluntaryExitSubmitter)
+	if !isSubmitter {
+		s.writeNotImplemented(w, "SubmitVoluntaryExit")
+
+		return
+	}
+	exit := &phase0.SignedVoluntaryExit{}
+	if err := decodeJSONBody(r, exit); err != nil {
+		s.writeError(w, http.StatusBadRequest, err.Error())
+
+		return
+	}
+
+	if err := submitter.SubmitVoluntaryExit(r.Context(), exit); err != nil {
+		s.writeServiceError(w, "SubmitVoluntaryExit", err)
+
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Service) submitAttesterSlashing(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	submitter, isSubmitter := s.service.(consensusclient.AttesterSlashingSubmitter)
+	if !isSubmitter {
+		s.writeNotImplemented(w, "SubmitAttesterSlashing")
+
+		return
+	}
+	slashing := &phase0.AttesterSlashing{}
+	if err := decodeJSONBody(r, slashing); err != nil {
+		s.writeError(w, http.StatusBadRequest, err.Error())
+
+		return
+	}
+
+	if err := submitter.SubmitAttesterSlashing(r.Context(), slashing); err != nil {
+		s.writeServiceError(w, "SubmitAttesterSlashing", err)
+
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Service) submitProposalSlashing(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	submitter, isSubmitter := s.service.(consensusclient.ProposalSlashingSubmitter)
+	if !isSubmitter {
+		s.writeNotImplemented(w, "SubmitProposalSlashing")
+
+		return
+	}
+	slashing := &phase0.ProposerSlashing{}
+	if err := decodeJSONBody(r, slashing); err != nil {
+		s.writeError(w, http.StatusBadRequest, err.Error())
+
+		return
+	}
+
+	if err := submitter.SubmitProposalSlashing(r.Context(), slashing); err != nil {
+		s.writeServiceError(w, "SubmitProposalSlashing", err)
+
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Service) submitBLSToExecutionChanges(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	submitter, isSubmitter := s.service.(consensusclient.BLSToExecutionChangesSubmitter)
+	if !isSubmitter {
+		s.writeNotImplemented(w, "SubmitBLSToExecutionChanges")
+
+		return
+	}
+	var changes []*capella.SignedBLSToExecutionChange
+	if err := decodeJSONBody(r, &changes); err != nil {
+		s.writeError(w, http.StatusBadRequest, err.Error())
+
+		return
+	}
+
+	if err := submitter.SubmitBLSToExecutionChanges(r.Context(), changes); err != nil {
+		s.writeServiceError(w, "SubmitBLSToExecutionChanges", err)
+
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,191 @@
+// Copyright © 2023 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"fmt"
+	"net/http"
+
+	consensusclient "github.com/attestantio/go-eth2-client"
+	"github.com/attestantio/go-eth2-client/api"
+	"github.com/attestantio/go-eth2-client/spec"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+)
+
+func (s *Service) proposal(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	response, ok := s.obtainProposal(w, r, params)
+	if !ok {
+		return
+	}
+
+	data, err := proposalData(response.Data)
+	if err != nil {
+		s.writeError(w, http.StatusInternalServerError, err.Error())
+
+		return
+	}
+	s.writeVersionedResponse(w, r, data, response.Data.Version, response.Metadata)
+}
+
+// proposalV3 serves a proposal from the v3 endpoint.
+// The service does not state if a proposal would have been blinded, so the
+// endpoint always returns an unblinded proposal.
+func (s *Service) proposalV3(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	response, ok := s.obtainProposal(w, r, params)
+	if !ok {
+		return
+	}
+
+	data, err := proposalData(response.Data)
+	if err != nil {
+		s.writeError(w, http.StatusInternalServerError, err.Error())
+
+		return
+	}
+
+	metadata := make(map[string]any, len(response.Metadata)+5)
+	for k, v := range response.Metadata {
+		metadata[k] = v
+	}
+	metadata["Eth-Execution-Payload-Blinded"] = "false"
+	metadata["execution_payload_blinded"] = false
+	for header, key := range map[string]string{
+		"Eth-Execution-Payload-Value": "execution_payload_value",
+		"Eth-Consensus-Block-Value":   "consensus_block_value",
+	} {
+		if _, exists := metadata[header]; !exists {
+			metadata[header] = "0"
+		}
+		metadata[key] = fmt.Sprintf("%v", metadata[header])
+	}
+	s.writeVersionedResponse(w, r, data, response.Data.Version, metadata)
+}
+
+// obtainProposal obtains a proposal from the service, writing an error response
+// and returning false if it cannot.
+func (s *Service) obtainProposal(w http.ResponseWriter,
+	r *http.Request,
+	params map[string]string,
+) (
+	*api.Response[*api.VersionedProposal],
+	bool,
+) {
+	provider, isProvider := s.service.(consensusclient.ProposalProvider)
+	if !isProvider {
+		s.writeNotImplemented(w, "Proposal")
+
+		return nil, false
+	}
+	opts, err := proposalOpts(r, params)
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, err.Error())
+
+		return nil, false
+	}
+
+	response, err := provider.Proposal(r.Context(), opts)
+	if err != nil {
+		s.writeServiceError(w, "Proposal", err)
+
+		return nil, false
+	}
+
+	return response, true
+}
+
+func (s *Service) blindedProposal(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	provider, isProvider := s.service.(consensusclient.BlindedProposalProvider)
+	if !isProvider {
+		s.writeNotImplemented(w, "BlindedProposal")
+
+		return
+	}
+	opts, err := proposalOpts(r, params)
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, err.Error())
+
+		return
+	}
+
+	response, err := provider.BlindedProposal(r.Context(), &api.BlindedProposalOpts{
+		Slot:                   opts.Slot,
+		RandaoReveal:           opts.RandaoReveal,
+		Graffiti:               opts.Graffiti,
+		SkipRandaoVerification: opts.SkipRandaoVerification,
+	})
+	if err != nil {
+		s.writeServiceError(w, "BlindedProposal", err)
+
+		return
+	}
+
+	var data any
+	switch response.Data.Version {
+	case spec.DataVersionBellatrix:
+		data = response.Data.Bellatrix
+	case spec.DataVersionCapella:
+		data = response.Data.Capella
+	case spec.DataVersionDeneb:
+		data = response.Data.Deneb
+	default:
+		s.writeError(w, http.StatusInternalServerError, fmt.Sprintf("unhandled blinded proposal version %s", response.Data.Version))
+
+		return
+	}
+	s.writeVersionedResponse(w, r, data, response.Data.Version, response.Metadata)
+}
+
+// proposalOpts returns the options for a proposal request.
+func proposalOpts(r *http.Request, params map[string]string) (*api.ProposalOpts, error) {
+	slot, err := parseUint64("slot", params["slot"])
+	if err != nil {
+		return nil, err
+	}
+	randaoReveal, err := queryHex(r, "randao_reveal", phase0.SignatureLength)
+	if err != nil {
+		return nil, err
+	}
+	opts := &api.ProposalOpts{
+		Slot:                   phase0.Slot(slot),
+		RandaoReveal:           phase0.BLSSignature(randaoReveal),
+		SkipRandaoVerification: r.URL.Query().Has("skip_randao_verification"),
+	}
+	if r.URL.Query().Has("graffiti") {
+		graffiti, err := queryHex(r, "graffiti", 32)
+		if err != nil {
+			return nil, err
+		}
+		copy(opts.Graffiti[:], graffiti)
+	}
+
+	return opts, nil
+}
+
+// proposalData returns the unversioned data of a proposal.
+func proposalData(proposal *api.VersionedProposal) (any, error) {
+	switch proposal.Version {
+	case spec.DataVersionPhase0:
+		return proposal.Phase0, nil
+	case spec.DataVersionAltair:
+		return proposal.Altair, nil
+	case spec.DataVersionBellatrix:
+		return proposal.Bellatrix, nil
+	case spec.DataVersionCapella:
+		return proposal.Capella, nil
+	case spec.DataVersionDeneb:
+		return proposal.Deneb, nil
+	default:
+		return nil, fmt.Errorf("unhandled proposal version %s", proposal.Version)
+	}
+}
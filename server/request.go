@@ -0,0 +1,199 @@
+// Copyright © 2023 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/attestantio/go-eth2-client/api"
+	"github.com/attestantio/go-eth2-client/spec"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/pkg/errors"
+)
+
+// maxBodySize is the maximum size of a request body.
+const maxBodySize = 64 * 1024 * 1024
+
+// stateID returns the state ID from the path parameters.
+func stateID(params map[string]string) (api.StateID, error) {
+	stateID := api.StateID(params["state_id"])
+	if err := stateID.Validate(); err != nil {
+		return "", err
+	}
+
+	return stateID, nil
+}
+
+// blockID returns the block ID from the path parameters.
+func blockID(params map[string]string) (api.BlockID, error) {
+	blockID := api.BlockID(params["block_id"])
+	if err := blockID.Validate(); err != nil {
+		return "", err
+	}
+
+	return blockID, nil
+}
+
+// parseUint64 parses a decimal value.
+func parseUint64(name string, input string) (uint64, error) {
+	val, err := strconv.ParseUint(input, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s %q", name, input)
+	}
+
+	return val, nil
+}
+
+// queryUint64 returns a mandatory decimal query parameter.
+func queryUint64(r *http.Request, name string) (uint64, error) {
+	input := r.URL.Query().Get(name)
+	if input == "" {
+		return 0, fmt.Errorf("%s missing", name)
+	}
+
+	return parseUint64(name, input)
+}
+
+// queryHex returns a mandatory hex query parameter of the given length.
+func queryHex(r *http.Request, name string, length int) ([]byte, error) {
+	input := r.URL.Query().Get(name)
+	if input == "" {
+		return nil, fmt.Errorf("%s missing", name)
+	}
+
+	return parseHex(name, input, length)
+}
+
+// parseHex parses a 0x-prefixed hex value of the given length.
+func parseHex(name string, input string, length int) ([]byte, error) {
+	data, err := hex.DecodeString(strings.TrimPrefix(input, "0x"))
+	if err != nil || len(data) != length {
+		return nil, fmt.Errorf("invalid %s %q", name, input)
+	}
+
+	return data, nil
+}
+
+// queryList returns the values of a query parameter, which can be repeated and
+// comma-separated.
+func queryList(r *http.Request, name string) []string {
+	values := make([]string, 0)
+	for _, value := range r.URL.Query()[name] {
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				values = append(values, item)
+			}
+		}
+	}
+
+	return values
+}
+
+// validatorIDs splits validator IDs in to indices and public keys.
+func validatorIDs(ids []string) ([]phase0.ValidatorIndex, []phase0.BLSPubKey, error) {
+	indices := make([]phase0.ValidatorIndex, 0)
+	pubKeys := make([]phase0.BLSPubKey, 0)
+	for _, id := range ids {
+		if strings.HasPrefix(id, "0x") {
+			data, err := parseHex("validator public key", id, phase0.PublicKeyLength)
+			if err != nil {
+				return nil, nil, err
+			}
+			var pubKey phase0.BLSPubKey
+			copy(pubKey[:], data)
+			pubKeys = append(pubKeys, pubKey)
+
+			continue
+		}
+		index, err := parseUint64("validator index", id)
+		if err != nil {
+			return nil, nil, err
+		}
+		indices = append(indices, phase0.ValidatorIndex(index))
+	}
+
+	return indices, pubKeys, nil
+}
+
+// readBody reads the body of a request.
+func readBody(r *http.Request) ([]byte, error) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxBodySize))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read body")
+	}
+
+	return body, nil
+}
+
+// decodeJSONBody decodes the JSON body of a request in to the supplied value.
+func decodeJSONBody(r *http.Request, v any) error {
+	body, err := readBody(r)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		return errors.Wrap(err, "invalid JSON body")
+	}
+
+	return nil
+}
+
+// indicesFromBody decodes a body containing a list of validator indices.
+func indicesFromBody(r *http.Request) ([]phase0.ValidatorIndex, error) {
+	var ids []string
+	if err := decodeJSONBody(r, &ids); err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return nil, errors.New("no validator indices supplied")
+	}
+
+	indices := make([]phase0.ValidatorIndex, len(ids))
+	for i := range ids {
+		index, err := parseUint64("validator index", ids[i])
+		if err != nil {
+			return nil, err
+		}
+		indices[i] = phase0.ValidatorIndex(index)
+	}
+
+	return indices, nil
+}
+
+// consensusVersion returns the consensus version from the Eth-Consensus-Version header
+// of a request, or spec.DataVersionUnknown if not present.
+func consensusVersion(r *http.Request) (spec.DataVersion, error) {
+	header := r.Header.Get("Eth-Consensus-Version")
+	if header == "" {
+		return spec.DataVersionUnknown, nil
+	}
+
+	var version spec.DataVersion
+	if err := version.UnmarshalJSON([]byte(fmt.Sprintf("%q", header))); err != nil {
+		return spec.DataVersionUnknown, err
+	}
+
+	return version, nil
+}
+
+// isSSZBody returns true if the body of the request is SSZ.
+func isSSZBody(r *http.Request) bool {
+	return strings.HasPrefix(strings.ToLower(r.Header.Get("Content-Type")), mediaTypeSSZ)
+}
@@ -0,0 +1,211 @@
+// Copyright © 2023 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/attestantio/go-eth2-client/api"
+	"github.com/attestantio/go-eth2-client/spec"
+	"github.com/pkg/errors"
+)
+
+const (
+	mediaTypeJSON = "application/json"
+	mediaTypeSSZ  = "application/octet-stream"
+)
+
+// bodyMetadata are the metadata keys that are returned in the body of a response
+// alongside the data.
+var bodyMetadata = map[string]bool{
+	"execution_optimistic":      true,
+	"finalized":                 true,
+	"dependent_root":            true,
+	"execution_payload_blinded": true,
+	"execution_payload_value":   true,
+	"consensus_block_value":     true,
+}
+
+// sszMarshaler is the interface for data that can be returned as SSZ.
+type sszMarshaler interface {
+	MarshalSSZ() ([]byte, error)
+}
+
+// errorResponse is the body of an error response.
+type errorResponse struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// writeError writes an error response.
+func (s *Service) writeError(w http.ResponseWriter, statusCode int, message string) {
+	data, err := json.Marshal(&errorResponse{
+		Code:    statusCode,
+		Message: message,
+	})
+	if err != nil {
+		s.log.Error().Err(err).Msg("Failed to marshal error response")
+		w.WriteHeader(http.StatusInternalServerError)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", mediaTypeJSON)
+	w.WriteHeader(statusCode)
+	if _, err := w.Write(data); err != nil {
+		s.log.Debug().Err(err).Msg("Failed to write error response")
+	}
+}
+
+// writeNotImplemented writes the response for an endpoint that the backing service does not support.
+func (s *Service) writeNotImplemented(w http.ResponseWriter, call string) {
+	s.writeError(w, http.StatusNotImplemented, fmt.Sprintf("%s not supported by the service", call))
+}
+
+// writeServiceError writes the response for an error returned by the backing service.
+// Errors from upstream beacon nodes keep their status code.
+func (s *Service) writeServiceError(w http.ResponseWriter, call string, err error) {
+	var apiErr *api.Error
+	switch {
+	case errors.As(err, &apiErr):
+		var upstream errorResponse
+		if json.Unmarshal(apiErr.Data, &upstream) == nil && upstream.Message != "" {
+			s.writeError(w, apiErr.StatusCode, upstream.Message)
+		} else {
+			s.writeError(w, apiErr.StatusCode, err.Error())
+		}
+	case errors.Is(err, api.ErrNotSupported):
+		s.writeNotImplemented(w, call)
+	case errors.Is(err, context.DeadlineExceeded):
+		s.writeError(w, http.StatusServiceUnavailable, fmt.Sprintf("%s timed out", call))
+	default:
+		s.log.Debug().Str("call", call).Err(err).Msg("Call failed")
+		s.writeError(w, http.StatusInternalServerError, err.Error())
+	}
+}
+
+// writeResponse writes a successful JSON response containing the given data and metadata.
+func (s *Service) writeResponse(w http.ResponseWriter, r *http.Request, data any, metadata map[string]any) {
+	s.respond(w, r, data, spec.DataVersionUnknown, metadata, false)
+}
+
+// writeVersionedResponse writes a successful response containing the given versioned data and metadata.
+// The version is returned in the Eth-Consensus-Version header and the body.
+// The data is returned as SSZ if the request prefers it, otherwise JSON.
+func (s *Service) writeVersionedResponse(w http.ResponseWriter,
+	r *http.Request,
+	data any,
+	version spec.DataVersion,
+	metadata map[string]any,
+) {
+	s.respond(w, r, data, version, metadata, true)
+}
+
+// respond writes a successful response.
+func (s *Service) respond(w http.ResponseWriter,
+	r *http.Request,
+	data any,
+	version spec.DataVersion,
+	metadata map[string]any,
+	allowSSZ bool,
+) {
+	body := make(map[string]any)
+	for k, v := range metadata {
+		switch {
+		case bodyMetadata[k]:
+			body[k] = v
+		case strings.HasPrefix(strings.ToLower(k), "eth-") && !strings.EqualFold(k, "Eth-Consensus-Version"):
+			w.Header().Set(k, fmt.Sprintf("%v", v))
+		}
+	}
+	if version != spec.DataVersionUnknown {
+		w.Header().Set("Eth-Consensus-Version", version.String())
+		body["version"] = version.String()
+	}
+
+	if marshaler, isMarshaler := data.(sszMarshaler); allowSSZ && isMarshaler && prefersSSZ(r) {
+		encoded, err := marshaler.MarshalSSZ()
+		if err != nil {
+			s.writeError(w, http.StatusInternalServerError, errors.Wrap(err, "failed to marshal SSZ").Error())
+
+			return
+		}
+		s.write(w, http.StatusOK, mediaTypeSSZ, encoded)
+
+		return
+	}
+
+	body["data"] = data
+	encoded, err := json.Marshal(body)
+	if err != nil {
+		s.writeError(w, http.StatusInternalServerError, errors.Wrap(err, "failed to marshal JSON").Error())
+
+		return
+	}
+	s.write(w, http.StatusOK, mediaTypeJSON, encoded)
+}
+
+// write writes a response body.
+func (s *Service) write(w http.ResponseWriter, statusCode int, contentType string, body []byte) {
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+	w.WriteHeader(statusCode)
+	if _, err := w.Write(body); err != nil {
+		s.log.Debug().Err(err).Msg("Failed to write response")
+	}
+}
+
+// prefersSSZ returns true if the Accept header of the request prefers SSZ to JSON.
+func prefersSSZ(r *http.Request) bool {
+	sszQuality := -1.0
+	jsonQuality := -1.0
+	for _, accept := range r.Header.Values("Accept") {
+		for _, part := range strings.Split(accept, ",") {
+			mediaType, quality := parseMediaRange(part)
+			switch mediaType {
+			case mediaTypeSSZ:
+				sszQuality = quality
+			case mediaTypeJSON, "*/*", "application/*":
+				if quality > jsonQuality {
+					jsonQuality = quality
+				}
+			}
+		}
+	}
+
+	return sszQuality > 0 && sszQuality > jsonQuality
+}
+
+// parseMediaRange parses an entry from an Accept header, returning the media type
+// and its quality.
+func parseMediaRange(input string) (string, float64) {
+	parts := strings.Split(input, ";")
+	quality := 1.0
+	for _, param := range parts[1:] {
+		key, value, found := strings.Cut(strings.TrimSpace(param), "=")
+		if !found || strings.TrimSpace(key) != "q" {
+			continue
+		}
+		if q, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil {
+			quality = q
+		}
+	}
+
+	return strings.ToLower(strings.TrimSpace(parts[0])), quality
+}
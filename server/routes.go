@@ -0,0 +1,86 @@
+// Copyright © 2023 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"net/http"
+)
+
+// addRoutes adds the routes served by the server.
+func (s *Service) addRoutes() {
+	// Beacon.
+	s.handle(http.MethodGet, "/eth/v1/beacon/genesis", s.genesis)
+	s.handle(http.MethodGet, "/eth/v1/beacon/states/{state_id}/root", s.beaconStateRoot)
+	s.handle(http.MethodGet, "/eth/v1/beacon/states/{state_id}/fork", s.fork)
+	s.handle(http.MethodGet, "/eth/v1/beacon/states/{state_id}/finality_checkpoints", s.finality)
+	s.handle(http.MethodGet, "/eth/v1/beacon/states/{state_id}/validators", s.validators)
+	s.handle(http.MethodPost, "/eth/v1/beacon/states/{state_id}/validators", s.validators)
+	s.handle(http.MethodGet, "/eth/v1/beacon/states/{state_id}/validator_balances", s.validatorBalances)
+	s.handle(http.MethodPost, "/eth/v1/beacon/states/{state_id}/validator_balances", s.validatorBalances)
+	s.handle(http.MethodGet, "/eth/v1/beacon/states/{state_id}/committees", s.beaconCommittees)
+	s.handle(http.MethodGet, "/eth/v1/beacon/states/{state_id}/sync_committees", s.syncCommittee)
+	s.handle(http.MethodGet, "/eth/v1/beacon/states/{state_id}/randao", s.beaconStateRandao)
+	s.handle(http.MethodGet, "/eth/v1/beacon/headers/{block_id}", s.beaconBlockHeader)
+	s.handle(http.MethodGet, "/eth/v1/beacon/blocks/{block_id}/root", s.beaconBlockRoot)
+	s.handle(http.MethodGet, "/eth/v2/beacon/blocks/{block_id}", s.signedBeaconBlock)
+	s.handle(http.MethodGet, "/eth/v1/beacon/blob_sidecars/{block_id}", s.blobSidecars)
+	s.handle(http.MethodPost, "/eth/v1/beacon/blocks", s.submitBeaconBlock)
+	s.handle(http.MethodPost, "/eth/v2/beacon/blocks", s.submitProposal)
+	s.handle(http.MethodPost, "/eth/v1/beacon/blinded_blocks", s.submitBlindedBeaconBlock)
+	s.handle(http.MethodPost, "/eth/v2/beacon/blinded_blocks", s.submitBlindedProposal)
+
+	// Pool.
+	s.handle(http.MethodGet, "/eth/v1/beacon/pool/attestations", s.attestationPool)
+	s.handle(http.MethodPost, "/eth/v1/beacon/pool/attestations", s.submitAttestations)
+	s.handle(http.MethodPost, "/eth/v1/beacon/pool/sync_committees", s.submitSyncCommitteeMessages)
+	s.handle(http.MethodGet, "/eth/v1/beacon/pool/voluntary_exits", s.voluntaryExitPool)
+	s.handle(http.MethodPost, "/eth/v1/beacon/pool/voluntary_exits", s.submitVoluntaryExit)
+	s.handle(http.MethodPost, "/eth/v1/beacon/pool/attester_slashings", s.submitAttesterSlashing)
+	s.handle(http.MethodPost, "/eth/v1/beacon/pool/proposer_slashings", s.submitProposalSlashing)
+	s.handle(http.MethodPost, "/eth/v1/beacon/pool/bls_to_execution_changes", s.submitBLSToExecutionChanges)
+
+	// Config.
+	s.handle(http.MethodGet, "/eth/v1/config/spec", s.spec)
+	s.handle(http.MethodGet, "/eth/v1/config/fork_schedule", s.forkSchedule)
+	s.handle(http.MethodGet, "/eth/v1/config/deposit_contract", s.depositContract)
+
+	// Debug.
+	s.handle(http.MethodGet, "/eth/v2/debug/beacon/states/{state_id}", s.beaconState)
+	s.handle(http.MethodGet, "/eth/v1/debug/fork_choice", s.forkChoice)
+
+	// Node.
+	s.handle(http.MethodGet, "/eth/v1/node/version", s.nodeVersion)
+	s.handle(http.MethodGet, "/eth/v1/node/syncing", s.nodeSyncing)
+	s.handle(http.MethodGet, "/eth/v1/node/peers", s.nodePeers)
+
+	// Validator.
+	s.handle(http.MethodPost, "/eth/v1/validator/duties/attester/{epoch}", s.attesterDuties)
+	s.handle(http.MethodGet, "/eth/v1/validator/duties/proposer/{epoch}", s.proposerDuties)
+	s.handle(http.MethodPost, "/eth/v1/validator/duties/sync/{epoch}", s.syncCommitteeDuties)
+	s.handle(http.MethodGet, "/eth/v1/validator/attestation_data", s.attestationData)
+	s.handle(http.MethodGet, "/eth/v1/validator/aggregate_attestation", s.aggregateAttestation)
+	s.handle(http.MethodGet, "/eth/v1/validator/sync_committee_contribution", s.syncCommitteeContribution)
+	s.handle(http.MethodGet, "/eth/v2/validator/blocks/{slot}", s.proposal)
+	s.handle(http.MethodGet, "/eth/v3/validator/blocks/{slot}", s.proposalV3)
+	s.handle(http.MethodGet, "/eth/v1/validator/blinded_blocks/{slot}", s.blindedProposal)
+	s.handle(http.MethodPost, "/eth/v1/validator/aggregate_and_proofs", s.submitAggregateAttestations)
+	s.handle(http.MethodPost, "/eth/v1/validator/beacon_committee_subscriptions", s.submitBeaconCommitteeSubscriptions)
+	s.handle(http.MethodPost, "/eth/v1/validator/sync_committee_subscriptions", s.submitSyncCommitteeSubscriptions)
+	s.handle(http.MethodPost, "/eth/v1/validator/contribution_and_proofs", s.submitSyncCommitteeContributions)
+	s.handle(http.MethodPost, "/eth/v1/validator/prepare_beacon_proposer", s.submitProposalPreparations)
+	s.handle(http.MethodPost, "/eth/v1/validator/register_validator", s.submitValidatorRegistrations)
+
+	// Events.
+	s.handleStream(http.MethodGet, "/eth/v1/events", s.events)
+}
@@ -0,0 +1,194 @@
+// Copyright © 2023 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	consensusclient "github.com/attestantio/go-eth2-client"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+	zerologger "github.com/rs/zerolog/log"
+)
+
+// Service is a beacon API server, serving the standard REST endpoints from a consensus
+// client service.
+// Endpoints are backed by the provider and submitter interfaces that the service implements;
+// those that it does not implement return 501 Not Implemented.
+type Service struct {
+	log      zerolog.Logger
+	service  consensusclient.Service
+	timeout  time.Duration
+	routes   []*route
+	server   *http.Server
+	listener net.Listener
+}
+
+// handlerFunc handles a request for a route, with the values of any path parameters.
+type handlerFunc func(w http.ResponseWriter, r *http.Request, params map[string]string)
+
+// route is an endpoint served by the server.
+type route struct {
+	method   string
+	segments []string
+	handler  handlerFunc
+	// stream is true if the route returns a long-lived stream, and so is not subject to the timeout.
+	stream bool
+}
+
+// New creates a new beacon API server.
+func New(ctx context.Context, params ...Parameter) (*Service, error) {
+	parameters, err := parseAndCheckParameters(params...)
+	if err != nil {
+		return nil, errors.Wrap(err, "problem with parameters")
+	}
+
+	// Set logging.
+	log := zerologger.With().Str("service", "server").Str("impl", "beaconapi").Logger()
+	if parameters.logLevel != log.GetLevel() {
+		log = log.Level(parameters.logLevel)
+	}
+
+	s := &Service{
+		log:     log,
+		service: parameters.service,
+		timeout: parameters.timeout,
+	}
+	s.addRoutes()
+
+	if parameters.listenAddress != "" {
+		if err := s.listen(ctx, parameters.listenAddress); err != nil {
+			return nil, err
+		}
+	}
+
+	return s, nil
+}
+
+// Address returns the address on which the server is listening, or an empty
+// string if it is not listening.
+func (s *Service) Address() string {
+	if s.listener == nil {
+		return ""
+	}
+
+	return s.listener.Addr().String()
+}
+
+// listen starts listening on the given address, shutting down when the context is done.
+func (s *Service) listen(ctx context.Context, address string) error {
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return errors.Wrap(err, "failed to listen")
+	}
+	s.listener = listener
+	s.server = &http.Server{
+		Handler:           s,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	go func() {
+		if err := s.server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			s.log.Error().Err(err).Msg("Server stopped")
+		}
+	}()
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := s.server.Shutdown(shutdownCtx); err != nil {
+			s.log.Debug().Err(err).Msg("Failed to shut down server cleanly")
+		}
+	}()
+	s.log.Trace().Str("address", listener.Addr().String()).Msg("Listening")
+
+	return nil
+}
+
+// handle adds a route to the server.
+func (s *Service) handle(method string, pattern string, handler handlerFunc) {
+	s.routes = append(s.routes, &route{
+		method:   method,
+		segments: strings.Split(strings.Trim(pattern, "/"), "/"),
+		handler:  handler,
+	})
+}
+
+// handleStream adds a streaming route to the server.
+func (s *Service) handleStream(method string, pattern string, handler handlerFunc) {
+	s.handle(method, pattern, handler)
+	s.routes[len(s.routes)-1].stream = true
+}
+
+// ServeHTTP implements http.Handler.
+func (s *Service) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	segments := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	pathMatched := false
+	for _, route := range s.routes {
+		params, matched := route.match(segments)
+		if !matched {
+			continue
+		}
+		pathMatched = true
+		if route.method != r.Method {
+			continue
+		}
+
+		s.log.Trace().Str("method", r.Method).Str("path", r.URL.Path).Msg("Request")
+		if !route.stream {
+			ctx, cancel := context.WithTimeout(r.Context(), s.timeout)
+			defer cancel()
+			r = r.WithContext(ctx)
+		}
+		route.handler(w, r, params)
+
+		return
+	}
+
+	if pathMatched {
+		s.writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+
+		return
+	}
+	s.writeError(w, http.StatusNotFound, "endpoint not found")
+}
+
+// match returns the path parameters if the path segments match the route.
+func (r *route) match(segments []string) (map[string]string, bool) {
+	if len(segments) != len(r.segments) {
+		return nil, false
+	}
+
+	var params map[string]string
+	for i, segment := range r.segments {
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			if params == nil {
+				params = make(map[string]string)
+			}
+			params[strings.Trim(segment, "{}")] = segments[i]
+
+			continue
+		}
+		if segment != segments[i] {
+			return nil, false
+		}
+	}
+
+	return params, true
+}
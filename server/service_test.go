@@ -0,0 +1,394 @@
+// Copyright © 2023 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	nethttp "net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	client "github.com/attestantio/go-eth2-client"
+	"github.com/attestantio/go-eth2-client/api"
+	apiv1 "github.com/attestantio/go-eth2-client/api/v1"
+	"github.com/attestantio/go-eth2-client/http"
+	"github.com/attestantio/go-eth2-client/mock"
+	"github.com/attestantio/go-eth2-client/server"
+	"github.com/attestantio/go-eth2-client/spec"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+// minimalService is a service that implements no providers other than events.
+type minimalService struct{}
+
+func (*minimalService) Name() string    { return "minimal" }
+func (*minimalService) Address() string { return "minimal" }
+
+func (*minimalService) Events(ctx context.Context, topics []string, handler client.EventHandlerFunc) error {
+	go func() {
+		for _, topic := range topics {
+			select {
+			case <-ctx.Done():
+				return
+			default:
+				handler(&apiv1.Event{
+					Topic: topic,
+					Data: &apiv1.HeadEvent{
+						Slot: 12345,
+					},
+				})
+			}
+		}
+	}()
+
+	return nil
+}
+
+func TestService(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name   string
+		params []server.Parameter
+		err    string
+	}{
+		{
+			name: "ServiceMissing",
+			params: []server.Parameter{
+				server.WithLogLevel(zerolog.Disabled),
+			},
+			err: "problem with parameters: no service specified",
+		},
+		{
+			name: "TimeoutZero",
+			params: []server.Parameter{
+				server.WithLogLevel(zerolog.Disabled),
+				server.WithService(&minimalService{}),
+				server.WithTimeout(0),
+			},
+			err: "problem with parameters: no timeout specified",
+		},
+		{
+			name: "TimeoutNegative",
+			params: []server.Parameter{
+				server.WithLogLevel(zerolog.Disabled),
+				server.WithService(&minimalService{}),
+				server.WithTimeout(-1),
+			},
+			err: "problem with parameters: timeout cannot be negative",
+		},
+		{
+			name: "Good",
+			params: []server.Parameter{
+				server.WithLogLevel(zerolog.Disabled),
+				server.WithService(&minimalService{}),
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := server.New(ctx, test.params...)
+			if test.err != "" {
+				require.EqualError(t, err, test.err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestListen(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	mockClient, err := mock.New(context.Background())
+	require.NoError(t, err)
+	s, err := server.New(ctx,
+		server.WithLogLevel(zerolog.Disabled),
+		server.WithService(mockClient),
+		server.WithListenAddress("127.0.0.1:0"),
+	)
+	require.NoError(t, err)
+	require.NotEmpty(t, s.Address())
+
+	httpClient, err := http.New(ctx,
+		http.WithLogLevel(zerolog.Disabled),
+		http.WithAddress("http://"+s.Address()),
+	)
+	require.NoError(t, err)
+	genesisResponse, err := httpClient.(client.GenesisProvider).Genesis(ctx)
+	require.NoError(t, err)
+	expectedGenesis, err := mockClient.Genesis(ctx)
+	require.NoError(t, err)
+	require.Equal(t, expectedGenesis.Data.GenesisTime.Unix(), genesisResponse.Data.GenesisTime.Unix())
+}
+
+func TestRoundTrip(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	mockClient, err := mock.New(context.Background())
+	require.NoError(t, err)
+	s, err := server.New(ctx,
+		server.WithLogLevel(zerolog.Disabled),
+		server.WithService(mockClient),
+	)
+	require.NoError(t, err)
+	srv := httptest.NewServer(s)
+	defer srv.Close()
+
+	service, err := http.New(ctx,
+		http.WithLogLevel(zerolog.Disabled),
+		http.WithAddress(srv.URL),
+	)
+	require.NoError(t, err)
+
+	nodeVersionResponse, err := service.(client.NodeVersionProvider).NodeVersion(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "mock", nodeVersionResponse.Data)
+
+	rootResponse, err := service.(client.BeaconBlockRootProvider).BeaconBlockRoot(ctx, &api.BeaconBlockRootOpts{Block: "head"})
+	require.NoError(t, err)
+	expectedRoot, err := mockClient.BeaconBlockRoot(ctx, &api.BeaconBlockRootOpts{Block: "head"})
+	require.NoError(t, err)
+	require.Equal(t, *expectedRoot.Data, *rootResponse.Data)
+
+	blockResponse, err := service.(client.SignedBeaconBlockProvider).SignedBeaconBlock(ctx, &api.SignedBeaconBlockOpts{Block: "head"})
+	require.NoError(t, err)
+	require.Equal(t, spec.DataVersionPhase0, blockResponse.Data.Version)
+
+	dutiesResponse, err := service.(client.AttesterDutiesProvider).AttesterDuties(ctx, &api.AttesterDutiesOpts{
+		Epoch:   1,
+		Indices: []phase0.ValidatorIndex{1, 2},
+	})
+	require.NoError(t, err)
+	expectedDuties, err := mockClient.AttesterDuties(ctx, &api.AttesterDutiesOpts{
+		Epoch:   1,
+		Indices: []phase0.ValidatorIndex{1, 2},
+	})
+	require.NoError(t, err)
+	require.Equal(t, len(expectedDuties.Data), len(dutiesResponse.Data))
+
+	// Block submission without a version header relies on the server detecting the version.
+	require.NoError(t, service.(client.BeaconBlockSubmitter).SubmitBeaconBlock(ctx, blockResponse.Data))
+
+	require.NoError(t, service.(client.AttestationsSubmitter).SubmitAttestations(ctx, []*phase0.Attestation{}))
+}
+
+func TestContentNegotiation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	mockClient, err := mock.New(context.Background())
+	require.NoError(t, err)
+	s, err := server.New(ctx,
+		server.WithLogLevel(zerolog.Disabled),
+		server.WithService(mockClient),
+	)
+	require.NoError(t, err)
+	srv := httptest.NewServer(s)
+	defer srv.Close()
+
+	tests := []struct {
+		name        string
+		endpoint    string
+		accept      string
+		statusCode  int
+		contentType string
+		version     string
+	}{
+		{
+			name:        "BlockJSON",
+			endpoint:    "/eth/v2/beacon/blocks/head",
+			accept:      "application/json",
+			statusCode:  nethttp.StatusOK,
+			contentType: "application/json",
+			version:     "phase0",
+		},
+		{
+			name:        "BlockSSZ",
+			endpoint:    "/eth/v2/beacon/blocks/head",
+			accept:      "application/octet-stream;q=1,application/json;q=0.9",
+			statusCode:  nethttp.StatusOK,
+			contentType: "application/octet-stream",
+			version:     "phase0",
+		},
+		{
+			name:        "GenesisJSONOnly",
+			endpoint:    "/eth/v1/beacon/genesis",
+			accept:      "application/octet-stream",
+			statusCode:  nethttp.StatusOK,
+			contentType: "application/json",
+		},
+		{
+			name:        "ProposalV3",
+			endpoint:    fmt.Sprintf("/eth/v3/validator/blocks/1?randao_reveal=%#x", phase0.BLSSignature{}),
+			accept:      "application/json",
+			statusCode:  nethttp.StatusOK,
+			contentType: "application/json",
+			version:     "phase0",
+		},
+		{
+			name:        "ProposalRandaoMissing",
+			endpoint:    "/eth/v3/validator/blocks/1",
+			statusCode:  nethttp.StatusBadRequest,
+			contentType: "application/json",
+		},
+		{
+			name:        "BadBlockID",
+			endpoint:    "/eth/v2/beacon/blocks/bad",
+			statusCode:  nethttp.StatusBadRequest,
+			contentType: "application/json",
+		},
+		{
+			name:        "UnknownEndpoint",
+			endpoint:    "/eth/v1/unknown",
+			statusCode:  nethttp.StatusNotFound,
+			contentType: "application/json",
+		},
+		{
+			name:        "WrongMethod",
+			endpoint:    "/eth/v1/validator/duties/attester/1",
+			statusCode:  nethttp.StatusMethodNotAllowed,
+			contentType: "application/json",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req, err := nethttp.NewRequestWithContext(ctx, nethttp.MethodGet, srv.URL+test.endpoint, nil)
+			require.NoError(t, err)
+			if test.accept != "" {
+				req.Header.Set("Accept", test.accept)
+			}
+			resp, err := nethttp.DefaultClient.Do(req)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+			_, err = io.Copy(io.Discard, resp.Body)
+			require.NoError(t, err)
+
+			require.Equal(t, test.statusCode, resp.StatusCode)
+			require.Equal(t, test.contentType, resp.Header.Get("Content-Type"))
+			require.Equal(t, test.version, resp.Header.Get("Eth-Consensus-Version"))
+		})
+	}
+}
+
+func TestSubmitBeaconBlockVersion(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	mockClient, err := mock.New(context.Background())
+	require.NoError(t, err)
+	s, err := server.New(ctx,
+		server.WithLogLevel(zerolog.Disabled),
+		server.WithService(mockClient),
+	)
+	require.NoError(t, err)
+	srv := httptest.NewServer(s)
+	defer srv.Close()
+
+	blockResponse, err := mockClient.SignedBeaconBlock(ctx, &api.SignedBeaconBlockOpts{Block: "head"})
+	require.NoError(t, err)
+	encoded, err := json.Marshal(blockResponse.Data.Phase0)
+	require.NoError(t, err)
+	// The mock block has no operations, which must be supplied as empty lists.
+	block := strings.ReplaceAll(string(encoded), "null", "[]")
+
+	tests := []struct {
+		name       string
+		body       string
+		statusCode int
+	}{
+		{
+			name:       "Inferred",
+			body:       block,
+			statusCode: nethttp.StatusOK,
+		},
+		{
+			// A later block that does not decode as its own version must not be submitted
+			// as an earlier version, dropping its additional fields.
+			name:       "NotInferred",
+			body:       strings.Replace(block, `"body":{`, `"body":{"execution_payload":{},`, 1),
+			statusCode: nethttp.StatusBadRequest,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			resp, err := nethttp.Post(srv.URL+"/eth/v1/beacon/blocks", "application/json", strings.NewReader(test.body))
+			require.NoError(t, err)
+			defer resp.Body.Close()
+			require.Equal(t, test.statusCode, resp.StatusCode)
+		})
+	}
+}
+
+func TestNotImplemented(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	s, err := server.New(ctx,
+		server.WithLogLevel(zerolog.Disabled),
+		server.WithService(&minimalService{}),
+	)
+	require.NoError(t, err)
+	srv := httptest.NewServer(s)
+	defer srv.Close()
+
+	resp, err := nethttp.Get(srv.URL + "/eth/v1/beacon/genesis")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Equal(t, nethttp.StatusNotImplemented, resp.StatusCode)
+	require.JSONEq(t, `{"code":501,"message":"Genesis not supported by the service"}`, string(body))
+}
+
+func TestEvents(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	s, err := server.New(ctx,
+		server.WithLogLevel(zerolog.Disabled),
+		server.WithService(&minimalService{}),
+	)
+	require.NoError(t, err)
+	srv := httptest.NewServer(s)
+	defer srv.Close()
+
+	req, err := nethttp.NewRequestWithContext(ctx, nethttp.MethodGet, srv.URL+"/eth/v1/events?topics=head", nil)
+	require.NoError(t, err)
+	resp, err := nethttp.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, nethttp.StatusOK, resp.StatusCode)
+	require.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	reader := bufio.NewReader(resp.Body)
+	line, err := reader.ReadString('\n')
+	require.NoError(t, err)
+	require.Equal(t, "event: head\n", line)
+	line, err = reader.ReadString('\n')
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(line, `data: {"slot":"12345"`))
+}
@@ -0,0 +1,356 @@
+// Copyright © 2023 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"net/http"
+
+	consensusclient "github.com/attestantio/go-eth2-client"
+	"github.com/attestantio/go-eth2-client/api"
+	apiv1 "github.com/attestantio/go-eth2-client/api/v1"
+	"github.com/attestantio/go-eth2-client/spec"
+	"github.com/attestantio/go-eth2-client/spec/altair"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+)
+
+func (s *Service) attesterDuties(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	provider, isProvider := s.service.(consensusclient.AttesterDutiesProvider)
+	if !isProvider {
+		s.writeNotImplemented(w, "AttesterDuties")
+
+		return
+	}
+	epoch, err := parseUint64("epoch", params["epoch"])
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, err.Error())
+
+		return
+	}
+	indices, err := indicesFromBody(r)
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, err.Error())
+
+		return
+	}
+
+	response, err := provider.AttesterDuties(r.Context(), &api.AttesterDutiesOpts{
+		Epoch:   phase0.Epoch(epoch),
+		Indices: indices,
+	})
+	if err != nil {
+		s.writeServiceError(w, "AttesterDuties", err)
+
+		return
+	}
+	s.writeResponse(w, r, response.Data, response.Metadata)
+}
+
+func (s *Service) proposerDuties(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	provider, isProvider := s.service.(consensusclient.ProposerDutiesProvider)
+	if !isProvider {
+		s.writeNotImplemented(w, "ProposerDuties")
+
+		return
+	}
+	epoch, err := parseUint64("epoch", params["epoch"])
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, err.Error())
+
+		return
+	}
+
+	response, err := provider.ProposerDuties(r.Context(), &api.ProposerDutiesOpts{
+		Epoch: phase0.Epoch(epoch),
+	})
+	if err != nil {
+		s.writeServiceError(w, "ProposerDuties", err)
+
+		return
+	}
+	s.writeResponse(w, r, response.Data, response.Metadata)
+}
+
+func (s *Service) syncCommitteeDuties(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	provider, isProvider := s.service.(consensusclient.SyncCommitteeDutiesProvider)
+	if !isProvider {
+		s.writeNotImplemented(w, "SyncCommitteeDuties")
+
+		return
+	}
+	epoch, err := parseUint64("epoch", params["epoch"])
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, err.Error())
+
+		return
+	}
+	indices, err := indicesFromBody(r)
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, err.Error())
+
+		return
+	}
+
+	response, err := provider.SyncCommitteeDuties(r.Context(), &api.SyncCommitteeDutiesOpts{
+		Epoch:   phase0.Epoch(epoch),
+		Indices: indices,
+	})
+	if err != nil {
+		s.writeServiceError(w, "SyncCommitteeDuties", err)
+
+		return
+	}
+	s.writeResponse(w, r, response.Data, response.Metadata)
+}
+
+func (s *Service) attestationData(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	provider, isProvider := s.service.(consensusclient.AttestationDataProvider)
+	if !isProvider {
+		s.writeNotImplemented(w, "AttestationData")
+
+		return
+	}
+	slot, err := queryUint64(r, "slot")
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, err.Error())
+
+		return
+	}
+	committeeIndex, err := queryUint64(r, "committee_index")
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, err.Error())
+
+		return
+	}
+
+	response, err := provider.AttestationData(r.Context(), &api.AttestationDataOpts{
+		Slot:           phase0.Slot(slot),
+		CommitteeIndex: phase0.CommitteeIndex(committeeIndex),
+	})
+	if err != nil {
+		s.writeServiceError(w, "AttestationData", err)
+
+		return
+	}
+	s.writeResponse(w, r, response.Data, response.Metadata)
+}
+
+func (s *Service) aggregateAttestation(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	provider, isProvider := s.service.(consensusclient.AggregateAttestationProvider)
+	if !isProvider {
+		s.writeNotImplemented(w, "AggregateAttestation")
+
+		return
+	}
+	slot, err := queryUint64(r, "slot")
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, err.Error())
+
+		return
+	}
+	root, err := queryHex(r, "attestation_data_root", phase0.RootLength)
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, err.Error())
+
+		return
+	}
+
+	response, err := provider.AggregateAttestation(r.Context(), &api.AggregateAttestationOpts{
+		Slot:                phase0.Slot(slot),
+		AttestationDataRoot: phase0.Root(root),
+	})
+	if err != nil {
+		s.writeServiceError(w, "AggregateAttestation", err)
+
+		return
+	}
+	s.writeResponse(w, r, response.Data, response.Metadata)
+}
+
+func (s *Service) syncCommitteeContribution(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	provider, isProvider := s.service.(consensusclient.SyncCommitteeContributionProvider)
+	if !isProvider {
+		s.writeNotImplemented(w, "SyncCommitteeContribution")
+
+		return
+	}
+	slot, err := queryUint64(r, "slot")
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, err.Error())
+
+		return
+	}
+	subcommitteeIndex, err := queryUint64(r, "subcommittee_index")
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, err.Error())
+
+		return
+	}
+	root, err := queryHex(r, "beacon_block_root", phase0.RootLength)
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, err.Error())
+
+		return
+	}
+
+	response, err := provider.SyncCommitteeContribution(r.Context(), &api.SyncCommitteeContributionOpts{
+		Slot:              phase0.Slot(slot),
+		SubcommitteeIndex: subcommitteeIndex,
+		BeaconBlockRoot:   phase0.Root(root),
+	})
+	if err != nil {
+		s.writeServiceError(w, "SyncCommitteeContribution", err)
+
+		return
+	}
+	s.writeResponse(w, r, response.Data, response.Metadata)
+}
+
+func (s *Service) submitAggregateAttestations(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	submitter, isSubmitter := s.service.(consensusclient.AggregateAttestationsSubmitter)
+	if !isSubmitter {
+		s.writeNotImplemented(w, "SubmitAggregateAttestations")
+
+		return
+	}
+	var aggregateAndProofs []*phase0.SignedAggregateAndProof
+	if err := decodeJSONBody(r, &aggregateAndProofs); err != nil {
+		s.writeError(w, http.StatusBadRequest, err.Error())
+
+		return
+	}
+
+	if err := submitter.SubmitAggregateAttestations(r.Context(), aggregateAndProofs); err != nil {
+		s.writeServiceError(w, "SubmitAggregateAttestations", err)
+
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Service) submitBeaconCommitteeSubscriptions(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	submitter, isSubmitter := s.service.(consensusclient.BeaconCommitteeSubscriptionsSubmitter)
+	if !isSubmitter {
+		s.writeNotImplemented(w, "SubmitBeaconCommitteeSubscriptions")
+
+		return
+	}
+	var subscriptions []*apiv1.BeaconCommitteeSubscription
+	if err := decodeJSONBody(r, &subscriptions); err != nil {
+		s.writeError(w, http.StatusBadRequest, err.Error())
+
+		return
+	}
+
+	if err := submitter.SubmitBeaconCommitteeSubscriptions(r.Context(), subscriptions); err != nil {
+		s.writeServiceError(w, "SubmitBeaconCommitteeSubscriptions", err)
+
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Service) submitSyncCommitteeSubscriptions(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	submitter, isSubmitter := s.service.(consensusclient.SyncCommitteeSubscriptionsSubmitter)
+	if !isSubmitter {
+		s.writeNotImplemented(w, "SubmitSyncCommitteeSubscriptions")
+
+		return
+	}
+	var subscriptions []*apiv1.SyncCommitteeSubscription
+	if err := decodeJSONBody(r, &subscriptions); err != nil {
+		s.writeError(w, http.StatusBadRequest, err.Error())
+
+		return
+	}
+
+	if err := submitter.SubmitSyncCommitteeSubscriptions(r.Context(), subscriptions); err != nil {
+		s.writeServiceError(w, "SubmitSyncCommitteeSubscriptions", err)
+
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Service) submitSyncCommitteeContributions(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	submitter, isSubmitter := s.service.(consensusclient.SyncCommitteeContributionsSubmitter)
+	if !isSubmitter {
+		s.writeNotImplemented(w, "SubmitSyncCommitteeContributions")
+
+		return
+	}
+	var contributionAndProofs []*altair.SignedContributionAndProof
+	if err := decodeJSONBody(r, &contributionAndProofs); err != nil {
+		s.writeError(w, http.StatusBadRequest, err.Error())
+
+		return
+	}
+
+	if err := submitter.SubmitSyncCommitteeContributions(r.Context(), contributionAndProofs); err != nil {
+		s.writeServiceError(w, "SubmitSyncCommitteeContributions", err)
+
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Service) submitProposalPreparations(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	submitter, isSubmitter := s.service.(consensusclient.ProposalPreparationsSubmitter)
+	if !isSubmitter {
+		s.writeNotImplemented(w, "SubmitProposalPreparations")
+
+		return
+	}
+	var preparations []*apiv1.ProposalPreparation
+	if err := decodeJSONBody(r, &preparations); err != nil {
+		s.writeError(w, http.StatusBadRequest, err.Error())
+
+		return
+	}
+
+	if err := submitter.SubmitProposalPreparations(r.Context(), preparations); err != nil {
+		s.writeServiceError(w, "SubmitProposalPreparations", err)
+
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Service) submitValidatorRegistrations(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	submitter, isSubmitter := s.service.(consensusclient.ValidatorRegistrationsSubmitter)
+	if !isSubmitter {
+		s.writeNotImplemented(w, "SubmitValidatorRegistrations")
+
+		return
+	}
+	var unversioned []*apiv1.SignedValidatorRegistration
+	if err := decodeJSONBody(r, &unversioned); err != nil {
+		s.writeError(w, http.StatusBadRequest, err.Error())
+
+		return
+	}
+	registrations := make([]*api.VersionedSignedValidatorRegistration, len(unversioned))
+	for i := range unversioned {
+		registrations[i] = &api.VersionedSignedValidatorRegistration{
+			Version: spec.BuilderVersionV1,
+			V1:      unversioned[i],
+		}
+	}
+
+	if err := submitter.SubmitValidatorRegistrations(r.Context(), registrations); err != nil {
+		s.writeServiceError(w, "SubmitValidatorRegistrations", err)
+
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
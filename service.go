@@ -15,6 +15,7 @@ package client
 
 import (
 	"context"
+	"io"
 	"time"
 
 	api "github.com/attestantio/go-eth2-client/api"
@@ -114,6 +115,13 @@ type SignedBeaconBlockProvider interface {
 	SignedBeaconBlock(ctx context.Context, opts *api.SignedBeaconBlockOpts) (*api.Response[*spec.VersionedSignedBeaconBlock], error)
 }
 
+// SignedBeaconBlockSSZWriter is the interface for writing SSZ-encoded beacon blocks.
+type SignedBeaconBlockSSZWriter interface {
+	// WriteSignedBeaconBlockSSZ writes the SSZ-encoded signed beacon block given a block ID to the supplied writer.
+	// The block is not decoded, so this is suitable for persisting blocks without holding them in memory.
+	WriteSignedBeaconBlockSSZ(ctx context.Context, opts *api.SignedBeaconBlockOpts, w io.Writer) (*api.Response[*api.SSZWriteResult], error)
+}
+
 // BlobSidecarsProvider is the interface for providing blobs for a given beacon block.
 type BlobSidecarsProvider interface {
 	// BlobSidecars fetches the blobs given a block ID.
@@ -266,6 +274,13 @@ type BeaconStateProvider interface {
 	BeaconState(ctx context.Context, opts *api.BeaconStateOpts) (*api.Response[*spec.VersionedBeaconState], error)
 }
 
+// BeaconStateSSZWriter is the interface for writing SSZ-encoded beacon state.
+type BeaconStateSSZWriter interface {
+	// WriteBeaconStateSSZ writes the SSZ-encoded beacon state given a state ID to the supplied writer.
+	// The state is not decoded, so this is suitable for persisting states without holding them in memory.
+	WriteBeaconStateSSZ(ctx context.Context, opts *api.BeaconStateOpts, w io.Writer) (*api.Response[*api.SSZWriteResult], error)
+}
+
 // BeaconStateRandaoProvider is the interface for providing beacon state RANDAOs.
 type BeaconStateRandaoProvider interface {
 	// BeaconStateRandao fetches a beacon state RANDAO given a state ID.
@@ -423,6 +438,12 @@ type GenesisTimeProvider interface {
 	GenesisTime(ctx context.Context) (time.Time, error)
 }
 
+// CapabilitiesProvider provides the capabilities of the node.
+type CapabilitiesProvider interface {
+	// Capabilities provides the capabilities of the node.
+	Capabilities(ctx context.Context) (*api.Response[*api.Capabilities], error)
+}
+
 // NodeClientProvider provides the client for the node.
 type NodeClientProvider interface {
 	// NodeClient provides the client for the node.